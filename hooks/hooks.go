@@ -0,0 +1,93 @@
+// Package hooks lets a deployment post-process API responses, and purge the
+// shared caches in front of the API, without changing the handlers. Hooks are
+// registered at startup, before the server is built, typically from an init
+// function in a file added to the main package:
+//
+//	func init() {
+//		hooks.RegisterResponseHook(hooks.ResponseHookFunc(func(r *http.Request, resp *hooks.Response) error {
+//			resp.Metadata["sponsor"] = "Brought to you by ..."
+//			return nil
+//		}))
+//	}
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Response is an API response about to be written in the standard envelope.
+// Hooks may change any field, including replacing Data.
+type Response struct {
+	Status   int
+	Metadata map[string]interface{}
+	Data     interface{}
+}
+
+// ResponseHook post-processes API responses. Error responses and streamed
+// exports are not passed to hooks.
+type ResponseHook interface {
+	// ProcessResponse is called for every enveloped response. An error is
+	// logged and the response is written as the hook left it.
+	ProcessResponse(r *http.Request, resp *Response) error
+}
+
+// ResponseHookFunc adapts a function to a ResponseHook.
+type ResponseHookFunc func(r *http.Request, resp *Response) error
+
+func (f ResponseHookFunc) ProcessResponse(r *http.Request, resp *Response) error {
+	return f(r, resp)
+}
+
+// PurgeHook purges the responses a sync made stale from a shared cache, such
+// as a CDN the built-in purge request does not suit. Keys are the surrogate
+// keys the responses were tagged with.
+type PurgeHook interface {
+	// Purge is called after each sync that changes the timetables. An error
+	// is logged.
+	Purge(ctx context.Context, keys []string) error
+}
+
+// PurgeHookFunc adapts a function to a PurgeHook.
+type PurgeHookFunc func(ctx context.Context, keys []string) error
+
+func (f PurgeHookFunc) Purge(ctx context.Context, keys []string) error {
+	return f(ctx, keys)
+}
+
+var (
+	mu            sync.Mutex
+	responseHooks []ResponseHook
+	purgeHooks    []PurgeHook
+)
+
+// RegisterResponseHook adds h to the hooks run on each response, after those
+// already registered. Hooks registered after the server is built are not used.
+func RegisterResponseHook(h ResponseHook) {
+	mu.Lock()
+	defer mu.Unlock()
+	responseHooks = append(responseHooks, h)
+}
+
+// ResponseHooks returns the registered response hooks in registration order.
+func ResponseHooks() []ResponseHook {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]ResponseHook(nil), responseHooks...)
+}
+
+// RegisterPurgeHook adds h to the hooks run after each sync. Hooks registered
+// after the server is built are not used.
+func RegisterPurgeHook(h PurgeHook) {
+	mu.Lock()
+	defer mu.Unlock()
+	purgeHooks = append(purgeHooks, h)
+}
+
+// PurgeHooks returns the registered purge hooks in registration order.
+func PurgeHooks() []PurgeHook {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]PurgeHook(nil), purgeHooks...)
+}
@@ -0,0 +1,264 @@
+// Package app wires the service together: it constructs every component in
+// dependency order, starts their background work, and on shutdown stops them
+// in reverse order, so HTTP servers drain before the scraper finishes its sync
+// and the store is closed last.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"llm-router/internal/backup"
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/events"
+	"llm-router/internal/geocode"
+	"llm-router/internal/grpcserver"
+	"llm-router/internal/handler"
+	"llm-router/internal/mailer"
+	"llm-router/internal/notify"
+	"llm-router/internal/push"
+	"llm-router/internal/retention"
+	"llm-router/internal/scrapper"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// hook is a component's lifecycle. start must not block: long-running work is
+// started in goroutines that exit once ctx is done. stop may block until the
+// component has wound down, or until its context expires.
+type hook struct {
+	name  string
+	start func(ctx context.Context) error
+	stop  func(ctx context.Context) error
+}
+
+// App owns the components of a running server.
+type App struct {
+	cfg      *config.Config
+	logger   *zap.Logger
+	logLevel zap.AtomicLevel
+
+	store   *store.Store
+	events  *events.Hub
+	scraper *scrapper.Scraper
+	router  *handler.Router
+	watcher *config.Watcher
+	servers []*server
+
+	hooks []hook
+	// errc receives errors that should end Run, such as a server failing
+	// after it started.
+	errc chan error
+}
+
+// New constructs every component. Nothing runs until Run is called.
+func New(cfg *config.Config, flags config.Flags, logger *zap.Logger, logLevel zap.AtomicLevel) (*App, error) {
+	a := &App{cfg: cfg, logger: logger, logLevel: logLevel, errc: make(chan error, 1)}
+
+	s, err := store.NewStore(context.Background(), cfg.DBDriver, cfg.DatabaseDSN(), store.Options{
+		Timeout:         cfg.DBTimeout,
+		MaxOpenConns:    cfg.DBPool.MaxOpenConns,
+		MaxIdleConns:    cfg.DBPool.MaxIdleConns,
+		ConnMaxLifetime: cfg.DBPool.ConnMaxLifetime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initialize store: %w", err)
+	}
+	a.store = s
+	a.OnStop("store", func(context.Context) error { return s.Close() })
+
+	// Optional geocoder for address-based journeys
+	geo, err := geocode.New(cfg.Geocoder)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("initialize geocoder: %w", err)
+	}
+
+	// Public holidays pick the timetable variant a sync stores and a request
+	// is served from.
+	cal, err := calendar.New(cfg.Holidays)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("initialize calendar: %w", err)
+	}
+
+	// Event hub shared by the scraper (publisher) and streaming handlers (subscribers)
+	a.events = events.NewHub()
+
+	// Registered before the scraper so it is subscribed before the first
+	// sync, and stopped after it so the last sync's messages go out.
+	if len(cfg.Notify.Webhooks) > 0 {
+		n := notify.New(cfg.Notify, a.events, logger)
+		a.add(hook{name: "notifier", start: n.Start, stop: n.Stop})
+	}
+
+	a.scraper = scrapper.NewScraper(cfg, s, a.events, geo, cal, logger)
+	a.add(hook{
+		name:  "scraper",
+		start: func(ctx context.Context) error { a.scraper.Start(ctx); return nil },
+		stop:  a.scraper.Stop,
+	})
+
+	if cfg.Backup.Enabled() {
+		b, err := backup.New(cfg.Backup, s, logger)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("initialize backups: %w", err)
+		}
+		a.add(hook{name: "backups", start: b.Start, stop: b.Stop})
+	}
+
+	job, err := retention.New(cfg, s, logger)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("initialize maintenance: %w", err)
+	}
+	a.add(hook{name: "retention", start: job.Start, stop: job.Stop})
+
+	a.router = handler.NewRouter(cfg, s, a.scraper, a.events, geo, cal, logger)
+	a.router.Retention = job
+	a.router.LogLevel = &a.logLevel
+	if cfg.Accounts.Enabled {
+		a.router.Mailer = mailer.New(cfg.Accounts.SMTP, logger)
+	}
+	if cfg.Accounts.Push.Enabled {
+		sender, err := push.New(cfg.Accounts.Push)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("initialize push notifications: %w", err)
+		}
+		a.router.Push = sender
+	}
+	a.add(hook{
+		name:  "router",
+		start: func(ctx context.Context) error { a.router.Start(ctx); return nil },
+		stop:  a.router.Stop,
+	})
+
+	a.watcher = a.newWatcher(flags)
+	a.router.ConfigWatcher = a.watcher
+	a.OnStart("config watcher", func(ctx context.Context) error {
+		go a.watcher.WatchSignals(ctx)
+		return nil
+	})
+
+	a.servers = a.newServers(a.middleware(a.routes()))
+	for _, srv := range a.servers {
+		a.add(hook{name: srv.name, start: srv.start(a.fail), stop: srv.stop})
+	}
+
+	if cfg.GRPCPort != 0 {
+		g := grpcserver.New(cfg.GRPCPort, s, a.scraper, a.events, a.router.Timetable, a.router.IsAdminToken, cfg.AccessLog.Enabled, logger)
+		a.add(hook{
+			name:  "grpc server",
+			start: func(ctx context.Context) error { return g.Start(ctx, a.fail) },
+			stop:  g.Stop,
+		})
+	}
+	return a, nil
+}
+
+// newWatcher reloads the log level, sync schedule, KAI token and maintenance
+// mode on SIGHUP or from the admin API without dropping the caches. A reload
+// only resets the log level when log_level changed, so a level set through
+// the admin API survives unrelated reloads.
+func (a *App) newWatcher(flags config.Flags) *config.Watcher {
+	watcher := config.NewWatcher(a.cfg, flags, a.logger)
+	configuredLevel := a.cfg.LogLevel
+	watcher.OnReload(func(next *config.Config) {
+		if next.LogLevel != configuredLevel {
+			configuredLevel = next.LogLevel
+			if err := a.logLevel.UnmarshalText([]byte(next.LogLevel)); err != nil {
+				a.logger.Error("Invalid log level", zap.Error(err))
+			}
+		}
+		a.scraper.Reconfigure(next)
+		a.router.ReconfigureMaintenance(next)
+	})
+	return watcher
+}
+
+// middleware wraps the routes in the middleware applied to every request,
+// outermost first.
+func (a *App) middleware(mux http.Handler) http.Handler {
+	cfg := a.cfg
+	return handler.RequestIDMiddleware(
+		handler.AccessLogMiddleware(cfg.AccessLog, cfg.RateLimit.TrustForwardedFor,
+			handler.CORSMiddleware(cfg.CORS,
+				handler.RateLimitMiddleware(cfg.RateLimit,
+					handler.CompressionMiddleware(cfg.Compression,
+						a.router.MaintenanceMiddleware(mux))),
+				a.logger),
+			a.logger),
+		a.logger)
+}
+
+// OnStart registers fn to run, in registration order, when the app starts.
+func (a *App) OnStart(name string, fn func(ctx context.Context) error) {
+	a.add(hook{name: name, start: fn})
+}
+
+// OnStop registers fn to run, in reverse registration order, when the app
+// stops.
+func (a *App) OnStop(name string, fn func(ctx context.Context) error) {
+	a.add(hook{name: name, stop: fn})
+}
+
+func (a *App) add(h hook) {
+	a.hooks = append(a.hooks, h)
+}
+
+// fail ends Run with err, unless it is already ending.
+func (a *App) fail(err error) {
+	select {
+	case a.errc <- err:
+	default:
+	}
+}
+
+// Run starts every component and blocks until ctx is done or a component
+// fails, then stops them all within the configured shutdown timeout. It
+// returns the error that ended the run, if any.
+func (a *App) Run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	started := 0
+	var runErr error
+	for _, h := range a.hooks {
+		if h.start != nil {
+			if err := h.start(runCtx); err != nil {
+				runErr = fmt.Errorf("start %s: %w", h.name, err)
+				break
+			}
+		}
+		started++
+	}
+
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Shutting down")
+		case runErr = <-a.errc:
+			a.logger.Error("Shutting down after a failure", zap.Error(runErr))
+		}
+	}
+
+	// Background loops see runCtx end before their components are stopped.
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
+	defer stopCancel()
+	for i := started - 1; i >= 0; i-- {
+		h := a.hooks[i]
+		if h.stop == nil {
+			continue
+		}
+		if err := h.stop(stopCtx); err != nil {
+			a.logger.Error("Failed to stop component", zap.String("component", h.name), zap.Error(err))
+		}
+	}
+	return runErr
+}
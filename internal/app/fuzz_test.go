@@ -0,0 +1,234 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// fuzzConfig enables accounts, so the routes behind a session are reached
+// too. The admin API stays off: it starts syncs against the upstream and can
+// put the whole API into maintenance.
+const fuzzConfig = `
+accounts:
+  enabled: true
+  base_url: https://commuter.example
+`
+
+// fuzzApp is the API over an in-memory store holding two stations, a train
+// between them today and a signed-in user.
+type fuzzApp struct {
+	handler http.Handler
+	session string
+}
+
+func newFuzzApp(f *testing.F) *fuzzApp {
+	f.Helper()
+	path := filepath.Join(f.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(fuzzConfig), 0o600); err != nil {
+		f.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(config.Flags{
+		ConfigPath: path,
+		DBPath:     fmt.Sprintf("file:%s?mode=memory&cache=shared", f.Name()),
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	cfg.AdminToken = ""
+
+	a, err := New(cfg, config.Flags{ConfigPath: path}, zap.NewNop(), zap.NewAtomicLevel())
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Cleanup(func() { a.store.Close() })
+
+	ctx := context.Background()
+	if err := a.store.SetStations(ctx, []domain.Station{
+		{UID: "1", ID: "MRI", Name: "MANGGARAI", Type: domain.StationTypeKRL},
+		{UID: "2", ID: "BOO", Name: "BOGOR", Type: domain.StationTypeKRL},
+	}); err != nil {
+		f.Fatal(err)
+	}
+	now := time.Now().In(calendar.Jakarta)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, calendar.Jakarta)
+	for _, sch := range []domain.Schedule{
+		{StationID: "MRI", DepartsAt: day.Add(8 * time.Hour)},
+		{StationID: "BOO", DepartsAt: day.Add(9 * time.Hour)},
+	} {
+		sch.ID = sch.StationID + "-1001"
+		sch.StationOriginID, sch.StationDestinationID = "MRI", "BOO"
+		sch.TrainID, sch.Line, sch.Route = "1001", "COMMUTER LINE BOGOR", "MANGGARAI-BOGOR"
+		sch.ArrivesAt, sch.UpdatedAt = day.Add(9*time.Hour), now
+		sch.ServiceType, sch.ServiceDay = domain.ServiceCommuter, domain.ServiceDayWeekday
+		if err := a.store.SetSchedules(ctx, sch.StationID, domain.ServiceDayWeekday, day.Format(time.DateOnly), []domain.Schedule{sch}); err != nil {
+			f.Fatal(err)
+		}
+	}
+	run, err := a.store.StartSyncRun(ctx, domain.SyncTriggerAPI)
+	if err != nil {
+		f.Fatal(err)
+	}
+	if err := a.store.FinishSyncRun(ctx, run, domain.SyncRunStats{StationsSucceeded: 2, RowsWritten: 2}, nil); err != nil {
+		f.Fatal(err)
+	}
+
+	if err := a.store.CreateLoginToken(ctx, "rider@example.com", "fuzz-link", now.Add(time.Hour)); err != nil {
+		f.Fatal(err)
+	}
+	if _, err := a.store.RedeemLoginToken(ctx, "fuzz-link", "fuzz-session", now.Add(time.Hour)); err != nil {
+		f.Fatal(err)
+	}
+
+	return &fuzzApp{handler: a.middleware(a.routes()), session: "fuzz-session"}
+}
+
+// serve sends a request for target and fails t if it panics or answers with
+// a server error. Streams are cut off after a moment. Address journeys
+// answer 501 without a geocoder, which is expected here.
+func (fa *fuzzApp) serve(t *testing.T, method, target string, body []byte) {
+	u, err := url.ParseRequestURI(target)
+	if err != nil || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		t.Skip()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	r := httptest.NewRequest(method, "/", bytes.NewReader(body)).WithContext(ctx)
+	r.URL, r.RequestURI = u, target
+	r.Header.Set("Authorization", "Bearer "+fa.session)
+	if body != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				t.Fatalf("%s %s panicked: %v", method, target, v)
+			}
+		}()
+		fa.handler.ServeHTTP(w, r)
+	}()
+
+	if w.Code < http.StatusInternalServerError {
+		return
+	}
+	var problem struct {
+		Code string `json:"code"`
+	}
+	if w.Code == http.StatusNotImplemented && json.Unmarshal(w.Body.Bytes(), &problem) == nil && problem.Code == "geocoding_disabled" {
+		return
+	}
+	t.Fatalf("%s %s = %d: %s", method, target, w.Code, w.Body.String())
+}
+
+// FuzzPath requests paths with fuzzed path parameters.
+func FuzzPath(f *testing.F) {
+	for _, path := range []string{
+		"/api/v1/station/MRI",
+		"/api/v1/station/mri/",
+		"/api/v1/station/MRI/nearby",
+		"/api/v1/station/%00",
+		"/api/v1/line/bogor",
+		"/api/v1/line/COMMUTER%20LINE%20BOGOR/stations",
+		"/api/v1/schedule/MRI",
+		"/api/v1/schedule/../../etc/passwd",
+		"/api/v1/route/1001",
+		"/api/v1/route/1001/",
+		"/api/v1/arrivals/BOO",
+		"/api/v1/realtime/train/1001",
+		"/api/v1/realtime/station/MRI",
+		"/api/v1/analytics/heatmap/MRI",
+		"/api/v1/me/favorites/MRI",
+		"/api/v1/me/commutes/1",
+		"/board/MRI",
+		"/api/admin/stations/MRI/sync",
+		"/health/ready",
+	} {
+		f.Add(path)
+	}
+	fa := newFuzzApp(f)
+	f.Fuzz(func(t *testing.T, path string) {
+		fa.serve(t, http.MethodGet, path, nil)
+	})
+}
+
+// FuzzQuery requests the endpoints that take query parameters with fuzzed
+// queries.
+func FuzzQuery(f *testing.F) {
+	for _, seed := range []struct{ path, query string }{
+		{"/api/v1/station", "type=KRL&q=man"},
+		{"/api/v1/station/search", "q=manggarai&limit=5"},
+		{"/api/v1/station/autocomplete", "q=ma&limit=20"},
+		{"/api/v1/schedule/MRI", "date=2026-10-17&from=07:00&to=09:00&line=bogor&destination_id=BOO"},
+		{"/api/v1/schedule/MRI", "limit=-1&since=yesterday"},
+		{"/api/v1/journey", "from=MRI&to=BOO&at=08:00&arrive_by=true&max_rides=2"},
+		{"/api/v1/journey", "from_address=Jl.%20Sudirman&to=BOO"},
+		{"/api/v1/next", "from=MRI&to=BOO&count=3"},
+		{"/api/v1/board/multi", "stations=MRI,BOO&limit=4"},
+		{"/api/v1/departures", "station_id=MRI&updated_since=2026-10-17T00:00:00Z"},
+		{"/api/v1/positions", "line=bogor"},
+		{"/api/v1/announcements", "status=active&lang=id"},
+		{"/api/v1/sync/history", "limit=999999999999999999999"},
+		{"/api/v1/sync/issues", "run=1&kind=emptied"},
+		{"/api/v1/changes", "since=2026-10-17T00:00:00Z"},
+		{"/api/v1/compare", "from=2026-10-16&to=2026-10-17&station_id=MRI"},
+		{"/api/v1/analytics/heatmap/MRI", "granularity=hour&older_than=30d"},
+		{"/api/v1/export/dump", "service_type=commuter&ordered=1"},
+		{"/api/v1/export/bundle", "filter=line:bogor"},
+		{"/api/v1/schedules", "since=0&topics=schedules"},
+		{"/api/v1/config", "lang=en"},
+		{"/board/MRI", "lang=id&limit=10"},
+	} {
+		f.Add(seed.path, seed.query)
+	}
+	fa := newFuzzApp(f)
+	f.Fuzz(func(t *testing.T, path, query string) {
+		fa.serve(t, http.MethodGet, path+"?"+query, nil)
+	})
+}
+
+// FuzzBody posts fuzzed bodies to the endpoints that read one.
+func FuzzBody(f *testing.F) {
+	for _, seed := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/v1/schedule/batch", `{"station_ids":["MRI","BOO"],"date":"2026-10-17","after":"22:00","before":"01:00"}`},
+		{http.MethodPost, "/api/v1/schedule/batch", `{"station_ids":[]}`},
+		{http.MethodPost, "/api/v1/report/delay", `{"train_id":"1001","station_id":"MRI","delay_minutes":5}`},
+		{http.MethodPost, "/api/v1/auth/login", `{"email":"rider@example.com"}`},
+		{http.MethodPost, "/api/v1/auth/verify", `{"token":"fuzz"}`},
+		{http.MethodPost, "/api/v1/me/favorites", `{"station_id":"MRI"}`},
+		{http.MethodPost, "/api/v1/me/commutes", `{"name":"Work","from":"MRI","to":"BOO","after":"07:00","before":"09:00","remind_before":10}`},
+		{http.MethodPost, "/api/v1/me/push", `{"endpoint":"https://push.example/1","keys":{"p256dh":"x","auth":"y"}}`},
+		{http.MethodDelete, "/api/v1/me/push", `{"endpoint":"https://push.example/1"}`},
+		{http.MethodPost, "/api/v1/sync", `{}`},
+		{http.MethodPost, "/api/v1/station/MRI/submissions", `{"kind":"photo","photo_url":"https://img.example/1.jpg","caption":"Hall"}`},
+		{http.MethodPost, "/api/v1/station/MRI/submissions", `{"kind":"amenity","facilities":{"toilet":true}}`},
+		{http.MethodPost, "/api/v1/schedule/batch", `[`},
+	} {
+		f.Add(seed.method, seed.path, []byte(seed.body))
+	}
+	fa := newFuzzApp(f)
+	f.Fuzz(func(t *testing.T, method, path string, body []byte) {
+		switch method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			t.Skip()
+		}
+		fa.serve(t, method, path, body)
+	})
+}
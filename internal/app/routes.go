@@ -0,0 +1,121 @@
+package app
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+
+	"llm-router/internal/handler"
+	"llm-router/web"
+
+	"go.uber.org/zap"
+)
+
+// routes registers the API, admin and static frontend routes.
+func (a *App) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// API Routes (Prefixed with /api)
+	mux.HandleFunc("/api/v1/station", a.router.Cacheable(a.router.HandleStation))
+	mux.HandleFunc("/api/v1/station/", a.router.Cacheable(a.router.HandleStationDetail))
+	mux.HandleFunc("/api/v1/station/search", a.router.HandleStationSearch)
+	mux.HandleFunc("/api/v1/station/autocomplete", a.router.HandleStationAutocomplete)
+	mux.HandleFunc("/api/v1/line", a.router.Cacheable(a.router.HandleLines))
+	mux.HandleFunc("/api/v1/line/", a.router.Cacheable(a.router.HandleLine))
+	mux.HandleFunc("/api/v1/schedule/", a.router.Cacheable(a.router.HandleSchedule)) // Trailing slash for path params
+	mux.HandleFunc("/api/v1/route/", a.router.Cacheable(a.router.HandleRoute))       // Trailing slash for path params
+	mux.HandleFunc("/api/v1/arrivals/", a.router.Cacheable(a.router.HandleArrivals))
+	mux.HandleFunc("/api/v1/journey", a.router.HandleJourney)
+	mux.HandleFunc("/api/v1/next", a.router.HandleNext)
+	mux.HandleFunc("/api/v1/board/multi", a.router.HandleMultiBoard)
+	mux.HandleFunc("/api/v1/schedule/batch", a.router.HandleScheduleBatch)
+	mux.HandleFunc("/api/v1/departures", a.router.Cacheable(a.router.HandleDepartures))
+	mux.HandleFunc("/api/v1/positions", a.router.HandlePositions)
+	mux.HandleFunc("/api/v1/status", a.router.HandleStatus)
+	mux.HandleFunc("/api/v1/announcements", a.router.HandleAnnouncements)
+	mux.HandleFunc("/api/v1/sync", a.router.HandleSync)
+	mux.HandleFunc("/api/v1/sync/history", a.router.HandleSyncHistory)
+	mux.HandleFunc("/api/v1/sync/issues", a.router.HandleSyncIssues)
+	mux.HandleFunc("/api/v1/changes", a.router.HandleChanges)
+	mux.HandleFunc("/api/v1/compare", a.router.HandleCompare)
+	mux.HandleFunc("/api/v1/realtime/train/", a.router.HandleRealtimeTrain)
+	mux.HandleFunc("/api/v1/realtime/station/", a.router.HandleRealtimeStation)
+	mux.HandleFunc("/api/v1/analytics/heatmap/", a.router.HandleHeatmap)
+	mux.HandleFunc("/api/v1/ws", a.router.HandleWebSocket)
+	mux.HandleFunc("/api/v1/export/dump", a.router.Cacheable(a.router.HandleDump))
+	mux.HandleFunc("/api/v1/export/bundle", a.router.HandleBundle)
+	mux.HandleFunc("/api/v1/schedules", a.router.HandleSchedulesSince)
+	mux.HandleFunc("/api/v1/report/delay", a.router.HandleDelayReport)
+	mux.HandleFunc("/api/v1/config", a.router.HandleClientConfig)
+
+	// User accounts (when accounts are enabled)
+	mux.HandleFunc("/api/v1/auth/login", a.router.HandleLogin)
+	mux.HandleFunc("/api/v1/auth/verify", a.router.HandleVerify)
+	mux.HandleFunc("/api/v1/auth/logout", a.router.RequireUser(a.router.HandleLogout))
+	mux.HandleFunc("/api/v1/me", a.router.RequireUser(a.router.HandleMe))
+	mux.HandleFunc("/api/v1/me/favorites", a.router.RequireUser(a.router.HandleFavorites))
+	mux.HandleFunc("/api/v1/me/favorites/", a.router.RequireUser(a.router.HandleFavorites))
+	mux.HandleFunc("/api/v1/me/commutes", a.router.RequireUser(a.router.HandleCommutes))
+	mux.HandleFunc("/api/v1/me/commutes/", a.router.RequireUser(a.router.HandleCommutes))
+	mux.HandleFunc("/api/v1/me/push", a.router.RequireUser(a.router.HandlePushSubscriptions))
+
+	// HTML departure boards for kiosks and overlays
+	mux.HandleFunc("/board/", a.router.HandleDepartureBoard)
+
+	// Admin API (requires ADMIN_TOKEN)
+	mux.HandleFunc("/api/admin/submissions", a.router.RequireAdmin(a.router.HandleAdminSubmissions))
+	mux.HandleFunc("/api/admin/submissions/", a.router.RequireAdmin(a.router.HandleAdminSubmissions))
+	mux.HandleFunc("/api/admin/db", a.router.RequireAdmin(a.router.HandleAdminDB))
+	mux.HandleFunc("/api/admin/sync", a.router.RequireAdmin(a.router.HandleAdminSync))
+	mux.HandleFunc("/api/admin/sync/", a.router.RequireAdmin(a.router.HandleAdminSync))
+	mux.HandleFunc("/api/admin/stations/", a.router.RequireAdmin(a.router.HandleAdminStations))
+	mux.HandleFunc("/api/admin/backup", a.router.RequireAdmin(a.router.HandleAdminBackup))
+	mux.HandleFunc("/api/admin/purge", a.router.RequireAdmin(a.router.HandleAdminPurge))
+	mux.HandleFunc("/api/admin/retention", a.router.RequireAdmin(a.router.HandleAdminRetention))
+	mux.HandleFunc("/api/admin/keys/", a.router.RequireAdmin(a.router.HandleAdminKeys))
+	mux.HandleFunc("/api/admin/announcements", a.router.RequireAdmin(a.router.HandleAdminAnnouncements))
+	mux.HandleFunc("/api/admin/announcements/", a.router.RequireAdmin(a.router.HandleAdminAnnouncements))
+	mux.HandleFunc("/api/admin/overrides", a.router.RequireAdmin(a.router.HandleAdminOverrides))
+	mux.HandleFunc("/api/admin/overrides/", a.router.RequireAdmin(a.router.HandleAdminOverrides))
+	mux.HandleFunc("/api/admin/flags", a.router.RequireAdmin(a.router.HandleAdminFlags))
+	mux.HandleFunc("/api/admin/flags/", a.router.RequireAdmin(a.router.HandleAdminFlags))
+	mux.HandleFunc("/api/admin/config/reload", a.router.RequireAdmin(a.router.HandleConfigReload))
+	mux.HandleFunc("/api/admin/metrics", a.router.RequireAdmin(a.router.HandleAdminMetrics))
+	mux.HandleFunc("/api/admin/maintenance", a.router.RequireAdmin(a.router.HandleAdminMaintenance))
+	mux.HandleFunc("/api/admin/log-level", a.router.RequireAdmin(a.router.HandleAdminLogLevel))
+	mux.HandleFunc("/api/admin/captures", a.router.RequireAdmin(a.router.HandleAdminCaptures))
+	mux.HandleFunc("/api/admin/captures/", a.router.RequireAdmin(a.router.HandleAdminCaptures))
+
+	// Health checks: liveness for restarts, readiness for routing traffic
+	mux.HandleFunc("/health", a.router.HandleLive)
+	mux.HandleFunc("/health/live", a.router.HandleLive)
+	mux.HandleFunc("/health/ready", a.router.HandleReady)
+
+	// The built frontend, embedded with the embedweb build tag or read from
+	// web/dist. In development, run the Vite dev server separately.
+	mux.Handle("/", handler.SPAHandler(a.frontend(a.cfg.WebDir)))
+
+	return mux
+}
+
+// frontend returns the frontend in dir when it is set, else the embedded
+// build, or else web/dist on disk, falling back to web for development.
+func (a *App) frontend(dir string) fs.FS {
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			a.logger.Warn("Frontend directory not found", zap.String("dir", dir), zap.Error(err))
+		}
+		a.logger.Info("Serving frontend from disk", zap.String("dir", dir))
+		return os.DirFS(dir)
+	}
+	if dist := web.Dist(); dist != nil {
+		a.logger.Info("Serving embedded frontend")
+		return dist
+	}
+	dir = "./web/dist"
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		dir = "./web"
+	}
+	a.logger.Info("Serving frontend from disk", zap.String("dir", dir))
+	return os.DirFS(dir)
+}
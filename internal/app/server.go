@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// server is an HTTP listener managed by the app. Its port is bound when the
+// app starts, so a port in use fails the start instead of the first request.
+type server struct {
+	name     string
+	srv      *http.Server
+	certFile string
+	keyFile  string
+	tls      bool
+	logger   *zap.Logger
+}
+
+// newServers returns the listeners for h: the API on the configured port,
+// over HTTPS when TLS is configured. In autocert mode a second, plain HTTP
+// listener answers Let's Encrypt's challenges and redirects everything else
+// to HTTPS.
+func (a *App) newServers(h http.Handler) []*server {
+	addr := fmt.Sprintf(":%d", a.cfg.ListeningPort)
+	tlsCfg := a.cfg.TLS
+
+	api := &server{name: "http server", srv: &http.Server{Addr: addr, Handler: h}, logger: a.logger}
+	switch {
+	case tlsCfg.Autocert.Enabled():
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(tlsCfg.Autocert.Domains...),
+			Cache:      autocert.DirCache(tlsCfg.Autocert.CacheDir),
+			Email:      tlsCfg.Autocert.Email,
+		}
+		api.srv.TLSConfig = manager.TLSConfig()
+		api.tls = true
+		challenges := &server{
+			name:   "acme challenge server",
+			srv:    &http.Server{Addr: fmt.Sprintf(":%d", tlsCfg.Autocert.HTTPPort), Handler: manager.HTTPHandler(nil)},
+			logger: a.logger,
+		}
+		return []*server{challenges, api}
+	case tlsCfg.CertFile != "":
+		api.tls = true
+		api.certFile, api.keyFile = tlsCfg.CertFile, tlsCfg.KeyFile
+	}
+	return []*server{api}
+}
+
+// start binds the port and serves in the background. Requests see ctx end
+// when the app shuts down, so streams close before the server drains.
+// Serving errors after the start are reported to fail.
+func (s *server) start(fail func(error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ln, err := net.Listen("tcp", s.srv.Addr)
+		if err != nil {
+			return err
+		}
+		s.srv.BaseContext = func(net.Listener) context.Context { return ctx }
+
+		s.logger.Info("Server listening", zap.String("server", s.name), zap.String("address", s.srv.Addr), zap.Bool("tls", s.tls))
+		go func() {
+			var err error
+			if s.tls {
+				err = s.srv.ServeTLS(ln, s.certFile, s.keyFile)
+			} else {
+				err = s.srv.Serve(ln)
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				fail(fmt.Errorf("%s: %w", s.name, err))
+			}
+		}()
+		return nil
+	}
+}
+
+// stop stops accepting connections and waits for requests in flight.
+func (s *server) stop(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
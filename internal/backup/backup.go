@@ -0,0 +1,146 @@
+// Package backup takes scheduled snapshots of the database into a directory
+// and prunes old ones.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/cron"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// filePrefix and fileSuffix frame the timestamp in backup file names, which
+// therefore sort oldest first.
+const (
+	filePrefix = "commuter-"
+	fileSuffix = ".db"
+	timeLayout = "20060102-150405"
+)
+
+// FileName is the name of a backup taken at t.
+func FileName(t time.Time) string {
+	return filePrefix + t.In(calendar.Jakarta).Format(timeLayout) + fileSuffix
+}
+
+// Scheduler writes a backup whenever its schedule fires.
+type Scheduler struct {
+	dir      string
+	keep     int
+	schedule cron.Schedule
+	store    *store.Store
+	logger   *zap.Logger
+	wg       sync.WaitGroup
+}
+
+// New returns a scheduler for cfg, which must be valid.
+func New(cfg config.BackupConfig, s *store.Store, logger *zap.Logger) (*Scheduler, error) {
+	schedule, err := cfg.Schedule()
+	if err != nil {
+		return nil, fmt.Errorf("backup schedule: %w", err)
+	}
+	return &Scheduler{dir: cfg.Dir, keep: cfg.Keep, schedule: schedule, store: s, logger: logger}, nil
+}
+
+// Start creates the backup directory and schedules backups until ctx is
+// done.
+func (b *Scheduler) Start(ctx context.Context) error {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.run(ctx)
+	}()
+	return nil
+}
+
+// Stop waits for a backup in progress to finish, or for ctx to expire.
+func (b *Scheduler) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Scheduler) run(ctx context.Context) {
+	for {
+		target := b.schedule.Next(time.Now().In(calendar.Jakarta))
+		if target.IsZero() {
+			b.logger.Warn("Backup schedule never fires, no backup scheduled", zap.Stringer("schedule", b.schedule))
+			return
+		}
+		b.logger.Info("Scheduled next backup", zap.Time("target_jakarta", target))
+
+		timer := time.NewTimer(time.Until(target))
+		select {
+		case <-timer.C:
+			path, err := b.Backup(ctx)
+			if err != nil {
+				b.logger.Error("Scheduled backup failed", zap.Error(err))
+				continue
+			}
+			b.logger.Info("Backup written", zap.String("path", path))
+			if err := b.prune(); err != nil {
+				b.logger.Warn("Failed to prune old backups", zap.Error(err))
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Backup writes a backup into the directory and returns its path. The file
+// only appears under its final name once complete.
+func (b *Scheduler) Backup(ctx context.Context) (string, error) {
+	path := filepath.Join(b.dir, FileName(time.Now()))
+	tmp := path + ".tmp"
+	if err := b.store.Backup(ctx, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("backup: %w", err)
+	}
+	return path, nil
+}
+
+// prune removes all but the newest keep backups. Other files in the
+// directory are left alone.
+func (b *Scheduler) prune() error {
+	paths, err := filepath.Glob(filepath.Join(b.dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return err
+	}
+	if len(paths) <= b.keep {
+		return nil
+	}
+	slices.Sort(paths)
+	for _, path := range paths[:len(paths)-b.keep] {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		b.logger.Info("Removed old backup", zap.String("path", path))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Package cache holds assembled responses, such as station boards and train
+// routes, keyed by station, train or line. Entries are built lazily on first
+// request and dropped wholesale when a sync completes.
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"llm-router/internal/domain"
+)
+
+// Cache maps keys to values of V. It is safe for concurrent use.
+type Cache[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]V
+
+	// hits and misses count lookups since the last metrics snapshot.
+	hits, misses atomic.Int64
+}
+
+func New[V any]() *Cache[V] {
+	return &Cache[V]{entries: make(map[string]V)}
+}
+
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.entries[key]
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return v, ok
+}
+
+func (c *Cache[V]) Set(key string, v V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = v
+}
+
+func (c *Cache[V]) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]V)
+}
+
+// Stats reports the cache's size and the lookups since it was last called.
+func (c *Cache[V]) Stats() domain.CacheStats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	s := domain.CacheStats{Entries: entries, Hits: c.hits.Swap(0), Misses: c.misses.Swap(0)}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	return s
+}
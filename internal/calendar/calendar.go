@@ -0,0 +1,167 @@
+// Package calendar classifies dates by the KRL timetable that runs on them:
+// weekdays, weekends, or Indonesian public holidays.
+package calendar
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// DateLayout is the format of the dates the calendar is keyed by.
+const DateLayout = "2006-01-02"
+
+// Jakarta is the zone timetables are published in. A fixed offset avoids
+// depending on the host's tzdata; Indonesia does not observe DST.
+var Jakarta = time.FixedZone("Asia/Jakarta", 7*60*60)
+
+// holidays are the national public holidays (hari libur nasional) set by the
+// joint ministerial decree for each year. Collective leave days (cuti
+// bersama) are not included, since KRL keeps its regular timetable on them.
+// Later years are added here as the decrees are published, or configured
+// with the holidays option in the meantime.
+var holidays = map[string]string{
+	// 2025
+	"2025-01-01": "Tahun Baru Masehi",
+	"2025-01-27": "Isra Mikraj Nabi Muhammad SAW",
+	"2025-01-29": "Tahun Baru Imlek",
+	"2025-03-29": "Hari Suci Nyepi",
+	"2025-03-31": "Idul Fitri",
+	"2025-04-01": "Idul Fitri",
+	"2025-04-18": "Wafat Yesus Kristus",
+	"2025-04-20": "Kebangkitan Yesus Kristus (Paskah)",
+	"2025-05-01": "Hari Buruh Internasional",
+	"2025-05-12": "Hari Raya Waisak",
+	"2025-05-29": "Kenaikan Yesus Kristus",
+	"2025-06-01": "Hari Lahir Pancasila",
+	"2025-06-06": "Idul Adha",
+	"2025-06-27": "Tahun Baru Islam",
+	"2025-08-17": "Hari Kemerdekaan Republik Indonesia",
+	"2025-09-05": "Maulid Nabi Muhammad SAW",
+	"2025-12-25": "Hari Raya Natal",
+
+	// 2026
+	"2026-01-01": "Tahun Baru Masehi",
+	"2026-01-16": "Isra Mikraj Nabi Muhammad SAW",
+	"2026-02-17": "Tahun Baru Imlek",
+	"2026-03-19": "Hari Suci Nyepi",
+	"2026-03-20": "Idul Fitri",
+	"2026-03-21": "Idul Fitri",
+	"2026-04-03": "Wafat Yesus Kristus",
+	"2026-04-05": "Kebangkitan Yesus Kristus (Paskah)",
+	"2026-05-01": "Hari Buruh Internasional",
+	"2026-05-14": "Kenaikan Yesus Kristus",
+	"2026-05-27": "Idul Adha",
+	"2026-05-31": "Hari Raya Waisak",
+	"2026-06-01": "Hari Lahir Pancasila",
+	"2026-06-16": "Tahun Baru Islam",
+	"2026-08-17": "Hari Kemerdekaan Republik Indonesia",
+	"2026-08-25": "Maulid Nabi Muhammad SAW",
+	"2026-12-25": "Hari Raya Natal",
+}
+
+// Calendar is the built-in holiday list extended with configured dates.
+type Calendar struct {
+	holidays map[string]string
+}
+
+// New returns a calendar that also treats each of extra, given as
+// YYYY-MM-DD, as a public holiday.
+func New(extra []string) (*Calendar, error) {
+	c := &Calendar{holidays: make(map[string]string, len(holidays)+len(extra))}
+	for date, name := range holidays {
+		c.holidays[date] = name
+	}
+	for _, date := range extra {
+		if _, err := time.Parse(DateLayout, date); err != nil {
+			return nil, fmt.Errorf("holiday %q: want YYYY-MM-DD", date)
+		}
+		if _, ok := c.holidays[date]; !ok {
+			c.holidays[date] = "Hari libur"
+		}
+	}
+	return c, nil
+}
+
+// Holiday returns the name of the public holiday on t's date in Jakarta, if
+// there is one.
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	name, ok := c.holidays[t.In(Jakarta).Format(DateLayout)]
+	return name, ok
+}
+
+// ServiceDay returns the calendar type of t's date in Jakarta. A holiday
+// that falls on a weekend is a holiday.
+func (c *Calendar) ServiceDay(t time.Time) string {
+	if _, ok := c.Holiday(t); ok {
+		return domain.ServiceDayHoliday
+	}
+	switch t.In(Jakarta).Weekday() {
+	case time.Saturday, time.Sunday:
+		return domain.ServiceDayWeekend
+	}
+	return domain.ServiceDayWeekday
+}
+
+// ParseDate parses a YYYY-MM-DD date as midnight in Jakarta.
+func ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, s, Jakarta)
+}
+
+// FormatDate returns t's date in Jakarta as YYYY-MM-DD.
+func FormatDate(t time.Time) string {
+	return t.In(Jakarta).Format(DateLayout)
+}
+
+// OperatingDate returns the service date t falls in, as midnight in
+// Jakarta. Before dayStartHour the previous day's service is still running,
+// so its date is returned.
+func OperatingDate(t time.Time, dayStartHour int) time.Time {
+	t = t.In(Jakarta).Add(-time.Duration(dayStartHour) * time.Hour)
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Jakarta)
+}
+
+// Timetable returns the schedules of the timetable that runs on serviceDay,
+// or of the closest one they hold when there is none for that kind of day
+// yet, such as at a station not yet synced on a holiday. schedules itself is
+// returned when it holds a single timetable. The input is not modified.
+func Timetable(schedules []domain.Schedule, serviceDay string) []domain.Schedule {
+	seen := make(map[string]bool)
+	var days []string
+	for _, sch := range schedules {
+		if !seen[sch.ServiceDay] {
+			seen[sch.ServiceDay] = true
+			days = append(days, sch.ServiceDay)
+		}
+	}
+	day := Variant(days, serviceDay)
+	if day == "" {
+		return schedules
+	}
+
+	filtered := []domain.Schedule{}
+	for _, sch := range schedules {
+		if sch.ServiceDay == day {
+			filtered = append(filtered, sch)
+		}
+	}
+	return filtered
+}
+
+// Variant returns which of the timetables a station holds, days, Timetable
+// serves on serviceDay. It returns "" when the station holds a single
+// timetable, which is served every day.
+func Variant(days []string, serviceDay string) string {
+	if len(days) < 2 {
+		return ""
+	}
+	for _, day := range domain.ServiceDayFallbacks[serviceDay] {
+		if slices.Contains(days, day) {
+			return day
+		}
+	}
+	return ""
+}
@@ -2,48 +2,1358 @@ package config
 
 import (
 	"flag"
+	"fmt"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/cron"
+	"llm-router/internal/domain"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type Config struct {
-	ListeningPort      int
-	KRLEndpointBaseURL string
-	KAIToken           string
-	Socks5Proxy        string
-	DBPath             string
-	Logger             *zap.Logger
+	ListeningPort      int         `yaml:"port"`
+	KRLEndpointBaseURL string      `yaml:"krl_endpoint_base_url"`
+	KAIToken           string      `yaml:"kai_token"`
+	Socks5Proxy        string      `yaml:"socks5_proxy"`
+	DBPath             string      `yaml:"db_path"`
+	LogLevel           string      `yaml:"log_level"`
+	Logger             *zap.Logger `yaml:"-"`
+
+	// DBDriver selects the database: "sqlite", the file at DBPath, or
+	// "postgres", the database at DBDSN, which several instances can share.
+	DBDriver string `yaml:"db_driver"`
+	DBDSN    string `yaml:"db_dsn"`
+	// DBTimeout bounds each database operation, so a locked or unreachable
+	// database fails requests instead of hanging them.
+	DBTimeout time.Duration `yaml:"db_timeout"`
+	DBPool    DBPoolConfig  `yaml:"db_pool"`
+
+	// GRPCPort serves the commuter.v1 gRPC API for internal consumers on a
+	// second port. Zero disables it.
+	GRPCPort int `yaml:"grpc_port"`
+
+	// LogFormat is "json" or "console"; empty picks console for the debug
+	// level and JSON otherwise. LogFile also writes the log to a file.
+	LogFormat string        `yaml:"log_format"`
+	LogFile   LogFileConfig `yaml:"log_file"`
+
+	// KAIAuth obtains a new KAI token when the upstream rejects the current
+	// one.
+	KAIAuth KAIAuthConfig `yaml:"kai_auth"`
+
+	// BootstrapSnapshotURL is another instance's /api/v1/export/dump. On a
+	// first start with an empty database it is imported before any upstream
+	// sync, so the API has data even before the KAI token works.
+	BootstrapSnapshotURL string `yaml:"bootstrap_snapshot_url"`
+
+	// ConfigFile is the config file that was loaded, if any.
+	ConfigFile string `yaml:"-"`
+
+	// Real-time polling. Disabled when KRLRealtimeEndpoint is empty.
+	KRLRealtimeEndpoint  string        `yaml:"realtime_endpoint"`
+	RealtimePollInterval time.Duration `yaml:"realtime_poll_interval"`
+
+	// Announcements polls the official announcement feed.
+	Announcements AnnouncementsConfig `yaml:"announcements"`
+
+	// Railink syncs the Airport Rail Link from its own upstream.
+	Railink RailinkConfig `yaml:"railink"`
+
+	// LocalTrains syncs KAI local and regional trains from their own
+	// upstream.
+	LocalTrains LocalTrainsConfig `yaml:"local_trains"`
+
+	// SyncTime is when the daily full sync runs, in Jakarta time. It is
+	// ignored when SyncCron is set.
+	SyncTime ClockTime `yaml:"sync_time"`
+
+	// SyncCron lists cron expressions, in Jakarta time, for full syncs; a
+	// sync runs whenever any of them fires.
+	SyncCron []string `yaml:"sync_cron"`
+
+	// StationSupplements is a YAML or JSON file listing stations the
+	// upstream station list leaves out, added to it on every sync. Empty
+	// uses the built-in list of the airport and local line stations.
+	StationSupplements string `yaml:"station_supplements"`
+
+	// Holidays adds public holidays, as YYYY-MM-DD, to the built-in calendar,
+	// such as those declared after the release or not yet listed for a year.
+	// Syncs on these days are stored as the holiday timetable.
+	Holidays []string `yaml:"holidays"`
+
+	// SyncLatencyBudget is the p90 upstream latency a sync tolerates before
+	// slowing down (and, well beyond it, aborting). Zero disables the budget.
+	SyncLatencyBudget time.Duration `yaml:"sync_latency_budget"`
+
+	// ScheduleTimeWindows are the timefrom/timeto ranges requested per station
+	// during a schedule sync; results from all windows are merged.
+	ScheduleTimeWindows []TimeWindow `yaml:"schedule_time_windows"`
+
+	// Scrape limits the load syncs put on the upstream.
+	Scrape ScrapeConfig `yaml:"scrape"`
+
+	// ShutdownTimeout bounds a graceful shutdown: draining requests and
+	// waiting for a running sync.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// TLS serves HTTPS directly instead of plain HTTP.
+	TLS TLSConfig `yaml:"tls"`
+
+	CORS      CORSConfig      `yaml:"cors"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	Compression CompressionConfig `yaml:"compression"`
+	HTTPCache   HTTPCacheConfig   `yaml:"http_cache"`
+	AccessLog   AccessLogConfig   `yaml:"access_log"`
+
+	// WebDir serves the frontend from a directory on disk even when a build
+	// is embedded in the binary, for development. Empty serves the embedded
+	// build, or web/dist when there is none.
+	WebDir string `yaml:"web_dir"`
+
+	// Maintenance is the maintenance mode the server starts in; admins can
+	// toggle it at runtime.
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+
+	// AdminToken guards the /api/admin namespace. Admin endpoints are
+	// disabled when it is empty. Once rotated through the admin API, the
+	// rotated token is required instead until this setting changes.
+	AdminToken string `yaml:"admin_token"`
+
+	// CommunityEnabled accepts user-submitted station photos and amenity
+	// corrections into a moderation queue. It is the default of the
+	// community feature flag, which admins can override at runtime.
+	CommunityEnabled bool `yaml:"community_enabled"`
+
+	// Accounts lets users sign in to save favorite stations and commutes.
+	Accounts AccountsConfig `yaml:"accounts"`
+
+	Geocoder GeocoderConfig `yaml:"geocoder"`
+
+	// CacheWarmTopN is how many of the most requested stations and lines
+	// have their caches rebuilt right after a sync. Zero disables warming.
+	CacheWarmTopN int `yaml:"cache_warm_top_n"`
+
+	// SyncRunRetention is how many sync runs keep their schedule snapshot
+	// for the changes API.
+	SyncRunRetention int `yaml:"sync_run_retention"`
+
+	// ServiceDates controls how schedules are kept and read by date.
+	ServiceDates ServiceDatesConfig `yaml:"service_dates"`
+
+	// MetricsInterval is how often cache, database and runtime metrics are
+	// snapshotted to the store; zero disables snapshots. Snapshots older than
+	// MetricsRetention are pruned.
+	MetricsInterval  time.Duration `yaml:"metrics_interval"`
+	MetricsRetention time.Duration `yaml:"metrics_retention"`
+
+	// ReadyMaxDataAge is how old the newest schedule may be before
+	// /health/ready reports the instance not ready; zero disables the check.
+	ReadyMaxDataAge time.Duration `yaml:"ready_max_data_age"`
+
+	Backup    BackupConfig    `yaml:"backup"`
+	Retention RetentionConfig `yaml:"retention"`
+	Notify    NotifyConfig    `yaml:"notify"`
+
+	// License and Attribution are added to every response envelope and export
+	// so mirrors can meet the data source's attribution terms. Either is
+	// omitted when empty.
+	License     string `yaml:"license"`
+	Attribution string `yaml:"attribution"`
+
+	// ScheduleParser names the parser that converts upstream schedules.
+	ScheduleParser string       `yaml:"schedule_parser"`
+	Shadow         ShadowConfig `yaml:"shadow"`
+
+	// Capture records raw upstream responses, and replays them, for
+	// developing parsers against real payloads.
+	Capture CaptureConfig `yaml:"capture"`
 }
 
-func LoadConfig() (*Config, error) {
-	_ = godotenv.Load()
+// KAIAuthConfig describes how to obtain a KAI token: TokenURL is POSTed, with
+// Username and Password as a JSON body when set, and the token is read from
+// the JSON response at TokenField, a dotted path such as "data.token".
+type KAIAuthConfig struct {
+	TokenURL   string `yaml:"token_url"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	TokenField string `yaml:"token_field"`
+}
+
+// Enabled reports whether the token can be refreshed.
+func (c KAIAuthConfig) Enabled() bool {
+	return c.TokenURL != ""
+}
+
+// ShadowConfig runs a second parser and/or upstream endpoint alongside every
+// schedule sync and reports how its output differs, without writing it. It is
+// used to validate parser or provider changes on a live instance.
+type ShadowConfig struct {
+	// Parser defaults to the active ScheduleParser, to compare endpoints only.
+	Parser string `yaml:"parser"`
+	// EndpointBaseURL defaults to the records already fetched from
+	// KRLEndpointBaseURL, to compare parsers only.
+	EndpointBaseURL string `yaml:"endpoint_base_url"`
+}
+
+// CaptureConfig stores every upstream response, compressed with its URL and
+// time, keeping the newest Keep per URL. In Replay mode syncs read the newest
+// capture of each URL instead of calling the upstream, so a parser change can
+// be tried on real payloads offline.
+type CaptureConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Replay  bool `yaml:"replay"`
+	Keep    int  `yaml:"keep"`
+}
+
+// Enabled reports whether a shadow comparison is configured.
+func (c ShadowConfig) Enabled() bool {
+	return c.Parser != "" || c.EndpointBaseURL != ""
+}
+
+// ScrapeConfig keeps syncs polite toward the upstream. Concurrency stations
+// are fetched at once, each request waits RequestDelay first, and all
+// requests together stay under RequestsPerMinute (unlimited when zero). A 429
+// answer pauses every request for its Retry-After, or ThrottleBackoff
+// doubling on each further 429, before the request is retried.
+type ScrapeConfig struct {
+	Concurrency       int           `yaml:"concurrency"`
+	RequestDelay      time.Duration `yaml:"request_delay"`
+	RequestsPerMinute int           `yaml:"requests_per_minute"`
+	ThrottleBackoff   time.Duration `yaml:"throttle_backoff"`
+}
+
+// RateLimitConfig limits API requests per client. It is disabled when
+// RequestsPerMinute is zero.
+type RateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	// Burst is how many requests a client may make at once; it defaults to
+	// RequestsPerMinute.
+	Burst int `yaml:"burst"`
+	// TrustForwardedFor identifies clients by X-Forwarded-For, for instances
+	// behind a reverse proxy.
+	TrustForwardedFor bool `yaml:"trust_forwarded_for"`
+}
+
+// CompressionConfig compresses responses for clients that accept it.
+type CompressionConfig struct {
+	// Gzip enables gzip; Brotli enables brotli, which is preferred when the
+	// client accepts both.
+	Gzip   bool `yaml:"gzip"`
+	Brotli bool `yaml:"brotli"`
+	// MinBytes is the smallest response worth compressing.
+	MinBytes int `yaml:"min_bytes"`
+}
+
+// HTTPCacheConfig lets shared caches such as a CDN keep read responses,
+// which are tagged with surrogate keys so that a sync purges only what it
+// changed.
+type HTTPCacheConfig struct {
+	// MaxAge is how long shared caches may serve a response before
+	// revalidating it. Zero sends no caching headers.
+	MaxAge time.Duration `yaml:"max_age"`
+	// StaleWhileRevalidate is how long past MaxAge a cache may keep serving
+	// a response while it revalidates it.
+	StaleWhileRevalidate time.Duration `yaml:"stale_while_revalidate"`
+	// PurgeURL is sent the surrogate keys to purge after each sync that
+	// changes the timetables and each change to the announcements, as a
+	// POST of {"surrogate_keys": [...]}, with PurgeHeaders added. Empty
+	// purges nothing.
+	PurgeURL     string            `yaml:"purge_url"`
+	PurgeHeaders map[string]string `yaml:"purge_headers"`
+}
+
+// DBPoolConfig sizes the database connection pool. Zero keeps database/sql's
+// defaults: no limit on open connections, two idle ones kept, and no
+// maximum lifetime.
+type DBPoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
 
-	port := 8873
-	endpoint := os.Getenv("KRL_ENDPOINT_BASE_URL")
-	if endpoint == "" {
-		endpoint = "https://api-partner.krl.co.id/krl-webs/v1"
+// LogFileConfig also writes the log to a file, rotated by size. It is
+// disabled when Path is empty.
+type LogFileConfig struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+	MaxAgeDays int    `yaml:"max_age_days"`
+	Compress   bool   `yaml:"compress"`
+}
+
+// TLSConfig serves HTTPS with either a certificate and key from disk or, in
+// autocert mode, certificates obtained from Let's Encrypt for Domains.
+type TLSConfig struct {
+	CertFile string         `yaml:"cert_file"`
+	KeyFile  string         `yaml:"key_file"`
+	Autocert AutocertConfig `yaml:"autocert"`
+}
+
+// AutocertConfig obtains and renews certificates automatically. Let's Encrypt
+// validates the domains over plain HTTP on HTTPPort, which must be reachable
+// as port 80; it also redirects other requests to HTTPS.
+type AutocertConfig struct {
+	Domains  []string `yaml:"domains"`
+	Email    string   `yaml:"email"`
+	CacheDir string   `yaml:"cache_dir"`
+	HTTPPort int      `yaml:"http_port"`
+}
+
+// Enabled reports whether HTTPS is served.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.Autocert.Enabled()
+}
+
+// Enabled reports whether certificates are obtained automatically.
+func (c AutocertConfig) Enabled() bool {
+	return len(c.Domains) > 0
+}
+
+// AccessLogConfig logs one line per API request.
+type AccessLogConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SampleRates logs only a fraction (0 to 1) of the successful requests
+	// whose path starts with a key, for high-traffic routes; the longest
+	// matching key wins. Failed requests are always logged.
+	SampleRates map[string]float64 `yaml:"sample_rates"`
+}
+
+// SampleRate returns the fraction of successful requests to path to log.
+func (c AccessLogConfig) SampleRate(path string) float64 {
+	rate, longest := 1.0, -1
+	for prefix, r := range c.SampleRates {
+		if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+			rate, longest = r, len(prefix)
+		}
 	}
+	return rate
+}
+
+// MaintenanceConfig puts the API into maintenance mode for planned
+// migrations. Mutating endpoints answer 503 with Retry-After; read endpoints
+// keep serving cached data when ServeReads is set.
+type MaintenanceConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Banner is shown to clients through /api/v1/config.
+	Banner     string        `yaml:"banner"`
+	RetryAfter time.Duration `yaml:"retry_after"`
+	ServeReads bool          `yaml:"serve_reads"`
+}
+
+// BackupConfig schedules snapshots of the database into Dir, keeping the
+// newest Keep of them. Cron expressions are in Jakarta time. Scheduled
+// backups are disabled when Dir is empty; POST /api/admin/backup works
+// either way.
+type BackupConfig struct {
+	Dir  string   `yaml:"dir"`
+	Cron []string `yaml:"cron"`
+	Keep int      `yaml:"keep"`
+}
+
+// Enabled reports whether scheduled backups are configured.
+func (b BackupConfig) Enabled() bool {
+	return b.Dir != ""
+}
 
-	token := os.Getenv("KAI_TOKEN")
-	proxy := os.Getenv("SOCKS5_PROXY")
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "comuline.db"
+// Schedule returns when scheduled backups run.
+func (b BackupConfig) Schedule() (cron.Schedule, error) {
+	return cron.ParseSchedule(b.Cron)
+}
+
+// RetentionConfig schedules a maintenance job that deletes raw captures
+// older than RawFetches and announcements that ended more than
+// Announcements ago, trims sync runs and schedule dates to their own
+// retention, and then optimizes the database. Cron expressions are in
+// Jakarta time; the job is not scheduled when Cron is empty, and POST
+// /api/admin/retention runs it either way.
+type RetentionConfig struct {
+	Cron          []string      `yaml:"cron"`
+	RawFetches    time.Duration `yaml:"raw_fetches"`
+	Announcements time.Duration `yaml:"announcements"`
+}
+
+// Schedule returns when the maintenance job runs.
+func (r RetentionConfig) Schedule() (cron.Schedule, error) {
+	return cron.ParseSchedule(r.Cron)
+}
+
+// ServiceDatesConfig keeps the timetable each station ran for HistoryDays
+// service dates, today included, so past dates can be queried. Requests
+// without a date before DayStartHour, in Jakarta, read the previous day,
+// whose last trains are still running.
+type ServiceDatesConfig struct {
+	HistoryDays  int `yaml:"history_days"`
+	DayStartHour int `yaml:"day_start_hour"`
+}
+
+// AnnouncementsConfig polls the official service announcement feed, JSON or
+// RSS, every PollInterval into the announcements. Entries that leave the
+// feed are ended. Polling is disabled when Endpoint is empty.
+type AnnouncementsConfig struct {
+	Endpoint     string        `yaml:"endpoint"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+// RailinkConfig syncs the Soekarno-Hatta Airport Rail Link, whose timetable
+// has its own upstream, with every full sync. It is disabled when Endpoint
+// is empty.
+type RailinkConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	// Line is the line its trips are listed under, and Color its color.
+	Line  string `yaml:"line"`
+	Color string `yaml:"color"`
+	// Stations are served by the Rail Link alone, so their KRL timetable
+	// is not fetched.
+	Stations []string `yaml:"stations"`
+	// DefaultFare is the fare in rupiah between two stops that Fares does
+	// not list.
+	DefaultFare int           `yaml:"default_fare"`
+	Fares       []RailinkFare `yaml:"fares"`
+}
+
+// RailinkFare is the fare in rupiah between two Rail Link stops, either
+// way.
+type RailinkFare struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+	Fare int    `yaml:"fare"`
+}
+
+// Fare returns the fare in rupiah between stops from and to.
+func (c RailinkConfig) Fare(from, to string) int {
+	for _, f := range c.Fares {
+		if (f.From == from && f.To == to) || (f.From == to && f.To == from) {
+			return f.Fare
+		}
+	}
+	return c.DefaultFare
+}
+
+// LocalTrainsConfig syncs the KAI local and regional trains that call at
+// stations of the commuter network, such as those to Merak beyond
+// Rangkasbitung, with every full sync. It is disabled when Endpoint is empty.
+type LocalTrainsConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	// Line is the line trips are listed under when the upstream does not
+	// name one, and Color the color of every local line.
+	Line  string `yaml:"line"`
+	Color string `yaml:"color"`
+	// Stations are served by local trains alone, so their KRL timetable is
+	// not fetched.
+	Stations []string `yaml:"stations"`
+}
+
+// NotifyConfig posts sync results and data anomalies to webhooks. A failed
+// delivery is retried up to Retries times with exponential backoff; each
+// attempt times out after Timeout.
+type NotifyConfig struct {
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+	Retries  int             `yaml:"retries"`
+	Timeout  time.Duration   `yaml:"timeout"`
+}
+
+// WebhookConfig is one webhook. Format is "json" for a generic JSON body,
+// "slack" or "discord" for those services' incoming webhooks. JSON bodies are
+// signed when Secret is set. Events limits the webhook to some of the
+// domain.NotifyEvents; empty means all of them.
+type WebhookConfig struct {
+	URL    string   `yaml:"url"`
+	Format string   `yaml:"format"`
+	Secret string   `yaml:"secret"`
+	Events []string `yaml:"events"`
+}
+
+// Webhook formats.
+const (
+	WebhookJSON    = "json"
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+)
+
+// Wants reports whether the webhook is sent event.
+func (w WebhookConfig) Wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+func (w WebhookConfig) validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q: must be an absolute http(s) URL", w.URL)
+	}
+	switch w.Format {
+	case WebhookJSON, WebhookSlack, WebhookDiscord:
+	default:
+		return fmt.Errorf("invalid webhook format %q: must be json, slack or discord", w.Format)
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(domain.NotifyEvents, event) {
+			return fmt.Errorf("invalid webhook event %q: must be one of %s", event, strings.Join(domain.NotifyEvents, ", "))
+		}
 	}
+	return nil
+}
+
+// AccountsConfig enables user accounts. Users sign in with a link emailed to
+// them, valid for LinkTTL, which points at BaseURL and opens a session that
+// lasts SessionTTL. Without an SMTP host the links are logged instead of
+// sent, for development.
+type AccountsConfig struct {
+	Enabled    bool          `yaml:"enabled"`
+	BaseURL    string        `yaml:"base_url"`
+	LinkTTL    time.Duration `yaml:"link_ttl"`
+	SessionTTL time.Duration `yaml:"session_ttl"`
+	SMTP       SMTPConfig    `yaml:"smtp"`
+	Push       PushConfig    `yaml:"push"`
+}
+
+// SMTPConfig is the mail server sign-in links are sent through.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// PushConfig enables Web Push notifications for saved commutes: a reminder
+// before the next train of a commute and an alert when a sync changes its
+// trains. VAPIDPrivateKey is the base64url P-256 key identifying the server
+// to push services and Subject a mailto: or https: contact for them. Commutes
+// are checked every Interval and undelivered messages expire after TTL.
+type PushConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	VAPIDPrivateKey string        `yaml:"vapid_private_key"`
+	Subject         string        `yaml:"subject"`
+	Interval        time.Duration `yaml:"interval"`
+	TTL             time.Duration `yaml:"ttl"`
+}
+
+// GeocoderConfig selects the provider used to resolve addresses for journey
+// planning. Geocoding is disabled when Provider is empty.
+type GeocoderConfig struct {
+	Provider     string `yaml:"provider"`
+	URL          string `yaml:"url"`
+	UserAgent    string `yaml:"user_agent"`
+	CountryCodes string `yaml:"country_codes"`
+}
 
+// CORSConfig is the cross-origin policy applied to every response.
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins, "*" for any origin, or wildcard
+	// subdomains such as "https://*.example.com".
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// AllowsOrigin reports whether origin may access the API.
+func (c CORSConfig) AllowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAnyOrigin reports whether the policy is a plain wildcard.
+func (c CORSConfig) AllowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func envBool(key string, def bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def, fmt.Errorf("invalid %s %q: %w", key, v, err)
+	}
+	return b, nil
+}
+
+func envList(key string, def []string) []string {
+	if v := os.Getenv(key); v != "" {
+		return splitList(v)
+	}
+	return def
+}
+
+// envInt overrides *dst with the integer in key, if set, rejecting values
+// below min.
+func envInt(key string, dst *int, min int, what string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		return fmt.Errorf("invalid %s %q: must be %s", key, v, what)
+	}
+	*dst = n
+	return nil
+}
+
+// envDuration overrides *dst with the whole number of units in key, if set,
+// rejecting values below min. Unset, *dst keeps its value even when it is
+// not a whole number of units, as durations from the file need not be.
+func envDuration(key string, dst *time.Duration, unit time.Duration, min int, what string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		return fmt.Errorf("invalid %s %q: must be %s", key, v, what)
+	}
+	*dst = time.Duration(n) * unit
+	return nil
+}
+
+// envString overrides *dst with key, if set.
+func envString(key string, dst *string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func applyTLSEnv(t *TLSConfig) error {
+	envString("TLS_CERT", &t.CertFile)
+	envString("TLS_KEY", &t.KeyFile)
+	t.Autocert.Domains = envList("TLS_AUTOCERT_DOMAINS", t.Autocert.Domains)
+	envString("TLS_AUTOCERT_EMAIL", &t.Autocert.Email)
+	envString("TLS_AUTOCERT_CACHE_DIR", &t.Autocert.CacheDir)
+	return envInt("TLS_HTTP_PORT", &t.Autocert.HTTPPort, 1, "a port number")
+}
+
+func applyDBPoolEnv(p *DBPoolConfig) error {
+	if err := envInt("DB_MAX_OPEN_CONNS", &p.MaxOpenConns, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	if err := envInt("DB_MAX_IDLE_CONNS", &p.MaxIdleConns, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	return envDuration("DB_CONN_MAX_LIFETIME", &p.ConnMaxLifetime, time.Second, 0, "a non-negative number of seconds")
+}
+
+func applyScrapeEnv(c *ScrapeConfig) error {
+	if err := envInt("SCRAPE_CONCURRENCY", &c.Concurrency, 1, "a positive number"); err != nil {
+		return err
+	}
+	if err := envDuration("SCRAPE_REQUEST_DELAY_MS", &c.RequestDelay, time.Millisecond, 0, "a non-negative number of milliseconds"); err != nil {
+		return err
+	}
+	if err := envInt("SCRAPE_RPM", &c.RequestsPerMinute, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	return envDuration("SCRAPE_THROTTLE_BACKOFF", &c.ThrottleBackoff, time.Second, 1, "a positive number of seconds")
+}
+
+func applyLogFileEnv(f *LogFileConfig) error {
+	envString("LOG_FILE", &f.Path)
+	if err := envInt("LOG_FILE_MAX_SIZE_MB", &f.MaxSizeMB, 1, "a positive number"); err != nil {
+		return err
+	}
+	if err := envInt("LOG_FILE_MAX_BACKUPS", &f.MaxBackups, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	if err := envInt("LOG_FILE_MAX_AGE_DAYS", &f.MaxAgeDays, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	compress, err := envBool("LOG_FILE_COMPRESS", f.Compress)
+	if err != nil {
+		return err
+	}
+	f.Compress = compress
+	return nil
+}
+
+// applyAccessLogEnv reads ACCESS_LOG_SAMPLE_RATES as comma-separated
+// prefix=rate pairs, such as "/api/v1/schedule/=0.1,/api/v1/next=0.5".
+func applyAccessLogEnv(a *AccessLogConfig) error {
+	enabled, err := envBool("ACCESS_LOG_ENABLED", a.Enabled)
+	if err != nil {
+		return err
+	}
+	a.Enabled = enabled
+	if v := os.Getenv("ACCESS_LOG_SAMPLE_RATES"); v != "" {
+		a.SampleRates = make(map[string]float64)
+		for _, pair := range strings.Split(v, ",") {
+			prefix, raw, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			rate, err := strconv.ParseFloat(raw, 64)
+			if !ok || err != nil {
+				return fmt.Errorf("invalid ACCESS_LOG_SAMPLE_RATES entry %q: expected prefix=rate", pair)
+			}
+			a.SampleRates[prefix] = rate
+		}
+	}
+	return nil
+}
+
+func applyBackupEnv(b *BackupConfig) error {
+	envString("BACKUP_DIR", &b.Dir)
+	if v := os.Getenv("BACKUP_CRON"); v != "" {
+		b.Cron = nil
+		for _, expr := range strings.Split(v, ";") {
+			if expr = strings.TrimSpace(expr); expr != "" {
+				b.Cron = append(b.Cron, expr)
+			}
+		}
+	}
+	return envInt("BACKUP_KEEP", &b.Keep, 1, "a positive number")
+}
+
+func applyRetentionEnv(r *RetentionConfig) error {
+	if v, ok := os.LookupEnv("RETENTION_CRON"); ok {
+		r.Cron = nil
+		for _, expr := range strings.Split(v, ";") {
+			if expr = strings.TrimSpace(expr); expr != "" {
+				r.Cron = append(r.Cron, expr)
+			}
+		}
+	}
+	if err := envDuration("RETENTION_RAW_FETCH_DAYS", &r.RawFetches, 24*time.Hour, 1, "a positive number of days"); err != nil {
+		return err
+	}
+	return envDuration("RETENTION_ANNOUNCEMENT_DAYS", &r.Announcements, 24*time.Hour, 1, "a positive number of days")
+}
+
+// applyNotifyEnv reads a single webhook from NOTIFY_WEBHOOK_URL and its
+// companions, replacing any configured in the file.
+func applyNotifyEnv(n *NotifyConfig) error {
+	if v := os.Getenv("NOTIFY_WEBHOOK_URL"); v != "" {
+		w := WebhookConfig{URL: v, Format: WebhookJSON}
+		envString("NOTIFY_WEBHOOK_FORMAT", &w.Format)
+		envString("NOTIFY_WEBHOOK_SECRET", &w.Secret)
+		w.Events = envList("NOTIFY_WEBHOOK_EVENTS", nil)
+		n.Webhooks = []WebhookConfig{w}
+	}
+	if err := envInt("NOTIFY_RETRIES", &n.Retries, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	return envDuration("NOTIFY_TIMEOUT", &n.Timeout, time.Second, 1, "a positive number of seconds")
+}
+
+func applyMaintenanceEnv(m *MaintenanceConfig) error {
+	enabled, err := envBool("MAINTENANCE_ENABLED", m.Enabled)
+	if err != nil {
+		return err
+	}
+	m.Enabled = enabled
+	envString("MAINTENANCE_BANNER", &m.Banner)
+	if err := envDuration("MAINTENANCE_RETRY_AFTER", &m.RetryAfter, time.Second, 1, "a positive number of seconds"); err != nil {
+		return err
+	}
+	serveReads, err := envBool("MAINTENANCE_SERVE_READS", m.ServeReads)
+	if err != nil {
+		return err
+	}
+	m.ServeReads = serveReads
+	return nil
+}
+
+func applyCORSEnv(cors *CORSConfig) error {
+	cors.AllowedOrigins = envList("CORS_ALLOWED_ORIGINS", cors.AllowedOrigins)
+	cors.AllowedMethods = envList("CORS_ALLOWED_METHODS", cors.AllowedMethods)
+	cors.AllowedHeaders = envList("CORS_ALLOWED_HEADERS", cors.AllowedHeaders)
+
+	credentials, err := envBool("CORS_ALLOW_CREDENTIALS", cors.AllowCredentials)
+	if err != nil {
+		return err
+	}
+	cors.AllowCredentials = credentials
+
+	return envDuration("CORS_MAX_AGE", &cors.MaxAge, time.Second, 0, "a non-negative number of seconds")
+}
+
+// TimeWindow is an inclusive HH:MM range of the service day.
+type TimeWindow struct {
+	From string
+	To   string
+}
+
+func (w TimeWindow) String() string {
+	return w.From + "-" + w.To
+}
+
+// ParseTimeWindows parses a comma separated list such as "00:00-11:59,12:00-23:59".
+func ParseTimeWindows(raw string) ([]TimeWindow, error) {
+	var windows []TimeWindow
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(part, "-")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok {
+			return nil, fmt.Errorf("time window %q: expected FROM-TO", part)
+		}
+		fromT, err := time.Parse("15:04", from)
+		if err != nil {
+			return nil, fmt.Errorf("time window %q: invalid start %q", part, from)
+		}
+		toT, err := time.Parse("15:04", to)
+		if err != nil {
+			return nil, fmt.Errorf("time window %q: invalid end %q", part, to)
+		}
+		if toT.Before(fromT) {
+			return nil, fmt.Errorf("time window %q: end is before start", part)
+		}
+
+		windows = append(windows, TimeWindow{From: from, To: to})
+	}
+
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no time windows in %q", raw)
+	}
+	return windows, nil
+}
+
+// defaultConfig is the configuration used for anything not set in the
+// config file, the environment or flags.
+func defaultConfig() *Config {
 	return &Config{
-		ListeningPort:      port,
-		KRLEndpointBaseURL: endpoint,
-		KAIToken:           token,
-		Socks5Proxy:        proxy,
-		DBPath:             dbPath,
-	}, nil
+		ListeningPort:        8873,
+		KRLEndpointBaseURL:   "https://api-partner.krl.co.id/krl-webs/v1",
+		DBPath:               "comuline.db",
+		DBDriver:             "sqlite",
+		DBTimeout:            10 * time.Second,
+		LogLevel:             "info",
+		RealtimePollInterval: 30 * time.Second,
+		SyncTime:             ClockTime{Hour: 5},
+		// The upstream window is inclusive, so 23:59 is needed to include the last hour.
+		ScheduleTimeWindows: []TimeWindow{{From: "00:00", To: "23:59"}},
+		Scrape:              ScrapeConfig{Concurrency: 50, ThrottleBackoff: 30 * time.Second},
+		Capture:             CaptureConfig{Keep: 3},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "Accept"},
+			MaxAge:         24 * time.Hour,
+		},
+		Geocoder: GeocoderConfig{
+			URL:          "https://nominatim.openstreetmap.org",
+			UserAgent:    "comuline-api",
+			CountryCodes: "id",
+		},
+		Accounts: AccountsConfig{
+			LinkTTL:    15 * time.Minute,
+			SessionTTL: 30 * 24 * time.Hour,
+			SMTP:       SMTPConfig{Port: 587},
+			Push:       PushConfig{Interval: time.Minute, TTL: 30 * time.Minute},
+		},
+		Compression:      CompressionConfig{Gzip: true, MinBytes: 1024},
+		HTTPCache:        HTTPCacheConfig{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute},
+		KAIAuth:          KAIAuthConfig{TokenField: "token"},
+		Maintenance:      MaintenanceConfig{RetryAfter: 10 * time.Minute, ServeReads: true},
+		AccessLog:        AccessLogConfig{Enabled: true},
+		LogFile:          LogFileConfig{MaxSizeMB: 100, MaxBackups: 5, MaxAgeDays: 30},
+		ShutdownTimeout:  30 * time.Second,
+		TLS:              TLSConfig{Autocert: AutocertConfig{CacheDir: "certs", HTTPPort: 80}},
+		CacheWarmTopN:    20,
+		SyncRunRetention: 30,
+		ServiceDates:     ServiceDatesConfig{HistoryDays: 7, DayStartHour: 3},
+		MetricsInterval:  5 * time.Minute,
+		MetricsRetention: 7 * 24 * time.Hour,
+		ReadyMaxDataAge:  48 * time.Hour,
+		Backup:           BackupConfig{Cron: []string{"30 3 * * *"}, Keep: 7},
+		Retention:        RetentionConfig{Cron: []string{"0 4 * * *"}, RawFetches: 14 * 24 * time.Hour, Announcements: 30 * 24 * time.Hour},
+		Notify:           NotifyConfig{Retries: 3, Timeout: 10 * time.Second},
+		Announcements:    AnnouncementsConfig{PollInterval: 10 * time.Minute},
+		Railink:          RailinkConfig{Line: "AIRPORT RAILINK", Color: "#00A3E0", Stations: []string{"BST"}, DefaultFare: 70000},
+		LocalTrains:      LocalTrainsConfig{Line: "KA LOKAL", Color: "#7B4F2C", Stations: []string{"CKP", "PWK"}},
+		ScheduleParser:   "v1",
+	}
+}
+
+// LoadConfig builds the configuration from, in increasing precedence, the
+// defaults, the config file, environment variables (including .env) and
+// command-line flags.
+func LoadConfig(flags Flags) (*Config, error) {
+	_ = godotenv.Load()
+
+	cfg := defaultConfig()
+
+	path, required := flags.ConfigPath, flags.ConfigPath != ""
+	if path == "" {
+		path = os.Getenv("COMMUTER_CONFIG")
+		required = path != ""
+	}
+	if path == "" {
+		path = DefaultConfigFile
+	}
+	if err := cfg.loadFile(path, required); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	flags.apply(cfg)
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the configuration with any environment variables set.
+func (cfg *Config) applyEnv() error {
+	if err := envInt("PORT", &cfg.ListeningPort, 1, "a port number"); err != nil {
+		return err
+	}
+	if err := envInt("GRPC_PORT", &cfg.GRPCPort, 0, "a port number"); err != nil {
+		return err
+	}
+	envString("KRL_ENDPOINT_BASE_URL", &cfg.KRLEndpointBaseURL)
+	envString("KAI_TOKEN", &cfg.KAIToken)
+	envString("KAI_TOKEN_URL", &cfg.KAIAuth.TokenURL)
+	envString("KAI_USERNAME", &cfg.KAIAuth.Username)
+	envString("KAI_PASSWORD", &cfg.KAIAuth.Password)
+	envString("KAI_TOKEN_FIELD", &cfg.KAIAuth.TokenField)
+	envString("BOOTSTRAP_SNAPSHOT_URL", &cfg.BootstrapSnapshotURL)
+	envString("SOCKS5_PROXY", &cfg.Socks5Proxy)
+	envString("DB_PATH", &cfg.DBPath)
+	envString("DB_DRIVER", &cfg.DBDriver)
+	envString("DB_DSN", &cfg.DBDSN)
+	if err := envDuration("DB_TIMEOUT", &cfg.DBTimeout, time.Second, 1, "a positive number of seconds"); err != nil {
+		return err
+	}
+	if err := applyDBPoolEnv(&cfg.DBPool); err != nil {
+		return err
+	}
+	envString("LOG_LEVEL", &cfg.LogLevel)
+	envString("LOG_FORMAT", &cfg.LogFormat)
+	if err := applyLogFileEnv(&cfg.LogFile); err != nil {
+		return err
+	}
+
+	envString("KRL_REALTIME_ENDPOINT", &cfg.KRLRealtimeEndpoint)
+	if err := envDuration("REALTIME_POLL_INTERVAL", &cfg.RealtimePollInterval, time.Second, 1, "a positive number of seconds"); err != nil {
+		return err
+	}
+	envString("ANNOUNCEMENTS_ENDPOINT", &cfg.Announcements.Endpoint)
+	envString("RAILINK_ENDPOINT", &cfg.Railink.Endpoint)
+	envString("LOCAL_TRAINS_ENDPOINT", &cfg.LocalTrains.Endpoint)
+	if err := envDuration("ANNOUNCEMENTS_POLL_INTERVAL", &cfg.Announcements.PollInterval, time.Minute, 1, "a positive number of minutes"); err != nil {
+		return err
+	}
+
+	if v := os.Getenv("SYNC_TIME"); v != "" {
+		t, err := ParseClockTime(v)
+		if err != nil {
+			return fmt.Errorf("invalid SYNC_TIME: %w", err)
+		}
+		cfg.SyncTime = t
+	}
+	envString("STATION_SUPPLEMENTS", &cfg.StationSupplements)
+	cfg.Holidays = envList("HOLIDAYS", cfg.Holidays)
+	// Cron expressions may contain commas, so entries are separated by ";".
+	if v := os.Getenv("SYNC_CRON"); v != "" {
+		cfg.SyncCron = nil
+		for _, expr := range strings.Split(v, ";") {
+			if expr = strings.TrimSpace(expr); expr != "" {
+				cfg.SyncCron = append(cfg.SyncCron, expr)
+			}
+		}
+	}
+
+	if err := envDuration("SYNC_LATENCY_BUDGET_MS", &cfg.SyncLatencyBudget, time.Millisecond, 0, "a non-negative number of milliseconds"); err != nil {
+		return err
+	}
+
+	if err := applyScrapeEnv(&cfg.Scrape); err != nil {
+		return err
+	}
+
+	if v := os.Getenv("SCHEDULE_TIME_WINDOWS"); v != "" {
+		windows, err := ParseTimeWindows(v)
+		if err != nil {
+			return fmt.Errorf("invalid SCHEDULE_TIME_WINDOWS: %w", err)
+		}
+		cfg.ScheduleTimeWindows = windows
+	}
+
+	if err := envDuration("SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout, time.Second, 1, "a positive number of seconds"); err != nil {
+		return err
+	}
+
+	if err := applyTLSEnv(&cfg.TLS); err != nil {
+		return err
+	}
+
+	if err := applyCORSEnv(&cfg.CORS); err != nil {
+		return err
+	}
+
+	if err := envInt("RATE_LIMIT_RPM", &cfg.RateLimit.RequestsPerMinute, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	if err := envInt("RATE_LIMIT_BURST", &cfg.RateLimit.Burst, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	trust, err := envBool("RATE_LIMIT_TRUST_FORWARDED_FOR", cfg.RateLimit.TrustForwardedFor)
+	if err != nil {
+		return err
+	}
+	cfg.RateLimit.TrustForwardedFor = trust
+
+	gzipOn, err := envBool("COMPRESSION_GZIP", cfg.Compression.Gzip)
+	if err != nil {
+		return err
+	}
+	cfg.Compression.Gzip = gzipOn
+	brotliOn, err := envBool("COMPRESSION_BROTLI", cfg.Compression.Brotli)
+	if err != nil {
+		return err
+	}
+	cfg.Compression.Brotli = brotliOn
+	if err := envInt("COMPRESSION_MIN_BYTES", &cfg.Compression.MinBytes, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	if err := envDuration("HTTP_CACHE_MAX_AGE", &cfg.HTTPCache.MaxAge, time.Second, 0, "a non-negative number of seconds"); err != nil {
+		return err
+	}
+	envString("HTTP_CACHE_PURGE_URL", &cfg.HTTPCache.PurgeURL)
+
+	if err := applyAccessLogEnv(&cfg.AccessLog); err != nil {
+		return err
+	}
+	if err := applyMaintenanceEnv(&cfg.Maintenance); err != nil {
+		return err
+	}
+
+	envString("ADMIN_TOKEN", &cfg.AdminToken)
+	envString("WEB_DIR", &cfg.WebDir)
+	community, err := envBool("COMMUNITY_ENABLED", cfg.CommunityEnabled)
+	if err != nil {
+		return err
+	}
+	cfg.CommunityEnabled = community
+
+	if err := applyAccountsEnv(&cfg.Accounts); err != nil {
+		return err
+	}
+	envString("GEOCODER_PROVIDER", &cfg.Geocoder.Provider)
+	envString("GEOCODER_URL", &cfg.Geocoder.URL)
+	envString("GEOCODER_USER_AGENT", &cfg.Geocoder.UserAgent)
+	envString("GEOCODER_COUNTRY_CODES", &cfg.Geocoder.CountryCodes)
+
+	if err := envInt("CACHE_WARM_TOP_N", &cfg.CacheWarmTopN, 0, "a non-negative number"); err != nil {
+		return err
+	}
+	if err := envInt("SYNC_RUN_RETENTION", &cfg.SyncRunRetention, 1, "a positive number"); err != nil {
+		return err
+	}
+	if err := envInt("SCHEDULE_HISTORY_DAYS", &cfg.ServiceDates.HistoryDays, 1, "a positive number of days"); err != nil {
+		return err
+	}
+	if err := envInt("SERVICE_DAY_START_HOUR", &cfg.ServiceDates.DayStartHour, 0, "an hour from 0 to 23"); err != nil {
+		return err
+	}
+	if err := envDuration("METRICS_INTERVAL", &cfg.MetricsInterval, time.Second, 0, "a non-negative number of seconds"); err != nil {
+		return err
+	}
+	if err := envDuration("METRICS_RETENTION_HOURS", &cfg.MetricsRetention, time.Hour, 1, "a positive number of hours"); err != nil {
+		return err
+	}
+	if err := envDuration("READY_MAX_DATA_AGE_HOURS", &cfg.ReadyMaxDataAge, time.Hour, 0, "a non-negative number of hours"); err != nil {
+		return err
+	}
+	if err := applyBackupEnv(&cfg.Backup); err != nil {
+		return err
+	}
+	if err := applyRetentionEnv(&cfg.Retention); err != nil {
+		return err
+	}
+	if err := applyNotifyEnv(&cfg.Notify); err != nil {
+		return err
+	}
+
+	envString("DATA_LICENSE", &cfg.License)
+	envString("DATA_ATTRIBUTION", &cfg.Attribution)
+
+	envString("SCHEDULE_PARSER", &cfg.ScheduleParser)
+	envString("SHADOW_PARSER", &cfg.Shadow.Parser)
+	envString("SHADOW_KRL_ENDPOINT_BASE_URL", &cfg.Shadow.EndpointBaseURL)
+	return applyCaptureEnv(&cfg.Capture)
 }
 
-func InitFlags() int {
-	listeningPort := flag.Int("port", 8873, "Listening port")
+func applyAccountsEnv(a *AccountsConfig) error {
+	enabled, err := envBool("ACCOUNTS_ENABLED", a.Enabled)
+	if err != nil {
+		return err
+	}
+	a.Enabled = enabled
+	envString("ACCOUNTS_BASE_URL", &a.BaseURL)
+	envString("SMTP_HOST", &a.SMTP.Host)
+	if err := envInt("SMTP_PORT", &a.SMTP.Port, 1, "a port number"); err != nil {
+		return err
+	}
+	envString("SMTP_USERNAME", &a.SMTP.Username)
+	envString("SMTP_PASSWORD", &a.SMTP.Password)
+	envString("SMTP_FROM", &a.SMTP.From)
+
+	push, err := envBool("PUSH_ENABLED", a.Push.Enabled)
+	if err != nil {
+		return err
+	}
+	a.Push.Enabled = push
+	envString("PUSH_VAPID_PRIVATE_KEY", &a.Push.VAPIDPrivateKey)
+	envString("PUSH_SUBJECT", &a.Push.Subject)
+	return nil
+}
+
+func applyCaptureEnv(c *CaptureConfig) error {
+	enabled, err := envBool("CAPTURE_ENABLED", c.Enabled)
+	if err != nil {
+		return err
+	}
+	c.Enabled = enabled
+	replay, err := envBool("CAPTURE_REPLAY", c.Replay)
+	if err != nil {
+		return err
+	}
+	c.Replay = replay
+	return envInt("CAPTURE_KEEP", &c.Keep, 1, "a positive number")
+}
+
+// validate checks values that may have come from the config file, which is
+// decoded without the per-variable checks applied to the environment.
+func (cfg *Config) validate() error {
+	cfg.Geocoder.Provider = strings.ToLower(cfg.Geocoder.Provider)
+
+	switch {
+	case cfg.ListeningPort < 1 || cfg.ListeningPort > 65535:
+		return fmt.Errorf("invalid port %d", cfg.ListeningPort)
+	case cfg.DBDriver != "sqlite" && cfg.DBDriver != "postgres":
+		return fmt.Errorf("invalid db driver %q: must be sqlite or postgres", cfg.DBDriver)
+	case cfg.DBDriver == "postgres" && cfg.DBDSN == "":
+		return fmt.Errorf("db_dsn is required with the postgres driver")
+	case cfg.DBDriver == "postgres" && cfg.Backup.Enabled():
+		return fmt.Errorf("backup.dir needs the sqlite driver; back up PostgreSQL with its own tools")
+	case cfg.DBTimeout < time.Second:
+		return fmt.Errorf("invalid db timeout %s: must be at least 1s", cfg.DBTimeout)
+	case cfg.DBPool.MaxOpenConns < 0 || cfg.DBPool.MaxIdleConns < 0 || cfg.DBPool.ConnMaxLifetime < 0:
+		return fmt.Errorf("invalid db pool: sizes and lifetime must not be negative")
+	case cfg.GRPCPort < 0 || cfg.GRPCPort > 65535 || (cfg.GRPCPort != 0 && cfg.GRPCPort == cfg.ListeningPort):
+		return fmt.Errorf("invalid grpc port %d: must be a port other than the listening port", cfg.GRPCPort)
+	case cfg.RealtimePollInterval <= 0:
+		return fmt.Errorf("invalid realtime poll interval %s: must be positive", cfg.RealtimePollInterval)
+	case cfg.Announcements.Endpoint != "" && cfg.Announcements.PollInterval < time.Minute:
+		return fmt.Errorf("invalid announcements poll interval %s: must be at least 1m", cfg.Announcements.PollInterval)
+	case cfg.Railink.Endpoint != "" && strings.TrimSpace(cfg.Railink.Line) == "":
+		return fmt.Errorf("railink.line is required with a railink endpoint")
+	case cfg.LocalTrains.Endpoint != "" && strings.TrimSpace(cfg.LocalTrains.Line) == "":
+		return fmt.Errorf("local_trains.line is required with a local trains endpoint")
+	case cfg.Railink.DefaultFare < 0:
+		return fmt.Errorf("invalid railink default fare %d: must not be negative", cfg.Railink.DefaultFare)
+	case cfg.SyncLatencyBudget < 0:
+		return fmt.Errorf("invalid sync latency budget %s: must not be negative", cfg.SyncLatencyBudget)
+	case len(cfg.ScheduleTimeWindows) == 0:
+		return fmt.Errorf("no schedule time windows configured")
+	case cfg.CORS.MaxAge < 0:
+		return fmt.Errorf("invalid CORS max age %s: must not be negative", cfg.CORS.MaxAge)
+	case cfg.ShutdownTimeout < time.Second:
+		return fmt.Errorf("invalid shutdown timeout %s: must be at least 1s", cfg.ShutdownTimeout)
+	case (cfg.TLS.CertFile == "") != (cfg.TLS.KeyFile == ""):
+		return fmt.Errorf("tls.cert_file and tls.key_file must be set together")
+	case cfg.TLS.CertFile != "" && cfg.TLS.Autocert.Enabled():
+		return fmt.Errorf("tls.cert_file and tls.autocert are mutually exclusive")
+	case cfg.TLS.Autocert.Enabled() && cfg.TLS.Autocert.CacheDir == "":
+		return fmt.Errorf("tls.autocert.cache_dir is required")
+	case cfg.TLS.Autocert.Enabled() && (cfg.TLS.Autocert.HTTPPort < 1 || cfg.TLS.Autocert.HTTPPort > 65535 || cfg.TLS.Autocert.HTTPPort == cfg.ListeningPort):
+		return fmt.Errorf("invalid tls.autocert.http_port %d: must be a port other than the listening port", cfg.TLS.Autocert.HTTPPort)
+	case cfg.LogFormat != "" && cfg.LogFormat != "json" && cfg.LogFormat != "console":
+		return fmt.Errorf("invalid log format %q: must be json or console", cfg.LogFormat)
+	case cfg.LogFile.MaxSizeMB < 1 || cfg.LogFile.MaxBackups < 0 || cfg.LogFile.MaxAgeDays < 0:
+		return fmt.Errorf("invalid log file rotation: max_size_mb must be positive and the rest non-negative")
+	case cfg.Maintenance.RetryAfter < time.Second:
+		return fmt.Errorf("invalid maintenance retry_after %s: must be at least 1s", cfg.Maintenance.RetryAfter)
+	case cfg.Compression.MinBytes < 0:
+		return fmt.Errorf("invalid compression min bytes %d: must not be negative", cfg.Compression.MinBytes)
+	case cfg.HTTPCache.MaxAge < 0 || cfg.HTTPCache.StaleWhileRevalidate < 0:
+		return fmt.Errorf("invalid http cache: max_age and stale_while_revalidate must not be negative")
+	case cfg.Accounts.Enabled && cfg.Accounts.BaseURL == "":
+		return fmt.Errorf("accounts.base_url is required for sign-in links")
+	case cfg.Accounts.Enabled && cfg.Accounts.SMTP.Host != "" && cfg.Accounts.SMTP.From == "":
+		return fmt.Errorf("accounts.smtp.from is required with an smtp host")
+	case cfg.Accounts.LinkTTL < time.Minute || cfg.Accounts.SessionTTL < time.Hour:
+		return fmt.Errorf("invalid accounts ttl: link_ttl must be at least 1m and session_ttl at least 1h")
+	case cfg.Accounts.Push.Enabled && !cfg.Accounts.Enabled:
+		return fmt.Errorf("accounts.push requires accounts to be enabled")
+	case cfg.Accounts.Push.Enabled && cfg.Accounts.Push.VAPIDPrivateKey == "":
+		return fmt.Errorf("accounts.push.vapid_private_key is required for push notifications")
+	case cfg.Accounts.Push.Enabled && !strings.HasPrefix(cfg.Accounts.Push.Subject, "mailto:") && !strings.HasPrefix(cfg.Accounts.Push.Subject, "https:"):
+		return fmt.Errorf("invalid accounts.push.subject %q: must be a mailto: or https: URL", cfg.Accounts.Push.Subject)
+	case cfg.Accounts.Push.Interval < 10*time.Second || cfg.Accounts.Push.TTL < time.Minute:
+		return fmt.Errorf("invalid accounts.push timing: interval must be at least 10s and ttl at least 1m")
+	case cfg.Capture.Keep < 1:
+		return fmt.Errorf("invalid capture keep %d: must be positive", cfg.Capture.Keep)
+	case cfg.Scrape.Concurrency < 1:
+		return fmt.Errorf("invalid scrape concurrency %d: must be positive", cfg.Scrape.Concurrency)
+	case cfg.Scrape.RequestDelay < 0 || cfg.Scrape.RequestsPerMinute < 0:
+		return fmt.Errorf("invalid scrape limits: request delay and requests per minute must not be negative")
+	case cfg.Scrape.ThrottleBackoff < time.Second:
+		return fmt.Errorf("invalid scrape throttle backoff %s: must be at least 1s", cfg.Scrape.ThrottleBackoff)
+	case cfg.RateLimit.RequestsPerMinute < 0 || cfg.RateLimit.Burst < 0:
+		return fmt.Errorf("invalid rate limit: values must not be negative")
+	case cfg.CacheWarmTopN < 0:
+		return fmt.Errorf("invalid cache warm top n %d: must not be negative", cfg.CacheWarmTopN)
+	case cfg.SyncRunRetention < 1:
+		return fmt.Errorf("invalid sync run retention %d: must be positive", cfg.SyncRunRetention)
+	case cfg.ServiceDates.HistoryDays < 1:
+		return fmt.Errorf("invalid schedule history of %d days: must be positive", cfg.ServiceDates.HistoryDays)
+	case cfg.ServiceDates.DayStartHour < 0 || cfg.ServiceDates.DayStartHour > 23:
+		return fmt.Errorf("invalid service day start hour %d: must be from 0 to 23", cfg.ServiceDates.DayStartHour)
+	case cfg.KAIAuth.Enabled() && cfg.KAIAuth.TokenField == "":
+		return fmt.Errorf("kai_auth.token_field is required with a token URL")
+	case cfg.MetricsInterval < 0:
+		return fmt.Errorf("invalid metrics interval %s: must not be negative", cfg.MetricsInterval)
+	case cfg.MetricsRetention < time.Hour:
+		return fmt.Errorf("invalid metrics retention %s: must be at least 1h", cfg.MetricsRetention)
+	case cfg.ReadyMaxDataAge < 0:
+		return fmt.Errorf("invalid ready max data age %s: must not be negative", cfg.ReadyMaxDataAge)
+	case cfg.Retention.RawFetches < 24*time.Hour || cfg.Retention.Announcements < 24*time.Hour:
+		return fmt.Errorf("invalid retention: raw_fetches and announcements must be at least 24h")
+	case cfg.Backup.Keep < 1:
+		return fmt.Errorf("invalid backup keep %d: must be positive", cfg.Backup.Keep)
+	case cfg.Backup.Enabled() && len(cfg.Backup.Cron) == 0:
+		return fmt.Errorf("backup.cron is required with backup.dir")
+	case cfg.Notify.Retries < 0:
+		return fmt.Errorf("invalid notify retries %d: must not be negative", cfg.Notify.Retries)
+	case cfg.Notify.Timeout < time.Second:
+		return fmt.Errorf("invalid notify timeout %s: must be at least 1s", cfg.Notify.Timeout)
+	}
+	for i := range cfg.Notify.Webhooks {
+		w := &cfg.Notify.Webhooks[i]
+		if w.Format == "" {
+			w.Format = WebhookJSON
+		}
+		if err := w.validate(); err != nil {
+			return err
+		}
+	}
+	if raw := cfg.HTTPCache.PurgeURL; raw != "" {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid http cache purge url %q: must be an http or https URL", raw)
+		}
+	}
+	if _, err := cfg.SyncSchedule(); err != nil {
+		return fmt.Errorf("invalid sync cron: %w", err)
+	}
+	if _, err := cfg.Backup.Schedule(); err != nil {
+		return fmt.Errorf("invalid backup cron: %w", err)
+	}
+	if _, err := cfg.Retention.Schedule(); err != nil {
+		return fmt.Errorf("invalid retention cron: %w", err)
+	}
+	if _, err := calendar.New(cfg.Holidays); err != nil {
+		return fmt.Errorf("invalid holidays: %w", err)
+	}
+	for _, f := range cfg.Railink.Fares {
+		if f.From == "" || f.To == "" || f.Fare < 0 {
+			return fmt.Errorf("invalid railink fare %s-%s: from and to are required and the fare must not be negative", f.From, f.To)
+		}
+	}
+	if cfg.StationSupplements != "" {
+		if _, err := os.Stat(cfg.StationSupplements); err != nil {
+			return fmt.Errorf("invalid station supplements: %w", err)
+		}
+	}
+	for prefix, rate := range cfg.AccessLog.SampleRates {
+		if !(rate >= 0 && rate <= 1) {
+			return fmt.Errorf("invalid access log sample rate %v for %q: must be between 0 and 1", rate, prefix)
+		}
+	}
+	if _, err := zapcore.ParseLevel(cfg.LogLevel); err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+	if cfg.RateLimit.RequestsPerMinute > 0 && cfg.RateLimit.Burst == 0 {
+		cfg.RateLimit.Burst = cfg.RateLimit.RequestsPerMinute
+	}
+	return nil
+}
+
+// Flags are the command-line options. Unset flags leave the config file and
+// environment values in place.
+type Flags struct {
+	ConfigPath string
+	Port       int
+	DBPath     string
+	LogLevel   string
+}
+
+func InitFlags() Flags {
+	var f Flags
+	flag.StringVar(&f.ConfigPath, "config", "", "Config file (default "+DefaultConfigFile+" if present)")
+	flag.IntVar(&f.Port, "port", 0, "Listening port (default 8873)")
+	flag.StringVar(&f.DBPath, "db", "", "SQLite database path")
+	flag.StringVar(&f.LogLevel, "log-level", "", "Log level: debug, info, warn or error")
 	flag.Parse()
-	return *listeningPort
+	return f
+}
+
+func (f Flags) apply(cfg *Config) {
+	if f.Port != 0 {
+		cfg.ListeningPort = f.Port
+	}
+	if f.DBPath != "" {
+		cfg.DBPath = f.DBPath
+	}
+	if f.LogLevel != "" {
+		cfg.LogLevel = f.LogLevel
+	}
+}
+
+// DatabaseDSN is where the store opens the database of DBDriver.
+func (cfg *Config) DatabaseDSN() string {
+	if cfg.DBDriver == "postgres" {
+		return cfg.DBDSN
+	}
+	return cfg.DBPath
+}
+
+// SyncSchedule is when full syncs run: the SyncCron expressions, or daily at
+// SyncTime when none are set.
+func (cfg *Config) SyncSchedule() (cron.Schedule, error) {
+	if len(cfg.SyncCron) == 0 {
+		return cron.Schedule{cron.Daily(cfg.SyncTime.Hour, cfg.SyncTime.Minute)}, nil
+	}
+	return cron.ParseSchedule(cfg.SyncCron)
 }
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// loadYAML loads the configuration from a file holding yaml.
+func loadYAML(t *testing.T, yaml string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(Flags{ConfigPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadConfigKeepsSubUnitDurations(t *testing.T) {
+	cfg := loadYAML(t, `
+db_timeout: 1500ms
+realtime_poll_interval: 500ms
+shutdown_timeout: 2500ms
+metrics_retention: 90m
+announcements:
+  poll_interval: 90s
+http_cache:
+  max_age: 90s
+`)
+	for name, d := range map[string]struct{ got, want time.Duration }{
+		"db_timeout":                  {cfg.DBTimeout, 1500 * time.Millisecond},
+		"realtime_poll_interval":      {cfg.RealtimePollInterval, 500 * time.Millisecond},
+		"shutdown_timeout":            {cfg.ShutdownTimeout, 2500 * time.Millisecond},
+		"metrics_retention":           {cfg.MetricsRetention, 90 * time.Minute},
+		"announcements.poll_interval": {cfg.Announcements.PollInterval, 90 * time.Second},
+		"http_cache.max_age":          {cfg.HTTPCache.MaxAge, 90 * time.Second},
+	} {
+		if d.got != d.want {
+			t.Errorf("%s = %s, want %s", name, d.got, d.want)
+		}
+	}
+}
+
+func TestLoadConfigEnvOverridesDurations(t *testing.T) {
+	t.Setenv("REALTIME_POLL_INTERVAL", "2")
+	cfg := loadYAML(t, "realtime_poll_interval: 500ms\n")
+	if cfg.RealtimePollInterval != 2*time.Second {
+		t.Errorf("realtime_poll_interval = %s, want 2s", cfg.RealtimePollInterval)
+	}
+}
+
+func TestLoadConfigKeepsReadyMaxDataAgeUnderAnHour(t *testing.T) {
+	cfg := loadYAML(t, "ready_max_data_age: 30m\n")
+	if cfg.ReadyMaxDataAge != 30*time.Minute {
+		t.Errorf("ready_max_data_age = %s, want 30m; the readiness check would be off", cfg.ReadyMaxDataAge)
+	}
+
+	t.Setenv("READY_MAX_DATA_AGE_HOURS", "0")
+	if cfg := loadYAML(t, "ready_max_data_age: 30m\n"); cfg.ReadyMaxDataAge != 0 {
+		t.Errorf("READY_MAX_DATA_AGE_HOURS=0 left ready_max_data_age at %s", cfg.ReadyMaxDataAge)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is read from the working directory when no config file is
+// given with -config or COMMUTER_CONFIG.
+const DefaultConfigFile = "commuter.yaml"
+
+// loadFile decodes the YAML config file at path over cfg. Keys missing from
+// the file keep their current values; unknown keys are rejected so typos do
+// not go unnoticed. A missing file is only an error when required.
+func (cfg *Config) loadFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	cfg.ConfigFile = path
+	return nil
+}
+
+// ClockTime is a time of day, such as 05:00.
+type ClockTime struct {
+	Hour   int
+	Minute int
+}
+
+// ParseClockTime parses an HH:MM time of day.
+func ParseClockTime(raw string) (ClockTime, error) {
+	t, err := time.Parse("15:04", raw)
+	if err != nil {
+		return ClockTime{}, fmt.Errorf("time of day %q: expected HH:MM", raw)
+	}
+	return ClockTime{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+func (c ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+}
+
+func (c *ClockTime) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	t, err := ParseClockTime(raw)
+	if err != nil {
+		return err
+	}
+	*c = t
+	return nil
+}
+
+// UnmarshalYAML accepts a single FROM-TO window such as "00:00-11:59".
+func (w *TimeWindow) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	windows, err := ParseTimeWindows(raw)
+	if err != nil {
+		return err
+	}
+	if len(windows) != 1 {
+		return fmt.Errorf("time window %q: expected a single FROM-TO range", raw)
+	}
+	*w = windows[0]
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// reloadable names the settings, by config file key, that are applied without
+// a restart. Changes to anything else are reported but take effect only on
+// the next start.
+var reloadable = map[string]bool{
+	"log_level":   true,
+	"sync_time":   true,
+	"sync_cron":   true,
+	"kai_token":   true,
+	"maintenance": true,
+}
+
+// ReloadResult lists the settings that changed in a reload.
+type ReloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// Watcher re-reads the configuration on SIGHUP or on request and passes it to
+// the registered handlers, which apply the reloadable settings. The Config
+// returned by LoadConfig at startup is never modified.
+type Watcher struct {
+	flags   Flags
+	logger  *zap.Logger
+	initial *Config
+
+	mu       sync.Mutex
+	current  *Config
+	handlers []func(*Config)
+}
+
+func NewWatcher(cfg *Config, flags Flags, logger *zap.Logger) *Watcher {
+	return &Watcher{flags: flags, logger: logger, initial: cfg, current: cfg}
+}
+
+// OnReload registers fn to receive every successfully reloaded configuration.
+func (w *Watcher) OnReload(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, fn)
+}
+
+// Reload re-reads the configuration. An invalid configuration is rejected as a
+// whole and the current settings stay in place.
+func (w *Watcher) Reload() (ReloadResult, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := LoadConfig(w.flags)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	result := ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	for _, key := range changedKeys(w.current, next) {
+		if reloadable[key] {
+			result.Applied = append(result.Applied, key)
+		}
+	}
+	for _, key := range changedKeys(w.initial, next) {
+		if !reloadable[key] {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+
+	for _, fn := range w.handlers {
+		fn(next)
+	}
+	w.current = next
+	return result, nil
+}
+
+// WatchSignals reloads the configuration whenever the process receives
+// SIGHUP, until ctx is done. It blocks, so run it in its own goroutine.
+func (w *Watcher) WatchSignals(ctx context.Context) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	defer signal.Stop(ch)
+	for {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return
+		}
+		w.logger.Info("Received SIGHUP, reloading configuration")
+		result, err := w.Reload()
+		if err != nil {
+			w.logger.Error("Failed to reload configuration, keeping current settings", zap.Error(err))
+			continue
+		}
+		w.logger.Info("Reloaded configuration",
+			zap.Strings("applied", result.Applied),
+			zap.Strings("restart_required", result.RestartRequired),
+		)
+	}
+}
+
+// changedKeys returns the config file keys whose values differ between a and b.
+func changedKeys(a, b *Config) []string {
+	var keys []string
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("yaml")
+		if key == "" || key == "-" {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,220 @@
+// Package cron parses standard five-field cron expressions and computes when
+// they next fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds the search for the next run, so expressions that can
+// never fire (such as "0 0 30 2 *") do not loop forever.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// field is the bit set of values a cron field matches.
+type field uint64
+
+func (f field) has(v int) bool { return f&(1<<uint(v)) != 0 }
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds = bounds{"minute", 0, 59}
+	hourBounds   = bounds{"hour", 0, 23}
+	domBounds    = bounds{"day of month", 1, 31}
+	monthBounds  = bounds{"month", 1, 12}
+	// Day of week accepts 7 as a second Sunday.
+	dowBounds = bounds{"day of week", 0, 7}
+)
+
+// Expr is a parsed cron expression: minute, hour, day of month, month and day
+// of week. Each field accepts *, values, ranges (1-5), steps (*/15, 8-18/2)
+// and comma-separated lists of these.
+type Expr struct {
+	raw                          string
+	minute, hour, dom, month     field
+	dow                          field
+	domRestricted, dowRestricted bool
+}
+
+// Parse parses a five-field cron expression.
+func Parse(raw string) (*Expr, error) {
+	parts := strings.Fields(raw)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression %q: expected 5 fields, got %d", raw, len(parts))
+	}
+
+	e := &Expr{raw: strings.Join(parts, " ")}
+	var err error
+	for _, p := range []struct {
+		dst *field
+		src string
+		b   bounds
+	}{
+		{&e.minute, parts[0], minuteBounds},
+		{&e.hour, parts[1], hourBounds},
+		{&e.dom, parts[2], domBounds},
+		{&e.month, parts[3], monthBounds},
+		{&e.dow, parts[4], dowBounds},
+	} {
+		if *p.dst, err = parseField(p.src, p.b); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", raw, err)
+		}
+	}
+	if e.dow.has(7) {
+		e.dow |= 1
+	}
+	// As in Vixie cron, a field starting with * (including */2) is not a
+	// restriction for the day-matching rule.
+	e.domRestricted = !strings.HasPrefix(parts[2], "*")
+	e.dowRestricted = !strings.HasPrefix(parts[4], "*")
+	return e, nil
+}
+
+// Daily returns the expression that fires once a day at hour:minute.
+func Daily(hour, minute int) *Expr {
+	e, err := Parse(fmt.Sprintf("%d %d * * *", minute, hour))
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func parseField(raw string, b bounds) (field, error) {
+	var f field
+	for _, part := range strings.Split(raw, ",") {
+		rng, stepRaw, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepRaw)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", b.name, stepRaw)
+			}
+			step = n
+		}
+
+		lo, hi := b.min, b.max
+		if rng != "*" {
+			loRaw, hiRaw, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseValue(loRaw, b); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = parseValue(hiRaw, b); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("invalid %s range %q", b.name, rng)
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func parseValue(raw string, b bounds) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < b.min || n > b.max {
+		return 0, fmt.Errorf("invalid %s %q: must be %d-%d", b.name, raw, b.min, b.max)
+	}
+	return n, nil
+}
+
+func (e *Expr) String() string { return e.raw }
+
+// matchesDay follows the usual cron rule: when both day of month and day of
+// week are restricted, a day matching either one fires.
+func (e *Expr) matchesDay(t time.Time) bool {
+	dom, dow := e.dom.has(t.Day()), e.dow.has(int(t.Weekday()))
+	if e.domRestricted && e.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Next returns the first time after t, in t's location, at which e fires, or
+// the zero time if it does not fire within five years.
+func (e *Expr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for next.Before(limit) {
+		switch {
+		case !e.month.has(int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !e.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+		case !e.hour.has(next.Hour()):
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+		case !e.minute.has(next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// Schedule is a set of expressions; it fires whenever any of them does.
+type Schedule []*Expr
+
+// ParseSchedule parses each expression in raw.
+func ParseSchedule(raw []string) (Schedule, error) {
+	sched := make(Schedule, 0, len(raw))
+	for _, r := range raw {
+		e, err := Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		sched = append(sched, e)
+	}
+	return sched, nil
+}
+
+// Next returns the earliest time after t at which any expression fires, or
+// the zero time if none does.
+func (s Schedule) Next(t time.Time) time.Time {
+	var earliest time.Time
+	for _, e := range s {
+		if next := e.Next(t); !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+			earliest = next
+		}
+	}
+	return earliest
+}
+
+// Upcoming returns the next n times the schedule fires after t.
+func (s Schedule) Upcoming(t time.Time, n int) []time.Time {
+	runs := make([]time.Time, 0, n)
+	for len(runs) < n {
+		next := s.Next(t)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+		t = next
+	}
+	return runs
+}
+
+func (s Schedule) String() string {
+	exprs := make([]string, len(s))
+	for i, e := range s {
+		exprs[i] = e.String()
+	}
+	return strings.Join(exprs, "; ")
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// User is a signed-up user, identified by their email address.
+type User struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session is a signed-in user's bearer token. Token is only set when the
+// session is created; the store keeps a hash of it.
+type Session struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
+
+// Favorite is a station a user saved.
+type Favorite struct {
+	StationID string    `json:"station_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Commute is a trip a user makes regularly. After and Before, as HH:MM,
+// bound the departures of interest; empty means any time of day.
+// RemindBefore is how many minutes before its next train a push reminder is
+// sent; 0 sends none.
+type Commute struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	After        string    `json:"after,omitempty"`
+	Before       string    `json:"before,omitempty"`
+	RemindBefore int       `json:"remind_before,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// UserID and TrainsHash are only set for the push worker. TrainsHash
+	// fingerprints the commute's trains as of the last sync it checked.
+	UserID     int64  `json:"-"`
+	TrainsHash string `json:"-"`
+}
+
+// PushSubscription is a browser's Web Push subscription, as serialized by
+// PushSubscription.toJSON.
+type PushSubscription struct {
+	Endpoint  string    `json:"endpoint"`
+	Keys      PushKeys  `json:"keys"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PushKeys are a subscription's base64url encryption keys.
+type PushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
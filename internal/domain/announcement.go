@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Announcement severities, from least to most severe.
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeveritySevere  = "severe"
+)
+
+// Severities lists the announcement severities, least severe first.
+var Severities = []string{SeverityInfo, SeverityWarning, SeveritySevere}
+
+// Announcement sources: entered through the admin API, or polled from the
+// official feed.
+const (
+	AnnouncementSourceAdmin = "admin"
+	AnnouncementSourceKAI   = "kai"
+)
+
+// Announcement is a service announcement or disruption, such as track work
+// or flooding. It concerns Line, or the whole network when Line is empty,
+// and StationIDs when any are listed. It is in effect from StartsAt until
+// EndsAt, or until removed when EndsAt is nil.
+type Announcement struct {
+	ID         int64    `json:"id"`
+	Line       string   `json:"line,omitempty"`
+	StationIDs []string `json:"station_ids,omitempty"`
+	Severity   string   `json:"severity"`
+	Message    string   `json:"message"`
+	// Translations are Message in other languages, by language code.
+	Translations map[string]string `json:"translations,omitempty"`
+	// DisplayMessage is Message in the reader's language when Translations
+	// has it, set by the API.
+	DisplayMessage string     `json:"display_message,omitempty"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	Source         string     `json:"source"`
+	// ExternalID identifies a polled announcement in its source's feed.
+	ExternalID string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ActiveAt reports whether the announcement is in effect at t.
+func (a Announcement) ActiveAt(t time.Time) bool {
+	return !t.Before(a.StartsAt) && (a.EndsAt == nil || t.Before(*a.EndsAt))
+}
+
+// Concerns reports whether the announcement applies to any of lines or
+// stations. Network-wide announcements apply to all of them.
+func (a Announcement) Concerns(lines, stations []string) bool {
+	if a.Line == "" && len(a.StationIDs) == 0 {
+		return true
+	}
+	if a.Line != "" && len(a.StationIDs) == 0 && slices.Contains(lines, a.Line) {
+		return true
+	}
+	for _, id := range a.StationIDs {
+		if slices.Contains(stations, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package domain
+
+// BRTCorridor is one direction of a TransJakarta corridor: its stops in
+// travel order, and the buses that run along them every HeadwayMinutes from
+// FirstDeparture to LastDeparture, as HH:MM, which may be past 24:00 for
+// buses running after midnight.
+type BRTCorridor struct {
+	ID             string    `json:"id"`
+	Direction      int       `json:"direction"`
+	Name           string    `json:"name"`
+	Color          string    `json:"color,omitempty"`
+	HeadwayMinutes int       `json:"headway_minutes"`
+	FirstDeparture string    `json:"first_departure"`
+	LastDeparture  string    `json:"last_departure"`
+	Stops          []BRTStop `json:"stops"`
+}
+
+// BRTStop is a stop of a corridor, reached Minutes after a bus leaves the
+// first one.
+type BRTStop struct {
+	StationID string `json:"station_id"`
+	Minutes   int    `json:"minutes"`
+}
+
+// Transfer is a walk between two nearby stations, either way: a rail
+// station and a BRT stop, found by the BRT import, or a pair an operator
+// curated, such as lines that do not share a station. ToName is the name of
+// ToStationID, set by the API.
+type Transfer struct {
+	StationID   string `json:"station_id"`
+	ToStationID string `json:"to_station_id"`
+	ToName      string `json:"to_name,omitempty"`
+	WalkMinutes int    `json:"walk_minutes"`
+	Note        string `json:"note,omitempty"`
+	Curated     bool   `json:"curated"`
+}
+
+// BRTNetwork is the TransJakarta network the journey planner rides with
+// trains: its corridors and where they meet the rail network.
+type BRTNetwork struct {
+	Corridors []BRTCorridor `json:"corridors"`
+	Transfers []Transfer    `json:"transfers"`
+}
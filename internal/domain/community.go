@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type SubmissionKind string
+
+const (
+	SubmissionKindPhoto   SubmissionKind = "photo"
+	SubmissionKindAmenity SubmissionKind = "amenity"
+)
+
+type SubmissionStatus string
+
+const (
+	SubmissionPending  SubmissionStatus = "pending"
+	SubmissionApproved SubmissionStatus = "approved"
+	SubmissionRejected SubmissionStatus = "rejected"
+)
+
+// SubmissionPayload is the user-provided content of a submission; which
+// fields are set depends on the kind.
+type SubmissionPayload struct {
+	PhotoURL   string             `json:"photo_url,omitempty"`
+	Caption    string             `json:"caption,omitempty"`
+	Facilities *StationFacilities `json:"facilities,omitempty"`
+}
+
+// Submission is a community contribution awaiting or past moderation.
+type Submission struct {
+	ID          int64             `json:"id"`
+	StationID   string            `json:"station_id"`
+	Kind        SubmissionKind    `json:"kind"`
+	Payload     SubmissionPayload `json:"payload"`
+	Submitter   string            `json:"submitter,omitempty"`
+	Status      SubmissionStatus  `json:"status"`
+	ReviewNote  string            `json:"review_note,omitempty"`
+	SubmittedAt time.Time         `json:"submitted_at"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// ClientConfig is the server configuration clients need to adapt their UI,
+// served at /api/v1/config.
+type ClientConfig struct {
+	Maintenance Maintenance `json:"maintenance"`
+	// Features maps each feature flag to whether it is enabled.
+	Features map[string]bool `json:"features"`
+	// PushPublicKey is the VAPID key to subscribe to push notifications
+	// with; empty when they are disabled.
+	PushPublicKey string `json:"push_public_key,omitempty"`
+}
+
+// Maintenance is the current maintenance mode. While it is enabled, mutating
+// endpoints answer 503 and read endpoints keep serving cached data only when
+// ServeReads is set.
+type Maintenance struct {
+	Enabled bool   `json:"enabled"`
+	Banner  string `json:"banner,omitempty"`
+	// RetryAfter is the number of seconds clients should wait before
+	// retrying a rejected request.
+	RetryAfter int        `json:"retry_after"`
+	ServeReads bool       `json:"serve_reads"`
+	Since      *time.Time `json:"since,omitempty"`
+}
+
+// Feature flags admins can toggle at runtime.
+const (
+	FeatureCommunity       = "community"
+	FeatureJourneyPlanner  = "journey_planner"
+	FeatureStreaming       = "streaming"
+	FeatureRealtimePolling = "realtime_polling"
+)
+
+// Features lists the feature flags.
+var Features = []string{FeatureCommunity, FeatureJourneyPlanner, FeatureStreaming, FeatureRealtimePolling}
+
+// FeatureFlag is a feature's current state. Overridden is set while an admin
+// override is in place; otherwise the feature follows the configuration.
+type FeatureFlag struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Overridden bool   `json:"overridden"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+const (
+	LegModeWalk  = "walk"
+	LegModeTrain = "train"
+	LegModeBRT   = "brt"
+)
+
+// Itinerary labels, telling apart the alternatives for a journey.
+const (
+	ItineraryFastest         = "fastest"
+	ItineraryFewestTransfers = "fewest_transfers"
+	ItineraryBalanced        = "balanced"
+)
+
+type Leg struct {
+	Mode string `json:"mode"`
+	// From and To are station IDs for train and BRT legs, and for walks
+	// between them; walking legs use the geocoded address on their street
+	// end.
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	TrainID         string    `json:"train_id,omitempty"`
+	Line            string    `json:"line,omitempty"`
+	Route           string    `json:"route,omitempty"`
+	ServiceType     string    `json:"service_type,omitempty"`
+	DepartsAt       time.Time `json:"departs_at"`
+	ArrivesAt       time.Time `json:"arrives_at"`
+	DistanceMeters  int       `json:"distance_meters,omitempty"`
+	DurationMinutes int       `json:"duration_minutes"`
+	// Fare is the leg's fare in rupiah, when its service has its own fare
+	// rules.
+	Fare int `json:"fare,omitempty"`
+}
+
+type Itinerary struct {
+	DepartsAt       time.Time `json:"departs_at"`
+	ArrivesAt       time.Time `json:"arrives_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Transfers       int       `json:"transfers"`
+	Legs            []Leg     `json:"legs"`
+	// Label tells the itinerary apart from its alternatives, when it has any.
+	Label string `json:"label,omitempty"`
+	// Alternatives are the other itineraries found for the journey, that
+	// trade arriving later for fewer transfers or the other way round.
+	Alternatives []Itinerary `json:"alternatives,omitempty"`
+}
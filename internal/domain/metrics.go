@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// MetricsSnapshot is a periodic sample of the server's runtime, cache and
+// database state, kept for basic observability history.
+type MetricsSnapshot struct {
+	ID         int64                 `json:"id"`
+	TakenAt    time.Time             `json:"taken_at"`
+	Goroutines int                   `json:"goroutines"`
+	HeapBytes  uint64                `json:"heap_bytes"`
+	Caches     map[string]CacheStats `json:"caches"`
+	DB         DBStats               `json:"db"`
+}
+
+// CacheStats counts the lookups a cache served since the previous snapshot.
+type CacheStats struct {
+	Entries int     `json:"entries"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// DBStats describes the database connection pool and file size.
+type DBStats struct {
+	SizeBytes       int64 `json:"size_bytes"`
+	OpenConnections int   `json:"open_connections"`
+	InUse           int   `json:"in_use"`
+	Idle            int   `json:"idle"`
+	WaitCount       int64 `json:"wait_count"`
+	WaitMillis      int64 `json:"wait_ms"`
+}
+
+// DBReport is the database state shown to admins: the pool statistics and
+// the number of rows in each table.
+type DBReport struct {
+	DBStats
+	Tables map[string]int64 `json:"tables"`
+}
+
+// PurgeResult counts the rows removed by a purge of stale data.
+type PurgeResult struct {
+	// Schedules, Facilities and Locations belong to stations that are no
+	// longer listed.
+	Schedules  int64 `json:"schedules"`
+	Facilities int64 `json:"facilities"`
+	Locations  int64 `json:"locations"`
+	// DelaySamples were observed before Cutoff.
+	DelaySamples int64     `json:"delay_samples"`
+	Cutoff       time.Time `json:"cutoff"`
+}
+
+// RetentionRun is the outcome of a run of the maintenance job: how many
+// rows each retention policy deleted and the database size around it.
+// Error is set when a step failed; the steps before it still took effect.
+type RetentionRun struct {
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	SyncRuns        int64     `json:"sync_runs"`
+	RawFetches      int64     `json:"raw_fetches"`
+	Announcements   int64     `json:"announcements"`
+	ScheduleHistory int64     `json:"schedule_history"`
+	SizeBeforeBytes int64     `json:"size_before_bytes"`
+	SizeAfterBytes  int64     `json:"size_after_bytes"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// RetentionStatus is the maintenance job's last run, when it has run since
+// the server started, and when it runs next, nil when it is not scheduled.
+type RetentionStatus struct {
+	LastRun *RetentionRun `json:"last_run"`
+	NextRun *time.Time    `json:"next_run"`
+	Running bool          `json:"running"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Schedule override kinds.
+const (
+	OverrideCancel = "cancel"
+	OverrideRetime = "retime"
+	OverrideAdd    = "add"
+)
+
+// OverrideKinds lists every schedule override kind.
+var OverrideKinds = []string{OverrideCancel, OverrideRetime, OverrideAdd}
+
+// ScheduleOverride is an operator's correction to the scraped timetable. It
+// is kept apart from the schedules, so it survives syncs, and applied when
+// they are read: on ServiceDate, or on every date when that is empty.
+//
+// A cancel removes the departures of TrainID, only the one from StationID
+// when that is set. A retime moves them by ShiftMinutes, or sets the
+// departure from StationID to DepartsAt. An add is an extra departure from
+// StationID described by the remaining fields; the adds of a TrainID make up
+// its route.
+type ScheduleOverride struct {
+	ID           int64  `json:"id"`
+	Kind         string `json:"kind"`
+	TrainID      string `json:"train_id"`
+	StationID    string `json:"station_id,omitempty"`
+	ServiceDate  string `json:"service_date,omitempty"`
+	ShiftMinutes int    `json:"shift_minutes,omitempty"`
+
+	// DepartsAt and ArrivesAt are times of day in Jakarta, as HH:MM.
+	DepartsAt string `json:"departs_at,omitempty"`
+	ArrivesAt string `json:"arrives_at,omitempty"`
+
+	Line                 string    `json:"line,omitempty"`
+	Route                string    `json:"route,omitempty"`
+	StationOriginID      string    `json:"station_origin_id,omitempty"`
+	StationDestinationID string    `json:"station_destination_id,omitempty"`
+	ServiceType          string    `json:"service_type,omitempty"`
+	Note                 string    `json:"note,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// AppliesOn reports whether the override is in effect on the service date
+// day, as YYYY-MM-DD.
+func (o ScheduleOverride) AppliesOn(day string) bool {
+	return o.ServiceDate == "" || o.ServiceDate == day
+}
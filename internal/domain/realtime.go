@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// TrainPosition is the latest real-time observation for a single train.
+type TrainPosition struct {
+	TrainID       string    `json:"train_id"`
+	StationID     string    `json:"station_id"`
+	NextStationID string    `json:"next_station_id"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	DelayMinutes  int       `json:"delay_minutes"`
+	Status        string    `json:"status"`
+	ObservedAt    time.Time `json:"observed_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type DelaySource string
+
+const (
+	DelaySourceRealtime DelaySource = "realtime"
+	DelaySourceReport   DelaySource = "report"
+)
+
+// DelaySample is one observation of a train's delay at a station.
+type DelaySample struct {
+	TrainID      string
+	StationID    string
+	Source       DelaySource
+	DelayMinutes int
+	ObservedAt   time.Time
+
+	// Reporter identifies the client a report came from and ServiceDate,
+	// as YYYY-MM-DD, the service date it is about. Both are only set for
+	// reports.
+	Reporter    string
+	ServiceDate string
+}
+
+// Reliability summarises how punctual a train has been recently.
+type Reliability struct {
+	// Score is 0-100, the weighted share of on-time observations, lowered
+	// for each schedule change and alert.
+	Score           int     `json:"score"`
+	OnTimeRate      float64 `json:"on_time_rate"`
+	AvgDelayMinutes float64 `json:"avg_delay_minutes"`
+	Observations    int     `json:"observations"`
+	Reports         int     `json:"reports"`
+	// ScheduleChanges counts the train's departures retimed by syncs, and
+	// Alerts the warning or severe announcements on its line.
+	ScheduleChanges int `json:"schedule_changes"`
+	Alerts          int `json:"alerts"`
+}
+
+// Estimated position statuses.
+const (
+	EstimateAtStation = "at_station"
+	EstimateMoving    = "moving"
+)
+
+// EstimatedPosition is where a train should be by its timetable, for lines
+// without a realtime feed. A train standing at StationID is AtStation;
+// a moving one has left StationID for NextStationID and covered Progress
+// (0-1) of the time between them. Heading is in degrees clockwise from
+// north.
+type EstimatedPosition struct {
+	TrainID       string  `json:"train_id"`
+	Route         string  `json:"route"`
+	Terminus      string  `json:"terminus"`
+	Status        string  `json:"status"`
+	StationID     string  `json:"station_id"`
+	NextStationID string  `json:"next_station_id,omitempty"`
+	Progress      float64 `json:"progress"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	Heading       float64 `json:"heading"`
+}
+
+// LinePositions is the estimated position of every train in service on a
+// line at EstimatedAt.
+type LinePositions struct {
+	Line        string              `json:"line"`
+	Color       string              `json:"color"`
+	ServiceDay  string              `json:"service_day"`
+	EstimatedAt time.Time           `json:"estimated_at"`
+	Trains      []EstimatedPosition `json:"trains"`
+}
+
+// LineDelays summarises the delays recently observed or reported on a
+// line's trains.
+type LineDelays struct {
+	Samples         int     `json:"samples"`
+	AvgDelayMinutes float64 `json:"avg_delay_minutes"`
+	MaxDelayMinutes int     `json:"max_delay_minutes"`
+}
@@ -0,0 +1,351 @@
+package domain
+
+import "time"
+
+type Schedule struct {
+	ID                   string           `json:"id"`
+	StationID            string           `json:"station_id"`
+	StationOriginID      string           `json:"station_origin_id"`
+	StationDestinationID string           `json:"station_destination_id"`
+	TrainID              string           `json:"train_id"`
+	Line                 string           `json:"line"`
+	Route                string           `json:"route"`
+	DepartsAt            time.Time        `json:"departs_at"`
+	ArrivesAt            time.Time        `json:"arrives_at"`
+	Metadata             ScheduleMetadata `json:"metadata"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+
+	// RunID is the sync run that last wrote this schedule.
+	RunID int64 `json:"run_id,omitempty"`
+
+	// ServiceType classifies the trip; see the Service* constants.
+	ServiceType string `json:"service_type"`
+
+	// ServiceDay is the calendar type the schedule runs on; see the
+	// ServiceDay* constants.
+	ServiceDay string `json:"service_day"`
+
+	// ServiceDate is the YYYY-MM-DD date of the sync that fetched the
+	// schedule, or of the day it ran when read from the history.
+	ServiceDate string `json:"service_date,omitempty"`
+
+	// Reliability is derived at read time and not stored with the schedule.
+	Reliability *Reliability `json:"reliability,omitempty"`
+
+	// To is the train's arrival at the destination a station board was
+	// requested for, derived at read time like Reliability.
+	To *Arrival `json:"to,omitempty"`
+}
+
+// Arrival is when a train reaches StationID. Estimated is set when it is
+// derived from the departure there rather than published.
+type Arrival struct {
+	StationID       string    `json:"station_id"`
+	ArrivesAt       time.Time `json:"arrives_at"`
+	Estimated       bool      `json:"estimated"`
+	DurationMinutes int       `json:"duration_minutes"`
+}
+
+// StationArrival is a train reaching a station, for an arrival board. From
+// is the stop it comes from, and DepartsAt its onward departure, nil when the
+// train terminates there. Estimated is set when ArrivesAt is derived from
+// the departure rather than published.
+type StationArrival struct {
+	TrainID         string     `json:"train_id"`
+	Line            string     `json:"line"`
+	Route           string     `json:"route"`
+	Color           string     `json:"color"`
+	ServiceType     string     `json:"service_type"`
+	OriginID        string     `json:"station_origin_id"`
+	OriginName      string     `json:"station_origin_name,omitempty"`
+	FromStationID   string     `json:"from_station_id"`
+	FromStationName string     `json:"from_station_name,omitempty"`
+	ArrivesAt       time.Time  `json:"arrives_at"`
+	Estimated       bool       `json:"estimated"`
+	DepartsAt       *time.Time `json:"departs_at"`
+	Terminates      bool       `json:"terminates"`
+	Platform        string     `json:"platform,omitempty"`
+}
+
+// ArrivalBoard lists the trains reaching a station on the timetable of
+// ServiceDay, by arrival time.
+type ArrivalBoard struct {
+	StationID  string           `json:"station_id"`
+	ServiceDay string           `json:"service_day"`
+	Arrivals   []StationArrival `json:"arrivals"`
+}
+
+// Service types a trip is classified as during sync.
+const (
+	ServiceCommuter = "commuter"
+	ServiceLocal    = "local"
+	ServiceAirport  = "airport"
+	ServiceFeeder   = "feeder"
+)
+
+// ServiceTypes lists every service type.
+var ServiceTypes = []string{ServiceCommuter, ServiceLocal, ServiceAirport, ServiceFeeder}
+
+// Calendar types a timetable runs on. KRL publishes separate timetables for
+// weekends and public holidays, and each sync is tagged with the type of the
+// day it ran.
+const (
+	ServiceDayWeekday = "weekday"
+	ServiceDayWeekend = "weekend"
+	ServiceDayHoliday = "holiday"
+)
+
+// ServiceDayFallbacks lists, for each calendar type, the timetables to serve
+// in order of preference when a station has not been synced on that type of
+// day yet.
+var ServiceDayFallbacks = map[string][]string{
+	ServiceDayWeekday: {ServiceDayWeekday, ServiceDayWeekend, ServiceDayHoliday},
+	ServiceDayWeekend: {ServiceDayWeekend, ServiceDayHoliday, ServiceDayWeekday},
+	ServiceDayHoliday: {ServiceDayHoliday, ServiceDayWeekend, ServiceDayWeekday},
+}
+
+type ScheduleMetadata struct {
+	Origin ScheduleOrigin `json:"origin"`
+
+	// Platform is the departure platform, when the upstream lists one or a
+	// platform assignment covers the departure.
+	Platform string `json:"platform,omitempty"`
+
+	// Fares are the fares in rupiah from the departure to each later stop,
+	// for services with their own fare rules such as the Airport Rail Link.
+	Fares map[string]int `json:"fares,omitempty"`
+
+	// Override is the kind of schedule override that changed or added the
+	// departure, and OverrideNote the operator's note on it.
+	Override     string `json:"override,omitempty"`
+	OverrideNote string `json:"override_note,omitempty"`
+}
+
+type ScheduleOrigin struct {
+	Color string `json:"color"`
+}
+
+type RouteData struct {
+	Routes  []RouteStop `json:"routes"`
+	Details RouteDetail `json:"details"`
+}
+
+type RouteStop struct {
+	ID          string    `json:"id"`
+	StationID   string    `json:"station_id"`
+	StationName string    `json:"station_name"`
+	DepartsAt   time.Time `json:"departs_at"`
+	CreatedAt   time.Time `json:"created_at"` // Not in DB, maybe derive?
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// ArrivesAt is when the train reaches the stop, or nil at its origin.
+	// ArrivalEstimated is set when it is derived from the departure rather
+	// than published.
+	ArrivesAt        *time.Time `json:"arrives_at"`
+	ArrivalEstimated bool       `json:"arrival_estimated"`
+
+	// Platform is the departure platform, when known.
+	Platform string `json:"platform,omitempty"`
+
+	// Boarding marks the stop given as from= in the request.
+	Boarding bool `json:"boarding,omitempty"`
+}
+
+type RouteDetail struct {
+	TrainID                string    `json:"train_id"`
+	Line                   string    `json:"line"`
+	Route                  string    `json:"route"`
+	ServiceType            string    `json:"service_type"`
+	ServiceDay             string    `json:"service_day"`
+	StationOriginID        string    `json:"station_origin_id"`
+	StationOriginName      string    `json:"station_origin_name"`
+	StationDestinationID   string    `json:"station_destination_id"`
+	StationDestinationName string    `json:"station_destination_name"`
+	ArrivesAt              time.Time `json:"arrives_at"`
+}
+
+// Line is a commuter line with its stations in travel order.
+type Line struct {
+	Name string `json:"name"`
+	// DisplayName is Name localized for the reader, set by the API.
+	DisplayName  string        `json:"display_name,omitempty"`
+	Color        string        `json:"color"`
+	StationCount int           `json:"station_count"`
+	Stations     []LineStation `json:"stations,omitempty"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type LineStation struct {
+	Position int    `json:"position"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+}
+
+// LatLng is a coordinate pair.
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// LineShape is the rail alignment of a line, imported from OpenStreetMap.
+// Each path is a continuous stretch of track; branches and gaps in the
+// source data make separate paths.
+type LineShape struct {
+	Line      string     `json:"line"`
+	Paths     [][]LatLng `json:"paths"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// RouteShape is the path a train travels, as a Google encoded polyline
+// (precision 5) through its stops. Stops without a known location are left
+// out; StraightSegments counts the legs drawn as straight lines because the
+// line's track did not cover them.
+type RouteShape struct {
+	TrainID          string `json:"train_id"`
+	Line             string `json:"line"`
+	ServiceDay       string `json:"service_day"`
+	Polyline         string `json:"polyline"`
+	Points           int    `json:"points"`
+	Stops            int    `json:"stops"`
+	StraightSegments int    `json:"straight_segments"`
+}
+
+// Line operating statuses.
+const (
+	LineOperating           = "operating"
+	LineOutsideServiceHours = "outside_service_hours"
+)
+
+// LineStatus is a line's service at GeneratedAt of the NetworkStatus it is
+// part of. HeadwayMinutes is the average time between trains heading the
+// same way around now, nil when too few run to tell. Disrupted is set when
+// recent delays on the line are severe or a severe announcement for it is
+// in effect.
+type LineStatus struct {
+	Line string `json:"line"`
+	// DisplayName and StatusLabel are Line and Status localized for the
+	// reader.
+	DisplayName    string      `json:"display_name,omitempty"`
+	Color          string      `json:"color"`
+	Status         string      `json:"status"`
+	StatusLabel    string      `json:"status_label,omitempty"`
+	TrainsRunning  int         `json:"trains_running"`
+	FirstTrain     *LineTrip   `json:"first_train"`
+	LastTrain      *LineTrip   `json:"last_train"`
+	HeadwayMinutes *float64    `json:"headway_minutes"`
+	Delays         *LineDelays `json:"delays,omitempty"`
+	Disrupted      bool        `json:"disrupted"`
+
+	// Announcements are those in effect for the line or its stations.
+	Announcements []Announcement `json:"announcements,omitempty"`
+}
+
+// LineTrip is a train's departure from its origin.
+type LineTrip struct {
+	TrainID   string    `json:"train_id"`
+	StationID string    `json:"station_id"`
+	DepartsAt time.Time `json:"departs_at"`
+}
+
+// NetworkStatus is the status of every line, for an overview screen.
+// Announcements lists those in effect for the whole network.
+type NetworkStatus struct {
+	ServiceDay    string         `json:"service_day"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+	Lines         []LineStatus   `json:"lines"`
+	Announcements []Announcement `json:"announcements,omitempty"`
+}
+
+// LineFrequency is how many trains a line runs in each interval of
+// ServiceDay, counted when they leave their origin, for the whole line and
+// for each direction, told apart by terminus.
+type LineFrequency struct {
+	Line               string               `json:"line"`
+	ServiceDay         string               `json:"service_day"`
+	GranularityMinutes int                  `json:"granularity_minutes"`
+	Trains             int                  `json:"trains"`
+	Intervals          []FrequencyInterval  `json:"intervals"`
+	Directions         []DirectionFrequency `json:"directions"`
+}
+
+// DirectionFrequency is a LineFrequency's count for the trains heading to one
+// terminus.
+type DirectionFrequency struct {
+	TerminusID   string `json:"terminus_id"`
+	TerminusName string `json:"terminus_name"`
+	// Label names the direction for the reader, as "To Bogor".
+	Label     string              `json:"label"`
+	Trains    int                 `json:"trains"`
+	Intervals []FrequencyInterval `json:"intervals"`
+}
+
+// FrequencyInterval counts the trains leaving in the interval starting at
+// Start, an HH:MM time of day. HeadwayMinutes is the interval's length over
+// its trains, only given for a direction's intervals that have any.
+type FrequencyInterval struct {
+	Start          string   `json:"start"`
+	Trains         int      `json:"trains"`
+	HeadwayMinutes *float64 `json:"headway_minutes,omitempty"`
+}
+
+type HourlyDepartureCount struct {
+	Line  string
+	Hour  int
+	Count int
+}
+
+// DeparturePage is one page of departures across the network from the
+// timetables run on ServiceDay. NextOffset is where the next page starts, or
+// nil on the last page.
+type DeparturePage struct {
+	ServiceDay string     `json:"service_day"`
+	Departures []Schedule `json:"departures"`
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+	NextOffset *int       `json:"next_offset"`
+}
+
+// StationStats summarizes the timetable a station runs on ServiceDay, which
+// is empty when the station has a single timetable for every day. Headways
+// are the average minutes between consecutive departures.
+type StationStats struct {
+	StationID      string     `json:"station_id"`
+	ServiceDay     string     `json:"service_day,omitempty"`
+	Departures     int        `json:"departures"`
+	FirstDeparture *time.Time `json:"first_departure"`
+	LastDeparture  *time.Time `json:"last_departure"`
+	AverageHeadway float64    `json:"average_headway_minutes"`
+	// TrainsPerHour counts departures by hour of day; BusiestHour is the
+	// first hour with the most of them, or nil without departures.
+	TrainsPerHour []int              `json:"trains_per_hour"`
+	BusiestHour   *int               `json:"busiest_hour"`
+	Lines         []LineStats        `json:"lines"`
+	Destinations  []DestinationStats `json:"destinations"`
+}
+
+// LineStats is one line's share of a station's departures.
+type LineStats struct {
+	Line           string  `json:"line"`
+	Departures     int     `json:"departures"`
+	AverageHeadway float64 `json:"average_headway_minutes"`
+}
+
+// DestinationStats is the service from a station towards one destination.
+type DestinationStats struct {
+	StationID      string    `json:"station_id"`
+	Name           string    `json:"name,omitempty"`
+	Departures     int       `json:"departures"`
+	FirstDeparture time.Time `json:"first_departure"`
+	LastDeparture  time.Time `json:"last_departure"`
+}
+
+// DepartureHeatmap is a line-by-hour matrix of departure counts for one station.
+// Matrix[i][h] is the number of departures on Lines[i] during hour h.
+type DepartureHeatmap struct {
+	StationID  string   `json:"station_id"`
+	ServiceDay string   `json:"service_day,omitempty"`
+	Lines      []string `json:"lines"`
+	Matrix     [][]int  `json:"matrix"`
+	Totals     []int    `json:"totals"`
+	Max        int      `json:"max"`
+}
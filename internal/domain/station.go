@@ -0,0 +1,207 @@
+// Package domain holds the canonical model shared by the store, the scraper
+// and the API. Storage-specific mapping lives with each store
+// implementation.
+package domain
+
+import "time"
+
+type StationType string
+
+const (
+	StationTypeKRL   StationType = "KRL"
+	StationTypeLocal StationType = "LOCAL"
+	// StationTypeBRT is a TransJakarta bus stop, imported rather than
+	// synced.
+	StationTypeBRT StationType = "BRT"
+)
+
+type Station struct {
+	UID      string         `json:"uid"`
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Type     StationType    `json:"type"`
+	Metadata Metadata       `json:"metadata"`
+	Aliases  []StationAlias `json:"aliases,omitempty"`
+	// DisplayName is Name localized for the reader, set by the API.
+	DisplayName string `json:"display_name,omitempty"`
+	// Changes is the station's scheduled closure or rename, when it has one.
+	Changes *StationChange `json:"changes,omitempty"`
+}
+
+// StationChange is a closure or a rename of a station taking effect on a
+// service date, as YYYY-MM-DD. A closed station has no departures from
+// ClosesOn. A renamed one is called Name from RenamedOn and FormerName
+// before, whatever the upstream calls it.
+type StationChange struct {
+	ClosesOn   string `json:"closes_on,omitempty"`
+	Name       string `json:"name,omitempty"`
+	FormerName string `json:"former_name,omitempty"`
+	RenamedOn  string `json:"renamed_on,omitempty"`
+}
+
+// ClosedOn reports whether the station is closed on day, as YYYY-MM-DD.
+func (c StationChange) ClosedOn(day string) bool {
+	return c.ClosesOn != "" && day >= c.ClosesOn
+}
+
+// NameOn returns the station's name on day, as YYYY-MM-DD, or "" when it has
+// no rename.
+func (c StationChange) NameOn(day string) string {
+	switch {
+	case c.RenamedOn == "":
+		return ""
+	case day >= c.RenamedOn:
+		return c.Name
+	default:
+		return c.FormerName
+	}
+}
+
+// StationAlias is another name a station goes by: an upstream spelling, a
+// colloquial name or a localized one. A display alias replaces the upstream
+// name when showing the station to readers of Lang, or of any language when
+// Lang is empty.
+type StationAlias struct {
+	Name    string `json:"name"`
+	Lang    string `json:"lang,omitempty"`
+	Display bool   `json:"display,omitempty"`
+}
+
+// PlatformAssignment is a curated platform for departures from a station,
+// used when the upstream does not list one. An empty DestinationID applies
+// to every departure of Line without an assignment of its own.
+type PlatformAssignment struct {
+	Line          string `json:"line"`
+	DestinationID string `json:"destination_id,omitempty"`
+	Platform      string `json:"platform"`
+}
+
+// NameOn returns the station's name on day, as YYYY-MM-DD: the one a rename
+// gives it then, or else LocalName.
+func (st Station) NameOn(day, lang string) string {
+	if st.Changes != nil {
+		if name := st.Changes.NameOn(day); name != "" {
+			return name
+		}
+	}
+	return st.LocalName(lang)
+}
+
+// LocalName returns the station's display alias for lang, falling back to a
+// display alias for any language and then to its name.
+func (st Station) LocalName(lang string) string {
+	name := st.Name
+	for _, a := range st.Aliases {
+		if !a.Display {
+			continue
+		}
+		if a.Lang == lang {
+			return a.Name
+		}
+		if a.Lang == "" {
+			name = a.Name
+		}
+	}
+	return name
+}
+
+type Metadata struct {
+	Active bool   `json:"active"`
+	Origin Origin `json:"origin"`
+}
+
+type Origin struct {
+	FgEnable int `json:"fg_enable"`
+	Daop     int `json:"daop"`
+}
+
+// StationFacilities describes amenities at a station. Nil fields are unknown.
+type StationFacilities struct {
+	Toilets        *bool  `json:"toilets,omitempty"`
+	PrayerRoom     *bool  `json:"prayer_room,omitempty"`
+	Lifts          *bool  `json:"lifts,omitempty"`
+	Escalators     *bool  `json:"escalators,omitempty"`
+	ParkAndRide    *bool  `json:"park_and_ride,omitempty"`
+	TicketMachines *bool  `json:"ticket_machines,omitempty"`
+	Notes          string `json:"notes,omitempty"`
+}
+
+// StationLine summarises one line's service at a station.
+type StationLine struct {
+	Line           string    `json:"line"`
+	Color          string    `json:"color"`
+	Departures     int       `json:"departures"`
+	FirstDeparture time.Time `json:"first_departure"`
+	LastDeparture  time.Time `json:"last_departure"`
+	Destinations   []string  `json:"destinations"`
+}
+
+// StationSuggestion is a station as station autocomplete lists it, kept
+// small for search-as-you-type.
+type StationSuggestion struct {
+	ID          string      `json:"id"`
+	DisplayName string      `json:"display_name"`
+	Type        StationType `json:"type"`
+	Lines       []LineBadge `json:"lines"`
+}
+
+// LineBadge names a line serving a station, and the color to draw it in.
+type LineBadge struct {
+	Line        string `json:"line"`
+	DisplayName string `json:"display_name"`
+	Color       string `json:"color"`
+}
+
+// StationDetail is a station with data derived from its schedules.
+type StationDetail struct {
+	Station
+	Lines          []StationLine      `json:"lines"`
+	FirstDeparture *time.Time         `json:"first_departure"`
+	LastDeparture  *time.Time         `json:"last_departure"`
+	Interchange    bool               `json:"interchange"`
+	Facilities     *StationFacilities `json:"facilities"`
+	Location       *StationLocation   `json:"location"`
+	Photos         []StationPhoto     `json:"photos,omitempty"`
+	// Transfers are the walks to nearby stations, the shortest first.
+	Transfers []Transfer `json:"transfers,omitempty"`
+}
+
+// StationPhoto is an approved community photo.
+type StationPhoto struct {
+	URL         string    `json:"url"`
+	Caption     string    `json:"caption,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// StationLocation is a station's coordinates. Locations are kept apart from
+// stations because the station list is replaced wholesale on every sync.
+// Address and Municipality are only known for imported geodata.
+type StationLocation struct {
+	StationID    string  `json:"station_id"`
+	Lat          float64 `json:"lat"`
+	Lng          float64 `json:"lng"`
+	Address      string  `json:"address,omitempty"`
+	Municipality string  `json:"municipality,omitempty"`
+}
+
+// FirstLastTrains is the first and last departure of the day from a station
+// towards each destination, on the timetable of ServiceDay. With To set,
+// only trains reaching To count, grouped by line.
+type FirstLastTrains struct {
+	StationID  string             `json:"station_id"`
+	ServiceDay string             `json:"service_day"`
+	To         string             `json:"to,omitempty"`
+	Services   []FirstLastService `json:"services"`
+}
+
+// FirstLastService is the first and last train of one line towards
+// DestinationID.
+type FirstLastService struct {
+	Line            string   `json:"line"`
+	Color           string   `json:"color"`
+	DestinationID   string   `json:"destination_id"`
+	DestinationName string   `json:"destination_name,omitempty"`
+	Departures      int      `json:"departures"`
+	First           Schedule `json:"first"`
+	Last            Schedule `json:"last"`
+}
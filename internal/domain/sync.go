@@ -0,0 +1,170 @@
+package domain
+
+import "time"
+
+// SyncRun statuses. A run is interrupted when the process exited while it was
+// running.
+const (
+	SyncRunRunning     = "running"
+	SyncRunSucceeded   = "succeeded"
+	SyncRunFailed      = "failed"
+	SyncRunInterrupted = "interrupted"
+)
+
+// SyncRun triggers: what started a run.
+const (
+	SyncTriggerStartup   = "startup"
+	SyncTriggerScheduled = "scheduled"
+	SyncTriggerAPI       = "api"
+	SyncTriggerAdmin     = "admin"
+	SyncTriggerGRPC      = "grpc"
+	SyncTriggerBootstrap = "bootstrap"
+)
+
+// Notification events, which webhooks can subscribe to.
+const (
+	NotifySyncCompleted = "sync.completed"
+	NotifySyncFailed    = "sync.failed"
+	NotifyAnomaly       = "anomaly"
+)
+
+// NotifyEvents lists every notification event.
+var NotifyEvents = []string{NotifySyncCompleted, NotifySyncFailed, NotifyAnomaly}
+
+// SyncRun is one execution of the full sync. Its ID versions the schedule data.
+type SyncRun struct {
+	ID         int64      `json:"id"`
+	Trigger    string     `json:"trigger,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	SyncRunStats
+}
+
+// SyncRunStats counts the work a sync run did. Runs recorded before these
+// were kept report zeros.
+type SyncRunStats struct {
+	StationsSucceeded int `json:"stations_succeeded"`
+	StationsFailed    int `json:"stations_failed"`
+	// RowsWritten is the number of schedules stored.
+	RowsWritten    int      `json:"rows_written"`
+	FailedStations []string `json:"failed_stations,omitempty"`
+}
+
+// Schedule anomaly kinds.
+const (
+	// AnomalySchedulesEmptied is a station whose timetable had departures
+	// before a sync and none after it.
+	AnomalySchedulesEmptied = "schedules_emptied"
+)
+
+// ScheduleAnomaly is a suspicious change a sync made to a station's
+// timetable for ServiceDay. Before and After count its departures.
+type ScheduleAnomaly struct {
+	Kind       string `json:"kind"`
+	StationID  string `json:"station_id"`
+	ServiceDay string `json:"service_day"`
+	Before     int    `json:"before"`
+	After      int    `json:"after"`
+}
+
+// Integrity issue kinds, found by the checks run after every sync.
+const (
+	// IssueUnresolvedOrigin and IssueUnresolvedDestination are trains whose
+	// route names an endpoint that matches no station.
+	IssueUnresolvedOrigin      = "unresolved_origin"
+	IssueUnresolvedDestination = "unresolved_destination"
+	// IssueSingleStop is a train listed at a single station.
+	IssueSingleStop = "single_stop"
+	// IssueNonMonotonic is a train whose departures do not run in route
+	// order: it leaves its origin after another stop, or leaves a stop after
+	// arriving at its destination.
+	IssueNonMonotonic = "non_monotonic_times"
+	// IssueStationWithoutSchedules is an active station without departures.
+	IssueStationWithoutSchedules = "station_without_schedules"
+)
+
+// IssueKinds lists every integrity issue kind.
+var IssueKinds = []string{IssueUnresolvedOrigin, IssueUnresolvedDestination, IssueSingleStop, IssueNonMonotonic, IssueStationWithoutSchedules}
+
+// SyncIssue is a data integrity problem found after a sync. Issues about a
+// train name it and its timetable; StationID is the station concerned, if
+// any.
+type SyncIssue struct {
+	Kind       string `json:"kind"`
+	StationID  string `json:"station_id,omitempty"`
+	TrainID    string `json:"train_id,omitempty"`
+	ServiceDay string `json:"service_day,omitempty"`
+	Detail     string `json:"detail"`
+}
+
+// SyncIssueReport is the integrity issues found after a sync run, with a
+// count per kind.
+type SyncIssueReport struct {
+	Run    SyncRun        `json:"run"`
+	Counts map[string]int `json:"counts"`
+	Issues []SyncIssue    `json:"issues"`
+}
+
+// ScheduleDiff lists the trains that changed between two sync runs.
+type ScheduleDiff struct {
+	Since   SyncRun       `json:"since"`
+	Current SyncRun       `json:"current"`
+	Added   []TrainChange `json:"added"`
+	Removed []TrainChange `json:"removed"`
+	Retimed []TrainChange `json:"retimed"`
+}
+
+type TrainChange struct {
+	TrainID    string       `json:"train_id"`
+	ServiceDay string       `json:"service_day"`
+	Line       string       `json:"line"`
+	Route      string       `json:"route"`
+	Stops      []StopChange `json:"stops,omitempty"`
+}
+
+// StopChange is a stop whose departure time changed. Before or After is empty
+// when the train started or stopped calling at the station. Times are
+// HH:MM:SS. ShiftMinutes is how much later the train leaves, negative when
+// earlier, when it calls at the station in both.
+type StopChange struct {
+	StationID    string `json:"station_id"`
+	Before       string `json:"before,omitempty"`
+	After        string `json:"after,omitempty"`
+	ShiftMinutes int    `json:"shift_minutes,omitempty"`
+}
+
+// ScheduleComparison is the ScheduleDiff from RunA to RunB, leaving out the
+// stops re-timed by less than MinShiftMinutes, summarised per line.
+type ScheduleComparison struct {
+	RunA            SyncRun       `json:"run_a"`
+	RunB            SyncRun       `json:"run_b"`
+	MinShiftMinutes int           `json:"min_shift_minutes"`
+	Lines           []LineChanges `json:"lines"`
+	Added           []TrainChange `json:"added"`
+	Removed         []TrainChange `json:"removed"`
+	Retimed         []TrainChange `json:"retimed"`
+}
+
+// LineChanges counts a line's trains in a ScheduleComparison.
+// MaxShiftMinutes is the largest shift, either way, of its re-timed stops.
+type LineChanges struct {
+	Line            string `json:"line"`
+	Added           int    `json:"added"`
+	Removed         int    `json:"removed"`
+	Retimed         int    `json:"retimed"`
+	MaxShiftMinutes int    `json:"max_shift_minutes"`
+}
+
+// RawFetch is an upstream response recorded in capture mode.
+type RawFetch struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// Size is the length of Body, which is only set when a single capture
+	// is read.
+	Size int    `json:"size"`
+	Body []byte `json:"-"`
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// TopicSync carries sync lifecycle events. Completed full syncs carry
+	// their domain.SyncRun.
+	TopicSync = "sync"
+
+	TypeSyncStarted     = "sync.started"
+	TypeSyncCompleted   = "sync.completed"
+	TypeScheduleUpdated = "schedule.updated"
+	TypeTrainPosition   = "train.position"
+
+	// TopicAnomaly carries the anomalies a sync found in the data, as a
+	// []domain.ScheduleAnomaly, once the sync completes.
+	TopicAnomaly = "anomaly"
+
+	TypeScheduleAnomalies = "schedule.anomalies"
+
+	// TopicAnnouncement carries the announcements a poll of an announcement
+	// feed added, altered or ended, as a []domain.Announcement.
+	TopicAnnouncement = "announcement"
+
+	TypeAnnouncementsChanged = "announcements.changed"
+)
+
+// subscriberBuffer is the number of events queued per subscriber before new
+// events are dropped for that subscriber.
+const subscriberBuffer = 32
+
+// StationTopic is the topic for events about a single station.
+func StationTopic(stationID string) string {
+	return "station:" + stationID
+}
+
+// TrainTopic is the topic for events about a single train.
+func TrainTopic(trainID string) string {
+	return "train:" + trainID
+}
+
+type Event struct {
+	Topic string      `json:"topic"`
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data,omitempty"`
+	Time  time.Time   `json:"time"`
+}
+
+// Hub is a small in-process publish/subscribe broker keyed by topic.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe registers interest in the given topics. Events arrive on C until
+// Close is called.
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{
+		C:      ch,
+		ch:     ch,
+		hub:    h,
+		topics: make(map[string]struct{}),
+	}
+	for _, t := range topics {
+		sub.topics[t] = struct{}{}
+	}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Publish delivers e to every subscriber of e.Topic. Slow subscribers whose
+// buffer is full miss the event rather than blocking the publisher.
+func (h *Hub) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subs {
+		if !sub.has(e.Topic) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	hub    *Hub
+	mu     sync.RWMutex
+	topics map[string]struct{}
+}
+
+func (s *Subscription) has(topic string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.topics[topic]
+	return ok
+}
+
+// Add subscribes to additional topics.
+func (s *Subscription) Add(topics ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		s.topics[t] = struct{}{}
+	}
+}
+
+// Remove unsubscribes from the given topics.
+func (s *Subscription) Remove(topics ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		delete(s.topics, t)
+	}
+}
+
+// Topics returns the topics currently subscribed to.
+func (s *Subscription) Topics() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	topics := make([]string, 0, len(s.topics))
+	for t := range s.topics {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.ch)
+	}
+}
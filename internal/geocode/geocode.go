@@ -0,0 +1,54 @@
+// Package geocode resolves free-form addresses to coordinates through a
+// configurable provider.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"llm-router/internal/config"
+)
+
+// ErrNotFound is returned when the provider has no match for a query.
+var ErrNotFound = errors.New("geocode: no match")
+
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Place is a geocoded address.
+type Place struct {
+	Point
+	Name string `json:"name"`
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (Place, error)
+}
+
+// New returns the geocoder selected by cfg, or nil when geocoding is disabled.
+func New(cfg config.GeocoderConfig) (Geocoder, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "nominatim":
+		return newNominatim(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder provider %q", cfg.Provider)
+	}
+}
+
+const earthRadiusMeters = 6371000
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
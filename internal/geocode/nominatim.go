@@ -0,0 +1,140 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/config"
+)
+
+const (
+	// Nominatim's usage policy allows at most one request per second.
+	nominatimInterval = time.Second
+	maxCachedPlaces   = 1000
+)
+
+type nominatim struct {
+	baseURL      string
+	userAgent    string
+	countryCodes string
+	client       *http.Client
+
+	// throttle serialises requests so they are spaced by nominatimInterval.
+	throttle sync.Mutex
+	last     time.Time
+
+	cacheMu sync.RWMutex
+	cache   map[string]Place
+}
+
+func newNominatim(cfg config.GeocoderConfig) *nominatim {
+	return &nominatim{
+		baseURL:      strings.TrimRight(cfg.URL, "/"),
+		userAgent:    cfg.UserAgent,
+		countryCodes: cfg.CountryCodes,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		cache:        make(map[string]Place),
+	}
+}
+
+func (n *nominatim) Geocode(ctx context.Context, query string) (Place, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+	if key == "" {
+		return Place{}, ErrNotFound
+	}
+
+	n.cacheMu.RLock()
+	place, ok := n.cache[key]
+	n.cacheMu.RUnlock()
+	if ok {
+		return place, nil
+	}
+
+	place, err := n.search(ctx, query)
+	if err != nil {
+		return Place{}, err
+	}
+
+	n.cacheMu.Lock()
+	if len(n.cache) >= maxCachedPlaces {
+		n.cache = make(map[string]Place)
+	}
+	n.cache[key] = place
+	n.cacheMu.Unlock()
+	return place, nil
+}
+
+func (n *nominatim) search(ctx context.Context, query string) (Place, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+	if n.countryCodes != "" {
+		params.Set("countrycodes", n.countryCodes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return Place{}, err
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	if err := n.wait(ctx); err != nil {
+		return Place{}, err
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return Place{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Place{}, fmt.Errorf("nominatim: status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Place{}, fmt.Errorf("nominatim: %w", err)
+	}
+	if len(results) == 0 {
+		return Place{}, ErrNotFound
+	}
+
+	lat, errLat := strconv.ParseFloat(results[0].Lat, 64)
+	lng, errLng := strconv.ParseFloat(results[0].Lon, 64)
+	if errLat != nil || errLng != nil {
+		return Place{}, fmt.Errorf("nominatim: invalid coordinates %q,%q", results[0].Lat, results[0].Lon)
+	}
+	return Place{Point: Point{Lat: lat, Lng: lng}, Name: results[0].DisplayName}, nil
+}
+
+// wait blocks until the next request is allowed under the rate limit.
+func (n *nominatim) wait(ctx context.Context) error {
+	n.throttle.Lock()
+	defer n.throttle.Unlock()
+
+	if d := nominatimInterval - time.Since(n.last); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	n.last = time.Now()
+	return nil
+}
@@ -0,0 +1,477 @@
+package geodata
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/geocode"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+)
+
+// brtStopPrefix sets BRT stop IDs apart from the rail station IDs.
+const brtStopPrefix = "TJ-"
+
+// BRTMain runs the import-brt subcommand, which imports TransJakarta
+// corridors from a GTFS feed, such as the one published on the Jakarta open
+// data portal, as a zip file or a directory: `commuter import-brt
+// transjakarta.zip`. BRT stops within walking distance of a rail station
+// with a known location become transfer points.
+func BRTMain(args []string) int {
+	fs := flag.NewFlagSet("import-brt", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Config file (default "+config.DefaultConfigFile+" if present)")
+	dbPath := fs.String("db", "", "SQLite database path")
+	routes := fs.String("routes", "", "Comma-separated route IDs or short names to import (default all)")
+	radius := fs.Float64("transfer-radius", 400, "Furthest straight-line distance in meters between a rail station and a BRT stop to transfer between")
+	dryRun := fs.Bool("dry-run", false, "Parse the feed and report what would be imported without writing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commuter import-brt [flags] FEED")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	feed, closeFeed, err := openFeed(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open feed:", err)
+		return 1
+	}
+	defer closeFeed()
+	var only []string
+	if *routes != "" {
+		only = strings.Split(*routes, ",")
+	}
+	brt, err := ParseGTFS(feed, only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse %s: %v\n", path, err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig(config.Flags{ConfigPath: *configPath, DBPath: *dbPath})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+		return 1
+	}
+	ctx := context.Background()
+	s, err := store.NewStore(ctx, cfg.DBDriver, cfg.DatabaseDSN(), store.Options{Timeout: cfg.DBTimeout})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the database:", err)
+		return 1
+	}
+	defer s.Close()
+
+	rail, err := railLocations(ctx, s)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read station locations:", err)
+		return 1
+	}
+	network := domain.BRTNetwork{
+		Corridors: brt.Corridors,
+		Transfers: Transfers(rail, brt.Locations, *radius),
+	}
+	if len(rail) == 0 {
+		fmt.Fprintln(os.Stderr, "Warning: no rail station has a location, so no transfers were found; run import-geodata first")
+	}
+	for _, c := range brt.Corridors {
+		fmt.Printf("%s (%d): %s, %d stops, every %d min %s-%s\n", c.ID, c.Direction, c.Name, len(c.Stops), c.HeadwayMinutes, c.FirstDeparture, c.LastDeparture)
+	}
+	for _, t := range network.Transfers {
+		fmt.Printf("%s <-> %s: %d min walk\n", t.StationID, t.ToStationID, t.WalkMinutes)
+	}
+	if *dryRun {
+		fmt.Printf("Would import %d corridors, %d stops and %d transfers from %s\n", len(brt.Corridors), len(brt.Stops), len(network.Transfers), path)
+		return 0
+	}
+	if err := s.ImportBRT(ctx, brt.Stops, brt.Locations, network); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to import BRT network:", err)
+		return 1
+	}
+	fmt.Printf("Imported %d corridors, %d stops and %d transfers from %s\n", len(brt.Corridors), len(brt.Stops), len(network.Transfers), path)
+	return 0
+}
+
+// openFeed opens a GTFS feed, a zip file or a directory.
+func openFeed(path string) (fs.FS, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(path), func() error { return nil }, nil
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, zr.Close, nil
+}
+
+// railLocations returns the locations of the stations that are not BRT
+// stops.
+func railLocations(ctx context.Context, s *store.Store) ([]domain.StationLocation, error) {
+	stations, err := s.GetStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rail := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		if st.Type != domain.StationTypeBRT {
+			rail[st.ID] = true
+		}
+	}
+	locs, err := s.GetStationLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(locs, func(loc domain.StationLocation) bool { return !rail[loc.StationID] }), nil
+}
+
+// Transfers pairs every rail station with the BRT stops within radius
+// meters of it, with the time to walk between them.
+func Transfers(rail, stops []domain.StationLocation, radius float64) []domain.Transfer {
+	var transfers []domain.Transfer
+	for _, st := range rail {
+		for _, stop := range stops {
+			d := geocode.Distance(geocode.Point{Lat: st.Lat, Lng: st.Lng}, geocode.Point{Lat: stop.Lat, Lng: stop.Lng})
+			if d > radius {
+				continue
+			}
+			transfers = append(transfers, domain.Transfer{
+				StationID:   st.StationID,
+				ToStationID: stop.StationID,
+				WalkMinutes: max(1, int(journey.WalkDuration(d).Minutes())),
+			})
+		}
+	}
+	return transfers
+}
+
+// BRTFeed is a BRT network read from a GTFS feed: its stops, as stations of
+// type BRT, their locations and its corridors.
+type BRTFeed struct {
+	Stops     []domain.Station
+	Locations []domain.StationLocation
+	Corridors []domain.BRTCorridor
+}
+
+// gtfsTrip is a trip of a GTFS feed, with its stops in sequence.
+type gtfsTrip struct {
+	route     string
+	direction int
+	stops     []gtfsStopTime
+}
+
+type gtfsStopTime struct {
+	seq    int
+	stopID string
+	// secs is the departure, or else the arrival, in seconds after
+	// midnight of the service day.
+	secs int
+}
+
+// ParseGTFS reads the corridors of a GTFS feed, one per route and
+// direction, limited to the routes with IDs or short names in only when it
+// is non-empty. A corridor calls at the stops of its longest trip. Its
+// headway averages the buses its trips run between its first and last
+// departure, from frequencies.txt where the feed has it and from the trips'
+// own times otherwise.
+func ParseGTFS(fsys fs.FS, only []string) (BRTFeed, error) {
+	type route struct{ id, name, color string }
+	routes := make(map[string]route)
+	err := readGTFS(fsys, "routes.txt", []string{"route_id"}, func(field func(string) string) error {
+		id, short := field("route_id"), field("route_short_name")
+		if len(only) > 0 && !slices.Contains(only, id) && !slices.Contains(only, short) {
+			return nil
+		}
+		name := strings.TrimSpace(short + " " + field("route_long_name"))
+		if name == "" {
+			name = id
+		}
+		color := field("route_color")
+		if color != "" {
+			color = "#" + strings.ToUpper(color)
+		}
+		routes[id] = route{id: id, name: name, color: color}
+		return nil
+	})
+	if err != nil {
+		return BRTFeed{}, err
+	}
+	if len(routes) == 0 {
+		return BRTFeed{}, errors.New("no routes to import")
+	}
+
+	trips := make(map[string]*gtfsTrip)
+	err = readGTFS(fsys, "trips.txt", []string{"route_id", "trip_id"}, func(field func(string) string) error {
+		if _, ok := routes[field("route_id")]; !ok {
+			return nil
+		}
+		dir := 0
+		if v := field("direction_id"); v != "" {
+			var err error
+			if dir, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("invalid direction_id %q", v)
+			}
+		}
+		trips[field("trip_id")] = &gtfsTrip{route: field("route_id"), direction: dir}
+		return nil
+	})
+	if err != nil {
+		return BRTFeed{}, err
+	}
+
+	err = readGTFS(fsys, "stop_times.txt", []string{"trip_id", "stop_id", "stop_sequence"}, func(field func(string) string) error {
+		trip, ok := trips[field("trip_id")]
+		if !ok {
+			return nil
+		}
+		seq, err := strconv.Atoi(field("stop_sequence"))
+		if err != nil {
+			return fmt.Errorf("invalid stop_sequence %q", field("stop_sequence"))
+		}
+		raw := field("departure_time")
+		if raw == "" {
+			raw = field("arrival_time")
+		}
+		secs, err := gtfsTime(raw)
+		if err != nil {
+			return err
+		}
+		trip.stops = append(trip.stops, gtfsStopTime{seq: seq, stopID: field("stop_id"), secs: secs})
+		return nil
+	})
+	if err != nil {
+		return BRTFeed{}, err
+	}
+
+	// frequencies are the windows trips repeat in: start, end and headway in
+	// seconds.
+	frequencies := make(map[string][][3]int)
+	err = readGTFS(fsys, "frequencies.txt", []string{"trip_id", "start_time", "end_time", "headway_secs"}, func(field func(string) string) error {
+		if _, ok := trips[field("trip_id")]; !ok {
+			return nil
+		}
+		start, err := gtfsTime(field("start_time"))
+		if err != nil {
+			return err
+		}
+		end, err := gtfsTime(field("end_time"))
+		if err != nil {
+			return err
+		}
+		headway, err := strconv.Atoi(field("headway_secs"))
+		if err != nil || headway <= 0 {
+			return fmt.Errorf("invalid headway_secs %q", field("headway_secs"))
+		}
+		frequencies[field("trip_id")] = append(frequencies[field("trip_id")], [3]int{start, end, headway})
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return BRTFeed{}, err
+	}
+
+	// service collects the departures of each corridor from its first stop:
+	// the first and last, and how many buses run between them.
+	type service struct {
+		pattern     *gtfsTrip
+		first, last int
+		buses       int
+	}
+	services := make(map[[2]string]*service)
+	var keys [][2]string
+	tripIDs := make([]string, 0, len(trips))
+	for id := range trips {
+		tripIDs = append(tripIDs, id)
+	}
+	sort.Strings(tripIDs)
+	for _, id := range tripIDs {
+		trip := trips[id]
+		if len(trip.stops) < 2 {
+			continue
+		}
+		sort.Slice(trip.stops, func(i, j int) bool { return trip.stops[i].seq < trip.stops[j].seq })
+		key := [2]string{trip.route, strconv.Itoa(trip.direction)}
+		svc, ok := services[key]
+		if !ok {
+			svc = &service{pattern: trip, first: math.MaxInt, last: -1}
+			services[key] = svc
+			keys = append(keys, key)
+		}
+		if len(trip.stops) > len(svc.pattern.stops) {
+			svc.pattern = trip
+		}
+
+		start := trip.stops[0].secs
+		windows := frequencies[id]
+		if len(windows) == 0 {
+			windows = [][3]int{{start, start, 1}}
+		}
+		for _, w := range windows {
+			n := (w[1]-w[0])/w[2] + 1
+			svc.buses += n
+			svc.first = min(svc.first, w[0])
+			svc.last = max(svc.last, w[0]+(n-1)*w[2])
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	used := make(map[string]bool)
+	var feed BRTFeed
+	for _, key := range keys {
+		svc, r := services[key], routes[key[0]]
+		dir, _ := strconv.Atoi(key[1])
+		headway := 0
+		if svc.buses > 1 {
+			headway = int(math.Round(float64(svc.last-svc.first) / float64(svc.buses-1) / 60))
+		}
+		c := domain.BRTCorridor{
+			ID:             r.id,
+			Direction:      dir,
+			Name:           r.name,
+			Color:          r.color,
+			HeadwayMinutes: max(1, headway),
+			FirstDeparture: clockOf(svc.first),
+			LastDeparture:  clockOf(svc.last),
+		}
+		origin := svc.pattern.stops[0].secs
+		for _, st := range svc.pattern.stops {
+			id := brtStopPrefix + strings.ToUpper(st.stopID)
+			c.Stops = append(c.Stops, domain.BRTStop{StationID: id, Minutes: (st.secs - origin) / 60})
+			used[st.stopID] = true
+		}
+		feed.Corridors = append(feed.Corridors, c)
+	}
+
+	err = readGTFS(fsys, "stops.txt", []string{"stop_id", "stop_name", "stop_lat", "stop_lon"}, func(field func(string) string) error {
+		if !used[field("stop_id")] {
+			return nil
+		}
+		id := brtStopPrefix + strings.ToUpper(field("stop_id"))
+		lat, err := strconv.ParseFloat(field("stop_lat"), 64)
+		if err != nil {
+			return fmt.Errorf("stop %s: invalid stop_lat: %w", id, err)
+		}
+		lng, err := strconv.ParseFloat(field("stop_lon"), 64)
+		if err != nil {
+			return fmt.Errorf("stop %s: invalid stop_lon: %w", id, err)
+		}
+		feed.Stops = append(feed.Stops, domain.Station{
+			UID:      "st_brt_" + strings.ToLower(field("stop_id")),
+			ID:       id,
+			Name:     strings.ToUpper(field("stop_name")),
+			Type:     domain.StationTypeBRT,
+			Metadata: domain.Metadata{Active: true},
+		})
+		feed.Locations = append(feed.Locations, domain.StationLocation{StationID: id, Lat: lat, Lng: lng})
+		delete(used, field("stop_id"))
+		return nil
+	})
+	if err != nil {
+		return BRTFeed{}, err
+	}
+	if len(used) > 0 {
+		missing := make([]string, 0, len(used))
+		for id := range used {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return BRTFeed{}, fmt.Errorf("stops.txt: stops %s are missing", strings.Join(missing, ", "))
+	}
+	return feed, nil
+}
+
+// readGTFS calls row with a field lookup for every row of the named file of
+// a GTFS feed, whose header must have the required columns.
+func readGTFS(fsys fs.FS, name string, required []string, row func(field func(string) string) error) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%s: read header: %w", name, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, col := range header {
+		// Feeds exported from spreadsheets may start with a byte order mark.
+		cols[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(col)), "\ufeff")] = i
+	}
+	for _, col := range required {
+		if _, ok := cols[col]; !ok {
+			return fmt.Errorf("%s: header has no %s column", name, col)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		field := func(col string) string {
+			if i, ok := cols[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if err := row(field); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+}
+
+// gtfsTime parses a GTFS time, H:MM:SS with hours past 24 for times after
+// midnight, into seconds.
+func gtfsTime(raw string) (int, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q, expected H:MM:SS", raw)
+	}
+	var secs int
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid time %q, expected H:MM:SS", raw)
+		}
+		secs = secs*60 + n
+	}
+	return secs, nil
+}
+
+// clockOf formats seconds as HH:MM, with hours past 24 for times after
+// midnight.
+func clockOf(secs int) string {
+	return fmt.Sprintf("%02d:%02d", secs/3600, secs/60%60)
+}
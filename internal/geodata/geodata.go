@@ -0,0 +1,266 @@
+// Package geodata imports station coordinates, addresses and municipalities
+// from a GeoJSON or CSV file into the station locations, merged by station
+// ID: `commuter import-geodata stations.geojson`.
+package geodata
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// File formats.
+const (
+	FormatGeoJSON = "geojson"
+	FormatCSV     = "csv"
+)
+
+// Main runs the import-geodata subcommand with the given arguments and
+// returns the process exit code.
+func Main(args []string) int {
+	fs := flag.NewFlagSet("import-geodata", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Config file (default "+config.DefaultConfigFile+" if present)")
+	dbPath := fs.String("db", "", "SQLite database path")
+	format := fs.String("format", "", "File format, geojson or csv (default from the file extension)")
+	dryRun := fs.Bool("dry-run", false, "Parse the file and report what would be imported without writing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commuter import-geodata [flags] FILE")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = formatOf(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open geodata:", err)
+		return 1
+	}
+	defer f.Close()
+	locs, err := Parse(f, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse %s: %v\n", path, err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig(config.Flags{ConfigPath: *configPath, DBPath: *dbPath})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+		return 1
+	}
+	ctx := context.Background()
+	s, err := store.NewStore(ctx, cfg.DBDriver, cfg.DatabaseDSN(), store.Options{Timeout: cfg.DBTimeout})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the database:", err)
+		return 1
+	}
+	defer s.Close()
+
+	unknown, err := unknownStations(ctx, s, locs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read stations:", err)
+		return 1
+	}
+	if len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d station IDs are not in the station list: %s\n", len(unknown), strings.Join(unknown, ", "))
+	}
+	if *dryRun {
+		fmt.Printf("Would import %d station locations from %s\n", len(locs), path)
+		return 0
+	}
+	if err := s.ImportStationLocations(ctx, locs); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to import geodata:", err)
+		return 1
+	}
+	fmt.Printf("Imported %d station locations from %s\n", len(locs), path)
+	return 0
+}
+
+func formatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatGeoJSON
+}
+
+// unknownStations lists the station IDs of locs that are not in the station
+// list. Their locations are still imported, for stations not synced yet.
+func unknownStations(ctx context.Context, s *store.Store, locs []domain.StationLocation) ([]string, error) {
+	stations, err := s.GetStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		known[st.ID] = true
+	}
+	var unknown []string
+	for _, loc := range locs {
+		if !known[loc.StationID] {
+			unknown = append(unknown, loc.StationID)
+		}
+	}
+	return unknown, nil
+}
+
+// Parse reads station locations from r in format, FormatGeoJSON or
+// FormatCSV. Station IDs are upper-cased, as the upstream lists them, and
+// may appear only once.
+func Parse(r io.Reader, format string) ([]domain.StationLocation, error) {
+	var locs []domain.StationLocation
+	var err error
+	switch format {
+	case FormatGeoJSON:
+		locs, err = parseGeoJSON(r)
+	case FormatCSV:
+		locs, err = parseCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q: must be %s or %s", format, FormatGeoJSON, FormatCSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(locs))
+	for i, loc := range locs {
+		if err := validate(loc); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if seen[loc.StationID] {
+			return nil, fmt.Errorf("record %d: station %s is listed twice", i+1, loc.StationID)
+		}
+		seen[loc.StationID] = true
+	}
+	return locs, nil
+}
+
+func validate(loc domain.StationLocation) error {
+	switch {
+	case loc.StationID == "":
+		return errors.New("station ID is missing")
+	case loc.Lat < -90 || loc.Lat > 90 || loc.Lng < -180 || loc.Lng > 180:
+		return fmt.Errorf("station %s: coordinates %g,%g are out of range", loc.StationID, loc.Lat, loc.Lng)
+	}
+	return nil
+}
+
+// featureCollection is a GeoJSON FeatureCollection of Points whose
+// properties name the station ("id" or "station_id") and optionally its
+// "address" and "municipality".
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Type string `json:"type"`
+			// Coordinates are longitude first, as GeoJSON orders them.
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			ID           string `json:"id"`
+			StationID    string `json:"station_id"`
+			Address      string `json:"address"`
+			Municipality string `json:"municipality"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+func parseGeoJSON(r io.Reader) ([]domain.StationLocation, error) {
+	var fc featureCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a FeatureCollection, got %q", fc.Type)
+	}
+
+	locs := make([]domain.StationLocation, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		if f.Geometry.Type != "Point" || len(f.Geometry.Coordinates) < 2 {
+			return nil, fmt.Errorf("feature %d: geometry must be a Point", i+1)
+		}
+		id := f.Properties.StationID
+		if id == "" {
+			id = f.Properties.ID
+		}
+		locs = append(locs, domain.StationLocation{
+			StationID:    strings.ToUpper(strings.TrimSpace(id)),
+			Lat:          f.Geometry.Coordinates[1],
+			Lng:          f.Geometry.Coordinates[0],
+			Address:      strings.TrimSpace(f.Properties.Address),
+			Municipality: strings.TrimSpace(f.Properties.Municipality),
+		})
+	}
+	return locs, nil
+}
+
+// parseCSV reads a CSV file whose header names the columns station_id, lat
+// and lng, and optionally address and municipality, in any order.
+func parseCSV(r io.Reader) ([]domain.StationLocation, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"station_id", "lat", "lng"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("header has no %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var locs []domain.StationLocation
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return locs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		lat, err := strconv.ParseFloat(field(record, "lat"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid lat: %w", line, err)
+		}
+		lng, err := strconv.ParseFloat(field(record, "lng"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid lng: %w", line, err)
+		}
+		locs = append(locs, domain.StationLocation{
+			StationID:    strings.ToUpper(field(record, "station_id")),
+			Lat:          lat,
+			Lng:          lng,
+			Address:      field(record, "address"),
+			Municipality: field(record, "municipality"),
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package geodata
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// ShapesMain runs the import-shapes subcommand, which imports the rail
+// alignment of lines from a GeoJSON file, such as an Overpass export of
+// OpenStreetMap route relations: `commuter import-shapes lines.geojson`.
+func ShapesMain(args []string) int {
+	fs := flag.NewFlagSet("import-shapes", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Config file (default "+config.DefaultConfigFile+" if present)")
+	dbPath := fs.String("db", "", "SQLite database path")
+	dryRun := fs.Bool("dry-run", false, "Parse the file and report what would be imported without writing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: commuter import-shapes [flags] FILE")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open shapes:", err)
+		return 1
+	}
+	defer f.Close()
+	shapes, err := ParseShapes(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse %s: %v\n", path, err)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig(config.Flags{ConfigPath: *configPath, DBPath: *dbPath})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load configuration:", err)
+		return 1
+	}
+	ctx := context.Background()
+	s, err := store.NewStore(ctx, cfg.DBDriver, cfg.DatabaseDSN(), store.Options{Timeout: cfg.DBTimeout})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open the database:", err)
+		return 1
+	}
+	defer s.Close()
+
+	unknown, err := unknownLines(ctx, s, shapes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read lines:", err)
+		return 1
+	}
+	if len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d lines are not in the line list: %s\n", len(unknown), strings.Join(unknown, ", "))
+	}
+	for _, shape := range shapes {
+		points := 0
+		for _, p := range shape.Paths {
+			points += len(p)
+		}
+		fmt.Printf("%s: %d paths, %d points\n", shape.Line, len(shape.Paths), points)
+	}
+	if *dryRun {
+		fmt.Printf("Would import %d line shapes from %s\n", len(shapes), path)
+		return 0
+	}
+	if err := s.ImportLineShapes(ctx, shapes); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to import shapes:", err)
+		return 1
+	}
+	fmt.Printf("Imported %d line shapes from %s\n", len(shapes), path)
+	return 0
+}
+
+// unknownLines lists the shapes' lines that are not in the line list. Line
+// names are matched regardless of case, as the API looks them up.
+func unknownLines(ctx context.Context, s *store.Store, shapes []domain.LineShape) ([]string, error) {
+	lines, err := s.GetLines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		known[strings.ToLower(l.Name)] = true
+	}
+	var unknown []string
+	for _, shape := range shapes {
+		if !known[strings.ToLower(shape.Line)] {
+			unknown = append(unknown, shape.Line)
+		}
+	}
+	return unknown, nil
+}
+
+// shapeCollection is a GeoJSON FeatureCollection of LineStrings and
+// MultiLineStrings whose properties name the line they belong to ("line",
+// or "name" as OpenStreetMap route relations have it). A line may span
+// several features, such as the individual ways of a relation.
+type shapeCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			Line string `json:"line"`
+			Name string `json:"name"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// ParseShapes reads line shapes from a GeoJSON file. The features of each
+// line are joined where their ends meet, so a line is stored as few
+// continuous paths as its data allows. Features of other geometries, such
+// as the stop positions in a route relation, are skipped.
+func ParseShapes(r io.Reader) ([]domain.LineShape, error) {
+	var fc shapeCollection
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a FeatureCollection, got %q", fc.Type)
+	}
+
+	paths := make(map[string][][]domain.LatLng)
+	var order []string
+	for i, f := range fc.Features {
+		var lines [][][]float64
+		switch f.Geometry.Type {
+		case "LineString":
+			var coords [][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i+1, err)
+			}
+			lines = [][][]float64{coords}
+		case "MultiLineString":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &lines); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i+1, err)
+			}
+		default:
+			continue
+		}
+
+		name := strings.TrimSpace(f.Properties.Line)
+		if name == "" {
+			name = strings.TrimSpace(f.Properties.Name)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("feature %d: line name is missing", i+1)
+		}
+		if _, ok := paths[name]; !ok {
+			order = append(order, name)
+		}
+		for _, coords := range lines {
+			path := make([]domain.LatLng, 0, len(coords))
+			for _, c := range coords {
+				if len(c) < 2 {
+					return nil, fmt.Errorf("feature %d: coordinates need a longitude and latitude", i+1)
+				}
+				// GeoJSON orders coordinates longitude first.
+				p := domain.LatLng{Lat: c[1], Lng: c[0]}
+				if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+					return nil, fmt.Errorf("feature %d: coordinates %g,%g are out of range", i+1, p.Lat, p.Lng)
+				}
+				path = append(path, p)
+			}
+			if len(path) >= 2 {
+				paths[name] = append(paths[name], path)
+			}
+		}
+	}
+
+	shapes := make([]domain.LineShape, 0, len(order))
+	for _, name := range order {
+		if len(paths[name]) == 0 {
+			continue
+		}
+		shapes = append(shapes, domain.LineShape{Line: name, Paths: stitch(paths[name])})
+	}
+	return shapes, nil
+}
+
+// stitch joins paths that share an end point, reversing them as needed,
+// until no two do. Where more than two paths meet, as at a junction, the
+// branches left over stay separate. The longest paths come first.
+func stitch(paths [][]domain.LatLng) [][]domain.LatLng {
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(paths) && !merged; i++ {
+			for j := i + 1; j < len(paths); j++ {
+				if joined, ok := join(paths[i], paths[j]); ok {
+					paths[i] = joined
+					paths = append(paths[:j], paths[j+1:]...)
+					merged = true
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	return paths
+}
+
+// join returns a and b as one path when an end of one is an end of the
+// other.
+func join(a, b []domain.LatLng) ([]domain.LatLng, bool) {
+	switch {
+	case a[len(a)-1] == b[0]:
+		return append(a, b[1:]...), true
+	case a[len(a)-1] == b[len(b)-1]:
+		return append(a, reversed(b)[1:]...), true
+	case a[0] == b[len(b)-1]:
+		return append(b, a[1:]...), true
+	case a[0] == b[0]:
+		return append(reversed(b), a[1:]...), true
+	}
+	return nil, false
+}
+
+func reversed(path []domain.LatLng) []domain.LatLng {
+	out := make([]domain.LatLng, len(path))
+	for i, p := range path {
+		out[len(path)-1-i] = p
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package geodata
+
+import (
+	"math"
+	"strings"
+
+	"llm-router/internal/domain"
+)
+
+// maxSnapMeters is how far from a line's track a station may lie and still
+// be placed on it.
+const maxSnapMeters = 300
+
+// Track returns the stretch of shape's track between two stations, from
+// from to to, or false when no path of the shape passes within reach of
+// both.
+func Track(shape domain.LineShape, from, to domain.LatLng) ([]domain.LatLng, bool) {
+	var best []domain.LatLng
+	bestDist := math.Inf(1)
+	for _, path := range shape.Paths {
+		a, da := project(path, from)
+		b, db := project(path, to)
+		if da > maxSnapMeters || db > maxSnapMeters || da+db >= bestDist || a == b {
+			continue
+		}
+		best, bestDist = slice(path, a, b, from, to), da+db
+	}
+	return best, best != nil
+}
+
+// project returns where on path the point nearest to p lies, as the index
+// of its segment plus the fraction along it, and its distance in meters.
+func project(path []domain.LatLng, p domain.LatLng) (float64, float64) {
+	best, bestDist := 0.0, math.Inf(1)
+	px, py := plane(p, p.Lat)
+	for i := 0; i+1 < len(path); i++ {
+		ax, ay := plane(path[i], p.Lat)
+		bx, by := plane(path[i+1], p.Lat)
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/l))
+		}
+		if d := math.Hypot(ax+t*dx-px, ay+t*dy-py); d < bestDist {
+			best, bestDist = float64(i)+t, d
+		}
+	}
+	return best, bestDist
+}
+
+// slice returns the part of path from a to b, which runs backwards along
+// the path when b comes before a. The ends are the stations themselves.
+func slice(path []domain.LatLng, a, b float64, from, to domain.LatLng) []domain.LatLng {
+	out := []domain.LatLng{from}
+	if a < b {
+		for i := int(math.Floor(a)) + 1; float64(i) < b; i++ {
+			out = append(out, path[i])
+		}
+	} else {
+		for i := int(math.Ceil(a)) - 1; float64(i) > b; i-- {
+			out = append(out, path[i])
+		}
+	}
+	return append(out, to)
+}
+
+// Along returns the point fraction (0-1) of the way along path by distance,
+// and the heading there in degrees clockwise from north.
+func Along(path []domain.LatLng, fraction float64) (domain.LatLng, float64) {
+	if len(path) == 1 {
+		return path[0], 0
+	}
+	lat0 := path[0].Lat
+	lengths := make([]float64, len(path)-1)
+	total := 0.0
+	for i := range lengths {
+		ax, ay := plane(path[i], lat0)
+		bx, by := plane(path[i+1], lat0)
+		lengths[i] = math.Hypot(bx-ax, by-ay)
+		total += lengths[i]
+	}
+
+	left := math.Max(0, math.Min(1, fraction)) * total
+	i := 0
+	for ; i < len(lengths)-1 && left > lengths[i]; i++ {
+		left -= lengths[i]
+	}
+	t := 0.0
+	if lengths[i] > 0 {
+		t = math.Min(1, left/lengths[i])
+	}
+	a, b := path[i], path[i+1]
+	p := domain.LatLng{Lat: a.Lat + t*(b.Lat-a.Lat), Lng: a.Lng + t*(b.Lng-a.Lng)}
+
+	ax, ay := plane(a, lat0)
+	bx, by := plane(b, lat0)
+	heading := math.Atan2(bx-ax, by-ay) * 180 / math.Pi
+	if heading < 0 {
+		heading += 360
+	}
+	return p, heading
+}
+
+// plane maps p to meters on a plane tangent at latitude lat0, which is
+// accurate enough over the length of a line.
+func plane(p domain.LatLng, lat0 float64) (x, y float64) {
+	const metersPerDegree = 111320
+	return p.Lng * metersPerDegree * math.Cos(lat0*math.Pi/180), p.Lat * metersPerDegree
+}
+
+// EncodePolyline encodes points in Google's encoded polyline format with
+// five decimal places, as map libraries decode it.
+func EncodePolyline(points []domain.LatLng) string {
+	var b strings.Builder
+	var lastLat, lastLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * 1e5))
+		lng := int64(math.Round(p.Lng * 1e5))
+		encodeValue(&b, lat-lastLat)
+		encodeValue(&b, lng-lastLng)
+		lastLat, lastLng = lat, lng
+	}
+	return b.String()
+}
+
+func encodeValue(b *strings.Builder, v int64) {
+	u := v << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		b.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	b.WriteByte(byte(u + 63))
+}
@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/protoconv"
+	"llm-router/internal/scrapper"
+	commuterv1 "llm-router/proto/commuter/v1"
+)
+
+func syncStatusProto(st scrapper.SyncStatus) *commuterv1.SyncStatus {
+	resp := &commuterv1.SyncStatus{
+		Running:          st.Running,
+		LastStartedAt:    protoconv.TimestampPtr(st.LastStartedAt),
+		LastFinishedAt:   protoconv.TimestampPtr(st.LastFinishedAt),
+		RunId:            st.RunID,
+		Aborted:          st.Aborted,
+		LastError:        st.LastError,
+		NextSyncAt:       protoconv.Timestamp(st.NextSyncAt),
+		FailedStationIds: make([]string, 0, len(st.FailedStations)),
+	}
+	for _, f := range st.FailedStations {
+		resp.FailedStationIds = append(resp.FailedStationIds, f.StationID)
+	}
+	return resp
+}
+
+func trainPositionProto(p domain.TrainPosition) *commuterv1.TrainPosition {
+	return &commuterv1.TrainPosition{
+		TrainId:       p.TrainID,
+		StationId:     p.StationID,
+		NextStationId: p.NextStationID,
+		Latitude:      p.Latitude,
+		Longitude:     p.Longitude,
+		DelayMinutes:  int32(p.DelayMinutes),
+		Status:        p.Status,
+		ObservedAt:    protoconv.Timestamp(p.ObservedAt),
+	}
+}
+
+// scheduleUpdateProto converts a schedule.updated event, whose data carries
+// the station and its departure count.
+func scheduleUpdateProto(e events.Event) *commuterv1.ScheduleUpdate {
+	update := &commuterv1.ScheduleUpdate{Time: protoconv.Timestamp(e.Time)}
+	if data, ok := e.Data.(map[string]interface{}); ok {
+		update.StationId, _ = data["station_id"].(string)
+		if n, ok := data["count"].(int); ok {
+			update.Count = int32(n)
+		}
+	}
+	return update
+}
+
+func syncEventProto(e events.Event) *commuterv1.SyncEvent {
+	t := commuterv1.SyncEventType_SYNC_EVENT_TYPE_UNSPECIFIED
+	switch e.Type {
+	case events.TypeSyncStarted:
+		t = commuterv1.SyncEventType_SYNC_EVENT_TYPE_STARTED
+	case events.TypeSyncCompleted:
+		t = commuterv1.SyncEventType_SYNC_EVENT_TYPE_COMPLETED
+	}
+	return &commuterv1.SyncEvent{Type: t, Time: protoconv.Timestamp(e.Time)}
+}
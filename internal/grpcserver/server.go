@@ -0,0 +1,321 @@
+// Package grpcserver serves the commuter.v1 gRPC API, which exposes the same
+// stations, timetables and sync control as the JSON API to backend services,
+// plus streaming RPCs fed by the event hub.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/protoconv"
+	"llm-router/internal/scrapper"
+	"llm-router/internal/store"
+	"llm-router/internal/timetable"
+	commuterv1 "llm-router/proto/commuter/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements commuterv1.CommuterServiceServer.
+type Server struct {
+	commuterv1.UnimplementedCommuterServiceServer
+
+	addr      string
+	store     *store.Store
+	scraper   *scrapper.Scraper
+	events    *events.Hub
+	timetable *timetable.Reader
+	isAdmin   func(token string) bool
+	logger    *zap.Logger
+	grpc      *grpc.Server
+
+	// done ends open streams when the app shuts down, since GracefulStop
+	// waits for them to return.
+	done <-chan struct{}
+}
+
+// New returns a server for port. Timetables are read through tt, shared with
+// the JSON API, and isAdmin checks the token admin RPCs are called with.
+// Requests are logged when logRequests is set, like the HTTP access log.
+func New(port int, s *store.Store, scr *scrapper.Scraper, hub *events.Hub, tt *timetable.Reader, isAdmin func(token string) bool, logRequests bool, logger *zap.Logger) *Server {
+	srv := &Server{
+		addr:      fmt.Sprintf(":%d", port),
+		store:     s,
+		scraper:   scr,
+		events:    hub,
+		timetable: tt,
+		isAdmin:   isAdmin,
+		logger:    logger,
+	}
+
+	var opts []grpc.ServerOption
+	if logRequests {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(srv.logUnary),
+			grpc.ChainStreamInterceptor(srv.logStream),
+		)
+	}
+	srv.grpc = grpc.NewServer(opts...)
+	commuterv1.RegisterCommuterServiceServer(srv.grpc, srv)
+	reflection.Register(srv.grpc)
+	return srv
+}
+
+// Start binds the port and serves in the background. Streams end when ctx
+// is done; serving errors after the start are reported to fail.
+func (s *Server) Start(ctx context.Context, fail func(error)) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.done = ctx.Done()
+
+	s.logger.Info("Server listening", zap.String("server", "grpc server"), zap.String("address", s.addr))
+	go func() {
+		if err := s.grpc.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			fail(fmt.Errorf("grpc server: %w", err))
+		}
+	}()
+	return nil
+}
+
+// Stop waits for RPCs in flight, or closes their connections once ctx
+// expires.
+func (s *Server) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.logRPC(info.FullMethod, start, err)
+	return resp, err
+}
+
+func (s *Server) logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.logRPC(info.FullMethod, start, err)
+	return err
+}
+
+func (s *Server) logRPC(method string, start time.Time, err error) {
+	s.logger.Info("RPC",
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("latency", time.Since(start)),
+	)
+}
+
+// storeError maps a store error to a gRPC status, logging unexpected ones.
+func (s *Server) storeError(err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return status.Error(codes.NotFound, "not found")
+	}
+	s.logger.Error("Store request failed", zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// requireAdmin checks the admin token sent as "authorization: Bearer"
+// metadata, like the admin endpoints of the JSON API.
+func (s *Server) requireAdmin(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok && s.isAdmin(token) {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "admin token required")
+}
+
+// serviceDate parses date, a YYYY-MM-DD day in Jakarta, or returns the
+// service date running now when empty.
+func (s *Server) serviceDate(date string) (time.Time, error) {
+	if date == "" {
+		return s.timetable.OperatingDate(), nil
+	}
+	t, err := calendar.ParseDate(date)
+	if err != nil {
+		return time.Time{}, status.Error(codes.InvalidArgument, "invalid date, expected YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+func (s *Server) ListStations(ctx context.Context, req *commuterv1.ListStationsRequest) (*commuterv1.ListStationsResponse, error) {
+	stations, err := s.store.GetStations(ctx)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	resp := &commuterv1.ListStationsResponse{Stations: make([]*commuterv1.Station, 0, len(stations))}
+	for _, st := range stations {
+		resp.Stations = append(resp.Stations, protoconv.Station(st))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetStation(ctx context.Context, req *commuterv1.GetStationRequest) (*commuterv1.Station, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	st, err := s.store.GetStation(ctx, req.GetId())
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	return protoconv.Station(st), nil
+}
+
+func (s *Server) ListSchedules(ctx context.Context, req *commuterv1.ListSchedulesRequest) (*commuterv1.ListSchedulesResponse, error) {
+	if req.GetStationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "station_id is required")
+	}
+	for _, t := range req.GetServiceTypes() {
+		if !slices.Contains(domain.ServiceTypes, t) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown service type %q, expected one of %s", t, strings.Join(domain.ServiceTypes, ", "))
+		}
+	}
+	date, err := s.serviceDate(req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.GetStation(ctx, req.GetStationId()); err != nil {
+		return nil, s.storeError(err)
+	}
+	schedules, err := s.timetable.DatedBoard(ctx, req.GetStationId(), date)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	resp := &commuterv1.ListSchedulesResponse{Schedules: []*commuterv1.Schedule{}}
+	for _, sch := range schedules {
+		if len(req.GetServiceTypes()) > 0 && !slices.Contains(req.GetServiceTypes(), sch.ServiceType) {
+			continue
+		}
+		resp.Schedules = append(resp.Schedules, protoconv.Schedule(sch))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetRoute(ctx context.Context, req *commuterv1.GetRouteRequest) (*commuterv1.Route, error) {
+	if req.GetTrainId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "train_id is required")
+	}
+	date, err := s.serviceDate(req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.timetable.DatedRoute(ctx, req.GetTrainId(), date)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	if from := req.GetFromStationId(); from != "" {
+		// The route may be cached, so the boarding stop is marked on a copy.
+		data.Routes = slices.Clone(data.Routes)
+		i := slices.IndexFunc(data.Routes, func(stop domain.RouteStop) bool { return stop.StationID == from })
+		if i < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "station %q is not on the route of train %s", from, req.GetTrainId())
+		}
+		data.Routes[i].Boarding = true
+	}
+	return protoconv.Route(data), nil
+}
+
+func (s *Server) GetSyncStatus(ctx context.Context, req *commuterv1.GetSyncStatusRequest) (*commuterv1.SyncStatus, error) {
+	return syncStatusProto(s.scraper.Status()), nil
+}
+
+func (s *Server) TriggerSync(ctx context.Context, req *commuterv1.TriggerSyncRequest) (*commuterv1.TriggerSyncResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	go s.scraper.SyncAll(domain.SyncTriggerGRPC)
+	return &commuterv1.TriggerSyncResponse{}, nil
+}
+
+func (s *Server) WatchSchedules(req *commuterv1.WatchSchedulesRequest, stream grpc.ServerStreamingServer[commuterv1.ScheduleUpdate]) error {
+	if len(req.GetStationIds()) == 0 {
+		return status.Error(codes.InvalidArgument, "station_ids is required")
+	}
+	topics := make([]string, 0, len(req.GetStationIds()))
+	for _, id := range req.GetStationIds() {
+		topics = append(topics, events.StationTopic(id))
+	}
+	return s.watch(stream.Context(), topics, func(e events.Event) error {
+		if e.Type != events.TypeScheduleUpdated {
+			return nil
+		}
+		return stream.Send(scheduleUpdateProto(e))
+	})
+}
+
+func (s *Server) WatchTrainPositions(req *commuterv1.WatchTrainPositionsRequest, stream grpc.ServerStreamingServer[commuterv1.TrainPosition]) error {
+	if len(req.GetTrainIds()) == 0 {
+		return status.Error(codes.InvalidArgument, "train_ids is required")
+	}
+	topics := make([]string, 0, len(req.GetTrainIds()))
+	for _, id := range req.GetTrainIds() {
+		topics = append(topics, events.TrainTopic(id))
+	}
+	return s.watch(stream.Context(), topics, func(e events.Event) error {
+		p, ok := e.Data.(domain.TrainPosition)
+		if e.Type != events.TypeTrainPosition || !ok {
+			return nil
+		}
+		return stream.Send(trainPositionProto(p))
+	})
+}
+
+func (s *Server) WatchSync(req *commuterv1.WatchSyncRequest, stream grpc.ServerStreamingServer[commuterv1.SyncEvent]) error {
+	return s.watch(stream.Context(), []string{events.TopicSync}, func(e events.Event) error {
+		return stream.Send(syncEventProto(e))
+	})
+}
+
+// watch passes events on topics to send until the client goes away, send
+// fails or the server shuts down.
+func (s *Server) watch(ctx context.Context, topics []string, send func(events.Event) error) error {
+	sub := s.events.Subscribe(topics...)
+	defer sub.Close()
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
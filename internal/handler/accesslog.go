@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// AccessLogMiddleware logs the method, path, status, latency, size and client
+// IP of every API request. Successful requests to sampled routes are logged
+// at the configured rate; failures always are. It returns next unchanged when
+// the access log is disabled.
+func AccessLogMiddleware(cfg config.AccessLogConfig, trustForwardedFor bool, next http.Handler, logger *zap.Logger) http.Handler {
+	if !cfg.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := utils.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		if rec.StatusCode < http.StatusBadRequest {
+			if rate := cfg.SampleRate(r.URL.Path); rate < 1 && rand.Float64() >= rate {
+				return
+			}
+		}
+		logger.Info("Request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rec.StatusCode),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", rec.Size),
+			zap.String("client_ip", clientKey(r, trustForwardedFor)),
+			zap.String("request_id", RequestID(r.Context())),
+		)
+	})
+}
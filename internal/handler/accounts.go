@@ -0,0 +1,461 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/mailer"
+	"llm-router/internal/store"
+	"llm-router/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxAccountBody     = 4 * 1024
+	maxFavorites       = 50
+	maxCommutes        = 20
+	maxCommuteNameLen  = 80
+	defaultCommuteNext = 3
+)
+
+// userHandlerFunc is a handler for a signed-in user.
+type userHandlerFunc func(w http.ResponseWriter, r *http.Request, u domain.User)
+
+// accountsEnabled reports whether user accounts are enabled; when they are
+// not, their endpoints do not exist.
+func (router *Router) accountsEnabled(w http.ResponseWriter, r *http.Request) bool {
+	if !router.Config.Accounts.Enabled || router.Mailer == nil {
+		http.NotFound(w, r)
+		return false
+	}
+	return true
+}
+
+// RequireUser guards account endpoints with a session token.
+func (router *Router) RequireUser(next userHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !router.accountsEnabled(w, r) {
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="user"`)
+			writeError(w, r, http.StatusUnauthorized, "session_required")
+			return
+		}
+		u, err := router.Store.SessionUser(r.Context(), token)
+		if errors.Is(err, store.ErrNotFound) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="user", error="invalid_token"`)
+			writeError(w, r, http.StatusUnauthorized, "session_required")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		next(w, r, u)
+	}
+}
+
+// HandleLogin serves POST /api/v1/auth/login with {"email": ...}, which
+// emails a sign-in link. It answers the same whether or not the address has
+// signed in before.
+func (router *Router) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if !router.accountsEnabled(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBody)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	addr, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil || addr.Name != "" {
+		writeError(w, r, http.StatusBadRequest, "email_invalid")
+		return
+	}
+	email := strings.ToLower(addr.Address)
+
+	token, err := utils.GenerateStrongAPIKey()
+	if err != nil {
+		router.log(r).Error("Failed to generate login token", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "login_failed")
+		return
+	}
+	cfg := router.Config.Accounts
+	if err := router.Store.CreateLoginToken(r.Context(), email, token, time.Now().Add(cfg.LinkTTL)); err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	// The link opens the web app's sign-in page, which posts the token to
+	// /api/v1/auth/verify once the user confirms, so mail scanners following
+	// links do not spend it. In the fragment it is not sent to the server.
+	link := strings.TrimRight(cfg.BaseURL, "/") + "/sign-in#token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      email,
+		Subject: "Your Commuter sign-in link",
+		Body: fmt.Sprintf("Open this link to sign in to Commuter:\n\n%s\n\nIt expires in %s. If you did not ask to sign in, ignore this email.\n",
+			link, cfg.LinkTTL),
+	}
+	if err := router.Mailer.Send(r.Context(), msg); err != nil {
+		router.log(r).Error("Failed to send sign-in link", zap.Error(err))
+		writeError(w, r, http.StatusBadGateway, "login_failed")
+		return
+	}
+	router.respondStatus(w, r, http.StatusAccepted, "Sign-in link sent")
+}
+
+// HandleVerify serves POST /api/v1/auth/verify with {"token": ...}, the
+// token of an emailed sign-in link, which it exchanges for a session. The
+// session token is sent as a bearer token to the /api/v1/me endpoints.
+func (router *Router) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	if !router.accountsEnabled(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBody)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		writeError(w, r, http.StatusBadRequest, "login_token_invalid")
+		return
+	}
+
+	session, err := utils.GenerateStrongAPIKey()
+	if err != nil {
+		router.log(r).Error("Failed to generate session token", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "login_failed")
+		return
+	}
+	expiresAt := time.Now().Add(router.Config.Accounts.SessionTTL)
+	u, err := router.Store.RedeemLoginToken(r.Context(), token, session, expiresAt)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusUnauthorized, "login_token_invalid")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, domain.Session{Token: session, ExpiresAt: expiresAt, User: u})
+}
+
+// HandleLogout serves POST /api/v1/auth/logout, which ends the session whose
+// token is sent.
+func (router *Router) HandleLogout(w http.ResponseWriter, r *http.Request, _ domain.User) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if err := router.Store.DeleteSession(r.Context(), token); err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, "Signed out")
+}
+
+// profile is the response of GET /api/v1/me.
+type profile struct {
+	domain.User
+	Favorites []domain.Favorite `json:"favorites"`
+	Commutes  []domain.Commute  `json:"commutes"`
+}
+
+// HandleMe serves GET /api/v1/me: the user with their favorites and commutes.
+func (router *Router) HandleMe(w http.ResponseWriter, r *http.Request, u domain.User) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	favorites, err := router.Store.GetFavorites(r.Context(), u.ID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	commutes, err := router.Store.GetCommutes(r.Context(), u.ID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, profile{User: u, Favorites: favorites, Commutes: commutes})
+}
+
+// HandleFavorites serves a user's favorite stations:
+//
+//	GET    /api/v1/me/favorites
+//	PUT    /api/v1/me/favorites/{stationID}
+//	DELETE /api/v1/me/favorites/{stationID}
+func (router *Router) HandleFavorites(w http.ResponseWriter, r *http.Request, u domain.User) {
+	stationID := strings.ToUpper(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/me/favorites"), "/"))
+	if stationID == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+			return
+		}
+		favorites, err := router.Store.GetFavorites(r.Context(), u.ID)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, favorites)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if !router.knownStations(w, r, stationID) {
+			return
+		}
+		favorites, err := router.Store.GetFavorites(r.Context(), u.ID)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		if len(favorites) >= maxFavorites {
+			writeError(w, r, http.StatusConflict, "favorites_limit", maxFavorites)
+			return
+		}
+		if err := router.Store.AddFavorite(r.Context(), u.ID, stationID); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, domain.Favorite{StationID: stationID, CreatedAt: time.Now()})
+	case http.MethodDelete:
+		err := router.Store.RemoveFavorite(r.Context(), u.ID, stationID)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "favorite_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+// knownStations writes a 404 and returns false unless every station exists.
+func (router *Router) knownStations(w http.ResponseWriter, r *http.Request, ids ...string) bool {
+	for _, id := range ids {
+		_, err := router.Store.GetStation(r.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "station_not_found_id", id)
+			return false
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return false
+		}
+	}
+	return true
+}
+
+// HandleCommutes serves a user's commutes:
+//
+//	GET    /api/v1/me/commutes
+//	POST   /api/v1/me/commutes with {"name", "from", "to", "after", "before", "remind_before"}
+//	DELETE /api/v1/me/commutes/{id}
+//	GET    /api/v1/me/commutes/next?count=
+func (router *Router) HandleCommutes(w http.ResponseWriter, r *http.Request, u domain.User) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/me/commutes"), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		commutes, err := router.Store.GetCommutes(r.Context(), u.ID)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, commutes)
+	case rest == "" && r.Method == http.MethodPost:
+		router.createCommute(w, r, u)
+	case rest == "next" && r.Method == http.MethodGet:
+		router.handleCommutesNext(w, r, u)
+	case rest != "" && rest != "next" && r.Method == http.MethodDelete:
+		id, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "commute_id_invalid")
+			return
+		}
+		err = router.Store.DeleteCommute(r.Context(), u.ID, id)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "commute_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+func (router *Router) createCommute(w http.ResponseWriter, r *http.Request, u domain.User) {
+	var c domain.Commute
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBody)).Decode(&c); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	c.Name = strings.TrimSpace(c.Name)
+	c.From = strings.ToUpper(strings.TrimSpace(c.From))
+	c.To = strings.ToUpper(strings.TrimSpace(c.To))
+	switch {
+	case c.From == "" || c.To == "":
+		writeError(w, r, http.StatusBadRequest, "from_to_required")
+		return
+	case c.From == c.To:
+		writeError(w, r, http.StatusBadRequest, "from_to_same")
+		return
+	case len(c.Name) > maxCommuteNameLen:
+		writeError(w, r, http.StatusBadRequest, "commute_name_too_long", maxCommuteNameLen)
+		return
+	case c.RemindBefore < 0 || c.RemindBefore > maxRemindBefore:
+		writeError(w, r, http.StatusBadRequest, "remind_before_invalid", maxRemindBefore)
+		return
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *string
+	}{{"after", &c.After}, {"before", &c.Before}} {
+		raw := strings.TrimSpace(*bound.dst)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "time_of_day_invalid", bound.name)
+			return
+		}
+		*bound.dst = t.Format("15:04")
+	}
+	if c.Name == "" {
+		c.Name = c.From + " - " + c.To
+	}
+	if !router.knownStations(w, r, c.From, c.To) {
+		return
+	}
+
+	commutes, err := router.Store.GetCommutes(r.Context(), u.ID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if len(commutes) >= maxCommutes {
+		writeError(w, r, http.StatusConflict, "commutes_limit", maxCommutes)
+		return
+	}
+	c, err = router.Store.CreateCommute(r.Context(), u.ID, c)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respondStatus(w, r, http.StatusCreated, c)
+}
+
+// commuteNext is one commute's entry in GET /api/v1/me/commutes/next.
+type commuteNext struct {
+	Commute domain.Commute `json:"commute"`
+	Trains  []NextTrain    `json:"trains"`
+}
+
+// handleCommutesNext returns, for each of the user's commutes, the next
+// direct trains today that leave within its time window.
+func (router *Router) handleCommutesNext(w http.ResponseWriter, r *http.Request, u domain.User) {
+	count := defaultCommuteNext
+	if raw := r.URL.Query().Get("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxNextCount {
+			writeError(w, r, http.StatusBadRequest, "count_out_of_range", maxNextCount)
+			return
+		}
+		count = n
+	}
+
+	commutes, err := router.Store.GetCommutes(r.Context(), u.ID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	result := make([]commuteNext, 0, len(commutes))
+	for _, c := range commutes {
+		trains, err := router.commuteTrains(r.Context(), c, now, count)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		result = append(result, commuteNext{Commute: c, Trains: trains})
+	}
+	router.respond(w, r, result)
+}
+
+// commuteTrains returns the first count trains of c after now that leave
+// within its window.
+func (router *Router) commuteTrains(ctx context.Context, c domain.Commute, now time.Time, count int) ([]NextTrain, error) {
+	date := router.Timetable.OperatingDate()
+	departures, err := router.Timetable.DatedBoard(ctx, c.From, date)
+	if err != nil {
+		return nil, err
+	}
+	arrivals, err := router.Timetable.DatedBoard(ctx, c.To, date)
+	if err != nil {
+		return nil, err
+	}
+
+	trains := []NextTrain{}
+	for _, t := range nextTrains(departures, arrivals, now, len(departures)) {
+		if len(trains) == count {
+			break
+		}
+		if inWindow(t.DepartsAt.Format("15:04"), c.After, c.Before) {
+			trains = append(trains, t)
+		}
+	}
+	return trains, nil
+}
+
+// inWindow reports whether clock, as HH:MM, lies between after and before,
+// both inclusive and either empty for no bound. An after later than before
+// wraps past midnight.
+func inWindow(clock, after, before string) bool {
+	switch {
+	case after != "" && before != "" && after > before:
+		return clock >= after || clock <= before
+	case after != "" && clock < after:
+		return false
+	case before != "" && clock > before:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,474 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/backup"
+	"llm-router/internal/domain"
+	"llm-router/internal/retention"
+	"llm-router/internal/scrapper"
+	"llm-router/internal/store"
+	"llm-router/internal/utils"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequireAdmin guards admin endpoints with the configured bearer token, or
+// the token it was rotated to. Without a configured token the admin API does
+// not exist.
+func (router *Router) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if router.Config.AdminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !router.IsAdminToken(token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			writeError(w, r, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// HandleConfigReload serves POST /api/admin/config/reload. It re-reads the
+// configuration like SIGHUP does and reports which settings changed.
+func (router *Router) HandleConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	if router.ConfigWatcher == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	result, err := router.ConfigWatcher.Reload()
+	if err != nil {
+		router.log(r).Error("Failed to reload configuration, keeping current settings", zap.Error(err))
+		writeError(w, r, http.StatusBadRequest, "config_reload_failed", err.Error())
+		return
+	}
+	router.log(r).Info("Reloaded configuration",
+		zap.Strings("applied", result.Applied),
+		zap.Strings("restart_required", result.RestartRequired),
+	)
+	router.respond(w, r, result)
+}
+
+// logLevelBody is the body of GET and PUT /api/admin/log-level.
+type logLevelBody struct {
+	Level string `json:"level"`
+}
+
+// HandleAdminLogLevel serves GET and PUT /api/admin/log-level, which report
+// and change the log level until the next restart, or until a reload that
+// changes log_level.
+func (router *Router) HandleAdminLogLevel(w http.ResponseWriter, r *http.Request) {
+	if router.LogLevel == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, logLevelBody{Level: router.LogLevel.String()})
+		return
+	case http.MethodPut:
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	var req logLevelBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "log_level_invalid")
+		return
+	}
+
+	router.LogLevel.SetLevel(level)
+	router.log(r).Warn("Log level changed", zap.Stringer("level", level))
+	router.respond(w, r, logLevelBody{Level: level.String()})
+}
+
+// adminKey is an admin token rotated through the API, which replaces the
+// configured one.
+type adminKey struct {
+	mu    sync.RWMutex
+	token string
+}
+
+// adminKeyName is the name a rotated admin token is stored under. It is
+// derived from the configured token, so changing admin_token discards
+// earlier rotations.
+func adminKeyName(configured string) string {
+	sum := sha256.Sum256([]byte(configured))
+	return "admin:" + hex.EncodeToString(sum[:8])
+}
+
+// loadAdminKey switches to a token rotated by an earlier run.
+func (router *Router) loadAdminKey(ctx context.Context) {
+	if router.Config.AdminToken == "" {
+		return
+	}
+	token, _, err := router.Store.GetAuthToken(ctx, adminKeyName(router.Config.AdminToken))
+	if errors.Is(err, store.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		router.Logger.Error("Failed to load rotated admin token, using the configured one", zap.Error(err))
+		return
+	}
+	router.adminKey.mu.Lock()
+	router.adminKey.token = token
+	router.adminKey.mu.Unlock()
+}
+
+// IsAdminToken reports whether token is the admin token, for admin calls
+// that do not come through RequireAdmin, such as the gRPC ones. Without a
+// configured token there is none.
+func (router *Router) IsAdminToken(token string) bool {
+	if router.Config.AdminToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(router.adminToken())) == 1
+}
+
+// adminToken returns the token admin requests must carry.
+func (router *Router) adminToken() string {
+	router.adminKey.mu.RLock()
+	defer router.adminKey.mu.RUnlock()
+	if router.adminKey.token != "" {
+		return router.adminKey.token
+	}
+	return router.Config.AdminToken
+}
+
+// HandleAdminDB serves GET /api/admin/db: the connection pool, file size and
+// row count of every table.
+func (router *Router) HandleAdminDB(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	stats, err := router.Store.DBStats(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	tables, err := router.Store.TableCounts(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, domain.DBReport{DBStats: stats, Tables: tables})
+}
+
+// HandleAdminSync serves POST /api/admin/sync, which starts a full sync like
+// POST /api/v1/sync, and GET /api/admin/sync/failed, the stations whose last
+// sync failed.
+func (router *Router) HandleAdminSync(w http.ResponseWriter, r *http.Request) {
+	switch strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/sync"), "/") {
+	case "":
+		if r.Method != http.MethodPost {
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+			return
+		}
+		go router.Scraper.SyncAll(domain.SyncTriggerAdmin)
+		router.respond(w, r, "Sync triggered")
+	case "failed":
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+			return
+		}
+		router.respond(w, r, router.Scraper.FailedStations())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// stationSyncResult is the response of POST /api/admin/stations/{id}/sync.
+type stationSyncResult struct {
+	StationID string `json:"station_id"`
+	Schedules int    `json:"schedules"`
+}
+
+// handleAdminStationSync re-syncs one station and waits for the result.
+func (router *Router) handleAdminStationSync(w http.ResponseWriter, r *http.Request, stationID string) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	err := router.Scraper.SyncStation(stationID)
+	switch {
+	case errors.Is(err, scrapper.ErrSyncPaused):
+		writeError(w, r, http.StatusConflict, "sync_paused")
+		return
+	case errors.Is(err, scrapper.ErrSyncInProgress):
+		writeError(w, r, http.StatusConflict, "sync_in_progress")
+		return
+	case err != nil:
+		writeError(w, r, http.StatusBadGateway, "station_sync_error", stationID, err.Error())
+		return
+	}
+	router.resetCaches()
+	router.purge(r.Context(), []string{stationKey(stationID), surrogateKeyNetwork})
+
+	schedules, err := router.Store.GetSchedules(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, stationSyncResult{StationID: stationID, Schedules: len(schedules)})
+}
+
+// minPurgeAge keeps a purge from removing the delay history scores are
+// still being computed from by mistake.
+const minPurgeAge = 24 * time.Hour
+
+// HandleAdminPurge serves POST /api/admin/purge?older_than=, which deletes
+// the schedules, facilities and locations of stations no longer listed and
+// delay samples older than older_than, a duration defaulting to the
+// reliability window.
+func (router *Router) HandleAdminPurge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	age := reliabilityWindow
+	if raw := r.URL.Query().Get("older_than"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < minPurgeAge {
+			writeError(w, r, http.StatusBadRequest, "older_than_invalid")
+			return
+		}
+		age = d
+	}
+
+	result, err := router.Store.PurgeStale(r.Context(), time.Now().Add(-age))
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.resetCaches()
+	router.purge(r.Context(), []string{surrogateKeyAll})
+	router.log(r).Warn("Purged stale data",
+		zap.Int64("schedules", result.Schedules),
+		zap.Int64("facilities", result.Facilities),
+		zap.Int64("locations", result.Locations),
+		zap.Int64("delay_samples", result.DelaySamples),
+	)
+	router.respond(w, r, result)
+}
+
+// HandleAdminRetention serves the maintenance job: GET /api/admin/retention
+// reports its last run and when it runs next, and POST runs it now and
+// returns what it deleted.
+func (router *Router) HandleAdminRetention(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, router.Retention.Status())
+	case http.MethodPost:
+		run, err := router.Retention.Run(r.Context())
+		if errors.Is(err, retention.ErrRunning) {
+			writeError(w, r, http.StatusConflict, "retention_running")
+			return
+		}
+		if err != nil && run.StartedAt.IsZero() {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.resetCaches()
+		router.respond(w, r, run)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+// HandleAdminBackup serves POST /api/admin/backup, which downloads a
+// consistent copy of the database taken while the server keeps running.
+func (router *Router) HandleAdminBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "commuter-backup-*.db")
+	if err != nil {
+		router.log(r).Error("Failed to create backup file", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "backup_failed")
+		return
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := router.Store.Backup(r.Context(), tmp.Name()); errors.Is(err, store.ErrBackupUnsupported) {
+		writeError(w, r, http.StatusNotImplemented, "backup_unsupported")
+		return
+	} else if err != nil {
+		router.log(r).Error("Backup failed", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "backup_failed")
+		return
+	}
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		router.log(r).Error("Failed to open backup file", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "backup_failed")
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		router.log(r).Error("Failed to open backup file", zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "backup_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", backup.FileName(time.Now())))
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := io.Copy(w, f); err != nil {
+		router.log(r).Warn("Backup download interrupted", zap.Error(err))
+		return
+	}
+	router.log(r).Warn("Backup downloaded", zap.Int64("bytes", info.Size()))
+}
+
+// keyRotation is the response of POST /api/admin/keys/{name}/rotate. Key is
+// only returned for the admin token; the KAI token is never shown.
+type keyRotation struct {
+	Name      string    `json:"name"`
+	Key       string    `json:"key,omitempty"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// HandleAdminKeys serves POST /api/admin/keys/{name}/rotate for the admin
+// token ("admin") and the upstream KAI token ("kai"). A new admin token
+// takes effect at once and the previous one stops working, so the response
+// is the only place it can be read. The KAI token is requested from the
+// configured token URL.
+func (router *Router) HandleAdminKeys(w http.ResponseWriter, r *http.Request) {
+	name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/admin/keys/"), "/")
+	if action != "rotate" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	switch name {
+	case "admin":
+		key, err := utils.GenerateStrongAPIKey()
+		if err != nil {
+			router.log(r).Error("Failed to generate admin token", zap.Error(err))
+			writeError(w, r, http.StatusInternalServerError, "key_generation_failed")
+			return
+		}
+		if err := router.Store.SetAuthToken(r.Context(), adminKeyName(router.Config.AdminToken), key); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.adminKey.mu.Lock()
+		router.adminKey.token = key
+		router.adminKey.mu.Unlock()
+		router.log(r).Warn("Admin token rotated")
+		router.respond(w, r, keyRotation{Name: name, Key: key, RotatedAt: time.Now()})
+	case "kai":
+		err := router.Scraper.RotateToken()
+		if errors.Is(err, scrapper.ErrTokenRefreshUnconfigured) {
+			writeError(w, r, http.StatusConflict, "kai_refresh_unconfigured")
+			return
+		}
+		if err != nil {
+			router.log(r).Error("Failed to rotate KAI token", zap.Error(err))
+			writeError(w, r, http.StatusBadGateway, "kai_rotate_failed", err.Error())
+			return
+		}
+		router.respond(w, r, keyRotation{Name: name, RotatedAt: time.Now()})
+	default:
+		writeError(w, r, http.StatusNotFound, "key_unknown", name)
+	}
+}
+
+const (
+	defaultCaptureLimit = 50
+	maxCaptureLimit     = 500
+)
+
+// HandleAdminCaptures serves GET /api/admin/captures?limit=, the upstream
+// responses recorded in capture mode, newest first, and
+// GET /api/admin/captures/{id}, one response's body as the upstream sent it.
+func (router *Router) HandleAdminCaptures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	rawID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/captures"), "/")
+	if rawID == "" {
+		limit := defaultCaptureLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxCaptureLimit {
+				writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxCaptureLimit)
+				return
+			}
+			limit = n
+		}
+		fetches, err := router.Store.GetRawFetches(r.Context(), limit)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, fetches)
+		return
+	}
+
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "capture_id_invalid")
+		return
+	}
+	f, err := router.Store.GetRawFetch(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "capture_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Capture-URL", f.URL)
+	w.Header().Set("X-Capture-Status", strconv.Itoa(f.Status))
+	w.Header().Set("X-Capture-Fetched-At", f.FetchedAt.UTC().Format(time.RFC3339))
+	w.Write(f.Body)
+}
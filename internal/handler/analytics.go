@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// HandleHeatmap serves /api/v1/analytics/heatmap/{station}?date=, the
+// station's departures by line and hour in the timetable it runs on date
+// (default today).
+func (router *Router) HandleHeatmap(w http.ResponseWriter, r *http.Request) {
+	stationID := strings.TrimPrefix(r.URL.Path, "/api/v1/analytics/heatmap/")
+
+	if stationID == "" {
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	days, err := router.Store.GetStationServiceDays(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	serviceDay := calendar.Variant(days, router.Calendar.ServiceDay(date))
+	counts, err := router.Store.GetHourlyDepartureCounts(r.Context(), stationID, serviceDay)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	heatmap := buildHeatmap(stationID, counts)
+	heatmap.ServiceDay = serviceDay
+
+	router.respond(w, r, heatmap)
+}
+
+func buildHeatmap(stationID string, counts []domain.HourlyDepartureCount) domain.DepartureHeatmap {
+	heatmap := domain.DepartureHeatmap{
+		StationID: stationID,
+		Lines:     []string{},
+		Matrix:    [][]int{},
+		Totals:    make([]int, 24),
+	}
+
+	lineIndex := make(map[string]int)
+	for _, c := range counts {
+		if c.Hour < 0 || c.Hour > 23 {
+			continue
+		}
+
+		i, ok := lineIndex[c.Line]
+		if !ok {
+			i = len(heatmap.Lines)
+			lineIndex[c.Line] = i
+			heatmap.Lines = append(heatmap.Lines, c.Line)
+			heatmap.Matrix = append(heatmap.Matrix, make([]int, 24))
+		}
+
+		heatmap.Matrix[i][c.Hour] += c.Count
+		heatmap.Totals[c.Hour] += c.Count
+	}
+
+	for _, total := range heatmap.Totals {
+		if total > heatmap.Max {
+			heatmap.Max = total
+		}
+	}
+	return heatmap
+}
+
+// HandleStationStats serves /api/v1/station/{id}/stats?date=, aggregates of
+// the timetable the station runs on date (default today): trains per hour,
+// the busiest hour, the lines served and their headways, and the first and
+// last train towards each destination.
+func (router *Router) HandleStationStats(w http.ResponseWriter, r *http.Request, stationID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := router.Store.GetStation(r.Context(), stationID); errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "station_not_found")
+		return
+	} else if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	days, err := router.Store.GetStationServiceDays(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	stats, err := router.Store.GetStationStats(r.Context(), stationID, calendar.Variant(days, router.Calendar.ServiceDay(date)))
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	lang := displayLang(w, r)
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		names[st.ID] = st.LocalName(lang)
+	}
+	for i := range stats.Destinations {
+		stats.Destinations[i].Name = names[stats.Destinations[i].StationID]
+	}
+
+	router.usage.hit(store.UsageKindStation, stationID)
+	router.respond(w, r, stats)
+}
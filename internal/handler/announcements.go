@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// maxAnnouncementMessage bounds an announcement's message and each of its
+// translations, in characters.
+const maxAnnouncementMessage = 2000
+
+// HandleAnnouncements serves /api/v1/announcements?line=&station=, the
+// announcements in effect now, optionally only those concerning a line or
+// station. Each carries its message in the language negotiated from
+// Accept-Language as display_message.
+func (router *Router) HandleAnnouncements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var lines, stations []string
+	if name := strings.TrimSpace(q.Get("line")); name != "" {
+		line, err := router.lineData(r.Context(), name)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "line_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		lines = append(lines, line.Name)
+	}
+	if station := strings.ToUpper(strings.TrimSpace(q.Get("station"))); station != "" {
+		stations = append(stations, station)
+	}
+
+	announcements, err := router.Store.GetActiveAnnouncements(r.Context(), time.Now())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if lines != nil || stations != nil {
+		announcements = concerning(announcements, lines, stations)
+	}
+	localizeAnnouncements(i18n.Lang(displayLang(w, r)), announcements)
+	router.respond(w, r, announcements)
+}
+
+// HandleAdminAnnouncements manages announcements:
+//
+//	GET    /api/admin/announcements
+//	POST   /api/admin/announcements with {"line", "station_ids", "severity", "message", "translations", "starts_at", "ends_at"}
+//	GET    /api/admin/announcements/{id}
+//	PUT    /api/admin/announcements/{id} with the same body as POST
+//	DELETE /api/admin/announcements/{id}
+func (router *Router) HandleAdminAnnouncements(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/announcements"), "/")
+	ctx := r.Context()
+
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			announcements, err := router.Store.ListAnnouncements(ctx)
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			router.respond(w, r, announcements)
+		case http.MethodPost:
+			a, ok := router.decodeAnnouncement(w, r, time.Now())
+			if !ok {
+				return
+			}
+			a.Source = domain.AnnouncementSourceAdmin
+			created, err := router.Store.CreateAnnouncement(ctx, a)
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			router.purge(ctx, announcementKeys(created))
+			router.respondStatus(w, r, http.StatusCreated, created)
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		}
+		return
+	}
+
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "announcement_id_invalid")
+		return
+	}
+	existing, err := router.Store.GetAnnouncement(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "announcement_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, existing)
+	case http.MethodPut:
+		a, ok := router.decodeAnnouncement(w, r, existing.StartsAt)
+		if !ok {
+			return
+		}
+		a.ID = id
+		if err := router.Store.UpdateAnnouncement(ctx, a); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.purge(ctx, announcementKeys(existing, a))
+		updated, err := router.Store.GetAnnouncement(ctx, id)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, updated)
+	case http.MethodDelete:
+		if err := router.Store.DeleteAnnouncement(ctx, id); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.purge(ctx, announcementKeys(existing))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+// decodeAnnouncement reads and validates an announcement from the request
+// body, writing a problem and returning false when it is not acceptable.
+// It starts at start unless the body says otherwise. The line is stored
+// under its canonical name.
+func (router *Router) decodeAnnouncement(w http.ResponseWriter, r *http.Request, start time.Time) (domain.Announcement, bool) {
+	var req struct {
+		Line         string            `json:"line"`
+		StationIDs   []string          `json:"station_ids"`
+		Severity     string            `json:"severity"`
+		Message      string            `json:"message"`
+		Translations map[string]string `json:"translations"`
+		StartsAt     *time.Time        `json:"starts_at"`
+		EndsAt       *time.Time        `json:"ends_at"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return domain.Announcement{}, false
+	}
+
+	a := domain.Announcement{
+		Severity: strings.ToLower(strings.TrimSpace(req.Severity)),
+		Message:  strings.TrimSpace(req.Message),
+		StartsAt: start,
+		EndsAt:   req.EndsAt,
+	}
+	if a.Severity == "" {
+		a.Severity = domain.SeverityInfo
+	}
+	if req.StartsAt != nil {
+		a.StartsAt = *req.StartsAt
+	}
+	switch {
+	case !slices.Contains(domain.Severities, a.Severity):
+		writeError(w, r, http.StatusBadRequest, "severity_invalid", strings.Join(domain.Severities, ", "))
+		return domain.Announcement{}, false
+	case a.Message == "":
+		writeError(w, r, http.StatusBadRequest, "message_required")
+		return domain.Announcement{}, false
+	case len([]rune(a.Message)) > maxAnnouncementMessage:
+		writeError(w, r, http.StatusBadRequest, "message_too_long", maxAnnouncementMessage)
+		return domain.Announcement{}, false
+	case a.EndsAt != nil && !a.EndsAt.After(a.StartsAt):
+		writeError(w, r, http.StatusBadRequest, "ends_before_start")
+		return domain.Announcement{}, false
+	}
+	// Translations are keyed by a catalog language; blank ones are dropped.
+	for lang, msg := range req.Translations {
+		lang, msg = strings.ToLower(strings.TrimSpace(lang)), strings.TrimSpace(msg)
+		if msg == "" {
+			continue
+		}
+		if !i18n.Supported(lang) || len([]rune(msg)) > maxAnnouncementMessage {
+			writeError(w, r, http.StatusBadRequest, "translations_invalid", maxAnnouncementMessage)
+			return domain.Announcement{}, false
+		}
+		if a.Translations == nil {
+			a.Translations = make(map[string]string)
+		}
+		a.Translations[lang] = msg
+	}
+
+	if line := strings.TrimSpace(req.Line); line != "" {
+		l, err := router.lineData(r.Context(), line)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "line_not_found")
+			return domain.Announcement{}, false
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return domain.Announcement{}, false
+		}
+		a.Line = l.Name
+	}
+	for _, id := range req.StationIDs {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" && !slices.Contains(a.StationIDs, id) {
+			a.StationIDs = append(a.StationIDs, id)
+		}
+	}
+	if !router.knownStations(w, r, a.StationIDs...) {
+		return domain.Announcement{}, false
+	}
+	return a, true
+}
+
+// announcementKeys returns the surrogate keys of the responses that may show
+// announcements: those of their stations and the network, or every response
+// when one covers a whole line or the network.
+func announcementKeys(announcements ...domain.Announcement) []string {
+	keys := []string{surrogateKeyNetwork}
+	for _, a := range announcements {
+		if len(a.StationIDs) == 0 {
+			return []string{surrogateKeyAll}
+		}
+		for _, id := range a.StationIDs {
+			if key := stationKey(id); !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// concerning returns the announcements that apply to any of lines or
+// stations.
+func concerning(announcements []domain.Announcement, lines, stations []string) []domain.Announcement {
+	kept := []domain.Announcement{}
+	for _, a := range announcements {
+		if a.Concerns(lines, stations) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// announcementsFor returns the announcements in effect now that apply to any
+// of lines or stations. A failure to read them is logged rather than failing
+// the response they accompany.
+func (router *Router) announcementsFor(r *http.Request, lines, stations []string) []domain.Announcement {
+	announcements, err := router.Store.GetActiveAnnouncements(r.Context(), time.Now())
+	if err != nil {
+		router.log(r).Warn("Failed to read announcements", zap.Error(err))
+		return nil
+	}
+	return concerning(announcements, lines, stations)
+}
+
+// localizeAnnouncements sets the display message of each of announcements
+// to its translation in lang, or else to its message.
+func localizeAnnouncements(lang i18n.Lang, announcements []domain.Announcement) {
+	for i, a := range announcements {
+		msg, ok := i18n.Pick(lang, a.Translations)
+		if !ok {
+			msg = a.Message
+		}
+		announcements[i].DisplayMessage = msg
+	}
+}
+
+// respondAnnounced is respond with announcements added to the metadata,
+// when there are any, localized like HandleAnnouncements.
+func (router *Router) respondAnnounced(w http.ResponseWriter, r *http.Request, data interface{}, announcements []domain.Announcement) {
+	env := newEnvelope(data)
+	if len(announcements) > 0 {
+		localizeAnnouncements(i18n.Lang(displayLang(w, r)), announcements)
+		env.Metadata["announcements"] = announcements
+	}
+	router.respondEnvelope(w, r, http.StatusOK, env)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+)
+
+// arrivalIndex holds the trains reaching each station, by arrival time.
+type arrivalIndex map[string][]domain.StationArrival
+
+// HandleArrivals serves /api/v1/arrivals/{station}?date=&service_type=, the
+// trains reaching a station, including those that terminate there. Each
+// train's arrival is derived from its departure at the stop before, as
+// published timetables only list departures.
+func (router *Router) HandleArrivals(w http.ResponseWriter, r *http.Request) {
+	stationID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/arrivals/"), "/")
+	if stationID == "" {
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
+		return
+	}
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+	if !router.knownStations(w, r, stationID) {
+		return
+	}
+
+	serviceDay := router.Calendar.ServiceDay(date)
+	index, err := router.arrivalIndex(r.Context(), date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	lang := displayLang(w, r)
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		names[st.ID] = st.LocalName(lang)
+	}
+
+	// The cached arrivals are shared, so they are copied before naming.
+	board := domain.ArrivalBoard{StationID: stationID, ServiceDay: serviceDay, Arrivals: []domain.StationArrival{}}
+	for _, a := range index[stationID] {
+		if len(services) > 0 && !slices.Contains(services, a.ServiceType) {
+			continue
+		}
+		a.OriginName, a.FromStationName = names[a.OriginID], names[a.FromStationID]
+		board.Arrivals = append(board.Arrivals, a)
+	}
+
+	if len(board.Arrivals) > 0 {
+		router.usage.hit(store.UsageKindStation, stationID)
+	}
+	router.respond(w, r, board)
+}
+
+// arrivalIndex returns the arrivals at every station as the network runs on
+// date, building them on first use after each sync or change to the
+// overrides or closures.
+func (router *Router) arrivalIndex(ctx context.Context, date time.Time) (arrivalIndex, error) {
+	key, err := router.Timetable.NetworkKey(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if index, ok := router.arrivals.Get(key); ok {
+		return index, nil
+	}
+	schedules, err := router.Timetable.DatedSchedules(ctx, store.ScheduleFilter{}, date)
+	if err != nil {
+		return nil, err
+	}
+
+	trains := make(map[string][]domain.Schedule)
+	for _, sch := range schedules {
+		if !sch.DepartsAt.IsZero() {
+			trains[sch.TrainID] = append(trains[sch.TrainID], sch)
+		}
+	}
+	index := make(arrivalIndex)
+	for _, stops := range trains {
+		for station, a := range trainArrivals(stops) {
+			index[station] = append(index[station], a)
+		}
+	}
+	for _, arrivals := range index {
+		sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].ArrivesAt.Before(arrivals[j].ArrivesAt) })
+	}
+	router.arrivals.Set(key, index)
+	return index, nil
+}
+
+// trainArrivals returns a train's arrival at each stop after its first, from
+// its schedules in any order. A terminus without a schedule of its own is
+// reached at the train's published arrival time.
+func trainArrivals(stops []domain.Schedule) map[string]domain.StationArrival {
+	sort.Slice(stops, func(i, j int) bool { return stops[i].DepartsAt.Before(stops[j].DepartsAt) })
+	arrival := func(prev domain.Schedule, station string, at time.Time, estimated bool) domain.StationArrival {
+		return domain.StationArrival{
+			TrainID:       prev.TrainID,
+			Line:          prev.Line,
+			Route:         prev.Route,
+			Color:         prev.Metadata.Origin.Color,
+			ServiceType:   prev.ServiceType,
+			OriginID:      stops[0].StationID,
+			FromStationID: prev.StationID,
+			ArrivesAt:     at,
+			Estimated:     estimated,
+			Terminates:    station == prev.StationDestinationID,
+		}
+	}
+
+	arrivals := make(map[string]domain.StationArrival, len(stops))
+	for i := 1; i < len(stops); i++ {
+		sch := stops[i]
+		at, estimated := journey.Arrival(stops[i-1], sch)
+		if at == nil {
+			continue
+		}
+		a := arrival(stops[i-1], sch.StationID, *at, estimated)
+		a.Platform = sch.Metadata.Platform
+		if !a.Terminates {
+			departs := sch.DepartsAt
+			a.DepartsAt = &departs
+		}
+		arrivals[sch.StationID] = a
+	}
+
+	last := stops[len(stops)-1]
+	terminus := last.StationDestinationID
+	if _, ok := arrivals[terminus]; !ok && terminus != "" && terminus != last.StationID && last.ArrivesAt.After(last.DepartsAt) {
+		arrivals[terminus] = arrival(last, terminus, last.ArrivesAt, false)
+	}
+	return arrivals
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+	"llm-router/internal/search"
+)
+
+const (
+	defaultAutocompleteLimit = 8
+	maxAutocompleteLimit     = 20
+)
+
+// stationAutocomplete is what station autocomplete answers from: the
+// stations by prefix and the lines through each.
+type stationAutocomplete struct {
+	prefixes *search.Prefixes
+	lines    map[string][]domain.LineBadge
+}
+
+// HandleStationAutocomplete serves /api/v1/station/autocomplete?q=&limit=,
+// the stations whose name, alias or ID starts with q, for search-as-you-type.
+// Each is listed with just its ID, its name in the language negotiated from
+// Accept-Language, its type and the lines through it. Misspellings are left
+// to /api/v1/station/search.
+func (router *Router) HandleStationAutocomplete(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, r, http.StatusBadRequest, "q_required")
+		return
+	}
+	if utf8.RuneCountInString(q) > maxSearchQuery {
+		writeError(w, r, http.StatusBadRequest, "q_too_long", maxSearchQuery)
+		return
+	}
+
+	limit := defaultAutocompleteLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxAutocompleteLimit {
+			writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxAutocompleteLimit)
+			return
+		}
+		limit = n
+	}
+
+	ac, err := router.stationAutocomplete(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	lang := displayLang(w, r)
+	stations := ac.prefixes.Complete(q, limit)
+	suggestions := make([]domain.StationSuggestion, len(stations))
+	for i, st := range stations {
+		lines := make([]domain.LineBadge, len(ac.lines[st.ID]))
+		for j, b := range ac.lines[st.ID] {
+			b.DisplayName = i18n.LineName(i18n.Lang(lang), b.Line)
+			lines[j] = b
+		}
+		suggestions[i] = domain.StationSuggestion{
+			ID:          st.ID,
+			DisplayName: st.LocalName(lang),
+			Type:        st.Type,
+			Lines:       lines,
+		}
+	}
+	router.respond(w, r, suggestions)
+}
+
+// stationAutocomplete returns the autocomplete index over the current
+// stations, building it on first use after each sync or station change.
+func (router *Router) stationAutocomplete(ctx context.Context) (*stationAutocomplete, error) {
+	if ac := router.autocomplete.Load(); ac != nil {
+		return ac, nil
+	}
+	stations, err := router.Store.GetStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := router.Store.GetStationLines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ac := &stationAutocomplete{prefixes: search.NewPrefixes(stations), lines: lines}
+	router.autocomplete.Store(ac)
+	return ac, nil
+}
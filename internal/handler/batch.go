@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// maxBatchStations bounds the stations of a batch schedule lookup, a few
+// more than a widget screen shows.
+const maxBatchStations = 20
+
+// scheduleBatchBody is the body of POST /api/v1/schedule/batch.
+type scheduleBatchBody struct {
+	StationIDs []string `json:"station_ids"`
+	Date       string   `json:"date"`
+	After      string   `json:"after"`
+	Before     string   `json:"before"`
+}
+
+// HandleScheduleBatch serves POST /api/v1/schedule/batch with {"station_ids",
+// "date", "after", "before"}, the schedules of several stations keyed by
+// station ID, so widgets showing a few stations need one request rather than
+// one each. date (default today) picks the timetable as on
+// /api/v1/schedule/{id}; after and before are inclusive HH:MM bounds on the
+// departures, and an after later than before wraps past midnight. A station
+// closed on date has no departures. Announcements in effect for the
+// stations or their lines are listed in the metadata.
+func (router *Router) HandleScheduleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	var body scheduleBatchBody
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&body); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+
+	var ids []string
+	for _, id := range body.StationIDs {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	switch {
+	case len(ids) == 0:
+		writeError(w, r, http.StatusBadRequest, "station_ids_required")
+		return
+	case len(ids) > maxBatchStations:
+		writeError(w, r, http.StatusBadRequest, "too_many_stations", maxBatchStations)
+		return
+	}
+
+	date := router.Timetable.OperatingDate()
+	if raw := strings.TrimSpace(body.Date); raw != "" {
+		d, err := calendar.ParseDate(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "date_invalid")
+			return
+		}
+		date = d
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *string
+	}{{"after", &body.After}, {"before", &body.Before}} {
+		raw := strings.TrimSpace(*bound.dst)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "time_of_day_invalid", bound.name)
+			return
+		}
+		*bound.dst = t.Format("15:04")
+	}
+
+	results := make(map[string][]domain.Schedule, len(ids))
+	var lines []string
+	for _, id := range ids {
+		_, err := router.Store.GetStation(r.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "station_not_found_id", id)
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+
+		board, err := router.Timetable.DatedBoard(r.Context(), id, date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		// The board may be cached, and reliability is attached per request.
+		schedules := []domain.Schedule{}
+		for _, sch := range board {
+			if !slices.Contains(lines, sch.Line) {
+				lines = append(lines, sch.Line)
+			}
+			if inWindow(sch.DepartsAt.Format("15:04"), body.After, body.Before) {
+				schedules = append(schedules, sch)
+			}
+		}
+		if len(schedules) > 0 {
+			router.usage.hit(store.UsageKindStation, id)
+		}
+		router.attachReliability(r.Context(), schedules)
+		results[id] = schedules
+	}
+	router.respondAnnounced(w, r, results, router.announcementsFor(r, lines, ids))
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/store"
+)
+
+const (
+	defaultBoardCount = 10
+	maxBoardCount     = 50
+	maxBoardPairs     = 10
+)
+
+// boardPair is one leg of a combined board: departures from From that call at
+// To later on.
+type boardPair struct {
+	From, To string
+}
+
+// HandleMultiBoard serves /api/v1/board/multi?pair=FROM:TO&pair=...&count=
+// &service_type=, the next departures for several station and destination
+// pairs merged into one time-sorted board, such as a "my commute" screen
+// combining Sudirman to Bogor and Tanah Abang to Serpong.
+func (router *Router) HandleMultiBoard(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pairs, ok := boardPairs(w, r, q["pair"])
+	if !ok {
+		return
+	}
+
+	count := defaultBoardCount
+	if raw := q.Get("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxBoardCount {
+			writeError(w, r, http.StatusBadRequest, "count_out_of_range", maxBoardCount)
+			return
+		}
+		count = n
+	}
+
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	date := router.Timetable.OperatingDate()
+	board := []NextTrain{}
+	for _, p := range pairs {
+		for _, id := range []string{p.From, p.To} {
+			_, err := router.Store.GetStation(r.Context(), id)
+			if errors.Is(err, store.ErrNotFound) {
+				writeError(w, r, http.StatusNotFound, "station_not_found_id", id)
+				return
+			}
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+		}
+
+		departures, err := router.Timetable.DatedBoard(r.Context(), p.From, date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		arrivals, err := router.Timetable.DatedBoard(r.Context(), p.To, date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.usage.hit(store.UsageKindStation, p.From)
+
+		// Each pair contributes at most count trains, which is all the merged
+		// board can show.
+		board = append(board, nextTrains(filterServices(departures, services), arrivals, now, count)...)
+	}
+
+	sort.SliceStable(board, func(i, j int) bool { return board[i].DepartsAt.Before(board[j].DepartsAt) })
+	if len(board) > count {
+		board = board[:count]
+	}
+	router.respond(w, r, board)
+}
+
+// boardPairs parses the pair parameters, writing an error and returning false
+// when they are missing or malformed. Repeated pairs are merged.
+func boardPairs(w http.ResponseWriter, r *http.Request, raw []string) ([]boardPair, bool) {
+	if len(raw) == 0 {
+		writeError(w, r, http.StatusBadRequest, "pair_required")
+		return nil, false
+	}
+
+	var pairs []boardPair
+	seen := make(map[boardPair]bool)
+	for _, v := range raw {
+		from, to, ok := strings.Cut(v, ":")
+		p := boardPair{From: strings.TrimSpace(from), To: strings.TrimSpace(to)}
+		if !ok || p.From == "" || p.To == "" || p.From == p.To {
+			writeError(w, r, http.StatusBadRequest, "pair_invalid", v)
+			return nil, false
+		}
+		if !seen[p] {
+			seen[p] = true
+			pairs = append(pairs, p)
+		}
+	}
+	if len(pairs) > maxBoardPairs {
+		writeError(w, r, http.StatusBadRequest, "too_many_pairs", maxBoardPairs)
+		return nil, false
+	}
+	return pairs, true
+}
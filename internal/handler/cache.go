@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/journey"
+	"llm-router/internal/search"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// journeyPlanner returns the planner for the network as it runs on date, the
+// BRT network and the curated transfers, building it on first use after each
+// sync, transfer change or change to the overrides or closures.
+func (router *Router) journeyPlanner(ctx context.Context, date time.Time) (*journey.Planner, error) {
+	key, err := router.Timetable.NetworkKey(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := router.planners.Get(key); ok {
+		return p, nil
+	}
+	schedules, err := router.Timetable.DatedSchedules(ctx, store.ScheduleFilter{}, date)
+	if err != nil {
+		return nil, err
+	}
+	brt, err := router.Store.GetBRTNetwork(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := router.Store.GetTransfers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := journey.NewPlanner(schedules, brt, transfers)
+	router.planners.Set(key, p)
+	return p, nil
+}
+
+// warmPlanner builds the journey planner for the service date running now,
+// so journey queries never wait on loading it.
+func (router *Router) warmPlanner(ctx context.Context) {
+	start := time.Now()
+	p, err := router.journeyPlanner(ctx, router.Timetable.OperatingDate())
+	if err != nil {
+		router.Logger.Warn("Failed to build journey planner", zap.Error(err))
+		return
+	}
+	stations, trips, connections := p.Size()
+	router.Logger.Info("Built journey planner",
+		zap.Int("stations", stations),
+		zap.Int("trips", trips),
+		zap.Int("connections", connections),
+		zap.Duration("took", time.Since(start)),
+	)
+}
+
+// stationIndex returns the search index over the current stations, building
+// it on first use after each sync.
+func (router *Router) stationIndex(ctx context.Context) (*search.Index, error) {
+	if idx := router.search.Load(); idx != nil {
+		return idx, nil
+	}
+	stations, err := router.Store.GetStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := search.NewIndex(stations)
+	router.search.Store(idx)
+	return idx, nil
+}
+
+// lineData returns a line by name; names are matched case-insensitively.
+func (router *Router) lineData(ctx context.Context, name string) (domain.Line, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if line, ok := router.lines.Get(key); ok {
+		return line, nil
+	}
+
+	line, err := router.Store.GetLine(ctx, name)
+	if err != nil {
+		return domain.Line{}, err
+	}
+	router.lines.Set(key, line)
+	return line, nil
+}
+
+// invalidateOnSync clears cached data whenever a sync completes, rebuilds
+// today's journey planner and station autocomplete, and then warms the
+// caches again for the most requested stations and lines.
+func (router *Router) invalidateOnSync(ctx context.Context) {
+	sub := router.Events.Subscribe(events.TopicSync)
+	defer sub.Close()
+	for {
+		select {
+		case e := <-sub.C:
+			if e.Type == events.TypeSyncCompleted {
+				router.resetCaches()
+				router.warmPlanner(ctx)
+				if _, err := router.stationAutocomplete(ctx); err != nil {
+					router.Logger.Warn("Failed to build station autocomplete", zap.Error(err))
+				}
+				router.warmCaches(ctx)
+				// Commutes are checked here, once the caches hold the
+				// new timetable.
+				if router.Push != nil {
+					router.alertCommuteChanges(ctx)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// resetDatedCaches drops the views built from the network as it runs on a
+// date, once the overrides or the stations' closures have changed.
+func (router *Router) resetDatedCaches() {
+	router.planners.Reset()
+	router.trips.Reset()
+	router.arrivals.Reset()
+}
+
+// resetCaches drops every cached view of the schedule data.
+func (router *Router) resetCaches() {
+	router.Timetable.Reset()
+	router.lines.Reset()
+	router.planners.Reset()
+	router.trips.Reset()
+	router.arrivals.Reset()
+	router.search.Store(nil)
+	router.autocomplete.Store(nil)
+	router.bundle.Store(nil)
+	router.lastSync.Store(nil)
+}
+
+// warmCaches pre-builds boards, line maps and routes for the top stations and
+// lines by recorded usage, so the first requests after a sync are not served
+// from a cold cache.
+func (router *Router) warmCaches(ctx context.Context) {
+	n := router.Config.CacheWarmTopN
+	if n <= 0 {
+		return
+	}
+
+	start := time.Now()
+	router.usage.flush(ctx)
+
+	// Warming is best effort: a failure leaves the rest to be built on demand.
+	stations, err := router.Store.GetTopUsage(ctx, store.UsageKindStation, n)
+	if err != nil {
+		router.Logger.Warn("Failed to load station usage for cache warming", zap.Error(err))
+	}
+	today := router.operatingDay()
+	trains := make(map[string]bool)
+	for _, id := range stations {
+		schedules, err := router.Timetable.Board(ctx, id)
+		if err != nil {
+			router.Logger.Warn("Failed to warm board", zap.String("station", id), zap.Error(err))
+			continue
+		}
+		for _, sch := range calendar.Timetable(schedules, today) {
+			trains[sch.TrainID] = true
+		}
+	}
+	for trainID := range trains {
+		if _, err := router.Timetable.Route(ctx, trainID, today); err != nil && !errors.Is(err, store.ErrNotFound) {
+			router.Logger.Warn("Failed to warm route", zap.String("train", trainID), zap.Error(err))
+		}
+	}
+
+	lines, err := router.Store.GetTopUsage(ctx, store.UsageKindLine, n)
+	if err != nil {
+		router.Logger.Warn("Failed to load line usage for cache warming", zap.Error(err))
+	}
+	for _, name := range lines {
+		if _, err := router.lineData(ctx, name); err != nil && !errors.Is(err, store.ErrNotFound) {
+			router.Logger.Warn("Failed to warm line", zap.String("line", name), zap.Error(err))
+		}
+	}
+
+	router.Logger.Info("Warmed caches after sync",
+		zap.Int("stations", len(stations)),
+		zap.Int("lines", len(lines)),
+		zap.Int("routes", len(trains)),
+		zap.Duration("took", time.Since(start)),
+	)
+}
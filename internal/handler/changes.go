@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+const problemTypeSyncRunUnavailable = "/problems/sync-run-unavailable"
+
+// HandleChanges serves /api/v1/changes?since=, listing trains added, removed
+// or re-timed between a past sync run and the latest one. since is either a
+// run ID (as reported by /api/v1/sync) or an RFC 3339 timestamp, which
+// selects the last run finished by then.
+func (router *Router) HandleChanges(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("since")
+	if raw == "" {
+		writeError(w, r, http.StatusBadRequest, "since_required")
+		return
+	}
+
+	since, ok := router.syncRunParam(w, r, raw, "since_invalid")
+	if !ok {
+		return
+	}
+
+	current, err := router.Store.GetLatestSyncRun(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	diff, err := router.Store.DiffSchedules(r.Context(), since, current)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, diff)
+}
+
+// syncRunParam resolves raw, a sync run ID or an RFC 3339 timestamp selecting
+// the last run finished by then, to a finished run. It writes an error
+// response, with invalidKey when raw is neither, and reports false when no
+// such run is kept.
+func (router *Router) syncRunParam(w http.ResponseWriter, r *http.Request, raw, invalidKey string) (domain.SyncRun, bool) {
+	var run domain.SyncRun
+	var err error
+	if id, convErr := strconv.ParseInt(raw, 10, 64); convErr == nil {
+		run, err = router.Store.GetSyncRun(r.Context(), id)
+	} else if t, parseErr := time.Parse(time.RFC3339, raw); parseErr == nil {
+		run, err = router.Store.GetSyncRunAt(r.Context(), t)
+	} else {
+		writeError(w, r, http.StatusBadRequest, invalidKey)
+		return domain.SyncRun{}, false
+	}
+	if errors.Is(err, store.ErrNotFound) || (err == nil && run.FinishedAt == nil) {
+		writeProblem(w, r, Problem{
+			Type:   problemTypeSyncRunUnavailable,
+			Code:   "sync_run_unavailable",
+			Status: http.StatusGone,
+		}, raw)
+		return domain.SyncRun{}, false
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return domain.SyncRun{}, false
+	}
+	return run, true
+}
+
+const (
+	defaultSyncHistoryLimit = 20
+	maxSyncHistoryLimit     = 100
+)
+
+// HandleSyncHistory serves /api/v1/sync/history?limit=, the most recent sync
+// runs first with what started them and how far they got. Runs older than
+// the sync_run_retention newest ones are pruned.
+func (router *Router) HandleSyncHistory(w http.ResponseWriter, r *http.Request) {
+	limit := defaultSyncHistoryLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxSyncHistoryLimit {
+			writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxSyncHistoryLimit)
+			return
+		}
+		limit = n
+	}
+
+	runs, err := router.Store.ListSyncRuns(r.Context(), limit)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, runs)
+}
+
+// HandleSyncIssues serves /api/v1/sync/issues?run=&kind=, the data integrity
+// issues found after a sync run (default the latest finished one), optionally
+// only those of kind. Counts cover every kind either way.
+func (router *Router) HandleSyncIssues(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	if kind != "" && !slices.Contains(domain.IssueKinds, kind) {
+		writeError(w, r, http.StatusBadRequest, "issue_kind_invalid", strings.Join(domain.IssueKinds, ", "))
+		return
+	}
+
+	var run domain.SyncRun
+	var err error
+	raw := query.Get("run")
+	if raw == "" {
+		run, err = router.Store.GetLatestSyncRun(r.Context())
+	} else if id, convErr := strconv.ParseInt(raw, 10, 64); convErr == nil {
+		run, err = router.Store.GetSyncRun(r.Context(), id)
+	} else {
+		writeError(w, r, http.StatusBadRequest, "run_invalid")
+		return
+	}
+	if errors.Is(err, store.ErrNotFound) || (err == nil && run.FinishedAt == nil) {
+		if raw == "" {
+			writeError(w, r, http.StatusNotFound, "no_sync_run")
+		} else {
+			writeError(w, r, http.StatusNotFound, "sync_run_not_found", raw)
+		}
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	counts, err := router.Store.CountSyncIssues(r.Context(), run.ID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	issues, err := router.Store.GetSyncIssues(r.Context(), run.ID, kind)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, domain.SyncIssueReport{Run: run, Counts: counts, Issues: issues})
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxSubmissionBody = 16 * 1024
+	maxCaptionLength  = 280
+)
+
+type submissionRequest struct {
+	Kind       domain.SubmissionKind     `json:"kind"`
+	PhotoURL   string                    `json:"photo_url"`
+	Caption    string                    `json:"caption"`
+	Facilities *domain.StationFacilities `json:"facilities"`
+	Submitter  string                    `json:"submitter"`
+}
+
+// HandleStationSubmission serves POST /api/v1/station/{id}/submissions,
+// queueing a photo or amenity correction for moderation.
+func (router *Router) HandleStationSubmission(w http.ResponseWriter, r *http.Request, stationID string) {
+	if !router.featureEnabled(domain.FeatureCommunity) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	if _, err := router.Store.GetStation(r.Context(), stationID); errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "station_not_found")
+		return
+	} else if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	var req submissionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionBody)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+
+	sub := domain.Submission{
+		StationID:   stationID,
+		Kind:        req.Kind,
+		Submitter:   strings.TrimSpace(req.Submitter),
+		SubmittedAt: time.Now(),
+	}
+
+	switch req.Kind {
+	case domain.SubmissionKindPhoto:
+		u, err := url.Parse(req.PhotoURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			writeError(w, r, http.StatusBadRequest, "photo_url_invalid")
+			return
+		}
+		if len(req.Caption) > maxCaptionLength {
+			writeError(w, r, http.StatusBadRequest, "caption_too_long")
+			return
+		}
+		sub.Payload = domain.SubmissionPayload{PhotoURL: u.String(), Caption: strings.TrimSpace(req.Caption)}
+	case domain.SubmissionKindAmenity:
+		if req.Facilities == nil {
+			writeError(w, r, http.StatusBadRequest, "facilities_required")
+			return
+		}
+		sub.Payload = domain.SubmissionPayload{Facilities: req.Facilities}
+	default:
+		writeError(w, r, http.StatusBadRequest, "submission_kind")
+		return
+	}
+
+	id, err := router.Store.CreateSubmission(r.Context(), sub)
+	if err != nil {
+		router.log(r).Error("Failed to store submission", zap.String("station", stationID), zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "submission_save_failed")
+		return
+	}
+
+	sub.ID = id
+	sub.Status = domain.SubmissionPending
+	router.respondStatus(w, r, http.StatusAccepted, sub)
+}
+
+// HandleAdminSubmissions serves the moderation queue:
+//
+//	GET  /api/admin/submissions?status=pending
+//	POST /api/admin/submissions/{id}/approve
+//	POST /api/admin/submissions/{id}/reject
+func (router *Router) HandleAdminSubmissions(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/submissions"), "/")
+
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+			return
+		}
+		status := domain.SubmissionStatus(r.URL.Query().Get("status"))
+		if status == "" {
+			status = domain.SubmissionPending
+		}
+		subs, err := router.Store.ListSubmissions(r.Context(), status)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		if subs == nil {
+			subs = []domain.Submission{}
+		}
+		router.respond(w, r, subs)
+		return
+	}
+
+	idStr, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "submission_id_invalid")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	var status domain.SubmissionStatus
+	switch action {
+	case "approve":
+		status = domain.SubmissionApproved
+	case "reject":
+		status = domain.SubmissionRejected
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := router.Store.GetSubmission(r.Context(), id); errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "submission_not_found")
+		return
+	} else if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	var body struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionBody)).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+	}
+
+	if err := router.Store.ReviewSubmission(r.Context(), id, status, body.Note); err != nil {
+		router.log(r).Error("Failed to review submission", zap.Int64("id", id), zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "review_failed")
+		return
+	}
+
+	sub, err := router.Store.GetSubmission(r.Context(), id)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	// An approved photo or amenity shows on the station's page.
+	if status == domain.SubmissionApproved {
+		router.purge(r.Context(), []string{stationKey(sub.StationID)})
+	}
+	router.respond(w, r, sub)
+}
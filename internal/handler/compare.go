@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"llm-router/internal/domain"
+)
+
+const (
+	// defaultMinShiftMinutes and maxMinShiftMinutes are the default and
+	// largest min_shift_minutes= of /api/v1/compare.
+	defaultMinShiftMinutes = 1
+	maxMinShiftMinutes     = 180
+)
+
+// HandleCompare serves /api/v1/compare?run_a=&run_b=&min_shift_minutes=,
+// the trains added, removed or re-timed from one kept sync run to another,
+// as when a new timetable takes effect. Runs are given as for
+// /api/v1/changes. Stops re-timed by less than min_shift_minutes (default 1)
+// are left out, and the changes are counted per line.
+func (router *Router) HandleCompare(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rawA, rawB := q.Get("run_a"), q.Get("run_b")
+	if rawA == "" || rawB == "" {
+		writeError(w, r, http.StatusBadRequest, "compare_runs_required")
+		return
+	}
+	minShift := defaultMinShiftMinutes
+	if raw := q.Get("min_shift_minutes"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > maxMinShiftMinutes {
+			writeError(w, r, http.StatusBadRequest, "min_shift_invalid", maxMinShiftMinutes)
+			return
+		}
+		minShift = n
+	}
+
+	runA, ok := router.syncRunParam(w, r, rawA, "compare_run_invalid")
+	if !ok {
+		return
+	}
+	runB, ok := router.syncRunParam(w, r, rawB, "compare_run_invalid")
+	if !ok {
+		return
+	}
+
+	diff, err := router.Store.DiffSchedules(r.Context(), runA, runB)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, compareSchedules(diff, minShift))
+}
+
+// compareSchedules drops from diff the stops re-timed by less than minShift
+// minutes, and the trains left without re-timed stops, and counts the rest
+// per line.
+func compareSchedules(diff domain.ScheduleDiff, minShift int) domain.ScheduleComparison {
+	c := domain.ScheduleComparison{
+		RunA:            diff.Since,
+		RunB:            diff.Current,
+		MinShiftMinutes: minShift,
+		Lines:           []domain.LineChanges{},
+		Added:           diff.Added,
+		Removed:         diff.Removed,
+		Retimed:         []domain.TrainChange{},
+	}
+
+	lines := make(map[string]*domain.LineChanges)
+	line := func(name string) *domain.LineChanges {
+		l, ok := lines[name]
+		if !ok {
+			l = &domain.LineChanges{Line: name}
+			lines[name] = l
+		}
+		return l
+	}
+	for _, t := range diff.Added {
+		line(t.Line).Added++
+	}
+	for _, t := range diff.Removed {
+		line(t.Line).Removed++
+	}
+	for _, t := range diff.Retimed {
+		var stops []domain.StopChange
+		shift := 0
+		for _, stop := range t.Stops {
+			s := abs(stop.ShiftMinutes)
+			if stop.Before != "" && stop.After != "" && s < minShift {
+				continue
+			}
+			stops = append(stops, stop)
+			shift = max(shift, s)
+		}
+		if len(stops) == 0 {
+			continue
+		}
+		t.Stops = stops
+		c.Retimed = append(c.Retimed, t)
+		l := line(t.Line)
+		l.Retimed++
+		l.MaxShiftMinutes = max(l.MaxShiftMinutes, shift)
+	}
+
+	for _, l := range lines {
+		c.Lines = append(c.Lines, *l)
+	}
+	sort.Slice(c.Lines, func(i, j int) bool { return c.Lines[i].Line < c.Lines[j].Line })
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
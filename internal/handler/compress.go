@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/config"
+
+	"github.com/andybalholm/brotli"
+)
+
+// CompressionMiddleware compresses responses with brotli or gzip, whichever
+// the client accepts and is enabled. Responses below the configured size,
+// event streams and WebSocket upgrades are sent as is. It returns next
+// unchanged when compression is disabled.
+func CompressionMiddleware(cfg config.CompressionConfig, next http.Handler) http.Handler {
+	if !cfg.Gzip && !cfg.Brotli {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), cfg)
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minBytes: cfg.MinBytes, status: http.StatusOK}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the enabled content coding the client accepts, or
+// "" for none. Brotli wins over gzip; an explicit q=0 or a malformed q-value
+// refuses a coding.
+func negotiateEncoding(acceptEncoding string, cfg config.CompressionConfig) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed <= 1 {
+				q = parsed
+			} else {
+				q = 0
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+
+	switch {
+	case cfg.Brotli && accepted["br"]:
+		return "br"
+	case cfg.Gzip && accepted["gzip"]:
+		return "gzip"
+	}
+	return ""
+}
+
+// compressWriter buffers the start of a response until it is large enough to
+// be worth compressing, then either compresses it or passes it through.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minBytes int
+
+	status      int
+	wroteHeader bool
+	buf         []byte
+	// Exactly one of enc and passthrough is set once the choice is made.
+	enc         io.WriteCloser
+	passthrough bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+
+	h := cw.Header()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		status == http.StatusPartialContent || h.Get("Content-Encoding") != "" ||
+		!compressible(h.Get("Content-Type")) {
+		cw.passthrough = true
+		cw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// compressible reports whether a content type is worth compressing. Event
+// streams are excluded because each event must reach the client as it is
+// flushed.
+func compressible(contentType string) bool {
+	for _, prefix := range []string{"text/event-stream", "image/", "video/", "audio/", "font/woff2", "application/zip", "application/gzip"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case cw.passthrough:
+		return cw.ResponseWriter.Write(p)
+	case cw.enc != nil:
+		return cw.enc.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minBytes {
+		if err := cw.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (cw *compressWriter) startCompression() error {
+	h := cw.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", cw.encoding)
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if cw.encoding == "br" {
+		cw.enc = brotli.NewWriter(cw.ResponseWriter)
+	} else {
+		cw.enc = gzip.NewWriter(cw.ResponseWriter)
+	}
+	_, err := cw.enc.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// sendUncompressed writes a buffered response that stayed below minBytes.
+func (cw *compressWriter) sendUncompressed() error {
+	cw.passthrough = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// Flush sends what has been written so far, so streaming handlers work
+// through the middleware.
+func (cw *compressWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.enc == nil && !cw.passthrough {
+		cw.sendUncompressed()
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the response once the handler has returned.
+func (cw *compressWriter) Close() error {
+	switch {
+	case cw.enc != nil:
+		return cw.enc.Close()
+	case cw.passthrough:
+		return nil
+	case !cw.wroteHeader:
+		// The handler wrote nothing; let net/http send its default response.
+		return nil
+	}
+	return cw.sendUncompressed()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
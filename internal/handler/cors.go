@@ -2,52 +2,63 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/config"
 
 	"go.uber.org/zap"
 )
 
-// CORSMiddleware wraps an http.Handler with CORS headers that allow all origins
-func CORSMiddleware(next http.HandlerFunc, logger *zap.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers for all requests
-		origin := r.Header.Get("Origin")
-		if origin == "" {
-			origin = "*"
+// CORSMiddleware applies the configured cross-origin policy. A plain "*"
+// origin is answered with a wildcard unless credentials are allowed, in which
+// case the request origin is reflected, as browsers reject "*" with credentials.
+func CORSMiddleware(cors config.CORSConfig, next http.Handler, logger *zap.Logger) http.Handler {
+	methods := strings.Join(cors.AllowedMethods, ", ")
+	headers := strings.Join(cors.AllowedHeaders, ", ")
+	reflectHeaders := false
+	for _, h := range cors.AllowedHeaders {
+		if h == "*" {
+			reflectHeaders = true
 		}
+	}
+	maxAge := strconv.Itoa(int(cors.MaxAge.Seconds()))
 
-		w.Header().Set("Access-Control-Allow-Origin", origin)
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
 
-		// Handle preflight OPTIONS requests
-		if r.Method == "OPTIONS" {
-			logger.Debug("Handling OPTIONS request for CORS preflight")
+		w.Header().Add("Vary", "Origin")
 
-			// Get requested headers from the preflight request
-			reqHeaders := r.Header.Get("Access-Control-Request-Headers")
-			if reqHeaders != "" {
-				w.Header().Set("Access-Control-Allow-Headers", reqHeaders)
+		if origin != "" && cors.AllowsOrigin(origin) {
+			if cors.AllowsAnyOrigin() && !cors.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
 			} else {
-				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
+				w.Header().Set("Access-Control-Allow-Origin", origin)
 			}
-
-			// Log the requested method in preflight
-			if reqMethod := r.Header.Get("Access-Control-Request-Method"); reqMethod != "" {
-				logger.Debug("Preflight requested method", zap.String("method", reqMethod))
+			if cors.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
 			}
+			w.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
+		} else if origin != "" {
+			logger.Debug("CORS origin not allowed", zap.String("origin", origin))
+		}
 
-			w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
-			w.Header().Set("Content-Type", "text/plain")
-			w.Header().Set("Content-Length", "0")
-			w.WriteHeader(http.StatusNoContent)
+		if !preflight {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// For non-OPTIONS requests, set allowed headers
-		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept")
-
-		// Call the next handler
-		next(w, r)
-	}
+		// Handle preflight OPTIONS requests
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", methods)
+		if reqHeaders := r.Header.Get("Access-Control-Request-Headers"); reflectHeaders && reqHeaders != "" {
+			w.Header().Set("Access-Control-Allow-Headers", reqHeaders)
+		} else {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.Header().Set("Access-Control-Max-Age", maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
 }
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/i18n"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBoardRefresh = 30
+	minBoardRefresh     = 10
+	maxBoardRefresh     = 3600
+)
+
+// lineColorPattern accepts the hex colors the upstream gives lines; anything
+// else is drawn in boardDefaultColor.
+var lineColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{3,8}$`)
+
+const boardDefaultColor = "#6b7280"
+
+// boardPage is what the departure board template renders.
+type boardPage struct {
+	Lang        string
+	Station     string
+	Clock       string
+	Refresh     int
+	Transparent bool
+	Rows        []boardRow
+	Labels      map[string]string
+}
+
+type boardRow struct {
+	Time        string
+	Line        string
+	Color       string
+	Destination string
+	TrainID     string
+	Platform    string
+	Until       string
+	Delay       string
+}
+
+// boardLabels are the catalog keys of the board's fixed text.
+var boardLabels = []string{"board.title", "board.time", "board.destination", "board.train", "board.platform", "board.departs", "board.empty"}
+
+// HandleDepartureBoard serves /board/{stationID}?count=&service_type=
+// &refresh=&lang=&transparent=, a self-refreshing HTML departure board for
+// kiosk displays and stream overlays. It shows today's next departures from
+// the same timetable as /api/v1/schedule, with realtime delays when known.
+// lang overrides Accept-Language, which kiosks often cannot set, and
+// transparent drops the background for overlays.
+func (router *Router) HandleDepartureBoard(w http.ResponseWriter, r *http.Request) {
+	stationID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/board/"), "/")
+	if stationID == "" {
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
+		return
+	}
+	q := r.URL.Query()
+	count := defaultBoardCount
+	if raw := q.Get("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxBoardCount {
+			writeError(w, r, http.StatusBadRequest, "count_out_of_range", maxBoardCount)
+			return
+		}
+		count = n
+	}
+	refresh := defaultBoardRefresh
+	if raw := q.Get("refresh"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < minBoardRefresh || n > maxBoardRefresh {
+			writeError(w, r, http.StatusBadRequest, "refresh_out_of_range", minBoardRefresh, maxBoardRefresh)
+			return
+		}
+		refresh = n
+	}
+	transparent := false
+	if raw := q.Get("transparent"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "transparent_invalid")
+			return
+		}
+		transparent = v
+	}
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+
+	lang := i18n.Lang(displayLang(w, r))
+	if raw := q.Get("lang"); raw != "" {
+		lang = i18n.Negotiate(raw)
+	}
+
+	station, err := router.Store.GetStation(r.Context(), stationID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "station_not_found_id", stationID)
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	board, err := router.Timetable.DatedBoard(r.Context(), stationID, router.Timetable.OperatingDate())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		names[st.ID] = st.LocalName(string(lang))
+	}
+	delays := map[string]int{}
+	if positions, err := router.Store.GetStationTrainPositions(r.Context(), stationID); err != nil {
+		router.log(r).Warn("Failed to load train positions for board", zap.String("station", stationID), zap.Error(err))
+	} else {
+		for _, p := range positions {
+			delays[p.TrainID] = p.DelayMinutes
+		}
+	}
+	router.usage.hit(store.UsageKindStation, stationID)
+
+	now := time.Now()
+	page := boardPage{
+		Lang:        string(lang),
+		Station:     station.LocalName(string(lang)),
+		Clock:       now.In(calendar.Jakarta).Format("15:04"),
+		Refresh:     refresh,
+		Transparent: transparent,
+		Rows:        []boardRow{},
+		Labels:      make(map[string]string, len(boardLabels)),
+	}
+	for _, key := range boardLabels {
+		page.Labels[strings.TrimPrefix(key, "board.")] = i18n.T(lang, key)
+	}
+
+	for _, sch := range filterServices(board, services) {
+		if len(page.Rows) == count {
+			break
+		}
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+		departsAt := clockToday(sch.DepartsAt, now)
+		delay := delays[sch.TrainID]
+		until := departsAt.Add(time.Duration(delay) * time.Minute).Sub(now)
+		if until < 0 {
+			continue
+		}
+
+		row := boardRow{
+			Time:        departsAt.In(calendar.Jakarta).Format("15:04"),
+			Line:        sch.Line,
+			Color:       sch.Metadata.Origin.Color,
+			Destination: names[sch.StationDestinationID],
+			TrainID:     sch.TrainID,
+			Platform:    sch.Metadata.Platform,
+			Until:       i18n.T(lang, "board.now"),
+		}
+		if !lineColorPattern.MatchString(row.Color) {
+			row.Color = boardDefaultColor
+		}
+		if row.Destination == "" {
+			row.Destination = sch.StationDestinationID
+		}
+		if minutes := int(until / time.Minute); minutes > 0 {
+			row.Until = i18n.T(lang, "board.minutes", minutes)
+		}
+		if delay > 0 {
+			row.Delay = i18n.T(lang, "board.delay", delay)
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	// Rendered into a buffer so a template error can still be answered with
+	// a proper status.
+	var buf bytes.Buffer
+	if err := boardTemplate.Execute(&buf, page); err != nil {
+		router.log(r).Error("Failed to render departure board", zap.String("station", stationID), zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "encode_failed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(buf.Bytes())
+}
+
+var boardTemplate = template.Must(template.New("board").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>{{.Station}} · {{.Labels.title}}</title>
+<style>
+  :root { color-scheme: dark; }
+  * { box-sizing: border-box; }
+  body {
+    margin: 0; padding: 2vw;
+    font-family: system-ui, -apple-system, "Segoe UI", Roboto, sans-serif;
+    font-size: clamp(16px, 2.4vw, 48px);
+    color: #f9fafb;
+    background: {{if .Transparent}}transparent{{else}}#0b1220{{end}};
+  }
+  header { display: flex; justify-content: space-between; align-items: baseline; margin-bottom: 1vw; }
+  h1 { margin: 0; font-size: 1.6em; }
+  h1 small { display: block; font-size: 0.5em; font-weight: 400; opacity: 0.7; }
+  .clock { font-size: 1.6em; font-variant-numeric: tabular-nums; }
+  table { width: 100%; border-collapse: collapse; font-variant-numeric: tabular-nums; }
+  th { text-align: left; font-size: 0.6em; font-weight: 500; text-transform: uppercase; opacity: 0.6; padding: 0.3em 0.4em; }
+  td { padding: 0.35em 0.4em; border-top: 1px solid rgba(255,255,255,0.12); }
+  .time { font-weight: 700; }
+  .line { display: inline-block; width: 0.9em; height: 0.9em; border-radius: 50%; vertical-align: -0.1em; margin-right: 0.4em; }
+  .train, .platform { opacity: 0.8; }
+  .until { text-align: right; font-weight: 700; }
+  .delay { display: block; font-size: 0.6em; color: #fbbf24; }
+  .empty { padding: 2em 0; text-align: center; opacity: 0.7; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Station}}<small>{{.Labels.title}}</small></h1>
+  <div class="clock">{{.Clock}}</div>
+</header>
+{{if .Rows}}
+<table>
+  <thead>
+    <tr>
+      <th>{{.Labels.time}}</th>
+      <th>{{.Labels.destination}}</th>
+      <th>{{.Labels.train}}</th>
+      <th>{{.Labels.platform}}</th>
+      <th class="until">{{.Labels.departs}}</th>
+    </tr>
+  </thead>
+  <tbody>
+  {{range .Rows}}
+    <tr>
+      <td class="time">{{.Time}}</td>
+      <td><span class="line" style="background: {{.Color}}" title="{{.Line}}"></span>{{.Destination}}</td>
+      <td class="train">{{.TrainID}}</td>
+      <td class="platform">{{.Platform}}</td>
+      <td class="until">{{.Until}}{{if .Delay}}<span class="delay">{{.Delay}}</span>{{end}}</td>
+    </tr>
+  {{end}}
+  </tbody>
+</table>
+{{else}}
+<p class="empty">{{.Labels.empty}}</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDeparturesLimit = 50
+	maxDeparturesLimit     = 500
+)
+
+// HandleDepartures serves /api/v1/departures?line=&destination=&after=
+// &before=&service_type=&filter=&date=&limit=&offset=, departures across
+// every station in time-of-day order, from the timetable each station runs on
+// date (default today). after and before are inclusive HH:MM bounds; an after
+// later than before wraps past midnight. filter= narrows them further with a
+// filter expression. Stations closed on date are left out. Pages continue at
+// next_offset.
+//
+// Clients that prefer NDJSON get the departures one per line as they are
+// read. limit is then unbounded, and without it every departure from offset
+// is sent.
+func (router *Router) HandleDepartures(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	stream := wantsNDJSON(r)
+	filter := store.ScheduleFilter{
+		Line:          strings.TrimSpace(q.Get("line")),
+		DestinationID: strings.ToUpper(strings.TrimSpace(q.Get("destination"))),
+		Limit:         defaultDeparturesLimit,
+	}
+	if stream {
+		filter.Limit = 0
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *string
+	}{{"after", &filter.After}, {"before", &filter.Before}} {
+		raw := strings.TrimSpace(q.Get(bound.name))
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "time_of_day_invalid", bound.name)
+			return
+		}
+		*bound.dst = t.Format("15:04")
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || (n > maxDeparturesLimit && !stream) {
+			writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxDeparturesLimit)
+			return
+		}
+		filter.Limit = n
+	}
+	if raw := q.Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, r, http.StatusBadRequest, "offset_invalid")
+			return
+		}
+		filter.Offset = n
+	}
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+	filter.ServiceTypes = services
+	if filter.Conditions, ok = scheduleConditions(w, r); !ok {
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	if stream {
+		router.streamDepartures(w, r, filter, date)
+		return
+	}
+
+	// One more than a page is fetched to tell whether another follows.
+	limit := filter.Limit
+	filter.Limit++
+	departures, err := router.Timetable.DatedSchedules(r.Context(), filter, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	page := domain.DeparturePage{
+		ServiceDay: router.Calendar.ServiceDay(date),
+		Departures: departures,
+		Limit:      limit,
+		Offset:     filter.Offset,
+	}
+	if len(departures) > limit {
+		page.Departures = departures[:limit]
+		next := filter.Offset + limit
+		page.NextOffset = &next
+	}
+	router.respond(w, r, page)
+}
+
+// streamDepartures writes the departures matching filter on date as NDJSON.
+func (router *Router) streamDepartures(w http.ResponseWriter, r *http.Request, filter store.ScheduleFilter, date time.Time) {
+	stream := newNDJSONStream(w)
+	err := router.Timetable.EachDatedSchedule(r.Context(), filter, date, func(sch domain.Schedule) error {
+		return stream.Row(sch)
+	})
+	stream.Flush()
+	if err == nil {
+		err = stream.Err()
+	}
+	if err != nil {
+		router.log(r).Error("Failed to stream departures", zap.Int("rows", stream.Rows()), zap.Error(err))
+	}
+}
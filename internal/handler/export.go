@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// HandleDump serves /api/v1/export/dump: every station and schedule in one
+// document. Schedules are streamed row by row straight from the database so
+// the full network never has to fit in memory. Clients that prefer NDJSON
+// get one dumpRow per line instead.
+func (router *Router) HandleDump(w http.ResponseWriter, r *http.Request) {
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if stations == nil {
+		stations = []domain.Station{}
+	}
+	if wantsNDJSON(r) {
+		router.streamDump(w, r, stations)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	js := newJSONStream(w)
+	js.BeginObject()
+	metadata := map[string]interface{}{
+		"success":      true,
+		"generated_at": time.Now(),
+	}
+	router.addDataNotice(metadata)
+
+	js.Key("metadata")
+	js.Value(metadata)
+	js.Key("data")
+	js.BeginObject()
+	js.Key("stations")
+	js.Value(stations)
+	js.Key("schedules")
+	js.BeginArray()
+	err = router.Store.EachSchedule(r.Context(), func(sch domain.Schedule) error {
+		js.Value(sch)
+		return js.Err()
+	})
+	js.EndArray()
+	js.EndObject()
+	js.EndObject()
+	js.Flush()
+
+	// Headers are long gone by now; all we can do is log and let the client
+	// see a truncated document.
+	if err == nil {
+		err = js.Err()
+	}
+	if err != nil {
+		router.log(r).Error("Failed to stream dump", zap.Error(err))
+	}
+}
+
+// dumpRow is a line of the NDJSON dump, holding one of a station or a
+// schedule. Every station comes before the first schedule.
+type dumpRow struct {
+	Station  *domain.Station  `json:"station,omitempty"`
+	Schedule *domain.Schedule `json:"schedule,omitempty"`
+}
+
+// streamDump writes the dump as NDJSON.
+func (router *Router) streamDump(w http.ResponseWriter, r *http.Request, stations []domain.Station) {
+	stream := newNDJSONStream(w)
+	for i := range stations {
+		stream.Row(dumpRow{Station: &stations[i]})
+	}
+	err := router.Store.EachSchedule(r.Context(), func(sch domain.Schedule) error {
+		return stream.Row(dumpRow{Schedule: &sch})
+	})
+	stream.Flush()
+	if err == nil {
+		err = stream.Err()
+	}
+	if err != nil {
+		router.log(r).Error("Failed to stream dump", zap.Int("rows", stream.Rows()), zap.Error(err))
+	}
+}
+
+// HandleSchedulesSince serves /api/v1/schedules?updated_since=, the schedule
+// rows updated after an RFC 3339 timestamp as newline-delimited JSON, oldest
+// update first. Offline clients pass the latest updated_at they have seen to
+// refresh their copy of the bundle without downloading it again. Trains that
+// left the timetable are listed by /api/v1/changes.
+func (router *Router) HandleSchedulesSince(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	raw := r.URL.Query().Get("updated_since")
+	if raw == "" {
+		writeError(w, r, http.StatusBadRequest, "updated_since_required")
+		return
+	}
+	since, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "updated_since_invalid")
+		return
+	}
+
+	stream := newNDJSONStream(w)
+	err = router.Store.EachScheduleSince(r.Context(), since, func(sch domain.Schedule) error {
+		return stream.Row(sch)
+	})
+	stream.Flush()
+	if err == nil {
+		err = stream.Err()
+	}
+
+	// As with the dump, a failure part way can only be logged; the client
+	// sees the stream end early and refreshes again from the last row it got.
+	if err != nil {
+		router.log(r).Error("Failed to stream schedules", zap.Int("rows", stream.Rows()), zap.Error(err))
+	}
+}
+
+// offlineBundle is a gzipped SQLite database of the whole network.
+type offlineBundle struct {
+	data    []byte
+	version string
+	builtAt time.Time
+}
+
+// HandleBundle serves /api/v1/export/bundle, every station, schedule and line
+// as a gzipped SQLite database for apps to use offline. X-Bundle-Version and
+// the ETag change only when the data does, so apps can check for updates
+// with a HEAD request or If-None-Match without downloading the bundle again.
+func (router *Router) HandleBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	bundle, err := router.bundleData(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="commuter-`+bundle.version+`.db.gz"`)
+	w.Header().Set("ETag", `"`+bundle.version+`"`)
+	w.Header().Set("X-Bundle-Version", bundle.version)
+	w.Header().Set("X-Bundle-Format", strconv.Itoa(store.BundleFormat))
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", bundle.builtAt, bytes.NewReader(bundle.data))
+}
+
+// bundleData returns the offline bundle, building it on first use after each
+// sync.
+func (router *Router) bundleData(ctx context.Context) (*offlineBundle, error) {
+	if b := router.bundle.Load(); b != nil {
+		return b, nil
+	}
+	router.bundleMu.Lock()
+	defer router.bundleMu.Unlock()
+	if b := router.bundle.Load(); b != nil {
+		return b, nil
+	}
+
+	start := time.Now()
+	dir, err := os.MkdirTemp("", "commuter-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bundle.db")
+	if err := router.Store.WriteBundle(ctx, path); err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(raw)
+	b := &offlineBundle{
+		data:    buf.Bytes(),
+		version: hex.EncodeToString(sum[:8]),
+		builtAt: start,
+	}
+	router.bundle.Store(b)
+	router.Logger.Info("Built offline bundle",
+		zap.String("version", b.version),
+		zap.Int("bytes", len(b.data)),
+		zap.Duration("took", time.Since(start)),
+	)
+	return b, nil
+}
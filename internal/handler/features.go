@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// featureFlags are the admin overrides of feature flags. Overrides are
+// stored, so they outlive restarts until an admin removes them.
+type featureFlags struct {
+	mu        sync.RWMutex
+	overrides map[string]bool
+}
+
+// featureDefault is a feature's state without an override.
+func (router *Router) featureDefault(name string) bool {
+	if name == domain.FeatureCommunity {
+		return router.Config.CommunityEnabled
+	}
+	return true
+}
+
+func (router *Router) featureEnabled(name string) bool {
+	router.features.mu.RLock()
+	enabled, ok := router.features.overrides[name]
+	router.features.mu.RUnlock()
+	if ok {
+		return enabled
+	}
+	return router.featureDefault(name)
+}
+
+func (router *Router) featureFlags() []domain.FeatureFlag {
+	router.features.mu.RLock()
+	defer router.features.mu.RUnlock()
+
+	flags := make([]domain.FeatureFlag, 0, len(domain.Features))
+	for _, name := range domain.Features {
+		enabled, ok := router.features.overrides[name]
+		if !ok {
+			enabled = router.featureDefault(name)
+		}
+		flags = append(flags, domain.FeatureFlag{Name: name, Enabled: enabled, Overridden: ok})
+	}
+	return flags
+}
+
+// loadFeatureFlags reads the stored overrides. Overrides that cannot be read
+// are not applied; the features then follow the configuration.
+func (router *Router) loadFeatureFlags(ctx context.Context) {
+	overrides, err := router.Store.GetFeatureFlags(ctx)
+	if err != nil {
+		router.Logger.Error("Failed to load feature flags, using configured defaults", zap.Error(err))
+		overrides = map[string]bool{}
+	}
+	router.features.mu.Lock()
+	router.features.overrides = overrides
+	router.features.mu.Unlock()
+	router.applyFeatureFlags()
+}
+
+// applyFeatureFlags passes the flags other components act on to them.
+func (router *Router) applyFeatureFlags() {
+	router.Scraper.SetRealtimePaused(!router.featureEnabled(domain.FeatureRealtimePolling))
+}
+
+// HandleAdminFlags serves the feature flags:
+//
+//	GET    /api/admin/flags
+//	PUT    /api/admin/flags         {"community": true, ...}
+//	DELETE /api/admin/flags/{name}  back to the configured default
+func (router *Router) HandleAdminFlags(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/flags"), "/")
+	if name != "" {
+		if r.Method != http.MethodDelete {
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+			return
+		}
+		if !slices.Contains(domain.Features, name) {
+			writeError(w, r, http.StatusNotFound, "feature_unknown", name, strings.Join(domain.Features, ", "))
+			return
+		}
+		if err := router.Store.DeleteFeatureFlag(r.Context(), name); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.setFeatureOverride(name, nil)
+		router.respond(w, r, router.featureFlags())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req map[string]bool
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		for flag := range req {
+			if !slices.Contains(domain.Features, flag) {
+				writeError(w, r, http.StatusBadRequest, "feature_unknown", flag, strings.Join(domain.Features, ", "))
+				return
+			}
+		}
+		for flag, enabled := range req {
+			if err := router.Store.SetFeatureFlag(r.Context(), flag, enabled); err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			router.setFeatureOverride(flag, &enabled)
+		}
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	router.respond(w, r, router.featureFlags())
+}
+
+// setFeatureOverride sets or, when enabled is nil, removes the override of a
+// flag and applies the result.
+func (router *Router) setFeatureOverride(name string, enabled *bool) {
+	router.features.mu.Lock()
+	if enabled != nil {
+		router.features.overrides[name] = *enabled
+	} else {
+		delete(router.features.overrides, name)
+	}
+	router.features.mu.Unlock()
+
+	router.applyFeatureFlags()
+	router.Logger.Warn("Feature flag changed", zap.String("feature", name), zap.Bool("enabled", router.featureEnabled(name)))
+}
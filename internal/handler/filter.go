@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// scheduleConditions parses the optional filter= expression (see
+// store.ParseFilter). It writes an error response and reports false when the
+// expression is unusable; an absent filter yields nil.
+func scheduleConditions(w http.ResponseWriter, r *http.Request) ([]store.Condition, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("filter"))
+	if raw == "" {
+		return nil, true
+	}
+	conds, err := store.ParseFilter(raw)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "filter_invalid", err.Error())
+		return nil, false
+	}
+	return conds, true
+}
+
+// filterConditions returns the schedules meeting every one of conds, or
+// schedules itself when there are none. The input is not modified.
+func filterConditions(schedules []domain.Schedule, conds []store.Condition) []domain.Schedule {
+	if len(conds) == 0 {
+		return schedules
+	}
+	filtered := []domain.Schedule{}
+	for _, sch := range schedules {
+		if store.MatchAll(conds, sch) {
+			filtered = append(filtered, sch)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// HandleFirstLast serves /api/v1/station/{id}/first-last?date=&service_type=&to=,
+// the first and last trains of the day from a station towards each
+// destination of each line. to= keeps the trains that reach that station,
+// with when they arrive there, grouped by line.
+func (router *Router) HandleFirstLast(w http.ResponseWriter, r *http.Request, stationID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+	if !router.knownStations(w, r, stationID) {
+		return
+	}
+
+	board, err := router.Timetable.DatedBoard(r.Context(), stationID, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	schedules := append([]domain.Schedule{}, filterServices(board, services)...)
+
+	to := strings.TrimSpace(r.URL.Query().Get("to"))
+	if to != "" {
+		if to == stationID {
+			writeError(w, r, http.StatusBadRequest, "from_to_same")
+			return
+		}
+		if !router.knownStations(w, r, to) {
+			return
+		}
+		toBoard, err := router.Timetable.DatedBoard(r.Context(), to, date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		schedules = arrivalsTo(schedules, to, toBoard)
+	}
+
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	lang := displayLang(w, r)
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		names[st.ID] = st.LocalName(lang)
+	}
+
+	result := firstLast(schedules, stationID, to)
+	result.ServiceDay = router.Calendar.ServiceDay(date)
+	for i := range result.Services {
+		result.Services[i].DestinationName = names[result.Services[i].DestinationID]
+	}
+	router.usage.hit(store.UsageKindStation, stationID)
+	router.respond(w, r, result)
+}
+
+// firstLast groups a station's departures by line and destination, or by
+// line alone towards to when it is set, keeping the first and last of each.
+// Trains terminating at the station are left out.
+func firstLast(schedules []domain.Schedule, stationID, to string) domain.FirstLastTrains {
+	result := domain.FirstLastTrains{StationID: stationID, To: to, Services: []domain.FirstLastService{}}
+
+	type key struct{ line, destination string }
+	groups := make(map[key]*domain.FirstLastService)
+	for _, sch := range schedules {
+		if sch.DepartsAt.IsZero() || sch.StationDestinationID == stationID {
+			continue
+		}
+		k := key{sch.Line, sch.StationDestinationID}
+		if to != "" {
+			k.destination = to
+		}
+		g, ok := groups[k]
+		if !ok {
+			g = &domain.FirstLastService{Line: k.line, Color: sch.Metadata.Origin.Color, DestinationID: k.destination, First: sch, Last: sch}
+			groups[k] = g
+		}
+		g.Departures++
+		if sch.DepartsAt.Before(g.First.DepartsAt) {
+			g.First = sch
+		}
+		if sch.DepartsAt.After(g.Last.DepartsAt) {
+			g.Last = sch
+		}
+	}
+
+	for _, g := range groups {
+		result.Services = append(result.Services, *g)
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		a, b := result.Services[i], result.Services[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.DestinationID < b.DestinationID
+	})
+	return result
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+)
+
+const (
+	// defaultFrequencyGranularity is the interval trains are counted over
+	// without granularity=.
+	defaultFrequencyGranularity = time.Hour
+
+	// minFrequencyGranularity and maxFrequencyGranularity bound
+	// granularity=, which must also divide the day evenly.
+	minFrequencyGranularity = 5 * time.Minute
+	maxFrequencyGranularity = 6 * time.Hour
+)
+
+// handleLineFrequency serves /api/v1/line/{name}/frequency: how many of the
+// line's trains leave their origin in each interval of the day, overall and
+// per direction. granularity= is the interval, such as 30m, and date= picks
+// the timetable as elsewhere.
+func (router *Router) handleLineFrequency(w http.ResponseWriter, r *http.Request, line domain.Line) {
+	granularity := defaultFrequencyGranularity
+	if raw := strings.TrimSpace(r.URL.Query().Get("granularity")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < minFrequencyGranularity || d > maxFrequencyGranularity || d%time.Minute != 0 || (24*time.Hour)%d != 0 {
+			writeError(w, r, http.StatusBadRequest, "granularity_invalid")
+			return
+		}
+		granularity = d
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	serviceDay := router.Calendar.ServiceDay(date)
+	trips, err := router.lineTrips(r.Context(), date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	names, err := router.Timetable.StationNames(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	lang := i18n.Lang(displayLang(w, r))
+	router.respond(w, r, lineFrequency(lang, line.Name, serviceDay, trips[line.Name], names, granularity))
+}
+
+// lineFrequency counts trips into intervals of granularity by the time of
+// day they leave their origin. Trips past midnight count towards the early
+// intervals. Directions are labeled in lang.
+func lineFrequency(lang i18n.Lang, line, serviceDay string, trips []tripSpan, names map[string]string, granularity time.Duration) domain.LineFrequency {
+	minutes := int(granularity / time.Minute)
+	n := 24 * 60 / minutes
+	bucket := func(t time.Time) int { return (t.Hour()*60 + t.Minute()) / minutes }
+
+	f := domain.LineFrequency{
+		Line:               line,
+		ServiceDay:         serviceDay,
+		GranularityMinutes: minutes,
+		Trains:             len(trips),
+		Intervals:          frequencyIntervals(n, minutes),
+		Directions:         []domain.DirectionFrequency{},
+	}
+	directions := make(map[string]*domain.DirectionFrequency)
+	for _, t := range trips {
+		f.Intervals[bucket(t.DepartsAt)].Trains++
+		d, ok := directions[t.Terminus]
+		if !ok {
+			d = &domain.DirectionFrequency{
+				TerminusID:   t.Terminus,
+				TerminusName: names[t.Terminus],
+				Label:        i18n.T(lang, "direction.towards", names[t.Terminus]),
+				Intervals:    frequencyIntervals(n, minutes),
+			}
+			directions[t.Terminus] = d
+		}
+		d.Trains++
+		d.Intervals[bucket(t.DepartsAt)].Trains++
+	}
+
+	for _, d := range directions {
+		for i, iv := range d.Intervals {
+			if iv.Trains > 0 {
+				headway := math.Round(float64(minutes)/float64(iv.Trains)*10) / 10
+				d.Intervals[i].HeadwayMinutes = &headway
+			}
+		}
+		f.Directions = append(f.Directions, *d)
+	}
+	sort.Slice(f.Directions, func(i, j int) bool { return f.Directions[i].TerminusName < f.Directions[j].TerminusName })
+	return f
+}
+
+// frequencyIntervals returns n empty intervals of minutes each, from
+// midnight.
+func frequencyIntervals(n, minutes int) []domain.FrequencyInterval {
+	intervals := make([]domain.FrequencyInterval, n)
+	for i := range intervals {
+		start := i * minutes
+		intervals[i].Start = fmt.Sprintf("%02d:%02d", start/60, start%60)
+	}
+	return intervals
+}
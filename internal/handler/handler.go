@@ -1,13 +1,30 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"llm-router/hooks"
+	"llm-router/internal/cache"
+	"llm-router/internal/calendar"
 	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/geocode"
+	"llm-router/internal/journey"
+	"llm-router/internal/mailer"
+	"llm-router/internal/push"
+	"llm-router/internal/retention"
 	"llm-router/internal/scrapper"
+	"llm-router/internal/search"
 	"llm-router/internal/store"
+	"llm-router/internal/timetable"
 
 	"go.uber.org/zap"
 )
@@ -16,129 +33,337 @@ type Router struct {
 	Config  *config.Config
 	Store   *store.Store
 	Scraper *scrapper.Scraper
+	Events  *events.Hub
 	Logger  *zap.Logger
+
+	// Geocoder resolves journey addresses; nil when geocoding is disabled.
+	Geocoder geocode.Geocoder
+
+	// Calendar picks the timetable variant served for a date.
+	Calendar *calendar.Calendar
+
+	// ConfigWatcher reloads the configuration on request; nil disables the
+	// reload endpoint.
+	ConfigWatcher *config.Watcher
+
+	// LogLevel is the logger's level, changed at runtime through the admin
+	// API; nil disables the log level endpoint.
+	LogLevel *zap.AtomicLevel
+
+	// Mailer sends sign-in links; nil disables user accounts.
+	Mailer mailer.Mailer
+
+	// Push sends commute notifications; nil disables them.
+	Push *push.Sender
+
+	// Retention runs the maintenance job on demand.
+	Retention *retention.Job
+
+	// Timetable reads boards and routes as they run on a date, shared with
+	// the gRPC API.
+	Timetable *timetable.Reader
+
+	lines    *cache.Cache[domain.Line]
+	planners *cache.Cache[*journey.Planner]
+	trips    *cache.Cache[lineTrips]
+	arrivals *cache.Cache[arrivalIndex]
+	search   atomic.Pointer[search.Index]
+	usage    *usageTracker
+
+	// autocomplete serves station autocomplete; see stationAutocomplete.
+	autocomplete atomic.Pointer[stationAutocomplete]
+
+	// bundle is the offline bundle built after the last sync; bundleMu
+	// keeps concurrent requests from building it more than once.
+	bundle   atomic.Pointer[offlineBundle]
+	bundleMu sync.Mutex
+
+	maintenance maintenanceMode
+	features    featureFlags
+	adminKey    adminKey
+
+	// hooks post-process enveloped responses and purgeHooks purge shared
+	// caches after a sync; see package hooks.
+	hooks      []hooks.ResponseHook
+	purgeHooks []hooks.PurgeHook
+
+	// lastSync is when the last sync finished, for Last-Modified.
+	lastSync atomic.Pointer[time.Time]
+
+	// loops tracks the goroutines started by Start.
+	loops sync.WaitGroup
+}
+
+func NewRouter(cfg *config.Config, s *store.Store, scr *scrapper.Scraper, hub *events.Hub, geo geocode.Geocoder, cal *calendar.Calendar, l *zap.Logger) *Router {
+	router := &Router{
+		Config:    cfg,
+		Store:     s,
+		Scraper:   scr,
+		Events:    hub,
+		Logger:    l,
+		Geocoder:  geo,
+		Calendar:  cal,
+		Timetable: timetable.New(cfg, s, cal),
+		lines:     cache.New[domain.Line](),
+		planners:  cache.New[*journey.Planner](),
+		trips:     cache.New[lineTrips](),
+		arrivals:  cache.New[arrivalIndex](),
+		usage:     newUsageTracker(s, l),
+		hooks:     hooks.ResponseHooks(),
+	}
+	router.purgeHooks = hooks.PurgeHooks()
+	router.maintenance.configured = cfg.Maintenance
+	router.maintenance.state = maintenanceFromConfig(cfg.Maintenance)
+	router.loadFeatureFlags(context.Background())
+	router.loadAdminKey(context.Background())
+	return router
 }
 
-func NewRouter(cfg *config.Config, s *store.Store, scr *scrapper.Scraper, l *zap.Logger) *Router {
-	return &Router{
-		Config:  cfg,
-		Store:   s,
-		Scraper: scr,
-		Logger:  l,
+// Start starts cache invalidation, usage flushing, metrics snapshots and CDN
+// purges, which run until ctx is done.
+func (router *Router) Start(ctx context.Context) {
+	loops := []func(context.Context){router.invalidateOnSync, router.usage.run}
+	if router.Config.MetricsInterval > 0 {
+		loops = append(loops, router.runMetrics)
+	}
+	if router.Push != nil {
+		loops = append(loops, router.runCommuteReminders)
+	}
+	if router.Config.HTTPCache.PurgeURL != "" || len(router.purgeHooks) > 0 {
+		loops = append(loops, router.purgeOnChange)
+	}
+	for _, loop := range loops {
+		router.loops.Add(1)
+		go func() {
+			defer router.loops.Done()
+			loop(ctx)
+		}()
 	}
 }
 
+// Stop waits for the loops started by Start to exit, which they do once its
+// context is done, and saves the usage counted since the last flush.
+func (router *Router) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		router.loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	router.usage.flush(ctx)
+	return nil
+}
+
 func (router *Router) HandleStation(w http.ResponseWriter, r *http.Request) {
-	stations := router.Store.GetStations()
+	stations, err := router.Store.GetStations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"metadata": map[string]bool{"success": true},
-		"data":     stations,
-	})
+	lang := displayLang(w, r)
+	today := calendar.FormatDate(router.Timetable.OperatingDate())
+	for i := range stations {
+		stations[i].DisplayName = stations[i].NameOn(today, lang)
+	}
+	router.respond(w, r, stations)
 }
 
+// HandleSchedule serves /api/v1/schedule/{id}?date=&service_type=&filter=&to=,
+// a station's departures. A past date= is served from the schedule history
+// while it is kept. filter= keeps those meeting a filter expression. to=
+// keeps only the trains that reach that station and adds when they arrive
+// there. Announcements in effect for the station
+// or its lines are listed in the metadata. A station closed on date is 410
+// Gone.
 func (router *Router) HandleSchedule(w http.ResponseWriter, r *http.Request) {
 	// Extract station ID from URL path (assuming /api/v1/schedule/{id})
 	stationID := strings.TrimPrefix(r.URL.Path, "/api/v1/schedule/")
 
 	if stationID == "" {
-		http.Error(w, "Station ID required", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
 		return
 	}
 
+	if id, ok := strings.CutSuffix(stationID, "/stream"); ok {
+		router.HandleScheduleStream(w, r, id)
+		return
+	}
+
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+	conds, ok := scheduleConditions(w, r)
+	if !ok {
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	closed, err := router.Timetable.StationClosed(r.Context(), stationID, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if closed {
+		writeError(w, r, http.StatusGone, "station_closed", stationID)
+		return
+	}
+
+	// Copy the cached board, since reliability is attached per request.
 	// If stationID is not found, return empty list [] instead of null
-	schedules := router.Store.GetSchedules(stationID)
-	if schedules == nil {
-		schedules = []store.Schedule{}
+	board, err := router.Timetable.DatedBoard(r.Context(), stationID, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if len(board) == 0 && router.writeStationSyncProblem(w, r, stationID) {
+		return
+	}
+	schedules := append([]domain.Schedule{}, filterConditions(filterServices(board, services), conds)...)
+
+	// to= keeps the trains that go on to that station, with their arrival.
+	if to := strings.TrimSpace(r.URL.Query().Get("to")); to != "" {
+		if to == stationID {
+			writeError(w, r, http.StatusBadRequest, "from_to_same")
+			return
+		}
+		if _, err := router.Store.GetStation(r.Context(), to); errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "station_not_found")
+			return
+		} else if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		toBoard, err := router.Timetable.DatedBoard(r.Context(), to, date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		schedules = arrivalsTo(schedules, to, toBoard)
+	}
+
+	if len(schedules) > 0 {
+		router.usage.hit(store.UsageKindStation, stationID)
 	}
+	router.attachReliability(r.Context(), schedules)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"metadata": map[string]bool{"success": true},
-		"data":     schedules,
-	})
+	var lines []string
+	for _, sch := range board {
+		if !slices.Contains(lines, sch.Line) {
+			lines = append(lines, sch.Line)
+		}
+	}
+	router.respondAnnounced(w, r, schedules, router.announcementsFor(r, lines, []string{stationID}))
 }
 
+// arrivalsTo keeps the schedules whose train reaches station to later on,
+// found among toBoard, that station's departures, and sets their To. A train
+// ending at to arrives at its published time even if to lists no departure
+// for it.
+func arrivalsTo(schedules []domain.Schedule, to string, toBoard []domain.Schedule) []domain.Schedule {
+	stops := make(map[string]domain.Schedule, len(toBoard))
+	for _, sch := range toBoard {
+		stops[sch.TrainID] = sch
+	}
+
+	kept := schedules[:0]
+	for _, sch := range schedules {
+		var arrivesAt *time.Time
+		var estimated bool
+		if sch.StationDestinationID == to && !sch.ArrivesAt.IsZero() {
+			arrivesAt = &sch.ArrivesAt
+		} else if stop, ok := stops[sch.TrainID]; ok {
+			arrivesAt, estimated = journey.Arrival(sch, stop)
+		}
+		if arrivesAt == nil {
+			continue
+		}
+
+		sch.To = &domain.Arrival{
+			StationID:       to,
+			ArrivesAt:       *arrivesAt,
+			Estimated:       estimated,
+			DurationMinutes: int(arrivesAt.Sub(sch.DepartsAt).Minutes()),
+		}
+		kept = append(kept, sch)
+	}
+	return kept
+}
+
+// HandleRoute serves /api/v1/route/{train}?date=&from=, a train's stops with
+// their departure and arrival times. from= marks the boarding stop.
+// Announcements in effect for its line or stops are listed in the metadata.
+// /api/v1/route/{train}/shape is served by HandleRouteShape.
 func (router *Router) HandleRoute(w http.ResponseWriter, r *http.Request) {
 	trainID := strings.TrimPrefix(r.URL.Path, "/api/v1/route/")
+	if id, ok := strings.CutSuffix(trainID, "/shape"); ok && id != "" {
+		router.HandleRouteShape(w, r, id)
+		return
+	}
 
 	if trainID == "" {
-		http.Error(w, "Train ID required", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "train_id_required")
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
 		return
 	}
 
-	schedules := router.Store.GetRoute(trainID)
-
-	if len(schedules) == 0 {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"metadata": map[string]bool{"success": true},
-			"data":     []interface{}{},
-		})
+	response, err := router.Timetable.DatedRoute(r.Context(), trainID, date)
+	if errors.Is(err, store.ErrNotFound) {
+		router.respond(w, r, []interface{}{})
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
 		return
 	}
 
-	// We need station names, so let's get all stations to lookup names
-	// This is slightly inefficient but given station count is small (100+), it's fine for now.
-	// A better way would be GetStation(id) but doing it in loop is worse (N+1).
-	// Or we could cache this map in the Router or Store.
-	// For now, let's just fetch all stations to build a map.
-	stationList := router.Store.GetStations()
-	stationMap := make(map[string]string)
-	for _, st := range stationList {
-		stationMap[st.ID] = st.Name
+	// from= marks where the rider boards. The cached stops are shared, so
+	// they are copied before marking.
+	if from := strings.TrimSpace(r.URL.Query().Get("from")); from != "" {
+		i := slices.IndexFunc(response.Routes, func(stop domain.RouteStop) bool { return stop.StationID == from })
+		if i < 0 {
+			writeError(w, r, http.StatusBadRequest, "from_not_on_route", from, trainID)
+			return
+		}
+		response.Routes = slices.Clone(response.Routes)
+		response.Routes[i].Boarding = true
 	}
 
-	var routes []store.RouteStop
-	for _, sch := range schedules {
-		routes = append(routes, store.RouteStop{
-			ID:          sch.ID,
-			StationID:   sch.StationID,
-			StationName: stationMap[sch.StationID],
-			DepartsAt:   sch.DepartsAt,
-			CreatedAt:   sch.UpdatedAt, // Use UpdatedAt as proxy
-			UpdatedAt:   sch.UpdatedAt,
-		})
-	}
-
-	first := schedules[0]
-	last := schedules[len(schedules)-1]
-
-	details := store.RouteDetail{
-		TrainID:                trainID,
-		Line:                   first.Line,
-		Route:                  first.Route,
-		StationOriginID:        first.StationOriginID,
-		StationOriginName:      stationMap[first.StationOriginID],
-		StationDestinationID:   first.StationDestinationID,
-		StationDestinationName: stationMap[first.StationDestinationID],
-		ArrivesAt:              last.ArrivesAt,
-	}
-
-	response := store.RouteData{
-		Routes:  routes,
-		Details: details,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"metadata": map[string]bool{"success": true},
-		"data":     response,
-	})
+	stations := make([]string, 0, len(response.Routes))
+	for _, stop := range response.Routes {
+		stations = append(stations, stop.StationID)
+	}
+	router.respondAnnounced(w, r, response, router.announcementsFor(r, []string{response.Details.Line}, stations))
 }
 
+// HandleSync serves GET /api/v1/sync, the status of the current or last
+// sync, and POST /api/v1/sync, which starts a full sync and, like the admin
+// endpoints, needs the admin token.
 func (router *Router) HandleSync(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if r.Method == http.MethodGet {
+		router.respond(w, r, router.Scraper.Status())
 		return
 	}
 
-	go router.Scraper.SyncAll()
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"metadata": map[string]bool{"success": true},
-		"data":     "Sync triggered",
-	})
+	router.RequireAdmin(func(w http.ResponseWriter, r *http.Request) {
+		go router.Scraper.SyncAll(domain.SyncTriggerAPI)
+		router.respond(w, r, "Sync triggered")
+	})(w, r)
 }
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// Health check statuses.
+const (
+	healthOK   = "ok"
+	healthFail = "fail"
+)
+
+// healthReport is the body of the health endpoints. Status is fail when any
+// check failed.
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]healthCheck `json:"checks,omitempty"`
+}
+
+type healthCheck struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// LatencyMillis is how long the check took.
+	LatencyMillis int64 `json:"latency_ms"`
+
+	Schedules  *int64     `json:"schedules,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	AgeSeconds *int64     `json:"age_seconds,omitempty"`
+
+	RunID      int64      `json:"run_id,omitempty"`
+	RunStatus  string     `json:"run_status,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// HandleLive serves /health and /health/live: the process is up and serving
+// requests. It checks no dependencies, so orchestrators only restart a
+// process that stopped responding.
+func (router *Router) HandleLive(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, healthReport{Status: healthOK})
+}
+
+// HandleReady serves /health/ready: whether the instance can serve useful
+// data. It answers 503 unless the database is reachable, holds schedules no
+// older than the configured maximum age, and the last sync did not fail.
+func (router *Router) HandleReady(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	report := healthReport{Status: healthOK, Checks: make(map[string]healthCheck, 3)}
+	run := func(name string, check func(*healthCheck)) {
+		start := time.Now()
+		c := healthCheck{Status: healthOK}
+		check(&c)
+		c.LatencyMillis = time.Since(start).Milliseconds()
+		if c.Status != healthOK {
+			report.Status = healthFail
+		}
+		report.Checks[name] = c
+	}
+
+	run("database", func(c *healthCheck) {
+		if err := router.Store.Ping(ctx); err != nil {
+			c.Status, c.Message = healthFail, err.Error()
+		}
+	})
+
+	run("data", func(c *healthCheck) {
+		count, updated, err := router.Store.ScheduleFreshness(ctx)
+		if err != nil {
+			c.Status, c.Message = healthFail, err.Error()
+			return
+		}
+		c.Schedules = &count
+		if count == 0 {
+			c.Status, c.Message = healthFail, "no schedules stored"
+			return
+		}
+		age := time.Since(updated)
+		ageSeconds := int64(age / time.Second)
+		c.UpdatedAt, c.AgeSeconds = &updated, &ageSeconds
+		if maxAge := router.Config.ReadyMaxDataAge; maxAge > 0 && age > maxAge {
+			c.Status, c.Message = healthFail, "schedules are older than "+maxAge.String()
+		}
+	})
+
+	run("sync", func(c *healthCheck) {
+		last, err := router.Store.GetLatestSyncRun(ctx)
+		if errors.Is(err, store.ErrNotFound) {
+			c.Message = "no sync has finished yet"
+			return
+		}
+		if err != nil {
+			c.Status, c.Message = healthFail, err.Error()
+			return
+		}
+		c.RunID, c.RunStatus, c.FinishedAt = last.ID, last.Status, last.FinishedAt
+		if last.Status == domain.SyncRunFailed {
+			c.Status, c.Message = healthFail, "last sync failed: "+last.Error
+		}
+	})
+
+	writeHealth(w, report)
+}
+
+func writeHealth(w http.ResponseWriter, report healthReport) {
+	status := http.StatusOK
+	if report.Status != healthOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
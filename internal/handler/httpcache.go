@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// Surrogate keys tag cached responses by the data they show: every response
+// has surrogateKeyAll, and either the station keys of the stations it covers
+// or surrogateKeyNetwork when it spans the network.
+const (
+	surrogateKeyAll     = "all"
+	surrogateKeyNetwork = "network"
+)
+
+// stationKey is the surrogate key of responses about one station.
+func stationKey(stationID string) string {
+	return "station-" + stationID
+}
+
+// purgeTimeout bounds a purge request to the CDN.
+const purgeTimeout = 10 * time.Second
+
+// Cacheable lets shared caches keep the successful responses of a read
+// endpoint for the configured max age, tagging them with surrogate keys
+// (see surrogateKeys) and the time of the last sync as Last-Modified.
+// Responses that set their own Cache-Control, and event streams, are left
+// alone.
+func (router *Router) Cacheable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := router.Config.HTTPCache
+		keys := surrogateKeys(r)
+		if cfg.MaxAge <= 0 || keys == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next(w, r)
+			return
+		}
+
+		next(&cacheHeaderWriter{ResponseWriter: w, header: func(h http.Header) {
+			cacheControl := "public, max-age=" + strconv.Itoa(int(cfg.MaxAge/time.Second))
+			if cfg.StaleWhileRevalidate > 0 {
+				cacheControl += ", stale-while-revalidate=" + strconv.Itoa(int(cfg.StaleWhileRevalidate/time.Second))
+			}
+			h.Set("Cache-Control", cacheControl)
+			h.Set("Surrogate-Key", strings.Join(keys, " "))
+			if t := router.lastModified(r.Context()); !t.IsZero() {
+				h.Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+			}
+		}}, r)
+	}
+}
+
+// surrogateKeys returns the keys to tag a response to r with, or nil when it
+// should not be cached: it changes between syncs or is a stream.
+func surrogateKeys(r *http.Request) []string {
+	path := r.URL.Path
+	var stations []string
+	if id, ok := strings.CutPrefix(path, "/api/v1/schedule/"); ok {
+		if strings.HasSuffix(id, "/stream") {
+			return nil
+		}
+		stations = []string{id}
+	} else if rest, ok := strings.CutPrefix(path, "/api/v1/station/"); ok {
+		id, sub, _ := strings.Cut(rest, "/")
+		switch {
+		case id == "":
+		case sub == "" || sub == "first-last":
+			stations = []string{id}
+		default:
+			return nil
+		}
+	}
+	if len(stations) == 0 {
+		return []string{surrogateKeyNetwork, surrogateKeyAll}
+	}
+
+	// to= adds the trains' arrival at another station.
+	if to := strings.TrimSpace(r.URL.Query().Get("to")); to != "" {
+		stations = append(stations, to)
+	}
+	keys := make([]string, 0, len(stations)+1)
+	for _, id := range stations {
+		keys = append(keys, stationKey(id))
+	}
+	return append(keys, surrogateKeyAll)
+}
+
+// cacheHeaderWriter adds caching headers to a response when it turns out to
+// be a 200.
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+	header      func(http.Header)
+	wroteHeader bool
+}
+
+func (cw *cacheHeaderWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		h := cw.Header()
+		if status == http.StatusOK && h.Get("Cache-Control") == "" && !strings.HasPrefix(h.Get("Content-Type"), "text/event-stream") {
+			cw.header(h)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheHeaderWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends what has been written so far, so streaming handlers work
+// through the wrapper.
+func (cw *cacheHeaderWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *cacheHeaderWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// lastModified returns when the last sync finished, or the zero time when
+// none has, loading it on first use after each sync.
+func (router *Router) lastModified(ctx context.Context) time.Time {
+	if t := router.lastSync.Load(); t != nil {
+		return *t
+	}
+	var t time.Time
+	run, err := router.Store.GetLatestSyncRun(ctx)
+	switch {
+	case err == nil && run.FinishedAt != nil:
+		t = *run.FinishedAt
+	case err != nil && !errors.Is(err, store.ErrNotFound):
+		router.Logger.Warn("Failed to load last sync for Last-Modified", zap.Error(err))
+		return t
+	}
+	router.lastSync.Store(&t)
+	return t
+}
+
+// purgeOnChange purges the responses each completed sync or announcement
+// feed poll made stale.
+func (router *Router) purgeOnChange(ctx context.Context) {
+	sub := router.Events.Subscribe(events.TopicSync, events.TopicAnnouncement)
+	defer sub.Close()
+	for {
+		select {
+		case e := <-sub.C:
+			var keys []string
+			switch e.Type {
+			case events.TypeSyncCompleted:
+				run, _ := e.Data.(domain.SyncRun)
+				keys = router.staleKeys(ctx, run)
+			case events.TypeAnnouncementsChanged:
+				changed, _ := e.Data.([]domain.Announcement)
+				keys = announcementKeys(changed...)
+			}
+			if len(keys) > 0 {
+				router.purge(ctx, keys)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// staleKeys returns the surrogate keys of the responses run changed: those
+// of the stations whose departures differ from the run before, and the
+// network. Everything is stale when there is no earlier run to compare with.
+func (router *Router) staleKeys(ctx context.Context, run domain.SyncRun) []string {
+	all := []string{surrogateKeyAll}
+	if run.ID == 0 {
+		return all
+	}
+	runs, err := router.Store.ListSyncRuns(ctx, router.Config.SyncRunRetention)
+	if err != nil {
+		router.Logger.Warn("Failed to list sync runs for purge", zap.Error(err))
+		return all
+	}
+	var since *domain.SyncRun
+	for i := range runs {
+		if runs[i].ID < run.ID && runs[i].FinishedAt != nil {
+			since = &runs[i]
+			break
+		}
+	}
+	if since == nil {
+		return all
+	}
+
+	stations, err := router.Store.ChangedStations(ctx, *since, run)
+	if errors.Is(err, store.ErrNotFound) {
+		return all
+	}
+	if err != nil {
+		router.Logger.Warn("Failed to compare sync runs for purge", zap.Error(err))
+		return all
+	}
+	if len(stations) == 0 {
+		return nil
+	}
+	keys := []string{surrogateKeyNetwork}
+	for _, id := range stations {
+		keys = append(keys, stationKey(id))
+	}
+	return keys
+}
+
+// purge asks the configured purge URL and the registered purge hooks to drop
+// the responses tagged with keys. Failures are logged.
+func (router *Router) purge(ctx context.Context, keys []string) {
+	if router.Config.HTTPCache.PurgeURL != "" {
+		if err := router.purgeURL(ctx, keys); err != nil {
+			router.Logger.Error("Failed to purge CDN", zap.Strings("keys", keys), zap.Error(err))
+		} else {
+			router.Logger.Info("Purged CDN", zap.Int("keys", len(keys)))
+		}
+	}
+	for _, h := range router.purgeHooks {
+		if err := h.Purge(ctx, keys); err != nil {
+			router.Logger.Warn("Purge hook failed", zap.Error(err))
+		}
+	}
+}
+
+// purgeURL posts keys to the configured purge URL.
+func (router *Router) purgeURL(ctx context.Context, keys []string) error {
+	cfg := router.Config.HTTPCache
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, purgeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.PurgeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cfg.PurgeHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("purge returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/geocode"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const (
+	problemTypeGeocodingDisabled = "/problems/geocoding-disabled"
+	problemTypeAddressNotFound   = "/problems/address-not-found"
+	problemTypeNoNearbyStation   = "/problems/no-nearby-station"
+	problemTypeNoJourney         = "/problems/no-journey"
+
+	// maxWalkMeters is the furthest straight-line distance from an address
+	// to the station it is resolved to.
+	maxWalkMeters = 3000
+
+	// maxJourneyTransfers and maxJourneyWalkMinutes bound max_transfers=
+	// and max_walk_minutes=.
+	maxJourneyTransfers   = 5
+	maxJourneyWalkMinutes = 30
+)
+
+// journeyEnd is one end of a journey: a station, and the address it was
+// resolved from when the request gave one.
+type journeyEnd struct {
+	stationID string
+	place     *geocode.Place
+	distance  float64
+}
+
+// HandleJourney serves /api/v1/journey. Each end is given either as a station
+// (from=, to=) or, when a geocoder is configured, as an address
+// (from_address=, to_address=) that is walked to or from the nearest station.
+// depart= is an optional HH:MM departure time, defaulting to now, on the
+// optional date=, which picks the timetable that runs that day; arrive_by=
+// instead plans the journey that leaves latest while arriving by HH:MM.
+// service_type= limits the trains that are boarded, and brt=true also rides
+// TransJakarta buses, walking to and from them at transfer points. Trains are
+// also changed on foot at the curated transfers between nearby stations.
+// avoid=, max_transfers= and max_walk_minutes= restrict the journey further
+// (see journeyOptions). The itinerary arriving earliest is returned, or with
+// optimize=transfers the one with the fewest transfers, alongside its
+// labeled alternatives.
+func (router *Router) HandleJourney(w http.ResponseWriter, r *http.Request) {
+	if !router.featureEnabled(domain.FeatureJourneyPlanner) {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+
+	opts, ok := router.journeyOptions(w, r)
+	if !ok {
+		return
+	}
+
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	// Schedules are stamped in Jakarta time, so depart= and arrive_by= are
+	// read there too. Without either, a trip on another date leaves at the
+	// current time of day.
+	now := time.Now().In(calendar.Jakarta)
+	depart := time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), now.Second(), 0, calendar.Jakarta)
+	if q.Get("depart") != "" && q.Get("arrive_by") != "" {
+		writeError(w, r, http.StatusBadRequest, "arrive_by_conflict")
+		return
+	}
+	if v := q.Get("depart"); v != "" {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "depart_invalid")
+			return
+		}
+		depart = time.Date(depart.Year(), depart.Month(), depart.Day(), t.Hour(), t.Minute(), 0, 0, calendar.Jakarta)
+	}
+	var arriveBy time.Time
+	if v := q.Get("arrive_by"); v != "" {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "arrive_by_invalid")
+			return
+		}
+		arriveBy = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, calendar.Jakarta)
+	}
+
+	origin, ok := router.resolveJourneyEnd(w, r, q.Get("from"), q.Get("from_address"), opts)
+	if !ok {
+		return
+	}
+	dest, ok := router.resolveJourneyEnd(w, r, q.Get("to"), q.Get("to_address"), opts)
+	if !ok {
+		return
+	}
+
+	// An arrive-by journey is planned backwards: the train or bus arrives in
+	// time for the walk to the address, and the walk from the address leaves
+	// in time for the train or bus.
+	backwards := !arriveBy.IsZero()
+	deadline := arriveBy
+	if backwards && dest.place != nil {
+		deadline = deadline.Add(-journey.WalkDuration(dest.distance))
+	}
+
+	trips := []domain.Itinerary{{}}
+	if origin.stationID != dest.stationID {
+		planner, err := router.journeyPlanner(r.Context(), date)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		if backwards {
+			trips = planner.ArriveByAlternatives(origin.stationID, dest.stationID, deadline, opts)
+		} else {
+			start := depart
+			if origin.place != nil {
+				start = start.Add(journey.WalkDuration(origin.distance))
+			}
+			trips = planner.Alternatives(origin.stationID, dest.stationID, start, opts)
+		}
+		if len(trips) == 0 {
+			writeProblem(w, r, Problem{
+				Type:   problemTypeNoJourney,
+				Code:   "no_journey",
+				Status: http.StatusNotFound,
+			}, origin.stationID, dest.stationID)
+			return
+		}
+	}
+
+	alts := make([]domain.Itinerary, len(trips))
+	for i, trip := range trips {
+		leave := depart
+		if backwards {
+			leave = deadline
+			if len(trip.Legs) > 0 {
+				leave = trip.DepartsAt
+			}
+			if origin.place != nil {
+				leave = leave.Add(-journey.WalkDuration(origin.distance))
+			}
+		}
+		alts[i] = journey.NewItinerary(journeyLegs(origin, dest, trip, leave))
+		alts[i].Label = trip.Label
+	}
+
+	it, _ := opts.Best(alts)
+	for _, alt := range alts {
+		if alt.Label != it.Label {
+			it.Alternatives = append(it.Alternatives, alt)
+		}
+	}
+	router.respond(w, r, it)
+}
+
+// journeyLegs returns the legs of trip with the walks from and to the
+// journey's addresses added, leaving the origin at leave. trip has no legs
+// when both ends are at the same station.
+func journeyLegs(origin, dest journeyEnd, trip domain.Itinerary, leave time.Time) []domain.Leg {
+	var legs []domain.Leg
+	at := leave
+	if origin.place != nil {
+		walk := walkLeg(origin.place.Name, origin.stationID, origin.distance, leave)
+		legs = append(legs, walk)
+		at = walk.ArrivesAt
+	}
+	if len(trip.Legs) > 0 {
+		legs = append(legs, trip.Legs...)
+		at = trip.ArrivesAt
+	}
+	if dest.place != nil {
+		legs = append(legs, walkLeg(dest.stationID, dest.place.Name, dest.distance, at))
+	}
+	return legs
+}
+
+// journeyOptions reads the ways a journey may be made from the request:
+// service_type=, brt=, avoid= (station IDs, comma separated),
+// max_transfers=, max_walk_minutes= and optimize=. It writes an error
+// response and reports false when one is invalid.
+func (router *Router) journeyOptions(w http.ResponseWriter, r *http.Request) (journey.Options, bool) {
+	q := r.URL.Query()
+
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return journey.Options{}, false
+	}
+	opts := journey.Options{Services: services}
+	if raw := q.Get("brt"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "brt_invalid")
+			return journey.Options{}, false
+		}
+		opts.BRT = v
+	}
+
+	if raw := strings.TrimSpace(q.Get("avoid")); raw != "" {
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+				opts.Avoid = append(opts.Avoid, id)
+			}
+		}
+		if !router.knownStations(w, r, opts.Avoid...) {
+			return journey.Options{}, false
+		}
+	}
+
+	if raw := q.Get("max_transfers"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > maxJourneyTransfers {
+			writeError(w, r, http.StatusBadRequest, "max_transfers_invalid", maxJourneyTransfers)
+			return journey.Options{}, false
+		}
+		opts.MaxRides = n + 1
+	}
+
+	if raw := q.Get("max_walk_minutes"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxJourneyWalkMinutes {
+			writeError(w, r, http.StatusBadRequest, "max_walk_invalid", maxJourneyWalkMinutes)
+			return journey.Options{}, false
+		}
+		opts.MaxWalk = time.Duration(n) * time.Minute
+	}
+
+	switch opts.Optimize = q.Get("optimize"); opts.Optimize {
+	case "", journey.OptimizeTime, journey.OptimizeTransfers:
+	default:
+		writeError(w, r, http.StatusBadRequest, "optimize_invalid")
+		return journey.Options{}, false
+	}
+	return opts, true
+}
+
+// resolveJourneyEnd resolves a station ID or an address to a station, which
+// is only a BRT stop when opts rides BRT, and never one opts avoids or
+// further than its longest walk from the address. It writes an error
+// response and reports false when neither can be resolved.
+func (router *Router) resolveJourneyEnd(w http.ResponseWriter, r *http.Request, stationID, address string, opts journey.Options) (journeyEnd, bool) {
+	stationID, address = strings.TrimSpace(stationID), strings.TrimSpace(address)
+
+	if stationID != "" {
+		if _, err := router.Store.GetStation(r.Context(), stationID); errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "station_not_found_id", stationID)
+			return journeyEnd{}, false
+		} else if err != nil {
+			router.writeStoreError(w, r, err)
+			return journeyEnd{}, false
+		}
+		return journeyEnd{stationID: stationID}, true
+	}
+
+	if address == "" {
+		writeError(w, r, http.StatusBadRequest, "journey_end_required")
+		return journeyEnd{}, false
+	}
+	if router.Geocoder == nil {
+		writeProblem(w, r, Problem{
+			Type:   problemTypeGeocodingDisabled,
+			Code:   "geocoding_disabled",
+			Status: http.StatusNotImplemented,
+		})
+		return journeyEnd{}, false
+	}
+
+	place, err := router.Geocoder.Geocode(r.Context(), address)
+	if errors.Is(err, geocode.ErrNotFound) {
+		writeProblem(w, r, Problem{
+			Type:   problemTypeAddressNotFound,
+			Code:   "address_not_found",
+			Status: http.StatusUnprocessableEntity,
+		}, address)
+		return journeyEnd{}, false
+	}
+	if err != nil {
+		router.log(r).Warn("Geocoding failed", zap.String("address", address), zap.Error(err))
+		writeError(w, r, http.StatusBadGateway, "address_lookup_failed")
+		return journeyEnd{}, false
+	}
+
+	locs, err := router.Store.GetStationLocations(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return journeyEnd{}, false
+	}
+	stops := make(map[string]bool)
+	for _, id := range opts.Avoid {
+		stops[id] = true
+	}
+	if !opts.BRT {
+		stations, err := router.Store.GetStations(r.Context())
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return journeyEnd{}, false
+		}
+		for _, st := range stations {
+			if st.Type == domain.StationTypeBRT {
+				stops[st.ID] = true
+			}
+		}
+	}
+	end := journeyEnd{place: &place, distance: maxWalkMeters + 1}
+	for _, loc := range locs {
+		if stops[loc.StationID] {
+			continue
+		}
+		d := geocode.Distance(place.Point, geocode.Point{Lat: loc.Lat, Lng: loc.Lng})
+		if d < end.distance {
+			end.stationID, end.distance = loc.StationID, d
+		}
+	}
+	if opts.MaxWalk > 0 && journey.WalkDuration(end.distance) > opts.MaxWalk {
+		end.stationID = ""
+	}
+	if end.stationID == "" {
+		writeProblem(w, r, Problem{
+			Type:   problemTypeNoNearbyStation,
+			Code:   "no_nearby_station",
+			Status: http.StatusUnprocessableEntity,
+		}, place.Name)
+		return journeyEnd{}, false
+	}
+	return end, true
+}
+
+func walkLeg(from, to string, meters float64, depart time.Time) domain.Leg {
+	d := journey.WalkDuration(meters)
+	return domain.Leg{
+		Mode:            domain.LegModeWalk,
+		From:            from,
+		To:              to,
+		DepartsAt:       depart,
+		ArrivesAt:       depart.Add(d),
+		DistanceMeters:  int(meters),
+		DurationMinutes: int(d.Minutes()),
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+	"llm-router/internal/store"
+)
+
+// HandleLines serves /api/v1/line, the list of lines without their stations,
+// named in the language negotiated from Accept-Language.
+func (router *Router) HandleLines(w http.ResponseWriter, r *http.Request) {
+	lines, err := router.Store.GetLines(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if lines == nil {
+		lines = []domain.Line{}
+	}
+	lang := i18n.Lang(displayLang(w, r))
+	for i := range lines {
+		lines[i].DisplayName = i18n.LineName(lang, lines[i].Name)
+	}
+	router.respond(w, r, lines)
+}
+
+// HandleLine serves /api/v1/line/{name} with the line's stations in order,
+// /api/v1/line/{name}/stations with just the stations, and
+// /api/v1/line/{name}/frequency with the trains it runs through the day.
+func (router *Router) HandleLine(w http.ResponseWriter, r *http.Request) {
+	name, sub, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/line/"), "/"), "/")
+	if name == "" {
+		router.HandleLines(w, r)
+		return
+	}
+
+	var ordered bool
+	switch sub {
+	case "":
+	case "stations":
+		var err error
+		if raw := r.URL.Query().Get("ordered"); raw != "" {
+			if ordered, err = strconv.ParseBool(raw); err != nil {
+				writeError(w, r, http.StatusBadRequest, "ordered_invalid")
+				return
+			}
+		}
+	case "frequency":
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	line, err := router.lineData(r.Context(), name)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "line_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.usage.hit(store.UsageKindLine, line.Name)
+	switch sub {
+	case "":
+		line.DisplayName = i18n.LineName(i18n.Lang(displayLang(w, r)), line.Name)
+		router.respond(w, r, line)
+		return
+	case "frequency":
+		router.handleLineFrequency(w, r, line)
+		return
+	}
+
+	// Stations are stored in the canonical order along the line, which
+	// line strip diagrams draw from; unordered they are sorted by name.
+	stations := append([]domain.LineStation{}, line.Stations...)
+	if !ordered {
+		sort.SliceStable(stations, func(i, j int) bool { return stations[i].Name < stations[j].Name })
+	}
+	router.respond(w, r, stations)
+}
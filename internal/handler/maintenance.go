@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const problemTypeMaintenance = "/problems/maintenance"
+
+// maintenanceMode is the maintenance state admins toggle at runtime. It starts
+// from the configuration, and a reload that changes the maintenance section
+// replaces whatever an admin set.
+type maintenanceMode struct {
+	mu         sync.RWMutex
+	state      domain.Maintenance
+	configured config.MaintenanceConfig
+}
+
+func maintenanceFromConfig(cfg config.MaintenanceConfig) domain.Maintenance {
+	m := domain.Maintenance{
+		Enabled:    cfg.Enabled,
+		Banner:     cfg.Banner,
+		RetryAfter: int(cfg.RetryAfter / time.Second),
+		ServeReads: cfg.ServeReads,
+	}
+	if m.Enabled {
+		now := time.Now()
+		m.Since = &now
+	}
+	return m
+}
+
+func (router *Router) maintenanceState() domain.Maintenance {
+	router.maintenance.mu.RLock()
+	defer router.maintenance.mu.RUnlock()
+	return router.maintenance.state
+}
+
+// setMaintenance replaces the maintenance state and pauses syncs while it is
+// enabled.
+func (router *Router) setMaintenance(m domain.Maintenance) {
+	router.maintenance.mu.Lock()
+	prev := router.maintenance.state
+	switch {
+	case !m.Enabled:
+		m.Since = nil
+	case prev.Enabled:
+		m.Since = prev.Since
+	case m.Since == nil:
+		now := time.Now()
+		m.Since = &now
+	}
+	router.maintenance.state = m
+	router.maintenance.mu.Unlock()
+
+	router.Scraper.SetPaused(m.Enabled)
+	if m.Enabled != prev.Enabled {
+		router.Logger.Info("Maintenance mode changed",
+			zap.Bool("enabled", m.Enabled),
+			zap.Bool("serve_reads", m.ServeReads),
+		)
+	}
+}
+
+// ReconfigureMaintenance applies a reloaded maintenance section. It does
+// nothing when the section is unchanged, so a reload does not undo a runtime
+// toggle for an unrelated change.
+func (router *Router) ReconfigureMaintenance(cfg *config.Config) {
+	router.maintenance.mu.Lock()
+	changed := cfg.Maintenance != router.maintenance.configured
+	router.maintenance.configured = cfg.Maintenance
+	router.maintenance.mu.Unlock()
+
+	if changed {
+		router.setMaintenance(maintenanceFromConfig(cfg.Maintenance))
+	}
+}
+
+// MaintenanceMiddleware answers API requests with 503 while maintenance mode
+// is enabled: mutating requests always, reads unless the mode serves them.
+// The admin API and /api/v1/config stay available so the mode can be seen
+// and lifted.
+func (router *Router) MaintenanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if !strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/api/admin/") || path == "/api/v1/config" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		m := router.maintenanceState()
+		if !m.Enabled || (m.ServeReads && isRead(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writeProblem(w, r, Problem{
+			Type:       problemTypeMaintenance,
+			Status:     http.StatusServiceUnavailable,
+			Code:       "maintenance",
+			RetryAfter: m.RetryAfter,
+		})
+	})
+}
+
+// readPosts are the endpoints that take their query as a POST body but only
+// read, served like GETs while maintenance serves reads.
+var readPosts = []string{"/api/v1/schedule/batch"}
+
+// isRead reports whether r only reads: a GET, HEAD or OPTIONS request, or a
+// POST to one of readPosts.
+func isRead(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return slices.Contains(readPosts, r.URL.Path)
+	}
+	return false
+}
+
+// HandleClientConfig serves GET /api/v1/config, the settings clients use to
+// adapt their UI, such as the maintenance banner.
+func (router *Router) HandleClientConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	features := make(map[string]bool, len(domain.Features))
+	for _, f := range router.featureFlags() {
+		features[f.Name] = f.Enabled
+	}
+	cfg := domain.ClientConfig{Maintenance: router.maintenanceState(), Features: features}
+	if router.Push != nil {
+		cfg.PushPublicKey = router.Push.PublicKey()
+	}
+	router.respond(w, r, cfg)
+}
+
+// maintenanceUpdate is the body of PUT /api/admin/maintenance. Omitted fields
+// keep their current value.
+type maintenanceUpdate struct {
+	Enabled    *bool   `json:"enabled"`
+	Banner     *string `json:"banner"`
+	RetryAfter *int    `json:"retry_after"`
+	ServeReads *bool   `json:"serve_reads"`
+}
+
+// HandleAdminMaintenance serves GET and PUT /api/admin/maintenance, which
+// report and toggle maintenance mode.
+func (router *Router) HandleAdminMaintenance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, router.maintenanceState())
+		return
+	case http.MethodPut:
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	var req maintenanceUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	if req.RetryAfter != nil && *req.RetryAfter < 1 {
+		writeError(w, r, http.StatusBadRequest, "retry_after_invalid")
+		return
+	}
+
+	m := router.maintenanceState()
+	if req.Enabled != nil {
+		m.Enabled = *req.Enabled
+	}
+	if req.Banner != nil {
+		m.Banner = strings.TrimSpace(*req.Banner)
+	}
+	if req.RetryAfter != nil {
+		m.RetryAfter = *req.RetryAfter
+	}
+	if req.ServeReads != nil {
+		m.ServeReads = *req.ServeReads
+	}
+	router.setMaintenance(m)
+
+	router.respond(w, r, router.maintenanceState())
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"llm-router/internal/domain"
+)
+
+func TestMaintenanceMiddlewareServeReads(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		serveReads bool
+		method     string
+		path       string
+		want       int
+	}{
+		{"get", true, http.MethodGet, "/api/v1/schedule/MRI", http.StatusNoContent},
+		{"batch", true, http.MethodPost, "/api/v1/schedule/batch", http.StatusNoContent},
+		{"batch without reads", false, http.MethodPost, "/api/v1/schedule/batch", http.StatusServiceUnavailable},
+		{"write", true, http.MethodPost, "/api/v1/me/favorites", http.StatusServiceUnavailable},
+		{"batch by put", true, http.MethodPut, "/api/v1/schedule/batch", http.StatusServiceUnavailable},
+		{"admin", false, http.MethodPut, "/api/admin/maintenance", http.StatusNoContent},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := &Router{}
+			router.maintenance.state = domain.Maintenance{Enabled: true, RetryAfter: 60, ServeReads: tc.serveReads}
+			h := router.MaintenanceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"station_ids":["MRI"]}`)))
+			if w.Code != tc.want {
+				t.Errorf("%s %s = %d, want %d", tc.method, tc.path, w.Code, tc.want)
+			}
+		})
+	}
+}
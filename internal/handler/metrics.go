@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMetricsLimit = 100
+	maxMetricsLimit     = 1000
+)
+
+// snapshotMetrics records the current cache, database and runtime metrics and
+// prunes snapshots past the retention period.
+func (router *Router) snapshotMetrics(ctx context.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	snap := domain.MetricsSnapshot{
+		TakenAt:    time.Now(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapBytes:  mem.HeapAlloc,
+		Caches:     router.Timetable.CacheStats(),
+	}
+	snap.Caches["lines"] = router.lines.Stats()
+	db, err := router.Store.DBStats(ctx)
+	if err != nil {
+		router.Logger.Warn("Failed to read database stats", zap.Error(err))
+	}
+	snap.DB = db
+
+	// Snapshots are best effort; a failed one leaves a gap in the history.
+	if err := router.Store.SaveMetricsSnapshot(ctx, snap); err != nil {
+		router.Logger.Warn("Failed to save metrics snapshot", zap.Error(err))
+	}
+	if err := router.Store.PruneMetricsSnapshots(ctx, snap.TakenAt.Add(-router.Config.MetricsRetention)); err != nil {
+		router.Logger.Warn("Failed to prune metrics snapshots", zap.Error(err))
+	}
+}
+
+func (router *Router) runMetrics(ctx context.Context) {
+	ticker := time.NewTicker(router.Config.MetricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			router.snapshotMetrics(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// HandleAdminMetrics serves /api/admin/metrics?since=&limit=, the stored
+// metrics snapshots newest first. since is an RFC 3339 timestamp.
+func (router *Router) HandleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	var since time.Time
+	if raw := q.Get("since"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "since_timestamp_invalid")
+			return
+		}
+		since = t
+	}
+
+	limit := defaultMetricsLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxMetricsLimit {
+			writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxMetricsLimit)
+			return
+		}
+		limit = n
+	}
+
+	snaps, err := router.Store.GetMetricsSnapshots(r.Context(), since, limit)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.respond(w, r, snaps)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+const (
+	defaultNextCount = 3
+	maxNextCount     = 20
+)
+
+// NextTrain is a departure from one station on a train that later calls at
+// the requested destination.
+type NextTrain struct {
+	TrainID string `json:"train_id"`
+	Line    string `json:"line"`
+	Route   string `json:"route"`
+	Color   string `json:"color"`
+	// Terminus is where the train ends its run, which may lie beyond To.
+	Terminus        string    `json:"station_destination_id"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	DepartsAt       time.Time `json:"departs_at"`
+	ArrivesAt       time.Time `json:"arrives_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	MinutesUntil    int       `json:"minutes_until"`
+}
+
+// HandleNext serves /api/v1/next?from=&to=&count=&service_type=, the next
+// departures from one station that reach another without changing trains.
+func (router *Router) HandleNext(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, to := strings.TrimSpace(q.Get("from")), strings.TrimSpace(q.Get("to"))
+	if from == "" || to == "" {
+		writeError(w, r, http.StatusBadRequest, "from_to_required")
+		return
+	}
+	if from == to {
+		writeError(w, r, http.StatusBadRequest, "from_to_same")
+		return
+	}
+
+	count := defaultNextCount
+	if raw := q.Get("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxNextCount {
+			writeError(w, r, http.StatusBadRequest, "count_out_of_range", maxNextCount)
+			return
+		}
+		count = n
+	}
+
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+
+	for _, id := range []string{from, to} {
+		_, err := router.Store.GetStation(r.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "station_not_found_id", id)
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+	}
+
+	date := router.Timetable.OperatingDate()
+	departures, err := router.Timetable.DatedBoard(r.Context(), from, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	arrivals, err := router.Timetable.DatedBoard(r.Context(), to, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.usage.hit(store.UsageKindStation, from)
+
+	router.respond(w, r, nextTrains(filterServices(departures, services), arrivals, time.Now(), count))
+}
+
+// nextTrains joins two station boards on train ID and returns the first count
+// trains that leave the first station after now and call at the second later.
+// A train's time at the destination is its departure from there.
+func nextTrains(departures, arrivals []domain.Schedule, now time.Time, count int) []NextTrain {
+	reaches := make(map[string]domain.Schedule, len(arrivals))
+	for _, sch := range arrivals {
+		reaches[sch.TrainID] = sch
+	}
+
+	trains := []NextTrain{}
+	for _, dep := range departures {
+		arr, ok := reaches[dep.TrainID]
+		if !ok || dep.DepartsAt.IsZero() || !arr.DepartsAt.After(dep.DepartsAt) {
+			continue
+		}
+
+		// Both boards are stamped with their sync date; move the departure
+		// onto today and keep the trip's duration.
+		departsAt := clockToday(dep.DepartsAt, now)
+		if !departsAt.After(now) {
+			continue
+		}
+		duration := arr.DepartsAt.Sub(dep.DepartsAt)
+
+		trains = append(trains, NextTrain{
+			TrainID:         dep.TrainID,
+			Line:            dep.Line,
+			Route:           dep.Route,
+			Color:           dep.Metadata.Origin.Color,
+			Terminus:        dep.StationDestinationID,
+			From:            dep.StationID,
+			To:              arr.StationID,
+			DepartsAt:       departsAt,
+			ArrivesAt:       departsAt.Add(duration),
+			DurationMinutes: int(duration.Round(time.Minute) / time.Minute),
+			MinutesUntil:    int(departsAt.Sub(now).Round(time.Minute) / time.Minute),
+		})
+	}
+
+	sort.Slice(trains, func(i, j int) bool { return trains[i].DepartsAt.Before(trains[j].DepartsAt) })
+	if len(trains) > count {
+		trains = trains[:count]
+	}
+	return trains
+}
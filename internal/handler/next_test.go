@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/journey"
+	"llm-router/internal/scrapper"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// newTestRouter is a router over an in-memory store holding MRI and BOO and
+// train 1001 between them, leaving MRI at depart on the service date running
+// now.
+func newTestRouter(t *testing.T, depart time.Time) *Router {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(config.Flags{ConfigPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s, err := store.NewStore(ctx, store.DriverSQLite, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()), store.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	cal, err := calendar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := events.NewHub()
+	scr := scrapper.NewScraper(cfg, s, hub, nil, cal, zap.NewNop())
+	router := NewRouter(cfg, s, scr, hub, nil, cal, zap.NewNop())
+
+	if err := s.SetStations(ctx, []domain.Station{
+		{UID: "1", ID: "MRI", Name: "MANGGARAI", Type: domain.StationTypeKRL},
+		{UID: "2", ID: "BOO", Name: "BOGOR", Type: domain.StationTypeKRL},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	date := router.Timetable.OperatingDate()
+	serviceDay := cal.ServiceDay(date)
+	for i, sch := range []domain.Schedule{
+		{StationID: "MRI", DepartsAt: depart},
+		{StationID: "BOO", DepartsAt: depart.Add(time.Hour)},
+	} {
+		sch.ID = sch.StationID + "-1001"
+		sch.StationOriginID, sch.StationDestinationID = "MRI", "BOO"
+		sch.TrainID, sch.Line, sch.Route = "1001", "COMMUTER LINE BOGOR", "MANGGARAI-BOGOR"
+		sch.ArrivesAt = depart.Add(time.Hour)
+		sch.ServiceType, sch.ServiceDay = domain.ServiceCommuter, serviceDay
+		if i == 1 {
+			sch.ArrivesAt = sch.DepartsAt
+		}
+		if err := s.SetSchedules(ctx, sch.StationID, serviceDay, calendar.FormatDate(date), []domain.Schedule{sch}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return router
+}
+
+func TestCancelledTripLeavesNextAndPlanner(t *testing.T) {
+	now := time.Now().In(calendar.Jakarta)
+	depart := now.Add(10 * time.Minute).Truncate(time.Minute)
+	if depart.Day() != now.Day() {
+		t.Skip("the departure would fall after midnight")
+	}
+	router := newTestRouter(t, depart)
+	ctx := context.Background()
+	date := router.Timetable.OperatingDate()
+
+	next := func() []NextTrain {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.HandleNext(w, httptest.NewRequest("GET", "/api/v1/next?from=MRI&to=BOO", nil))
+		var body struct {
+			Data []NextTrain `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("/next = %d: %s", w.Code, w.Body.String())
+		}
+		return body.Data
+	}
+	planned := func() bool {
+		t.Helper()
+		p, err := router.journeyPlanner(ctx, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, ok := p.Plan("MRI", "BOO", now, journey.Options{})
+		return ok
+	}
+
+	if trains := next(); len(trains) != 1 || trains[0].TrainID != "1001" {
+		t.Fatalf("/next before the cancellation = %+v, want train 1001", trains)
+	}
+	if !planned() {
+		t.Fatal("no journey planned before the cancellation")
+	}
+
+	if _, err := router.Store.CreateScheduleOverride(ctx, domain.ScheduleOverride{
+		Kind:        domain.OverrideCancel,
+		TrainID:     "1001",
+		ServiceDate: calendar.FormatDate(date),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	router.overridesChanged(ctx)
+
+	if trains := next(); len(trains) != 0 {
+		t.Errorf("/next after the cancellation = %+v, want none", trains)
+	}
+	if planned() {
+		t.Error("journey planned on the cancelled train")
+	}
+}
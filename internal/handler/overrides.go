@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+const (
+	// maxOverrideShift bounds a retime's shift, in minutes either way.
+	maxOverrideShift = 12 * 60
+
+	// maxOverrideNote bounds an override's note, in characters.
+	maxOverrideNote = 500
+)
+
+// HandleAdminOverrides manages schedule overrides, which cancel, retime or
+// add departures on top of the scraped timetable and survive syncs:
+//
+//	GET    /api/admin/overrides
+//	POST   /api/admin/overrides with {"kind", "train_id", "station_id", "service_date", "shift_minutes",
+//	       "departs_at", "arrives_at", "line", "route", "station_origin_id", "station_destination_id",
+//	       "service_type", "note"}
+//	GET    /api/admin/overrides/{id}
+//	PUT    /api/admin/overrides/{id} with the same body as POST
+//	DELETE /api/admin/overrides/{id}
+//
+// Overrides show on station boards and routes; see domain.ScheduleOverride.
+func (router *Router) HandleAdminOverrides(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/overrides"), "/")
+	ctx := r.Context()
+
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			overrides, err := router.Store.ListScheduleOverrides(ctx)
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			router.respond(w, r, overrides)
+		case http.MethodPost:
+			o, ok := router.decodeOverride(w, r)
+			if !ok {
+				return
+			}
+			created, err := router.Store.CreateScheduleOverride(ctx, o)
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			router.overridesChanged(ctx)
+			router.respondStatus(w, r, http.StatusCreated, created)
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		}
+		return
+	}
+
+	id, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "override_id_invalid")
+		return
+	}
+	existing, err := router.Store.GetScheduleOverride(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "override_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, existing)
+	case http.MethodPut:
+		o, ok := router.decodeOverride(w, r)
+		if !ok {
+			return
+		}
+		o.ID = id
+		if err := router.Store.UpdateScheduleOverride(ctx, o); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.overridesChanged(ctx)
+		updated, err := router.Store.GetScheduleOverride(ctx, id)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, updated)
+	case http.MethodDelete:
+		if err := router.Store.DeleteScheduleOverride(ctx, id); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.overridesChanged(ctx)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+// decodeOverride reads and validates a schedule override from the request
+// body, writing a problem and returning false when it is not acceptable.
+func (router *Router) decodeOverride(w http.ResponseWriter, r *http.Request) (domain.ScheduleOverride, bool) {
+	var o domain.ScheduleOverride
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&o); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return domain.ScheduleOverride{}, false
+	}
+	o.Kind = strings.ToLower(strings.TrimSpace(o.Kind))
+	o.TrainID = strings.TrimSpace(o.TrainID)
+	o.StationID = strings.ToUpper(strings.TrimSpace(o.StationID))
+	o.StationOriginID = strings.ToUpper(strings.TrimSpace(o.StationOriginID))
+	o.StationDestinationID = strings.ToUpper(strings.TrimSpace(o.StationDestinationID))
+	o.ServiceDate = strings.TrimSpace(o.ServiceDate)
+	o.ServiceType = strings.ToLower(strings.TrimSpace(o.ServiceType))
+	o.Line = strings.TrimSpace(o.Line)
+	o.Route = strings.TrimSpace(o.Route)
+	o.Note = strings.TrimSpace(o.Note)
+
+	for _, clock := range []struct {
+		name string
+		dst  *string
+	}{{"departs_at", &o.DepartsAt}, {"arrives_at", &o.ArrivesAt}} {
+		raw := strings.TrimSpace(*clock.dst)
+		if raw == "" {
+			*clock.dst = ""
+			continue
+		}
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "time_of_day_invalid", clock.name)
+			return domain.ScheduleOverride{}, false
+		}
+		*clock.dst = t.Format("15:04")
+	}
+
+	switch {
+	case !slices.Contains(domain.OverrideKinds, o.Kind):
+		writeError(w, r, http.StatusBadRequest, "override_kind_invalid", strings.Join(domain.OverrideKinds, ", "))
+		return domain.ScheduleOverride{}, false
+	case o.TrainID == "":
+		writeError(w, r, http.StatusBadRequest, "train_id_required")
+		return domain.ScheduleOverride{}, false
+	case len([]rune(o.Note)) > maxOverrideNote:
+		writeError(w, r, http.StatusBadRequest, "override_note_too_long", maxOverrideNote)
+		return domain.ScheduleOverride{}, false
+	}
+	if o.ServiceDate != "" {
+		if _, err := calendar.ParseDate(o.ServiceDate); err != nil {
+			writeError(w, r, http.StatusBadRequest, "date_invalid")
+			return domain.ScheduleOverride{}, false
+		}
+	}
+
+	switch o.Kind {
+	case domain.OverrideCancel:
+		o.ShiftMinutes, o.DepartsAt, o.ArrivesAt = 0, "", ""
+	case domain.OverrideRetime:
+		switch {
+		case o.ShiftMinutes < -maxOverrideShift || o.ShiftMinutes > maxOverrideShift:
+			writeError(w, r, http.StatusBadRequest, "override_shift_invalid", maxOverrideShift)
+			return domain.ScheduleOverride{}, false
+		case o.ShiftMinutes == 0 && o.DepartsAt == "":
+			writeError(w, r, http.StatusBadRequest, "override_retime_required")
+			return domain.ScheduleOverride{}, false
+		case o.ShiftMinutes != 0 && o.DepartsAt != "":
+			writeError(w, r, http.StatusBadRequest, "override_retime_required")
+			return domain.ScheduleOverride{}, false
+		case o.DepartsAt != "" && o.StationID == "":
+			writeError(w, r, http.StatusBadRequest, "station_id_required")
+			return domain.ScheduleOverride{}, false
+		}
+	case domain.OverrideAdd:
+		o.ShiftMinutes = 0
+		if o.ServiceType == "" {
+			o.ServiceType = domain.ServiceCommuter
+		}
+		switch {
+		case o.StationID == "":
+			writeError(w, r, http.StatusBadRequest, "station_id_required")
+			return domain.ScheduleOverride{}, false
+		case o.DepartsAt == "":
+			writeError(w, r, http.StatusBadRequest, "time_of_day_invalid", "departs_at")
+			return domain.ScheduleOverride{}, false
+		case o.Line == "":
+			writeError(w, r, http.StatusBadRequest, "line_required")
+			return domain.ScheduleOverride{}, false
+		case !slices.Contains(domain.ServiceTypes, o.ServiceType):
+			writeError(w, r, http.StatusBadRequest, "service_type_invalid", strings.Join(domain.ServiceTypes, ", "))
+			return domain.ScheduleOverride{}, false
+		}
+		line, err := router.lineData(r.Context(), o.Line)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "line_not_found")
+			return domain.ScheduleOverride{}, false
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return domain.ScheduleOverride{}, false
+		}
+		o.Line = line.Name
+	}
+
+	var stations []string
+	for _, id := range []string{o.StationID, o.StationOriginID, o.StationDestinationID} {
+		if id != "" && !slices.Contains(stations, id) {
+			stations = append(stations, id)
+		}
+	}
+	if !router.knownStations(w, r, stations...) {
+		return domain.ScheduleOverride{}, false
+	}
+	return o, true
+}
+
+// overridesChanged drops the cached overrides and purges the responses they
+// may show in.
+func (router *Router) overridesChanged(ctx context.Context) {
+	router.Timetable.OverridesChanged()
+	router.resetDatedCaches()
+	router.purge(ctx, []string{surrogateKeyAll})
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/geodata"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+)
+
+// HandlePositions serves /api/v1/positions?line=, where each train of the
+// line in service now should be by today's timetable: standing at a stop,
+// or between its last and next stop in proportion to the time between them,
+// placed along the line's track where it is known. It is estimated on every
+// request, for a live map of lines without a realtime feed.
+func (router *Router) HandlePositions(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.URL.Query().Get("line"))
+	if name == "" {
+		writeError(w, r, http.StatusBadRequest, "line_required")
+		return
+	}
+	ctx := r.Context()
+
+	line, err := router.lineData(ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "line_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	date := router.Timetable.OperatingDate()
+	serviceDay := router.Calendar.ServiceDay(date)
+	schedules, err := router.Timetable.DatedSchedules(ctx, store.ScheduleFilter{Line: line.Name}, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	locs, err := router.Store.GetStationLocations(ctx)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	shape, err := router.Store.GetLineShape(ctx, line.Name)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	located := make(map[string]domain.LatLng, len(locs))
+	for _, loc := range locs {
+		located[loc.StationID] = domain.LatLng{Lat: loc.Lat, Lng: loc.Lng}
+	}
+	// Most trains run over the same legs, so each is traced once.
+	tracks := make(map[[2]string][]domain.LatLng)
+	track := func(from, to string) []domain.LatLng {
+		key := [2]string{from, to}
+		if t, ok := tracks[key]; ok {
+			return t
+		}
+		t, ok := geodata.Track(shape, located[from], located[to])
+		if !ok {
+			t = []domain.LatLng{located[from], located[to]}
+		}
+		tracks[key] = t
+		return t
+	}
+
+	byTrain := make(map[string][]domain.Schedule)
+	for _, sch := range schedules {
+		if _, ok := located[sch.StationID]; ok {
+			byTrain[sch.TrainID] = append(byTrain[sch.TrainID], sch)
+		}
+	}
+	trains := []domain.EstimatedPosition{}
+	for trainID, stops := range byTrain {
+		sort.Slice(stops, func(i, j int) bool { return stops[i].DepartsAt.Before(stops[j].DepartsAt) })
+		if pos, ok := estimatePosition(trainID, stops, now, located, track); ok {
+			trains = append(trains, pos)
+		}
+	}
+	sort.Slice(trains, func(i, j int) bool { return trains[i].TrainID < trains[j].TrainID })
+
+	router.respond(w, r, domain.LinePositions{
+		Line:        line.Name,
+		Color:       line.Color,
+		ServiceDay:  serviceDay,
+		EstimatedAt: now,
+		Trains:      trains,
+	})
+}
+
+// estimatePosition places the train at now from its schedules at located
+// stations, ordered by departure. It is false when the train is not running
+// at now.
+func estimatePosition(trainID string, schedules []domain.Schedule, now time.Time, located map[string]domain.LatLng, track func(from, to string) []domain.LatLng) (domain.EstimatedPosition, bool) {
+	if len(schedules) < 2 {
+		return domain.EstimatedPosition{}, false
+	}
+	stops := journey.NewRoute(trainID, schedules, nil).Routes
+
+	// Schedules are stamped with their sync date; the trip is moved onto
+	// today as a whole, so one past midnight keeps its order.
+	shift := clockToday(stops[0].DepartsAt, now).Sub(stops[0].DepartsAt)
+	arrives := func(i int) time.Time {
+		if stops[i].ArrivesAt == nil {
+			return stops[i].DepartsAt.Add(shift)
+		}
+		return stops[i].ArrivesAt.Add(shift)
+	}
+	departs := func(i int) time.Time {
+		if i == len(stops)-1 || stops[i].DepartsAt.IsZero() {
+			return arrives(i)
+		}
+		return stops[i].DepartsAt.Add(shift)
+	}
+
+	pos := domain.EstimatedPosition{
+		TrainID:  trainID,
+		Route:    schedules[0].Route,
+		Terminus: schedules[0].StationDestinationID,
+	}
+	for i, stop := range stops {
+		if now.Before(arrives(i)) {
+			break
+		}
+		if !now.After(departs(i)) {
+			p := located[stop.StationID]
+			pos.Status, pos.StationID = domain.EstimateAtStation, stop.StationID
+			pos.Latitude, pos.Longitude = p.Lat, p.Lng
+			if i+1 < len(stops) {
+				_, pos.Heading = geodata.Along(track(stop.StationID, stops[i+1].StationID), 0)
+			} else {
+				_, pos.Heading = geodata.Along(track(stops[i-1].StationID, stop.StationID), 1)
+			}
+			return pos, true
+		}
+		if i+1 < len(stops) && now.Before(arrives(i+1)) {
+			next := stops[i+1]
+			leg := arrives(i + 1).Sub(departs(i))
+			pos.Status, pos.StationID, pos.NextStationID = domain.EstimateMoving, stop.StationID, next.StationID
+			pos.Progress = float64(now.Sub(departs(i))) / float64(leg)
+			p, heading := geodata.Along(track(stop.StationID, next.StationID), pos.Progress)
+			pos.Latitude, pos.Longitude, pos.Heading = p.Lat, p.Lng, heading
+			return pos, true
+		}
+	}
+	return domain.EstimatedPosition{}, false
+}
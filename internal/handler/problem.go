@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"llm-router/internal/i18n"
+
+	"go.uber.org/zap"
+)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Code identifies the error independently of the response language. It
+	// is the message catalog key for the detail.
+	Code string `json:"code,omitempty"`
+
+	// RetryAfter is the number of seconds until the data is expected to be retried.
+	RetryAfter  int        `json:"retry_after,omitempty"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+
+	// RequestID identifies the request in the server logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+const problemTypeStationSyncFailed = "/problems/station-sync-failed"
+
+// writeProblem writes p, filling its title and detail from the message
+// catalog in the client's language when it has a code. A typed problem's code
+// names its own title and detail; otherwise the title is the status text and
+// args format the code's message.
+func writeProblem(w http.ResponseWriter, r *http.Request, p Problem, args ...any) {
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	if p.Code != "" {
+		if p.Type != "" {
+			p.Title = i18n.T(lang, p.Code+".title")
+			p.Detail = i18n.T(lang, p.Code+".detail", args...)
+		} else {
+			p.Title = i18n.StatusTitle(lang, p.Status)
+			p.Detail = i18n.T(lang, p.Code, args...)
+		}
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	p.RequestID = RequestID(r.Context())
+	if p.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(p.RetryAfter))
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Content-Language", string(lang))
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeStoreError logs a failed store call and reports it to the client
+// without exposing the underlying error.
+func (router *Router) writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	router.log(r).Error("Store error", zap.String("path", r.URL.Path), zap.Error(err))
+	writeError(w, r, http.StatusInternalServerError, "store_error")
+}
+
+// writeError reports a failed request as a problem with the catalog message
+// for code.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, args ...any) {
+	writeProblem(w, r, Problem{Status: status, Code: code}, args...)
+}
+
+// writeStationSyncProblem reports that a station's data is missing because its
+// last sync failed, with guidance on when it will be retried.
+func (router *Router) writeStationSyncProblem(w http.ResponseWriter, r *http.Request, stationID string) bool {
+	failure, ok := router.Scraper.StationFailure(stationID)
+	if !ok {
+		return false
+	}
+
+	retryAfter := int(time.Until(failure.NextRetry).Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	lastAttempt := failure.LastAttempt
+
+	writeProblem(w, r, Problem{
+		Type:        problemTypeStationSyncFailed,
+		Code:        "station_sync_failed",
+		Status:      http.StatusServiceUnavailable,
+		RetryAfter:  retryAfter,
+		LastAttempt: &lastAttempt,
+	}, stationID)
+	return true
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/push"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxPushSubscriptions = 10
+	maxRemindBefore      = 120
+)
+
+// HandlePushSubscriptions serves a user's Web Push subscriptions:
+//
+//	GET    /api/v1/me/push
+//	PUT    /api/v1/me/push with a PushSubscription.toJSON() body
+//	DELETE /api/v1/me/push with {"endpoint": ...}
+func (router *Router) HandlePushSubscriptions(w http.ResponseWriter, r *http.Request, u domain.User) {
+	if router.Push == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		subs, err := router.Store.GetPushSubscriptions(r.Context(), u.ID)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.respond(w, r, subs)
+	case http.MethodPut:
+		var sub domain.PushSubscription
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBody)).Decode(&sub); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		if err := push.Validate(r.Context(), sub); err != nil {
+			writeError(w, r, http.StatusBadRequest, "push_invalid", err)
+			return
+		}
+		subs, err := router.Store.GetPushSubscriptions(r.Context(), u.ID)
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		if len(subs) >= maxPushSubscriptions && !hasEndpoint(subs, sub.Endpoint) {
+			writeError(w, r, http.StatusConflict, "push_limit", maxPushSubscriptions)
+			return
+		}
+		if err := router.Store.SavePushSubscription(r.Context(), u.ID, sub); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		sub.CreatedAt = time.Now()
+		router.respond(w, r, sub)
+	case http.MethodDelete:
+		var req struct {
+			Endpoint string `json:"endpoint"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAccountBody)).Decode(&req); err != nil || req.Endpoint == "" {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		err := router.Store.DeletePushSubscription(r.Context(), u.ID, req.Endpoint)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "push_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+	}
+}
+
+func hasEndpoint(subs []domain.PushSubscription, endpoint string) bool {
+	for _, sub := range subs {
+		if sub.Endpoint == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommuteReminders sends each commute's reminder once its next train is
+// RemindBefore minutes away, checking every push interval.
+func (router *Router) runCommuteReminders(ctx context.Context) {
+	ticker := time.NewTicker(router.Config.Accounts.Push.Interval)
+	defer ticker.Stop()
+
+	// reminded holds the trains already reminded of, by commute, train and
+	// date, so each is reminded of once.
+	reminded := make(map[string]time.Time)
+	for {
+		select {
+		case now := <-ticker.C:
+			for key, at := range reminded {
+				if now.Sub(at) > 24*time.Hour {
+					delete(reminded, key)
+				}
+			}
+			router.remindCommutes(ctx, now, reminded)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (router *Router) remindCommutes(ctx context.Context, now time.Time, reminded map[string]time.Time) {
+	commutes, err := router.Store.GetAllCommutes(ctx)
+	if err != nil {
+		router.Logger.Error("Failed to load commutes for reminders", zap.Error(err))
+		return
+	}
+
+	subs := router.pushSubscriptions(ctx)
+	for _, c := range commutes {
+		if c.RemindBefore <= 0 {
+			continue
+		}
+		trains, err := router.commuteTrains(ctx, c, now, 1)
+		if err != nil {
+			router.Logger.Error("Failed to find commute trains", zap.Int64("commute", c.ID), zap.Error(err))
+			continue
+		}
+		if len(trains) == 0 || trains[0].DepartsAt.Sub(now) > time.Duration(c.RemindBefore)*time.Minute {
+			continue
+		}
+		t := trains[0]
+		key := fmt.Sprintf("%d/%s/%s", c.ID, t.TrainID, t.DepartsAt.Format("2006-01-02"))
+		if _, ok := reminded[key]; ok {
+			continue
+		}
+		reminded[key] = now
+
+		router.sendPush(ctx, subs(c.UserID), push.Message{
+			Title: c.Name,
+			Body: fmt.Sprintf("Train %s to %s leaves %s at %s, in %d min.",
+				t.TrainID, t.To, t.From, t.DepartsAt.In(calendar.Jakarta).Format("15:04"), t.MinutesUntil),
+			Tag:  fmt.Sprintf("commute-%d", c.ID),
+			Data: t,
+		}, push.UrgencyHigh)
+	}
+}
+
+// alertCommuteChanges tells users when a sync changed the day's trains of
+// one of their commutes. The first check of a commute, and the first on a
+// different kind of service day, only records its trains.
+func (router *Router) alertCommuteChanges(ctx context.Context) {
+	commutes, err := router.Store.GetAllCommutes(ctx)
+	if err != nil {
+		router.Logger.Error("Failed to load commutes for change alerts", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	y, m, d := now.In(calendar.Jakarta).Date()
+	startOfDay := time.Date(y, m, d, 0, 0, 0, 0, calendar.Jakarta)
+	serviceDay := router.operatingDay()
+
+	subs := router.pushSubscriptions(ctx)
+	for _, c := range commutes {
+		trains, err := router.commuteTrains(ctx, c, startOfDay, maxTrainsPerDay)
+		if err != nil {
+			router.Logger.Error("Failed to find commute trains", zap.Int64("commute", c.ID), zap.Error(err))
+			continue
+		}
+		hash := serviceDay + ":" + trainsHash(trains)
+		if hash == c.TrainsHash {
+			continue
+		}
+		if err := router.Store.SetCommuteTrainsHash(ctx, c.ID, hash); err != nil {
+			router.Logger.Error("Failed to save commute trains", zap.Int64("commute", c.ID), zap.Error(err))
+			continue
+		}
+		if !strings.HasPrefix(c.TrainsHash, serviceDay+":") {
+			continue
+		}
+
+		body := fmt.Sprintf("The %s to %s timetable changed: %d trains today.", c.From, c.To, len(trains))
+		if next := firstAfter(trains, now); next != nil {
+			body += fmt.Sprintf(" Next: %s at %s.", next.TrainID, next.DepartsAt.In(calendar.Jakarta).Format("15:04"))
+		}
+		router.sendPush(ctx, subs(c.UserID), push.Message{
+			Title: c.Name + ": schedule changed",
+			Body:  body,
+			Tag:   fmt.Sprintf("commute-%d-changed", c.ID),
+		}, push.UrgencyNormal)
+	}
+}
+
+// maxTrainsPerDay bounds the trains a commute's fingerprint covers.
+const maxTrainsPerDay = 1000
+
+// trainsHash fingerprints trains by their IDs and times.
+func trainsHash(trains []NextTrain) string {
+	h := sha256.New()
+	for _, t := range trains {
+		fmt.Fprintf(h, "%s@%s>%s;", t.TrainID, t.DepartsAt.Format("15:04"), t.ArrivesAt.Format("15:04"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func firstAfter(trains []NextTrain, now time.Time) *NextTrain {
+	for i := range trains {
+		if trains[i].DepartsAt.After(now) {
+			return &trains[i]
+		}
+	}
+	return nil
+}
+
+// pushSubscriptions returns a loader of users' subscriptions that reads each
+// user's once.
+func (router *Router) pushSubscriptions(ctx context.Context) func(userID int64) []domain.PushSubscription {
+	loaded := make(map[int64][]domain.PushSubscription)
+	return func(userID int64) []domain.PushSubscription {
+		if subs, ok := loaded[userID]; ok {
+			return subs
+		}
+		subs, err := router.Store.GetPushSubscriptions(ctx, userID)
+		if err != nil {
+			router.Logger.Error("Failed to load push subscriptions", zap.Int64("user", userID), zap.Error(err))
+		}
+		loaded[userID] = subs
+		return subs
+	}
+}
+
+// sendPush delivers msg to every subscription, deleting those the push
+// service reports gone.
+func (router *Router) sendPush(ctx context.Context, subs []domain.PushSubscription, msg push.Message, urgency push.Urgency) {
+	for _, sub := range subs {
+		err := router.Push.Send(ctx, sub, msg, urgency)
+		if errors.Is(err, push.ErrGone) {
+			if err := router.Store.DeletePushEndpoint(ctx, sub.Endpoint); err != nil {
+				router.Logger.Error("Failed to delete expired push subscription", zap.Error(err))
+			}
+			continue
+		}
+		if err != nil {
+			host := sub.Endpoint
+			if u, parseErr := url.Parse(sub.Endpoint); parseErr == nil {
+				host = u.Host
+			}
+			router.Logger.Warn("Failed to send push notification", zap.String("push_service", host), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/config"
+)
+
+// bucketIdleTTL is how long a client's bucket is kept after its last request.
+const bucketIdleTTL = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per client.
+type rateLimiter struct {
+	perSecond float64
+	burst     float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// allow takes a token from key's bucket. When the bucket is empty it reports
+// how long until the next token is available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > bucketIdleTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > bucketIdleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.perSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.perSecond * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// RateLimitMiddleware limits API requests per client. Static files and the
+// health check are not limited. It returns next unchanged when rate limiting
+// is disabled.
+func RateLimitMiddleware(cfg config.RateLimitConfig, next http.Handler) http.Handler {
+	if cfg.RequestsPerMinute <= 0 {
+		return next
+	}
+	limiter := &rateLimiter{
+		perSecond: float64(cfg.RequestsPerMinute) / 60,
+		burst:     float64(max(cfg.Burst, 1)),
+		buckets:   make(map[string]*tokenBucket),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, wait := limiter.allow(clientKey(r, cfg.TrustForwardedFor), time.Now())
+		if !ok {
+			writeProblem(w, r, Problem{
+				Code:       "rate_limited",
+				Status:     http.StatusTooManyRequests,
+				RetryAfter: int(math.Ceil(wait.Seconds())),
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client a request counts against.
+func clientKey(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+func (router *Router) HandleRealtimeTrain(w http.ResponseWriter, r *http.Request) {
+	trainID := strings.TrimPrefix(r.URL.Path, "/api/v1/realtime/train/")
+
+	if trainID == "" {
+		writeError(w, r, http.StatusBadRequest, "train_id_required")
+		return
+	}
+
+	position, err := router.Store.GetTrainPosition(r.Context(), trainID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "no_realtime_data")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	router.respond(w, r, position)
+}
+
+func (router *Router) HandleRealtimeStation(w http.ResponseWriter, r *http.Request) {
+	stationID := strings.TrimPrefix(r.URL.Path, "/api/v1/realtime/station/")
+
+	if stationID == "" {
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
+		return
+	}
+
+	positions, err := router.Store.GetStationTrainPositions(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if positions == nil {
+		positions = []domain.TrainPosition{}
+	}
+
+	router.respond(w, r, positions)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// reliabilityWindow is how far back delay history counts towards a score.
+	reliabilityWindow = 30 * 24 * time.Hour
+	maxReportedDelay  = 240
+)
+
+// attachReliability fills in the reliability score of each schedule's train.
+func (router *Router) attachReliability(ctx context.Context, schedules []domain.Schedule) {
+	if len(schedules) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var trainIDs []string
+	for _, sch := range schedules {
+		if !seen[sch.TrainID] {
+			seen[sch.TrainID] = true
+			trainIDs = append(trainIDs, sch.TrainID)
+		}
+	}
+
+	// Reliability is supplementary, so a failure leaves it off the response.
+	scores, err := router.Store.GetTrainReliability(ctx, trainIDs, time.Now().Add(-reliabilityWindow))
+	if err != nil {
+		router.Logger.Warn("Failed to load train reliability", zap.Error(err))
+		return
+	}
+	for i := range schedules {
+		if rel, ok := scores[schedules[i].TrainID]; ok {
+			schedules[i].Reliability = &rel
+		}
+	}
+}
+
+// HandleDelayReport serves POST /api/v1/report/delay, letting riders report
+// how late a train was at one of its stations. A client's later report on the
+// same train and service date replaces its earlier one.
+func (router *Router) HandleDelayReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	var req struct {
+		TrainID      string `json:"train_id"`
+		StationID    string `json:"station_id"`
+		DelayMinutes int    `json:"delay_minutes"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body")
+		return
+	}
+
+	req.TrainID = strings.TrimSpace(req.TrainID)
+	req.StationID = strings.TrimSpace(req.StationID)
+	if req.TrainID == "" || req.StationID == "" {
+		writeError(w, r, http.StatusBadRequest, "delay_fields_required")
+		return
+	}
+	if req.DelayMinutes < 0 || req.DelayMinutes > maxReportedDelay {
+		writeError(w, r, http.StatusBadRequest, "delay_out_of_range")
+		return
+	}
+	route, err := router.Store.GetRoute(r.Context(), req.TrainID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if len(route) == 0 {
+		writeError(w, r, http.StatusNotFound, "unknown_train")
+		return
+	}
+	if !slices.ContainsFunc(route, func(sch domain.Schedule) bool { return sch.StationID == req.StationID }) {
+		writeError(w, r, http.StatusBadRequest, "station_not_on_route", req.TrainID, req.StationID)
+		return
+	}
+
+	err = router.Store.AddDelayReport(r.Context(), domain.DelaySample{
+		TrainID:      req.TrainID,
+		StationID:    req.StationID,
+		Source:       domain.DelaySourceReport,
+		DelayMinutes: req.DelayMinutes,
+		ObservedAt:   time.Now(),
+		Reporter:     clientKey(r, router.Config.RateLimit.TrustForwardedFor),
+		ServiceDate:  calendar.FormatDate(router.Timetable.OperatingDate()),
+	})
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	router.respondStatus(w, r, http.StatusAccepted, "Report received")
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries a request's ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds the client-supplied IDs that are honored.
+const maxRequestIDLen = 128
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	loggerKey
+)
+
+// RequestIDMiddleware gives every request an ID: the client's X-Request-ID
+// when it is a plausible one, otherwise a random one. The ID is echoed in the
+// response header, added to error responses, and attached to the logger that
+// handlers log the request with, so a reported failure can be found in the
+// logs.
+func RequestIDMiddleware(next http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		ctx = context.WithValue(ctx, loggerKey, logger.With(zap.String("request_id", id)))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// log returns the logger for r, which tags lines with its request ID.
+func (router *Router) log(r *http.Request) *zap.Logger {
+	if l, ok := r.Context().Value(loggerKey).(*zap.Logger); ok {
+		return l
+	}
+	return router.Logger
+}
+
+// validRequestID accepts IDs of printable ASCII without spaces, which are
+// safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"llm-router/hooks"
+	"llm-router/internal/protoconv"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Serializer turns a response envelope into bytes for one media type.
+type Serializer interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) ContentType() string { return "application/json" }
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Match json.Encoder output, which the API has always produced.
+	return append(b, '\n'), nil
+}
+
+// protobufSerializer encodes envelopes as commuter.v1.Response messages. It
+// covers the read endpoints mobile clients poll; other responses fail with
+// protoconv.ErrUnsupported and are sent as JSON instead.
+type protobufSerializer struct{}
+
+func (protobufSerializer) ContentType() string { return "application/x-protobuf" }
+
+func (protobufSerializer) Marshal(v interface{}) ([]byte, error) {
+	env, ok := v.(*Envelope)
+	if !ok {
+		return nil, protoconv.ErrUnsupported
+	}
+	msg, err := protoconv.Response(env.Metadata, env.Data)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(msg)
+}
+
+// defaultSerializer is used when the client expresses no usable preference.
+var defaultSerializer Serializer = jsonSerializer{}
+
+// serializers maps the media types clients may ask for to their serializers.
+var serializers = map[string]Serializer{
+	"application/json":       jsonSerializer{},
+	"application/x-protobuf": protobufSerializer{},
+	"application/protobuf":   protobufSerializer{},
+}
+
+// ndjsonType is newline-delimited JSON, one row per line. Endpoints that
+// can list thousands of rows stream them in it, as they are read, to clients
+// that prefer it; it is not a Serializer since an envelope is not rows.
+const ndjsonType = "application/x-ndjson"
+
+// serializerFor picks the serializer for the media type the request's Accept
+// header ranks highest, the first listed among equals.
+func serializerFor(r *http.Request) Serializer {
+	if s, ok := serializers[negotiate(r, nil)]; ok {
+		return s
+	}
+	return defaultSerializer
+}
+
+// wantsNDJSON reports whether the request's Accept header ranks NDJSON above
+// every serializer.
+func wantsNDJSON(r *http.Request) bool {
+	return negotiate(r, []string{ndjsonType}) == ndjsonType
+}
+
+// negotiate returns the media type, among the serializers' and extra, that
+// the request's Accept header ranks highest, the first listed among equals,
+// or "" when it accepts none of them.
+func negotiate(r *http.Request, extra []string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if _, ok := serializers[mediaType]; !ok && !slices.Contains(extra, mediaType) {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > bestQ {
+			best, bestQ = mediaType, q
+		}
+	}
+	return best
+}
+
+// Envelope is the standard response wrapper.
+type Envelope struct {
+	Metadata map[string]interface{} `json:"metadata"`
+	Data     interface{}            `json:"data"`
+}
+
+func newEnvelope(data interface{}) *Envelope {
+	return &Envelope{
+		Metadata: map[string]interface{}{"success": true},
+		Data:     data,
+	}
+}
+
+// addDataNotice adds the configured data license and attribution to response
+// metadata.
+func (router *Router) addDataNotice(metadata map[string]interface{}) {
+	if router.Config.License != "" {
+		metadata["license"] = router.Config.License
+	}
+	if router.Config.Attribution != "" {
+		metadata["attribution"] = router.Config.Attribution
+	}
+}
+
+// runResponseHooks passes the envelope through the registered response hooks
+// and returns the status they leave it with.
+func (router *Router) runResponseHooks(r *http.Request, status int, env *Envelope) int {
+	if len(router.hooks) == 0 {
+		return status
+	}
+	resp := &hooks.Response{Status: status, Metadata: env.Metadata, Data: env.Data}
+	for _, h := range router.hooks {
+		if err := h.ProcessResponse(r, resp); err != nil {
+			router.log(r).Warn("Response hook failed", zap.String("path", r.URL.Path), zap.Error(err))
+		}
+	}
+	if resp.Metadata == nil {
+		resp.Metadata = map[string]interface{}{}
+	}
+	env.Metadata, env.Data = resp.Metadata, resp.Data
+	return resp.Status
+}
+
+// respond writes data in the standard envelope. The body is fully encoded
+// before anything is written so that an encoding failure becomes a clean 500
+// rather than a truncated 200.
+func (router *Router) respond(w http.ResponseWriter, r *http.Request, data interface{}) {
+	router.respondEnvelope(w, r, http.StatusOK, newEnvelope(data))
+}
+
+// respondStatus is respond with a status code other than 200.
+func (router *Router) respondStatus(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	router.respondEnvelope(w, r, status, newEnvelope(data))
+}
+
+func (router *Router) respondEnvelope(w http.ResponseWriter, r *http.Request, status int, env *Envelope) {
+	router.addDataNotice(env.Metadata)
+	status = router.runResponseHooks(r, status, env)
+	s := serializerFor(r)
+	body, err := s.Marshal(env)
+	if errors.Is(err, protoconv.ErrUnsupported) {
+		s = defaultSerializer
+		body, err = s.Marshal(env)
+	}
+	if err != nil {
+		router.log(r).Error("Failed to encode response", zap.String("path", r.URL.Path), zap.Error(err))
+		writeError(w, r, http.StatusInternalServerError, "encode_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", s.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// jsonStream writes a JSON document to the client incrementally, for payloads
+// too large to build in memory first (e.g. full network dumps). Errors are
+// sticky: after the first failure every call is a no-op and Err reports it.
+type jsonStream struct {
+	w   *bufio.Writer
+	err error
+	// needComma tracks, per open container, whether the next element needs
+	// a separator.
+	needComma []bool
+	// afterKey is set between an object key and its value.
+	afterKey bool
+}
+
+func newJSONStream(w io.Writer) *jsonStream {
+	return &jsonStream{w: bufio.NewWriterSize(w, 32*1024)}
+}
+
+func (s *jsonStream) raw(b string) {
+	if s.err == nil {
+		_, s.err = s.w.WriteString(b)
+	}
+}
+
+func (s *jsonStream) separator() {
+	if s.afterKey {
+		s.afterKey = false
+		return
+	}
+	if n := len(s.needComma); n > 0 {
+		if s.needComma[n-1] {
+			s.raw(",")
+		}
+		s.needComma[n-1] = true
+	}
+}
+
+func (s *jsonStream) open(delim string) {
+	s.raw(delim)
+	s.needComma = append(s.needComma, false)
+}
+
+func (s *jsonStream) close(delim string) {
+	if len(s.needComma) == 0 {
+		if s.err == nil {
+			s.err = errors.New("json stream: close without open")
+		}
+		return
+	}
+	s.needComma = s.needComma[:len(s.needComma)-1]
+	s.raw(delim)
+}
+
+func (s *jsonStream) BeginObject() { s.separator(); s.open("{") }
+func (s *jsonStream) EndObject()   { s.close("}") }
+func (s *jsonStream) BeginArray()  { s.separator(); s.open("[") }
+func (s *jsonStream) EndArray()    { s.close("]") }
+
+// Key writes an object key; the following Begin*/Value call provides its value.
+func (s *jsonStream) Key(k string) {
+	s.separator()
+	b, _ := json.Marshal(k)
+	s.raw(string(b) + ":")
+	s.afterKey = true
+}
+
+// Value writes a complete JSON value as an array element or object value.
+func (s *jsonStream) Value(v interface{}) {
+	s.separator()
+	if s.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	_, s.err = s.w.Write(b)
+}
+
+// Flush pushes buffered output to the client.
+func (s *jsonStream) Flush() {
+	if s.err == nil {
+		s.err = s.w.Flush()
+	}
+}
+
+func (s *jsonStream) Err() error {
+	return s.err
+}
+
+// ndjsonStream writes rows to the client as newline-delimited JSON as they
+// are produced. Like jsonStream its errors are sticky.
+type ndjsonStream struct {
+	w    *bufio.Writer
+	enc  *json.Encoder
+	rows int
+	err  error
+}
+
+// newNDJSONStream sets the response's content type and returns a stream
+// writing to it.
+func newNDJSONStream(w http.ResponseWriter) *ndjsonStream {
+	w.Header().Set("Content-Type", ndjsonType)
+	w.Header().Add("Vary", "Accept")
+	bw := bufio.NewWriterSize(w, 32*1024)
+	return &ndjsonStream{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Row writes v as one line, returning the stream's error so that it can end
+// a store iteration.
+func (s *ndjsonStream) Row(v interface{}) error {
+	if s.err == nil {
+		if s.err = s.enc.Encode(v); s.err == nil {
+			s.rows++
+		}
+	}
+	return s.err
+}
+
+// Flush pushes buffered output to the client.
+func (s *ndjsonStream) Flush() {
+	if s.err == nil {
+		s.err = s.w.Flush()
+	}
+}
+
+// Rows returns the number of rows written.
+func (s *ndjsonStream) Rows() int {
+	return s.rows
+}
+
+func (s *ndjsonStream) Err() error {
+	return s.err
+}
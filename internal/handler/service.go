@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// serviceTypes parses the optional service_type= filter, a comma-separated
+// list of service types. It writes an error response and reports false when
+// a type is unknown; an absent filter yields nil.
+func serviceTypes(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("service_type"))
+	if raw == "" {
+		return nil, true
+	}
+
+	var services []string
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !slices.Contains(domain.ServiceTypes, s) {
+			writeError(w, r, http.StatusBadRequest, "service_type_invalid", strings.Join(domain.ServiceTypes, ", "))
+			return nil, false
+		}
+		services = append(services, s)
+	}
+	return services, true
+}
+
+// filterServices returns the schedules of the given service types, or
+// schedules itself when services is empty. The input is not modified.
+func filterServices(schedules []domain.Schedule, services []string) []domain.Schedule {
+	if len(services) == 0 {
+		return schedules
+	}
+	filtered := []domain.Schedule{}
+	for _, sch := range schedules {
+		if slices.Contains(services, sch.ServiceType) {
+			filtered = append(filtered, sch)
+		}
+	}
+	return filtered
+}
+
+// operatingDay returns the kind of day of the service date running now,
+// whose timetable the live views show. Late trains after midnight still run
+// on the previous date's timetable.
+func (router *Router) operatingDay() string {
+	return router.Calendar.ServiceDay(router.Timetable.OperatingDate())
+}
+
+// serviceDate parses the optional date= parameter, a YYYY-MM-DD day in
+// Jakarta, defaulting to the service date running now; see
+// calendar.OperatingDate. It writes an error response and reports false
+// when the date is malformed.
+func (router *Router) serviceDate(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("date"))
+	if raw == "" {
+		return router.Timetable.OperatingDate(), true
+	}
+	date, err := calendar.ParseDate(raw)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "date_invalid")
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/geodata"
+	"llm-router/internal/store"
+)
+
+// HandleRouteShape serves /api/v1/route/{train}/shape?date=, the path the
+// train travels as an encoded polyline. Between stops it follows the
+// imported track of the train's line, falling back to a straight line where
+// there is none, so maps can draw the route without importing the track
+// themselves.
+func (router *Router) HandleRouteShape(w http.ResponseWriter, r *http.Request, trainID string) {
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+
+	route, err := router.Timetable.DatedRoute(ctx, trainID, date)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "train_not_found", trainID)
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	locs, err := router.Store.GetStationLocations(ctx)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	byStation := make(map[string]domain.LatLng, len(locs))
+	for _, loc := range locs {
+		byStation[loc.StationID] = domain.LatLng{Lat: loc.Lat, Lng: loc.Lng}
+	}
+	var stops []domain.LatLng
+	for _, stop := range route.Routes {
+		if p, ok := byStation[stop.StationID]; ok {
+			stops = append(stops, p)
+		}
+	}
+	if len(stops) < 2 {
+		writeError(w, r, http.StatusNotFound, "shape_unavailable", trainID)
+		return
+	}
+
+	shape, err := router.Store.GetLineShape(ctx, route.Details.Line)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	resp := domain.RouteShape{
+		TrainID:    route.Details.TrainID,
+		Line:       route.Details.Line,
+		ServiceDay: route.Details.ServiceDay,
+		Stops:      len(stops),
+	}
+	points := []domain.LatLng{stops[0]}
+	for i := 1; i < len(stops); i++ {
+		track, ok := geodata.Track(shape, stops[i-1], stops[i])
+		if !ok {
+			track = []domain.LatLng{stops[i-1], stops[i]}
+			resp.StraightSegments++
+		}
+		points = append(points, track[1:]...)
+	}
+	resp.Polyline = geodata.EncodePolyline(points)
+	resp.Points = len(points)
+	router.respond(w, r, resp)
+}
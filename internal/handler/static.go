@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	spaIndex = "index.html"
+
+	// spaAssetsDir is where the frontend build writes content-hashed files,
+	// which never change under the same name.
+	spaAssetsDir = "assets/"
+
+	cacheImmutable  = "public, max-age=31536000, immutable"
+	cacheRevalidate = "no-cache"
+)
+
+// SPAHandler serves the single-page frontend from fsys. Existing files are
+// served as they are; other paths without a file extension are client-side
+// routes and get index.html, while missing files with one answer 404 rather
+// than an HTML page the browser would try to parse as a script or style.
+// Hashed build assets are cached for good; everything else, index.html
+// included, is revalidated so a deploy is picked up on the next load.
+func SPAHandler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/api" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Cleaning a rooted path resolves every "..", so the name cannot
+		// leave fsys. A NUL is valid to fs but no file system opens it.
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" || !fs.ValidPath(name) || strings.ContainsRune(name, 0) {
+			name = spaIndex
+		}
+
+		info, err := fs.Stat(fsys, name)
+		switch {
+		case err == nil && !info.IsDir():
+		case err != nil && !errors.Is(err, fs.ErrNotExist):
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		case path.Ext(name) != "":
+			http.NotFound(w, r)
+			return
+		default:
+			name = spaIndex
+		}
+
+		if strings.HasPrefix(name, spaAssetsDir) {
+			w.Header().Set("Cache-Control", cacheImmutable)
+		} else {
+			w.Header().Set("Cache-Control", cacheRevalidate)
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		serveFile(w, r, fsys, name)
+	})
+}
+
+// serveFile serves name from fsys. Unlike http.ServeFileFS it does not
+// redirect requests for index.html, which the SPA fallback serves under any
+// path.
+func serveFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string) {
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
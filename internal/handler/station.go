@@ -0,0 +1,421 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+	"llm-router/internal/store"
+)
+
+// HandleStationDetail serves /api/v1/station/{id}.
+func (router *Router) HandleStationDetail(w http.ResponseWriter, r *http.Request) {
+	stationID, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/station/"), "/")
+
+	if stationID == "" {
+		router.HandleStation(w, r)
+		return
+	}
+
+	switch sub {
+	case "":
+	case "submissions":
+		router.HandleStationSubmission(w, r, stationID)
+		return
+	case "stats":
+		router.HandleStationStats(w, r, stationID)
+		return
+	case "first-last":
+		router.HandleFirstLast(w, r, stationID)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	station, err := router.Store.GetStation(r.Context(), stationID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "station_not_found")
+		return
+	}
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	services, ok := serviceTypes(w, r)
+	if !ok {
+		return
+	}
+	date, ok := router.serviceDate(w, r)
+	if !ok {
+		return
+	}
+
+	router.usage.hit(store.UsageKindStation, stationID)
+
+	schedules, err := router.Timetable.DatedBoard(r.Context(), stationID, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	station.DisplayName = station.NameOn(calendar.FormatDate(date), displayLang(w, r))
+	detail := buildStationDetail(station, filterServices(schedules, services))
+
+	f, err := router.Store.GetStationFacilities(r.Context(), stationID)
+	switch {
+	case err == nil:
+		detail.Facilities = &f
+	case !errors.Is(err, store.ErrNotFound):
+		router.writeStoreError(w, r, err)
+		return
+	}
+	loc, err := router.Store.GetStationLocation(r.Context(), stationID)
+	switch {
+	case err == nil:
+		detail.Location = &loc
+	case !errors.Is(err, store.ErrNotFound):
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if router.featureEnabled(domain.FeatureCommunity) {
+		if detail.Photos, err = router.Store.GetApprovedPhotos(r.Context(), stationID); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+	}
+	if detail.Transfers, err = router.stationTransfers(r.Context(), stationID); err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	router.respond(w, r, detail)
+}
+
+// buildStationDetail derives per-line service and first/last departures from
+// a station's schedules.
+func buildStationDetail(station domain.Station, schedules []domain.Schedule) domain.StationDetail {
+	detail := domain.StationDetail{
+		Station: station,
+		Lines:   []domain.StationLine{},
+	}
+
+	lines := make(map[string]*domain.StationLine)
+	destinations := make(map[string]map[string]bool)
+	for _, sch := range schedules {
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+
+		l, ok := lines[sch.Line]
+		if !ok {
+			l = &domain.StationLine{
+				Line:           sch.Line,
+				Color:          sch.Metadata.Origin.Color,
+				FirstDeparture: sch.DepartsAt,
+				LastDeparture:  sch.DepartsAt,
+			}
+			lines[sch.Line] = l
+			destinations[sch.Line] = make(map[string]bool)
+		}
+
+		l.Departures++
+		if sch.DepartsAt.Before(l.FirstDeparture) {
+			l.FirstDeparture = sch.DepartsAt
+		}
+		if sch.DepartsAt.After(l.LastDeparture) {
+			l.LastDeparture = sch.DepartsAt
+		}
+		if sch.StationDestinationID != "" && sch.StationDestinationID != station.ID {
+			destinations[sch.Line][sch.StationDestinationID] = true
+		}
+
+		if detail.FirstDeparture == nil || sch.DepartsAt.Before(*detail.FirstDeparture) {
+			t := sch.DepartsAt
+			detail.FirstDeparture = &t
+		}
+		if detail.LastDeparture == nil || sch.DepartsAt.After(*detail.LastDeparture) {
+			t := sch.DepartsAt
+			detail.LastDeparture = &t
+		}
+	}
+
+	for name, l := range lines {
+		l.Destinations = make([]string, 0, len(destinations[name]))
+		for dest := range destinations[name] {
+			l.Destinations = append(l.Destinations, dest)
+		}
+		sort.Strings(l.Destinations)
+		detail.Lines = append(detail.Lines, *l)
+	}
+	sort.Slice(detail.Lines, func(i, j int) bool { return detail.Lines[i].Line < detail.Lines[j].Line })
+
+	detail.Interchange = len(detail.Lines) > 1
+	return detail
+}
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	// maxSearchQuery bounds the query, in characters, since matching cost
+	// grows with its length. No station name comes close.
+	maxSearchQuery = 100
+)
+
+// HandleStationSearch serves /api/v1/station/search?q=, ranking stations by
+// how closely their names or aliases match the query.
+func (router *Router) HandleStationSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, r, http.StatusBadRequest, "q_required")
+		return
+	}
+	if utf8.RuneCountInString(q) > maxSearchQuery {
+		writeError(w, r, http.StatusBadRequest, "q_too_long", maxSearchQuery)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			writeError(w, r, http.StatusBadRequest, "limit_out_of_range", maxSearchLimit)
+			return
+		}
+		limit = n
+	}
+
+	idx, err := router.stationIndex(r.Context())
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	results := idx.Search(q, limit)
+	lang := displayLang(w, r)
+	for i := range results {
+		results[i].Station.DisplayName = results[i].Station.LocalName(lang)
+	}
+	router.respond(w, r, results)
+}
+
+// displayLang returns the language to show station names in, negotiated from
+// Accept-Language.
+func displayLang(w http.ResponseWriter, r *http.Request) string {
+	w.Header().Add("Vary", "Accept-Language")
+	return string(i18n.Negotiate(r.Header.Get("Accept-Language")))
+}
+
+// stationAliasBody is the body of POST /api/admin/stations/{id}/aliases.
+type stationAliasBody struct {
+	Name    string `json:"name"`
+	Lang    string `json:"lang"`
+	Display bool   `json:"display"`
+}
+
+// maxAliasName bounds alias names, which are station names.
+const maxAliasName = 100
+
+// HandleAdminStations serves the per-station admin endpoints:
+//
+//	GET    /api/admin/stations/{id}/aliases
+//	POST   /api/admin/stations/{id}/aliases          {"name", "lang", "display"}
+//	DELETE /api/admin/stations/{id}/aliases?name=&lang=
+//	GET    /api/admin/stations/{id}/platforms
+//	POST   /api/admin/stations/{id}/platforms        {"line", "destination_id", "platform"}
+//	DELETE /api/admin/stations/{id}/platforms?line=&destination_id=
+//	GET    /api/admin/stations/{id}/transfers
+//	PUT    /api/admin/stations/{id}/transfers        {"to_station_id", "walk_minutes", "note"}
+//	DELETE /api/admin/stations/{id}/transfers?to=
+//	POST   /api/admin/stations/{id}/sync
+//	GET    /api/admin/stations/{id}/closure
+//	PUT    /api/admin/stations/{id}/closure          {"on"}
+//	DELETE /api/admin/stations/{id}/closure
+//	GET    /api/admin/stations/{id}/rename
+//	PUT    /api/admin/stations/{id}/rename           {"name", "on"}
+//	DELETE /api/admin/stations/{id}/rename
+func (router *Router) HandleAdminStations(w http.ResponseWriter, r *http.Request) {
+	stationID, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/admin/stations/"), "/")
+	switch sub {
+	case "aliases", "platforms", "transfers", "sync", "closure", "rename":
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if stationID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	station, err := router.Store.GetStation(r.Context(), stationID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "station_not_found")
+		return
+	} else if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	switch sub {
+	case "closure", "rename":
+		router.handleAdminStationChange(w, r, station, sub)
+	case "sync":
+		router.handleAdminStationSync(w, r, stationID)
+	case "platforms":
+		router.handleAdminStationPlatforms(w, r, stationID)
+	case "transfers":
+		router.handleAdminStationTransfers(w, r, stationID)
+	default:
+		router.handleAdminStationAliases(w, r, stationID)
+	}
+}
+
+// handleAdminStationAliases manages the names a station is also known by.
+// Aliases take effect in search at once and in route name resolution from
+// the next sync.
+func (router *Router) handleAdminStationAliases(w http.ResponseWriter, r *http.Request, stationID string) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var body stationAliasBody
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		alias, ok := validAlias(w, r, body.Name, body.Lang)
+		if !ok {
+			return
+		}
+		alias.Display = body.Display
+		if err := router.Store.SetStationAlias(r.Context(), stationID, alias); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.search.Store(nil)
+		router.autocomplete.Store(nil)
+	case http.MethodDelete:
+		alias, ok := validAlias(w, r, r.URL.Query().Get("name"), r.URL.Query().Get("lang"))
+		if !ok {
+			return
+		}
+		err := router.Store.DeleteStationAlias(r.Context(), stationID, alias.Name, alias.Lang)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "alias_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		router.search.Store(nil)
+		router.autocomplete.Store(nil)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	aliases, err := router.Store.GetStationAliases(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if aliases == nil {
+		aliases = []domain.StationAlias{}
+	}
+	router.respond(w, r, aliases)
+}
+
+// validAlias checks an alias name and language given to the admin API,
+// writing a 400 when they are unusable.
+func validAlias(w http.ResponseWriter, r *http.Request, name, lang string) (domain.StationAlias, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAliasName {
+		writeError(w, r, http.StatusBadRequest, "alias_name_invalid", maxAliasName)
+		return domain.StationAlias{}, false
+	}
+	if lang != "" && !i18n.Supported(lang) {
+		writeError(w, r, http.StatusBadRequest, "alias_lang_invalid")
+		return domain.StationAlias{}, false
+	}
+	return domain.StationAlias{Name: name, Lang: lang}, true
+}
+
+// maxPlatform bounds platform names, which are a number or two and a letter
+// at the largest stations.
+const maxPlatform = 10
+
+// handleAdminStationPlatforms manages the platforms assigned to a station's
+// departures where the upstream lists none. Assignments take effect from the
+// station's next sync.
+func (router *Router) handleAdminStationPlatforms(w http.ResponseWriter, r *http.Request, stationID string) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var body domain.PlatformAssignment
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		a, ok := validPlatformAssignment(w, r, body.Line, body.DestinationID)
+		if !ok {
+			return
+		}
+		a.Platform = strings.TrimSpace(body.Platform)
+		if a.Platform == "" || utf8.RuneCountInString(a.Platform) > maxPlatform {
+			writeError(w, r, http.StatusBadRequest, "platform_invalid", maxPlatform)
+			return
+		}
+		if a.DestinationID != "" && !router.knownStations(w, r, a.DestinationID) {
+			return
+		}
+		if err := router.Store.SetPlatformAssignment(r.Context(), stationID, a); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+	case http.MethodDelete:
+		a, ok := validPlatformAssignment(w, r, r.URL.Query().Get("line"), r.URL.Query().Get("destination_id"))
+		if !ok {
+			return
+		}
+		err := router.Store.DeletePlatformAssignment(r.Context(), stationID, a.Line, a.DestinationID)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "platform_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	assignments, err := router.Store.GetPlatformAssignments(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if assignments == nil {
+		assignments = []domain.PlatformAssignment{}
+	}
+	router.respond(w, r, assignments)
+}
+
+// validPlatformAssignment checks the line and destination of an assignment
+// given to the admin API, writing a 400 when the line is missing.
+func validPlatformAssignment(w http.ResponseWriter, r *http.Request, line, destinationID string) (domain.PlatformAssignment, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		writeError(w, r, http.StatusBadRequest, "platform_line_required")
+		return domain.PlatformAssignment{}, false
+	}
+	return domain.PlatformAssignment{Line: line, DestinationID: strings.TrimSpace(destinationID)}, true
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// stationChangeBody is the body of PUT /api/admin/stations/{id}/closure and
+// /rename. On is the service date the change takes effect, as YYYY-MM-DD.
+type stationChangeBody struct {
+	Name string `json:"name"`
+	On   string `json:"on"`
+}
+
+// handleAdminStationChange schedules a station's closure or rename, kind
+// being "closure" or "rename", or with DELETE calls it off. A rename keeps
+// the station's current name as its former one. Changes take effect on
+// departures, routes and names at once, and dates before them keep being
+// served as they were.
+func (router *Router) handleAdminStationChange(w http.ResponseWriter, r *http.Request, station domain.Station, kind string) {
+	var c domain.StationChange
+	if station.Changes != nil {
+		c = *station.Changes
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		router.respond(w, r, c)
+		return
+	case http.MethodPut:
+		var body stationChangeBody
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		on := strings.TrimSpace(body.On)
+		if _, err := calendar.ParseDate(on); err != nil {
+			writeError(w, r, http.StatusBadRequest, "date_invalid")
+			return
+		}
+		if kind == "closure" {
+			c.ClosesOn = on
+			break
+		}
+		name := strings.TrimSpace(body.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxAliasName {
+			writeError(w, r, http.StatusBadRequest, "station_name_invalid", maxAliasName)
+			return
+		}
+		if c.RenamedOn == "" {
+			c.FormerName = station.LocalName("")
+		}
+		c.Name, c.RenamedOn = name, on
+	case http.MethodDelete:
+		if kind == "closure" {
+			c.ClosesOn = ""
+		} else {
+			c.Name, c.FormerName, c.RenamedOn = "", "", ""
+		}
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	if err := router.Store.SetStationChange(r.Context(), station.ID, c); err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	router.Timetable.StationChangesChanged()
+	router.resetDatedCaches()
+	router.search.Store(nil)
+	router.autocomplete.Store(nil)
+	router.purge(r.Context(), []string{stationKey(station.ID), surrogateKeyNetwork})
+	router.respond(w, r, c)
+}
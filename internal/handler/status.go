@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/i18n"
+	"llm-router/internal/store"
+)
+
+const (
+	// headwayWindow is the span around now whose departures the headway is
+	// averaged over.
+	headwayWindow = time.Hour
+
+	// recentDelayWindow is how far back delays count towards a line's
+	// status.
+	recentDelayWindow = 30 * time.Minute
+
+	// disruptedDelayMinutes is the average recent delay from which a line
+	// is reported as disrupted.
+	disruptedDelayMinutes = 10
+)
+
+// tripSpan is one train's trip, from its first departure to its arrival at
+// the terminus, with times as stamped on the schedules.
+type tripSpan struct {
+	TrainID   string
+	Origin    string
+	Terminus  string
+	DepartsAt time.Time
+	ArrivesAt time.Time
+}
+
+// lineTrips holds the trips of each line, ordered by departure.
+type lineTrips map[string][]tripSpan
+
+// HandleStatus serves /api/v1/status, an overview of every line now:
+// whether it is operating, its first and last trains today, how many trains
+// are running and how often they come, recent delays and announcements.
+// Line names, statuses and announcements are given in the language
+// negotiated from Accept-Language as well.
+func (router *Router) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	now := time.Now()
+	date := router.Timetable.OperatingDate()
+	serviceDay := router.Calendar.ServiceDay(date)
+
+	lines, err := router.Store.GetLines(ctx)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	trips, err := router.lineTrips(ctx, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	delays, err := router.Store.GetLineDelays(ctx, now.Add(-recentDelayWindow))
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	announcements, err := router.Store.GetActiveAnnouncements(ctx, now)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	lang := i18n.Lang(displayLang(w, r))
+	localizeAnnouncements(lang, announcements)
+
+	status := domain.NetworkStatus{ServiceDay: serviceDay, GeneratedAt: now, Lines: []domain.LineStatus{}}
+	for _, a := range announcements {
+		if a.Line == "" && len(a.StationIDs) == 0 {
+			status.Announcements = append(status.Announcements, a)
+		}
+	}
+	for _, line := range lines {
+		ls := lineStatus(trips[line.Name], now)
+		ls.Line, ls.Color = line.Name, line.Color
+		ls.DisplayName = i18n.LineName(lang, line.Name)
+		ls.StatusLabel = i18n.T(lang, "line_status."+ls.Status)
+		if d, ok := delays[line.Name]; ok {
+			ls.Delays = &d
+			ls.Disrupted = d.AvgDelayMinutes >= disruptedDelayMinutes
+		}
+		for _, a := range announcements {
+			if a.Line == "" && len(a.StationIDs) == 0 {
+				continue
+			}
+			stations, err := router.lineStations(ctx, line.Name)
+			if err != nil {
+				router.writeStoreError(w, r, err)
+				return
+			}
+			if a.Concerns([]string{line.Name}, stations) {
+				ls.Announcements = append(ls.Announcements, a)
+				ls.Disrupted = ls.Disrupted || a.Severity == domain.SeveritySevere
+			}
+		}
+		status.Lines = append(status.Lines, ls)
+	}
+	router.respond(w, r, status)
+}
+
+// lineStatus derives a line's service at now from its trips today.
+func lineStatus(trips []tripSpan, now time.Time) domain.LineStatus {
+	ls := domain.LineStatus{Status: domain.LineOutsideServiceHours}
+	if len(trips) == 0 {
+		return ls
+	}
+
+	// Trips are moved onto today like the other live views; see clockToday.
+	type span struct {
+		tripSpan
+		start, end time.Time
+	}
+	spans := make([]span, 0, len(trips))
+	for _, t := range trips {
+		start := clockToday(t.DepartsAt, now)
+		end := start
+		if t.ArrivesAt.After(t.DepartsAt) {
+			end = start.Add(t.ArrivesAt.Sub(t.DepartsAt))
+		}
+		spans = append(spans, span{t, start, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	first, last := spans[0], spans[len(spans)-1]
+	ls.FirstTrain = &domain.LineTrip{TrainID: first.TrainID, StationID: first.Origin, DepartsAt: first.start}
+	ls.LastTrain = &domain.LineTrip{TrainID: last.TrainID, StationID: last.Origin, DepartsAt: last.start}
+
+	closes := first.end
+	gaps, total := 0, time.Duration(0)
+	previous := make(map[string]time.Time)
+	for _, s := range spans {
+		if s.end.After(closes) {
+			closes = s.end
+		}
+		if !now.Before(s.start) && !now.After(s.end) {
+			ls.TrainsRunning++
+		}
+		if s.start.Before(now.Add(-headwayWindow/2)) || s.start.After(now.Add(headwayWindow/2)) {
+			continue
+		}
+		// Headways are between trains heading to the same terminus.
+		if prev, ok := previous[s.Terminus]; ok {
+			gaps++
+			total += s.start.Sub(prev)
+		}
+		previous[s.Terminus] = s.start
+	}
+	if !now.Before(first.start) && !now.After(closes) {
+		ls.Status = domain.LineOperating
+	}
+	if gaps > 0 {
+		headway := (total / time.Duration(gaps)).Round(6 * time.Second).Minutes()
+		ls.HeadwayMinutes = &headway
+	}
+	return ls
+}
+
+// lineTrips returns the trips of every line as they run on date, building
+// them on first use after each sync or change to the overrides or closures.
+func (router *Router) lineTrips(ctx context.Context, date time.Time) (lineTrips, error) {
+	key, err := router.Timetable.NetworkKey(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if trips, ok := router.trips.Get(key); ok {
+		return trips, nil
+	}
+	schedules, err := router.Timetable.DatedSchedules(ctx, store.ScheduleFilter{}, date)
+	if err != nil {
+		return nil, err
+	}
+
+	byTrain := make(map[string]*tripSpan)
+	lineOf := make(map[string]string)
+	for _, sch := range schedules {
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+		t, ok := byTrain[sch.TrainID]
+		if !ok {
+			t = &tripSpan{TrainID: sch.TrainID, Terminus: sch.StationDestinationID, DepartsAt: sch.DepartsAt, Origin: sch.StationID}
+			byTrain[sch.TrainID] = t
+			lineOf[sch.TrainID] = sch.Line
+		}
+		if sch.DepartsAt.Before(t.DepartsAt) {
+			t.DepartsAt, t.Origin = sch.DepartsAt, sch.StationID
+		}
+		arrives := sch.DepartsAt
+		if sch.StationID == sch.StationDestinationID && !sch.ArrivesAt.IsZero() {
+			arrives = sch.ArrivesAt
+		}
+		if arrives.After(t.ArrivesAt) {
+			t.ArrivesAt = arrives
+		}
+	}
+
+	trips := make(lineTrips)
+	for id, t := range byTrain {
+		trips[lineOf[id]] = append(trips[lineOf[id]], *t)
+	}
+	for _, ts := range trips {
+		sort.Slice(ts, func(i, j int) bool { return ts[i].DepartsAt.Before(ts[j].DepartsAt) })
+	}
+	router.trips.Set(key, trips)
+	return trips, nil
+}
+
+// lineStations returns the IDs of a line's stations.
+func (router *Router) lineStations(ctx context.Context, name string) ([]string, error) {
+	line, err := router.lineData(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(line.Stations))
+	for _, st := range line.Stations {
+		ids = append(ids, st.ID)
+	}
+	return ids, nil
+}
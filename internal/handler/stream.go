@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	streamCheckInterval = 15 * time.Second
+	streamKeepAlive     = 30 * time.Second
+)
+
+// imminentThresholds are the lead times, in ascending order, at which a
+// departure event is pushed to stream subscribers.
+var imminentThresholds = []time.Duration{5 * time.Minute, 10 * time.Minute}
+
+type departureEvent struct {
+	Schedule     domain.Schedule `json:"schedule"`
+	DepartsAt    time.Time       `json:"departs_at"`
+	MinutesUntil int             `json:"minutes_until"`
+	Threshold    int             `json:"threshold"`
+}
+
+// HandleScheduleStream serves /api/v1/schedule/{id}/stream as Server-Sent Events.
+// It emits "departure" events as trains approach their departure time and
+// "refresh" events whenever the station's schedule data is re-synced.
+func (router *Router) HandleScheduleStream(w http.ResponseWriter, r *http.Request, stationID string) {
+	if !router.featureEnabled(domain.FeatureStreaming) {
+		http.NotFound(w, r)
+		return
+	}
+	if stationID == "" {
+		writeError(w, r, http.StatusBadRequest, "station_id_required")
+		return
+	}
+
+	date := router.Timetable.OperatingDate()
+	schedules, err := router.Timetable.DatedBoard(r.Context(), stationID, date)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+
+	sse, ok := utils.NewSSEWriter(w)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, "streaming_unsupported")
+		return
+	}
+
+	sub := router.Events.Subscribe(events.StationTopic(stationID))
+	defer sub.Close()
+
+	router.log(r).Debug("Schedule stream opened", zap.String("station", stationID))
+	defer router.log(r).Debug("Schedule stream closed", zap.String("station", stationID))
+
+	sent := make(map[string]bool)
+
+	checkTicker := time.NewTicker(streamCheckInterval)
+	defer checkTicker.Stop()
+	keepAliveTicker := time.NewTicker(streamKeepAlive)
+	defer keepAliveTicker.Stop()
+
+	if err := router.pushImminentDepartures(sse, schedules, sent, time.Now()); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			// On a failed reload keep streaming from the previous schedules.
+			// The sync is still running, so its board is not cached yet.
+			if updated, err := router.Timetable.UncachedDatedBoard(r.Context(), stationID, date); err != nil {
+				router.log(r).Warn("Failed to reload schedules for stream", zap.String("station", stationID), zap.Error(err))
+			} else {
+				schedules = updated
+			}
+			if err := sse.Event("refresh", e); err != nil {
+				return
+			}
+		case now := <-checkTicker.C:
+			// A stream open into the next service date moves onto its board.
+			if next := router.Timetable.OperatingDate(); !next.Equal(date) {
+				if updated, err := router.Timetable.DatedBoard(r.Context(), stationID, next); err != nil {
+					router.log(r).Warn("Failed to reload schedules for stream", zap.String("station", stationID), zap.Error(err))
+				} else {
+					schedules, date = updated, next
+				}
+			}
+			if err := router.pushImminentDepartures(sse, schedules, sent, now); err != nil {
+				return
+			}
+		case <-keepAliveTicker.C:
+			if err := sse.Comment("keep-alive"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// pushImminentDepartures sends one event per departure and threshold crossed.
+// A departure already inside several thresholds is only reported for the
+// smallest one. schedules are the station's board on the service date
+// running now.
+func (router *Router) pushImminentDepartures(sse *utils.SSEWriter, schedules []domain.Schedule, sent map[string]bool, now time.Time) error {
+	for _, sch := range schedules {
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+
+		departsAt := clockToday(sch.DepartsAt, now)
+		until := departsAt.Sub(now)
+		if until <= 0 {
+			continue
+		}
+
+		for i, threshold := range imminentThresholds {
+			if until > threshold {
+				continue
+			}
+
+			key := fmt.Sprintf("%s:%d", sch.TrainID, threshold/time.Minute)
+			if sent[key] {
+				break
+			}
+			for _, t := range imminentThresholds[i:] {
+				sent[fmt.Sprintf("%s:%d", sch.TrainID, t/time.Minute)] = true
+			}
+
+			if err := sse.Event("departure", departureEvent{
+				Schedule:     sch,
+				DepartsAt:    departsAt,
+				MinutesUntil: int(until.Round(time.Minute) / time.Minute),
+				Threshold:    int(threshold / time.Minute),
+			}); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// clockToday moves the wall-clock time of t onto now's calendar day, in t's
+// location. Schedules are stamped with the sync date, so this keeps them
+// meaningful after midnight until the next sync.
+func clockToday(t, now time.Time) time.Time {
+	n := now.In(t.Location())
+	return time.Date(n.Year(), n.Month(), n.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+const (
+	// maxTransferWalk bounds the walk of a curated transfer, in minutes.
+	maxTransferWalk = 30
+	// maxTransferNote bounds the note on a curated transfer, in characters.
+	maxTransferNote = 200
+)
+
+// transferBody is the body of PUT /api/admin/stations/{id}/transfers.
+type transferBody struct {
+	ToStationID string `json:"to_station_id"`
+	WalkMinutes int    `json:"walk_minutes"`
+	Note        string `json:"note"`
+}
+
+// handleAdminStationTransfers curates the walks between a station and the
+// stations near it, either way. Changes take effect in the journey planner
+// and the station detail at once.
+func (router *Router) handleAdminStationTransfers(w http.ResponseWriter, r *http.Request, stationID string) {
+	var changed string
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var body transferBody
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&body); err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_body")
+			return
+		}
+		to := strings.TrimSpace(body.ToStationID)
+		if to == "" || to == stationID {
+			writeError(w, r, http.StatusBadRequest, "transfer_station_invalid")
+			return
+		}
+		if body.WalkMinutes < 1 || body.WalkMinutes > maxTransferWalk {
+			writeError(w, r, http.StatusBadRequest, "transfer_walk_invalid", maxTransferWalk)
+			return
+		}
+		note := strings.TrimSpace(body.Note)
+		if utf8.RuneCountInString(note) > maxTransferNote {
+			writeError(w, r, http.StatusBadRequest, "transfer_note_too_long", maxTransferNote)
+			return
+		}
+		if !router.knownStations(w, r, to) {
+			return
+		}
+		t := domain.Transfer{StationID: stationID, ToStationID: to, WalkMinutes: body.WalkMinutes, Note: note}
+		if err := router.Store.SetTransfer(r.Context(), t); err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		changed = to
+	case http.MethodDelete:
+		to := strings.TrimSpace(r.URL.Query().Get("to"))
+		err := router.Store.DeleteTransfer(r.Context(), stationID, to)
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, "transfer_not_found")
+			return
+		}
+		if err != nil {
+			router.writeStoreError(w, r, err)
+			return
+		}
+		changed = to
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	if changed != "" {
+		router.planners.Reset()
+		router.purge(r.Context(), []string{stationKey(stationID), stationKey(changed)})
+	}
+	transfers, err := router.stationTransfers(r.Context(), stationID)
+	if err != nil {
+		router.writeStoreError(w, r, err)
+		return
+	}
+	if transfers == nil {
+		transfers = []domain.Transfer{}
+	}
+	router.respond(w, r, transfers)
+}
+
+// stationTransfers returns the walks from stationID to the stations near it,
+// with their names.
+func (router *Router) stationTransfers(ctx context.Context, stationID string) ([]domain.Transfer, error) {
+	transfers, err := router.Store.GetStationTransfers(ctx, stationID)
+	if err != nil || len(transfers) == 0 {
+		return transfers, err
+	}
+	names, err := router.Timetable.StationNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transfers {
+		transfers[i].ToName = names[transfers[i].ToStationID]
+	}
+	return transfers, nil
+}
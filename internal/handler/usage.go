@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+const usageFlushInterval = 5 * time.Minute
+
+// usageTracker counts requests per station and line in memory and
+// periodically adds them to the store, where they rank what to warm after a
+// sync.
+type usageTracker struct {
+	store  *store.Store
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	pending map[string]map[string]int
+}
+
+func newUsageTracker(s *store.Store, l *zap.Logger) *usageTracker {
+	return &usageTracker{store: s, logger: l, pending: make(map[string]map[string]int)}
+}
+
+func (u *usageTracker) hit(kind, key string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.pending[kind] == nil {
+		u.pending[kind] = make(map[string]int)
+	}
+	u.pending[kind][key]++
+}
+
+func (u *usageTracker) flush(ctx context.Context) {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = make(map[string]map[string]int)
+	u.mu.Unlock()
+
+	// Counts that fail to save are dropped; usage only ranks cache warming.
+	for kind, counts := range pending {
+		if err := u.store.AddUsageCounts(ctx, kind, counts); err != nil {
+			u.logger.Warn("Failed to save usage counts", zap.Error(err))
+		}
+	}
+}
+
+func (u *usageTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			u.flush(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = (wsPongWait * 9) / 10
+	wsMaxMessage = 4096
+)
+
+// wsClientMessage is sent by clients to manage their subscriptions, e.g.
+// {"action": "subscribe", "topics": ["station:BOO", "sync"]}.
+type wsClientMessage struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// wsServerMessage acknowledges subscription changes or reports errors.
+type wsServerMessage struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// HandleWebSocket serves /api/v1/ws. Clients subscribe to topics such as
+// "station:BOO", "train:1234" or "sync", either with the ?topics= query
+// parameter (comma separated) or by sending subscribe/unsubscribe messages,
+// and receive every matching event as a JSON message.
+func (router *Router) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !router.featureEnabled(domain.FeatureStreaming) {
+		http.NotFound(w, r)
+		return
+	}
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		// Browsers don't apply CORS to WebSockets, so enforce the same origin policy here.
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || router.Config.CORS.AllowsOrigin(origin)
+		},
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		router.log(r).Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	sub := router.Events.Subscribe(parseTopics(r.URL.Query().Get("topics"))...)
+	defer sub.Close()
+
+	// Outgoing messages from the reader goroutine go through the writer loop
+	// so that only one goroutine writes to the connection.
+	replies := make(chan wsServerMessage, 8)
+	done := make(chan struct{})
+	go router.readWebSocket(conn, sub, replies, done)
+
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		var msg interface{}
+		select {
+		case <-done:
+			return
+		case reply := <-replies:
+			msg = reply
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			msg = e
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+func (router *Router) readWebSocket(conn *websocket.Conn, sub *events.Subscription, replies chan<- wsServerMessage, done chan<- struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(wsMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	reply := func(m wsServerMessage) {
+		// Never block: the writer may already have exited.
+		select {
+		case replies <- m:
+		default:
+		}
+	}
+
+	for {
+		var msg wsClientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				reply(wsServerMessage{Type: "error", Error: "invalid message"})
+				continue
+			}
+			return
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			sub.Add(msg.Topics...)
+			reply(wsServerMessage{Type: "subscribed", Topics: sub.Topics()})
+		case "unsubscribe":
+			sub.Remove(msg.Topics...)
+			reply(wsServerMessage{Type: "unsubscribed", Topics: sub.Topics()})
+		default:
+			reply(wsServerMessage{Type: "error", Error: "unknown action: " + msg.Action})
+		}
+	}
+}
+
+func parseTopics(raw string) []string {
+	var topics []string
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// locales holds the messages of each language by key, in a JSON file named
+// after the language, such as id.json. Keys double as the stable error codes
+// in API responses, so they must not be renamed. A file's "_fallback" key
+// names the language its missing messages are taken from before Default.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// fallbackKey is the locale file key naming a language's fallback.
+const fallbackKey = "_fallback"
+
+// catalog maps each language to its messages by key.
+var catalog = loadCatalog()
+
+// loadCatalog reads the locale files. They are built into the binary, so a
+// malformed one is a programming error.
+func loadCatalog() map[Lang]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read locales: %v", err))
+	}
+	catalog := make(map[Lang]map[string]string, len(files))
+	for _, f := range files {
+		data, err := locales.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read %s: %v", f.Name(), err))
+		}
+		var msgs map[string]string
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", f.Name(), err))
+		}
+		catalog[Lang(strings.TrimSuffix(f.Name(), ".json"))] = msgs
+	}
+	if _, ok := catalog[Default]; !ok {
+		panic("i18n: no locale file for the default language")
+	}
+	return catalog
+}
+
+// chain returns the languages lang's messages are looked up in, in order:
+// lang, the fallbacks its locale files name, then Default.
+func chain(lang Lang) []Lang {
+	var langs []Lang
+	for l := lang; l != ""; l = Lang(catalog[l][fallbackKey]) {
+		if _, ok := catalog[l]; !ok || contains(langs, l) {
+			break
+		}
+		langs = append(langs, l)
+	}
+	if !contains(langs, Default) {
+		langs = append(langs, Default)
+	}
+	return langs
+}
+
+func contains(langs []Lang, lang Lang) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the message key in lang, following its fallback chain.
+func lookup(lang Lang, key string) (string, bool) {
+	for _, l := range chain(lang) {
+		if msg, ok := catalog[l][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
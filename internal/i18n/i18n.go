@@ -0,0 +1,107 @@
+// Package i18n holds the catalog of user-facing API messages, embedded as a
+// locale file per language, and picks the language to answer in from
+// Accept-Language.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	English    Lang = "en"
+	Indonesian Lang = "id"
+
+	// Default is used when the client accepts none of the catalog languages.
+	Default = English
+)
+
+// Negotiate returns the catalog language the client prefers most, honouring
+// q-values. Region subtags are ignored, and the legacy "in" tag is treated as
+// Indonesian. Any language with a locale file can be negotiated.
+func Negotiate(acceptLanguage string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			// q-values are 0 to 1; anything else, including NaN, is
+			// malformed and the tag is skipped.
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || !(parsed >= 0 && parsed <= 1) {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if primary == "in" {
+			primary = string(Indonesian)
+		}
+		if Supported(primary) {
+			candidates = append(candidates, candidate{Lang(primary), q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) == 0 || candidates[0].q <= 0 {
+		return Default
+	}
+	return candidates[0].lang
+}
+
+// Supported reports whether lang has a locale file.
+func Supported(lang string) bool {
+	_, ok := catalog[Lang(lang)]
+	return ok
+}
+
+// T formats the message key in lang, falling back along lang's chain to the
+// default language and then to the key itself.
+func T(lang Lang, key string, args ...any) string {
+	msg, ok := lookup(lang, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// StatusTitle is the localized title for an HTTP status code.
+func StatusTitle(lang Lang, status int) string {
+	return T(lang, "status."+strconv.Itoa(status))
+}
+
+// LineName is the name of line in lang, or line itself when the catalog has
+// none. Lines are looked up by their upstream name, as "line.<NAME>".
+func LineName(lang Lang, line string) string {
+	if msg, ok := lookup(lang, "line."+strings.ToUpper(line)); ok {
+		return msg
+	}
+	return line
+}
+
+// Pick returns the text in lang from texts, by language, following the
+// fallbacks lang's locale files name. Default is not tried unless asked for:
+// texts are translations of an original the caller falls back to, and it
+// reports false when none of the chain has one.
+func Pick(lang Lang, texts map[string]string) (string, bool) {
+	for _, l := range chain(lang) {
+		if l == Default && l != lang {
+			break
+		}
+		if text, ok := texts[string(l)]; ok {
+			return text, true
+		}
+	}
+	return "", false
+}
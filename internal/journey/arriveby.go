@@ -0,0 +1,132 @@
+package journey
+
+import (
+	"sort"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// PlanArriveBy returns the itinerary from station from to station to that
+// suits opts best among those arriving no later than arriveBy, on the
+// trains and buses opts allows, within arriveBy's service day.
+func (p *Planner) PlanArriveBy(from, to string, arriveBy time.Time, opts Options) (domain.Itinerary, bool) {
+	return opts.Best(p.ArriveByAlternatives(from, to, arriveBy, opts))
+}
+
+// ArriveByAlternatives is Alternatives for the journeys that leave latest
+// while arriving no later than arriveBy.
+//
+// The latest departure is found by scanning connections backwards from
+// arriveBy, and the itineraries are then planned forwards from it, so they
+// are made up like any other. That scan does not count rides, so when opts
+// limits them, earlier departures from from are tried in turn until one
+// arrives in time.
+func (p *Planner) ArriveByAlternatives(from, to string, arriveBy time.Time, opts Options) []domain.Itinerary {
+	if from == to {
+		return nil
+	}
+
+	src, ok := p.index[from]
+	dst, ok2 := p.index[to]
+	if !ok || !ok2 {
+		return nil
+	}
+
+	arriveBy = arriveBy.In(p.loc)
+	day := time.Date(arriveBy.Year(), arriveBy.Month(), arriveBy.Day(), 0, 0, 0, 0, p.loc)
+	q := p.query(opts)
+
+	leave, ok := p.latestDeparture(src, dst, secondsOfDay(arriveBy), q)
+	for ok {
+		var alts []domain.Itinerary
+		for _, it := range p.Alternatives(from, to, day.Add(time.Duration(leave)*time.Second), opts) {
+			if !it.ArrivesAt.After(arriveBy) {
+				alts = append(alts, it)
+			}
+		}
+		if len(alts) > 0 {
+			return label(alts)
+		}
+		if opts.MaxRides == 0 {
+			break
+		}
+		leave, ok = p.departureBefore(src, leave, q)
+	}
+	return nil
+}
+
+// departureBefore returns the latest time before t that a train or bus q
+// boards leaves station from.
+func (p *Planner) departureBefore(from, t int, q query) (int, bool) {
+	i := sort.Search(len(p.connections), func(i int) bool { return p.connections[i].dep >= t })
+	for i--; i >= 0; i-- {
+		if c := p.connections[i]; c.from == from && q.boards[c.trip] {
+			return c.dep, true
+		}
+	}
+	return 0, false
+}
+
+// latestDeparture returns the latest time, as seconds since midnight, to
+// leave from and reach to by deadline.
+//
+// need holds, for each station, the latest time to be there and still make
+// it, or -1; onFoot marks the stations where that is a walk away rather than
+// a departure, which needs no time to change. onTrip marks the trips that
+// reach to in time once aboard.
+func (p *Planner) latestDeparture(from, to, deadline int, q query) (int, bool) {
+	need := make([]int, len(p.stations))
+	for i := range need {
+		need[i] = -1
+	}
+	onFoot := make([]bool, len(p.stations))
+	onTrip := make([]bool, len(p.trips))
+	need[to], onFoot[to] = deadline, true
+
+	// walk lets the stations with a transfer to at, needed by t, be left
+	// on foot.
+	walk := func(at, t int) {
+		for _, fp := range p.footpaths[at] {
+			if !q.walks(fp) || fp.to != from && q.avoid[fp.to] {
+				continue
+			}
+			if need[fp.to] < t-fp.secs {
+				need[fp.to] = t - fp.secs
+				onFoot[fp.to] = true
+			}
+		}
+	}
+	walk(to, deadline)
+
+	for _, i := range p.byArrival {
+		c := &p.connections[i]
+		if c.arr > deadline {
+			continue
+		}
+		if need[from] >= 0 && c.arr < need[from] {
+			break
+		}
+		if !q.boards[c.trip] || c.from != from && q.avoid[c.from] {
+			continue
+		}
+
+		if !onTrip[c.trip] {
+			t := need[c.to]
+			if !onFoot[c.to] {
+				t -= int(MinTransfer / time.Second)
+			}
+			if need[c.to] < 0 || c.arr > t {
+				continue
+			}
+			onTrip[c.trip] = true
+		}
+
+		if need[c.from] < c.dep {
+			need[c.from] = c.dep
+			onFoot[c.from] = false
+			walk(c.from, c.dep)
+		}
+	}
+	return need[from], need[from] >= 0
+}
@@ -0,0 +1,172 @@
+// Package journey plans station-to-station trips over the synced timetable.
+package journey
+
+import (
+	"math"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const (
+	// MinTransfer is the time allowed to change trains at a station.
+	MinTransfer = 2 * time.Minute
+
+	// Walking estimates assume a street detour over the straight-line
+	// distance and an unhurried pace.
+	walkDetourFactor = 1.3
+	walkMetersPerMin = 80.0
+
+	unreachable = math.MaxInt32
+
+	// maxRides bounds the trains and buses ridden on a journey when its
+	// options set no bound.
+	maxRides = 6
+
+	// alternativeSlack is how much later than the earliest arrival an
+	// alternative with fewer transfers may arrive.
+	alternativeSlack = time.Hour
+)
+
+// Optimize values, what Plan picks a journey by.
+const (
+	OptimizeTime      = "time"
+	OptimizeTransfers = "transfers"
+)
+
+// Planner answers journey queries using the connection scan algorithm over
+// a compact timetable, one round per train or bus ridden. It is immutable
+// once built and safe for concurrent use.
+type Planner struct {
+	// stations are the station IDs by number, and index their numbers by
+	// ID.
+	stations []string
+	index    map[string]int
+	trips    []trip
+	// connections are ordered by departure, and byArrival indexes them by
+	// arrival, latest first.
+	connections []connection
+	byArrival   []int
+	footpaths   [][]footpath
+	loc         *time.Location
+}
+
+// Options are the ways a journey may be made.
+type Options struct {
+	// Services are the service types of the trains boarded, any when empty.
+	Services []string
+
+	// BRT also boards TransJakarta buses, walking between them and the
+	// trains at transfer points.
+	BRT bool
+
+	// Avoid are stations that are not boarded, changed or alighted at, nor
+	// walked to. Trains still pass through them.
+	Avoid []string
+
+	// MaxRides is the most trains and buses a journey rides, one more than
+	// its transfers. Zero is no limit.
+	MaxRides int
+
+	// MaxWalk is the longest walk between stations. Zero is no limit.
+	MaxWalk time.Duration
+
+	// Optimize is what Plan picks a journey by, OptimizeTime when empty.
+	Optimize string
+}
+
+// Best returns the alternative opts prefers: the one with the fewest
+// transfers when optimizing for them, or else the one arriving earliest.
+func (o Options) Best(alts []domain.Itinerary) (domain.Itinerary, bool) {
+	if len(alts) == 0 {
+		return domain.Itinerary{}, false
+	}
+	if o.Optimize == OptimizeTransfers {
+		return alts[0], true
+	}
+	return alts[len(alts)-1], true
+}
+
+// Plan returns the itinerary from station from to station to that leaving no
+// sooner than depart suits opts best: the one arriving earliest, or the one
+// with the fewest transfers when opts optimizes for them. Trips are planned
+// within depart's service day, on the trains and buses opts allows.
+func (p *Planner) Plan(from, to string, depart time.Time, opts Options) (domain.Itinerary, bool) {
+	return opts.Best(p.Alternatives(from, to, depart, opts))
+}
+
+// Alternatives returns the itineraries from station from to station to
+// leaving no sooner than depart that each arrive earlier than any with fewer
+// transfers, fewest transfers first, labeled. Those arriving over an hour
+// after the earliest are left out.
+func (p *Planner) Alternatives(from, to string, depart time.Time, opts Options) []domain.Itinerary {
+	if from == to {
+		return nil
+	}
+
+	src, ok := p.index[from]
+	dst, ok2 := p.index[to]
+	if !ok || !ok2 {
+		return nil
+	}
+
+	depart = depart.In(p.loc)
+	day := time.Date(depart.Year(), depart.Month(), depart.Day(), 0, 0, 0, 0, p.loc)
+	sr := p.search(src, dst, secondsOfDay(depart), p.query(opts))
+
+	var alts []domain.Itinerary
+	best := unreachable
+	for k, r := range sr.rounds {
+		if t := r.arrivesAt[dst]; t < best {
+			best = t
+			alts = append(alts, sr.itinerary(day, k))
+		}
+	}
+	return label(alts)
+}
+
+// label labels alts, which are ordered by transfers: the first has the
+// fewest and the last arrives earliest.
+func label(alts []domain.Itinerary) []domain.Itinerary {
+	for i := range alts {
+		switch i {
+		case len(alts) - 1:
+			alts[i].Label = domain.ItineraryFastest
+		case 0:
+			alts[i].Label = domain.ItineraryFewestTransfers
+		default:
+			alts[i].Label = domain.ItineraryBalanced
+		}
+	}
+	return alts
+}
+
+// NewItinerary summarises legs, which must be in travel order.
+func NewItinerary(legs []domain.Leg) domain.Itinerary {
+	it := domain.Itinerary{Legs: legs}
+	if len(legs) == 0 {
+		return it
+	}
+	it.DepartsAt = legs[0].DepartsAt
+	it.ArrivesAt = legs[len(legs)-1].ArrivesAt
+	it.DurationMinutes = int(it.ArrivesAt.Sub(it.DepartsAt).Minutes())
+	for _, leg := range legs {
+		if leg.Mode != domain.LegModeWalk {
+			it.Transfers++
+		}
+	}
+	if it.Transfers > 0 {
+		it.Transfers--
+	}
+	return it
+}
+
+// WalkDuration estimates the time to walk a straight-line distance in meters.
+func WalkDuration(meters float64) time.Duration {
+	minutes := math.Ceil(meters * walkDetourFactor / walkMetersPerMin)
+	return time.Duration(minutes) * time.Minute
+}
+
+func secondsOfDay(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
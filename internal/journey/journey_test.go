@@ -0,0 +1,169 @@
+package journey
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// stop returns train's schedule at station, leaving at the given time on
+// 2026-10-17 plus days.
+func stop(train, station string, days, hour, minute int) domain.Schedule {
+	return domain.Schedule{
+		TrainID:   train,
+		StationID: station,
+		Line:      "COMMUTER LINE BOGOR",
+		DepartsAt: time.Date(2026, 10, 17+days, hour, minute, 0, 0, calendar.Jakarta),
+	}
+}
+
+func TestPlanPastMidnight(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		arrival domain.Schedule
+	}{
+		{"before midnight", stop("T1", "C", 0, 23, 59)},
+		{"after midnight", stop("T1", "C", 1, 0, 15)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPlanner([]domain.Schedule{
+				stop("T1", "A", 0, 23, 55),
+				stop("T1", "B", 0, 23, 57),
+				tc.arrival,
+			}, domain.BRTNetwork{}, nil)
+
+			it, ok := p.Plan("A", "C", time.Date(2026, 10, 17, 23, 50, 0, 0, calendar.Jakarta), Options{})
+			if !ok {
+				t.Fatal("no journey found")
+			}
+			if !it.ArrivesAt.Equal(tc.arrival.DepartsAt) {
+				t.Errorf("arrives at %v, want %v", it.ArrivesAt, tc.arrival.DepartsAt)
+			}
+		})
+	}
+}
+
+// benchmarkNetwork returns a timetable the size of the network: eight rail
+// lines of 25 stations, meeting at a hub halfway along and end to end, each
+// with a train every six minutes both ways from 05:00, and twenty two-way BRT
+// corridors of 20 stops every five minutes, each with two transfers to the
+// rail lines.
+func benchmarkNetwork() ([]domain.Schedule, domain.BRTNetwork) {
+	const lines, stations = 8, 25
+	station := func(line, i int) string {
+		switch {
+		case i == stations/2:
+			return "HUB"
+		case i == stations-1:
+			return fmt.Sprintf("L%dS0", (line+1)%lines)
+		}
+		return fmt.Sprintf("L%dS%d", line, i)
+	}
+
+	var schedules []domain.Schedule
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, calendar.Jakarta)
+	for l := 0; l < lines; l++ {
+		for dir := 0; dir < 2; dir++ {
+			for start := 5 * 60; start < 24*60; start += 6 {
+				train := fmt.Sprintf("%d-%d-%d", l, dir, start)
+				for i := 0; i < stations; i++ {
+					at := i
+					if dir == 1 {
+						at = stations - 1 - i
+					}
+					schedules = append(schedules, domain.Schedule{
+						TrainID:   train,
+						StationID: station(l, at),
+						Line:      fmt.Sprintf("LINE %d", l),
+						DepartsAt: day.Add(time.Duration(start+2*i) * time.Minute),
+					})
+				}
+			}
+		}
+	}
+
+	var brt domain.BRTNetwork
+	for c := 0; c < 20; c++ {
+		for dir := 0; dir < 2; dir++ {
+			corridor := domain.BRTCorridor{
+				ID:             fmt.Sprint(c),
+				Direction:      dir,
+				Name:           fmt.Sprintf("Corridor %d", c),
+				HeadwayMinutes: 5,
+				FirstDeparture: "05:00",
+				LastDeparture:  "22:00",
+			}
+			for j := 0; j < 20; j++ {
+				at := j
+				if dir == 1 {
+					at = 19 - j
+				}
+				corridor.Stops = append(corridor.Stops, domain.BRTStop{StationID: fmt.Sprintf("B%dS%d", c, at), Minutes: 3 * j})
+			}
+			brt.Corridors = append(brt.Corridors, corridor)
+		}
+		brt.Transfers = append(brt.Transfers,
+			domain.Transfer{StationID: fmt.Sprintf("B%dS0", c), ToStationID: station(c%lines, c%(stations-1)), WalkMinutes: 5},
+			domain.Transfer{StationID: fmt.Sprintf("B%dS10", c), ToStationID: station((c+3)%lines, (c*7)%(stations-1)), WalkMinutes: 4},
+		)
+	}
+	return schedules, brt
+}
+
+var benchmarkPlanner = sync.OnceValue(func() *Planner {
+	schedules, brt := benchmarkNetwork()
+	return NewPlanner(schedules, brt, nil)
+})
+
+func benchmarkJourney(b *testing.B, plan func(p *Planner) []domain.Itinerary) {
+	p := benchmarkPlanner()
+	if len(plan(p)) == 0 {
+		b.Fatal("no journey found")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plan(p)
+	}
+	_, _, connections := p.Size()
+	b.ReportMetric(float64(connections), "connections")
+}
+
+func BenchmarkNewPlanner(b *testing.B) {
+	schedules, brt := benchmarkNetwork()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewPlanner(schedules, brt, nil)
+	}
+}
+
+func BenchmarkAlternatives(b *testing.B) {
+	depart := time.Date(2026, 10, 17, 8, 0, 0, 0, calendar.Jakarta)
+	benchmarkJourney(b, func(p *Planner) []domain.Itinerary {
+		return p.Alternatives("L0S3", "L5S20", depart, Options{})
+	})
+}
+
+func BenchmarkAlternativesBRT(b *testing.B) {
+	depart := time.Date(2026, 10, 17, 8, 0, 0, 0, calendar.Jakarta)
+	benchmarkJourney(b, func(p *Planner) []domain.Itinerary {
+		return p.Alternatives("B0S15", "B7S5", depart, Options{BRT: true})
+	})
+}
+
+func BenchmarkArriveByAlternatives(b *testing.B) {
+	arriveBy := time.Date(2026, 10, 17, 18, 0, 0, 0, calendar.Jakarta)
+	benchmarkJourney(b, func(p *Planner) []domain.Itinerary {
+		return p.ArriveByAlternatives("L0S3", "L5S20", arriveBy, Options{})
+	})
+}
+
+func BenchmarkArriveByAlternativesMaxRides(b *testing.B) {
+	arriveBy := time.Date(2026, 10, 17, 18, 0, 0, 0, calendar.Jakarta)
+	benchmarkJourney(b, func(p *Planner) []domain.Itinerary {
+		return p.ArriveByAlternatives("L0S3", "L5S20", arriveBy, Options{MaxRides: 2})
+	})
+}
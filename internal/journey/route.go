@@ -0,0 +1,82 @@
+package journey
+
+import (
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// StopDwell is the time a train is assumed to stand at an intermediate stop.
+// Timetables only publish departures, so arrivals are estimated from it.
+const StopDwell = time.Minute
+
+// NewRoute assembles a train's trip from its schedules, which must be ordered
+// by departure, with station names looked up in names.
+//
+// The first stop has no arrival. The terminus arrives at the train's
+// published arrival time; every other stop is estimated to be reached
+// StopDwell before it departs, but never before the previous stop departs.
+func NewRoute(trainID string, schedules []domain.Schedule, names map[string]string) domain.RouteData {
+	stops := make([]domain.RouteStop, 0, len(schedules))
+	for i, sch := range schedules {
+		stop := domain.RouteStop{
+			ID:          sch.ID,
+			StationID:   sch.StationID,
+			StationName: names[sch.StationID],
+			DepartsAt:   sch.DepartsAt,
+			Platform:    sch.Metadata.Platform,
+			CreatedAt:   sch.UpdatedAt, // Use UpdatedAt as proxy
+			UpdatedAt:   sch.UpdatedAt,
+		}
+		if i > 0 {
+			stop.ArrivesAt, stop.ArrivalEstimated = stopArrival(schedules[i-1], sch)
+		}
+		stops = append(stops, stop)
+	}
+
+	first := schedules[0]
+	last := schedules[len(schedules)-1]
+	return domain.RouteData{
+		Routes: stops,
+		Details: domain.RouteDetail{
+			TrainID:                trainID,
+			Line:                   first.Line,
+			Route:                  first.Route,
+			ServiceType:            first.ServiceType,
+			ServiceDay:             first.ServiceDay,
+			StationOriginID:        first.StationOriginID,
+			StationOriginName:      names[first.StationOriginID],
+			StationDestinationID:   first.StationDestinationID,
+			StationDestinationName: names[first.StationDestinationID],
+			ArrivesAt:              last.ArrivesAt,
+		},
+	}
+}
+
+// Arrival returns when a train that leaves from reaches the stop at, both
+// schedules of the same train, and whether that time is an estimate. It is
+// nil when at is not further along the route than from.
+func Arrival(from, at domain.Schedule) (*time.Time, bool) {
+	if !at.DepartsAt.After(from.DepartsAt) {
+		return nil, false
+	}
+	return stopArrival(from, at)
+}
+
+// stopArrival returns when the train reaches sch after leaving prev, and
+// whether that time is an estimate.
+func stopArrival(prev, sch domain.Schedule) (*time.Time, bool) {
+	if sch.StationID == sch.StationDestinationID && !sch.ArrivesAt.IsZero() {
+		t := sch.ArrivesAt
+		return &t, false
+	}
+	if sch.DepartsAt.IsZero() {
+		return nil, false
+	}
+
+	t := sch.DepartsAt.Add(-StopDwell)
+	if !t.After(prev.DepartsAt) {
+		t = sch.DepartsAt
+	}
+	return &t, true
+}
@@ -0,0 +1,208 @@
+package journey
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// query is a journey's options resolved against a planner's numbering:
+// boards marks the trips that may be boarded and avoid the stations avoided.
+type query struct {
+	opts    Options
+	boards  []bool
+	avoid   []bool
+	maxWalk int
+}
+
+// query resolves opts.
+func (p *Planner) query(opts Options) query {
+	q := query{opts: opts, boards: make([]bool, len(p.trips)), avoid: make([]bool, len(p.stations))}
+	for i, t := range p.trips {
+		if t.mode == domain.LegModeBRT {
+			q.boards[i] = opts.BRT
+		} else {
+			q.boards[i] = len(opts.Services) == 0 || slices.Contains(opts.Services, t.service)
+		}
+	}
+	for _, id := range opts.Avoid {
+		if n, ok := p.index[id]; ok {
+			q.avoid[n] = true
+		}
+	}
+	q.maxWalk = unreachable
+	if opts.MaxWalk > 0 {
+		q.maxWalk = int(opts.MaxWalk / time.Second)
+	}
+	return q
+}
+
+// walks reports whether fp may be walked.
+func (q query) walks(fp footpath) bool {
+	return (!fp.brt || q.opts.BRT) && fp.secs <= q.maxWalk
+}
+
+// round holds a search's progress on a number of rides: when each station is
+// reached earliest, and from when it can be left on a new train or bus.
+// arrival holds the connection range (boarded, alighted) that reaches a
+// station, and walked the station walked from when it is reached on foot,
+// or -1. boarded holds the connection each trip is boarded at, or -1.
+type round struct {
+	arrivesAt []int
+	ready     []int
+	arrival   [][2]int
+	walked    []int
+	boarded   []int
+}
+
+// search is a run of the planner from station from, leaving at start. Its
+// rounds are indexed by the trains and buses ridden, so each arrival in
+// round k is the earliest made on at most k of them.
+type search struct {
+	p        *Planner
+	from, to int
+	rounds   []round
+}
+
+// search scans the connections from start, on at most opts' rides.
+func (p *Planner) search(from, to, start int, q query) *search {
+	rides := maxRides
+	if q.opts.MaxRides > 0 {
+		rides = q.opts.MaxRides
+	}
+	sr := &search{p: p, from: from, to: to, rounds: make([]round, rides+1)}
+	for k := range sr.rounds {
+		r := round{
+			arrivesAt: make([]int, len(p.stations)),
+			ready:     make([]int, len(p.stations)),
+			arrival:   make([][2]int, len(p.stations)),
+			walked:    make([]int, len(p.stations)),
+			boarded:   make([]int, len(p.trips)),
+		}
+		for i := range r.arrivesAt {
+			r.arrivesAt[i], r.ready[i], r.walked[i] = unreachable, unreachable, -1
+		}
+		for i := range r.boarded {
+			r.boarded[i] = -1
+		}
+		sr.rounds[k] = r
+	}
+	sr.rounds[0].arrivesAt[from] = start
+	sr.rounds[0].ready[from] = start
+
+	// stops reports whether station at may be alighted at or walked to.
+	stops := func(at int) bool {
+		return at != from && (at == to || !q.avoid[at])
+	}
+	// reached reports whether at is reached by t in round k or one before.
+	reached := func(at, k, t int) bool {
+		for j := 0; j <= k; j++ {
+			if sr.rounds[j].arrivesAt[at] <= t {
+				return true
+			}
+		}
+		return false
+	}
+	// walk reaches the stations a transfer leads to from at, reached at t
+	// in round k.
+	walk := func(k, at, t int) {
+		r := sr.rounds[k]
+		for _, fp := range p.footpaths[at] {
+			arr := t + fp.secs
+			if !q.walks(fp) || !stops(fp.to) || reached(fp.to, k, arr) {
+				continue
+			}
+			r.arrivesAt[fp.to] = arr
+			r.walked[fp.to] = at
+			r.ready[fp.to] = arr
+		}
+	}
+	walk(0, from, start)
+
+	// Once to is reached on the fewest rides it can be, nothing departing
+	// later improves on it, and alternatives arriving over slack after the
+	// earliest arrival are not looked for. top is the last round that has
+	// reached a station, the last a trip can be boarded from.
+	slack := int(alternativeSlack / time.Second)
+	earliest, top := sr.rounds[0].arrivesAt[to], 0
+	first := sort.Search(len(p.connections), func(i int) bool { return p.connections[i].dep >= start })
+	for i := first; i < len(p.connections); i++ {
+		c := &p.connections[i]
+		if c.dep >= min(sr.rounds[0].arrivesAt[to], sr.rounds[1].arrivesAt[to]) || earliest < unreachable && c.dep >= earliest+slack {
+			break
+		}
+		if !q.boards[c.trip] {
+			continue
+		}
+
+		for k := 0; k <= min(top, rides-1); k++ {
+			r, next := sr.rounds[k], sr.rounds[k+1]
+			enter := r.boarded[c.trip]
+			if enter < 0 {
+				if r.ready[c.from] > c.dep {
+					continue
+				}
+				enter = i
+				r.boarded[c.trip] = i
+			}
+
+			if !stops(c.to) || reached(c.to, k+1, c.arr) {
+				continue
+			}
+			next.arrivesAt[c.to] = c.arr
+			next.arrival[c.to] = [2]int{enter, i}
+			next.walked[c.to] = -1
+			next.ready[c.to] = c.arr + int(MinTransfer/time.Second)
+			walk(k+1, c.to, c.arr)
+			top = max(top, k+1)
+			earliest = min(earliest, next.arrivesAt[to])
+		}
+	}
+	return sr
+}
+
+// itinerary returns the journey reaching the search's destination in round
+// k, on day.
+func (sr *search) itinerary(day time.Time, k int) domain.Itinerary {
+	p := sr.p
+	at := func(t int) time.Time { return day.Add(time.Duration(t) * time.Second) }
+
+	var legs []domain.Leg
+	for st := sr.to; st != sr.from; {
+		r := sr.rounds[k]
+		if prev := r.walked[st]; prev >= 0 {
+			legs = append(legs, domain.Leg{
+				Mode:            domain.LegModeWalk,
+				From:            p.stations[prev],
+				To:              p.stations[st],
+				DepartsAt:       at(r.arrivesAt[prev]),
+				ArrivesAt:       at(r.arrivesAt[st]),
+				DurationMinutes: (r.arrivesAt[st] - r.arrivesAt[prev]) / 60,
+			})
+			st = prev
+			continue
+		}
+		hop := r.arrival[st]
+		board, alight := p.connections[hop[0]], p.connections[hop[1]]
+		t := p.trips[board.trip]
+		legs = append(legs, domain.Leg{
+			Mode:            t.mode,
+			From:            p.stations[board.from],
+			To:              p.stations[alight.to],
+			TrainID:         t.id,
+			Line:            t.line,
+			Route:           t.route,
+			ServiceType:     t.service,
+			DepartsAt:       at(board.dep),
+			ArrivesAt:       at(alight.arr),
+			DurationMinutes: (alight.arr - board.dep) / 60,
+			Fare:            board.fares[p.stations[alight.to]],
+		})
+		st = board.from
+		k--
+	}
+	slices.Reverse(legs)
+	return NewItinerary(legs)
+}
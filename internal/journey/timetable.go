@@ -0,0 +1,177 @@
+package journey
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// The planner's timetable is compact: stations and trips are numbered, and
+// the searches index slices by those numbers rather than looking stations
+// and trains up by ID.
+
+// trip is a train or a bus run, as its legs show it. mode is the leg mode it
+// is ridden in.
+type trip struct {
+	id, line, route, service, mode string
+}
+
+// connection is one hop of a trip between consecutive stops, with times as
+// seconds since the midnight starting its service day, past 86400 after
+// midnight. fares are the fares from its from stop, by the ID of the station
+// alighted at.
+type connection struct {
+	from, to int
+	dep, arr int
+	trip     int
+	fares    map[string]int
+}
+
+// footpath is a walk to station to, taking secs. brt marks the walks to
+// and from BRT stops found by the import, only taken when riding BRT.
+type footpath struct {
+	to   int
+	secs int
+	brt  bool
+}
+
+// NewPlanner builds a planner from the schedules of every station, the BRT
+// network and the curated transfers, which replace those the BRT import
+// found between the same stations. A train's stops are ordered by departure
+// time, and its times count from the midnight before its first stop, so a
+// train running past midnight goes on past 24:00. BRT buses are laid out along each corridor at its headway, as the
+// network publishes no timetable.
+func NewPlanner(schedules []domain.Schedule, brt domain.BRTNetwork, transfers []domain.Transfer) *Planner {
+	trains := make(map[string][]domain.Schedule)
+	var order []string
+	p := &Planner{loc: time.UTC, index: make(map[string]int)}
+	for _, sch := range schedules {
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+		if _, seen := trains[sch.TrainID]; !seen {
+			order = append(order, sch.TrainID)
+		}
+		trains[sch.TrainID] = append(trains[sch.TrainID], sch)
+		p.loc = sch.DepartsAt.Location()
+	}
+
+	for _, id := range order {
+		stops := trains[id]
+		sort.Slice(stops, func(i, j int) bool { return stops[i].DepartsAt.Before(stops[j].DepartsAt) })
+		t := p.addTrip(trip{id: id, line: stops[0].Line, route: stops[0].Route, service: stops[0].ServiceType, mode: domain.LegModeTrain})
+		first := stops[0].DepartsAt.In(p.loc)
+		midnight := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, p.loc)
+		for i := 0; i+1 < len(stops); i++ {
+			p.connections = append(p.connections, connection{
+				from:  p.station(stops[i].StationID),
+				to:    p.station(stops[i+1].StationID),
+				dep:   int(stops[i].DepartsAt.Sub(midnight) / time.Second),
+				arr:   int(stops[i+1].DepartsAt.Sub(midnight) / time.Second),
+				trip:  t,
+				fares: stops[i].Metadata.Fares,
+			})
+		}
+	}
+	for _, c := range brt.Corridors {
+		p.addCorridor(c)
+	}
+
+	walks := make(map[[2]string]footpath)
+	for _, t := range brt.Transfers {
+		walks[[2]string{t.StationID, t.ToStationID}] = footpath{secs: t.WalkMinutes * 60, brt: true}
+	}
+	for _, t := range transfers {
+		delete(walks, [2]string{t.ToStationID, t.StationID})
+		walks[[2]string{t.StationID, t.ToStationID}] = footpath{secs: t.WalkMinutes * 60}
+	}
+	for pair, fp := range walks {
+		a, b := p.station(pair[0]), p.station(pair[1])
+		p.footpaths[a] = append(p.footpaths[a], footpath{to: b, secs: fp.secs, brt: fp.brt})
+		p.footpaths[b] = append(p.footpaths[b], footpath{to: a, secs: fp.secs, brt: fp.brt})
+	}
+
+	sort.SliceStable(p.connections, func(i, j int) bool { return p.connections[i].dep < p.connections[j].dep })
+	p.byArrival = make([]int, len(p.connections))
+	for i := range p.byArrival {
+		p.byArrival[i] = i
+	}
+	sort.SliceStable(p.byArrival, func(i, j int) bool {
+		return p.connections[p.byArrival[i]].arr > p.connections[p.byArrival[j]].arr
+	})
+	return p
+}
+
+// Size returns how many stations, trips and connections the planner holds.
+func (p *Planner) Size() (stations, trips, connections int) {
+	return len(p.stations), len(p.trips), len(p.connections)
+}
+
+// station returns the number of the station with the given ID, numbering it
+// when it is new.
+func (p *Planner) station(id string) int {
+	if n, ok := p.index[id]; ok {
+		return n
+	}
+	n := len(p.stations)
+	p.index[id] = n
+	p.stations = append(p.stations, id)
+	p.footpaths = append(p.footpaths, nil)
+	return n
+}
+
+// addTrip numbers t.
+func (p *Planner) addTrip(t trip) int {
+	p.trips = append(p.trips, t)
+	return len(p.trips) - 1
+}
+
+// addCorridor adds the buses of corridor c, one leaving its first stop every
+// headway from its first departure to its last. A corridor whose times
+// cannot be read runs no buses.
+func (p *Planner) addCorridor(c domain.BRTCorridor) {
+	first, err1 := clockSeconds(c.FirstDeparture)
+	last, err2 := clockSeconds(c.LastDeparture)
+	if err1 != nil || err2 != nil || c.HeadwayMinutes <= 0 || len(c.Stops) < 2 {
+		return
+	}
+	stops := make([]int, len(c.Stops))
+	for i, stop := range c.Stops {
+		stops[i] = p.station(stop.StationID)
+	}
+	for start := first; start <= last; start += c.HeadwayMinutes * 60 {
+		t := p.addTrip(trip{
+			id:    fmt.Sprintf("TJ-%s-%d-%d", c.ID, c.Direction, start/60),
+			line:  c.Name,
+			route: c.Name,
+			mode:  domain.LegModeBRT,
+		})
+		for i := 0; i+1 < len(c.Stops); i++ {
+			p.connections = append(p.connections, connection{
+				from: stops[i],
+				to:   stops[i+1],
+				dep:  start + c.Stops[i].Minutes*60,
+				arr:  start + c.Stops[i+1].Minutes*60,
+				trip: t,
+			})
+		}
+	}
+}
+
+// clockSeconds parses HH:MM, which may be past 24:00, into seconds.
+func clockSeconds(v string) (int, error) {
+	h, m, ok := strings.Cut(v, ":")
+	hours, err := strconv.Atoi(h)
+	if err != nil || !ok {
+		return 0, fmt.Errorf("invalid time %q", v)
+	}
+	minutes, err := strconv.Atoi(m)
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q", v)
+	}
+	return hours*3600 + minutes*60, nil
+}
@@ -1,33 +1,65 @@
 package logging
 
 import (
+	"llm-router/internal/config"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// NewLogger initializes and returns a new zap.Logger based on the provided log level.
-func NewLogger(level string) (*zap.Logger, error) {
-	var zapConfig zap.Config
+// NewLogger initializes and returns a new zap.Logger with the given level and
+// format ("json" or "console"; empty picks console for debug and JSON
+// otherwise), along with the level handle used to change it at runtime. When
+// file has a path, the log is also written there and rotated by size.
+func NewLogger(level, format string, file config.LogFileConfig) (*zap.Logger, zap.AtomicLevel, error) {
+	if format == "" {
+		format = "json"
+		if level == "debug" {
+			format = "console"
+		}
+	}
 
-	// Set up production or development config based on your needs
-	if level == "debug" {
+	var zapConfig zap.Config
+	if format == "console" {
 		zapConfig = zap.NewDevelopmentConfig()
 	} else {
 		zapConfig = zap.NewProductionConfig()
 	}
 
 	// Adjust log level based on input
-	var logLevel zap.AtomicLevel
-	err := logLevel.UnmarshalText([]byte(level))
-	if err != nil {
-		return nil, err
+	logLevel := zap.NewAtomicLevel()
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, logLevel, err
 	}
 	zapConfig.Level = logLevel
 
+	var opts []zap.Option
+	if file.Path != "" {
+		// The file gets the same encoding as the console, and rotated files
+		// are pruned by count and age.
+		writer := &lumberjack.Logger{
+			Filename:   file.Path,
+			MaxSize:    file.MaxSizeMB,
+			MaxBackups: file.MaxBackups,
+			MaxAge:     file.MaxAgeDays,
+			Compress:   file.Compress,
+		}
+		encoder := zapcore.NewJSONEncoder(zapConfig.EncoderConfig)
+		if format == "console" {
+			encoder = zapcore.NewConsoleEncoder(zapConfig.EncoderConfig)
+		}
+		fileCore := zapcore.NewCore(encoder, zapcore.AddSync(writer), logLevel)
+		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, fileCore)
+		}))
+	}
+
 	// Build and return the configured logger
-	logger, err := zapConfig.Build()
+	logger, err := zapConfig.Build(opts...)
 	if err != nil {
-		return nil, err
+		return nil, logLevel, err
 	}
 
-	return logger, nil
+	return logger, logLevel, nil
 }
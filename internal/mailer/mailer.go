@@ -0,0 +1,86 @@
+// Package mailer sends the emails of user accounts, such as sign-in links.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/config"
+
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns a mailer sending through cfg's SMTP server or, when no host is
+// configured, one that logs messages instead.
+func New(cfg config.SMTPConfig, logger *zap.Logger) Mailer {
+	if cfg.Host == "" {
+		return logMailer{logger: logger}
+	}
+	return smtpMailer{cfg: cfg}
+}
+
+// logMailer logs messages for development instances without a mail server.
+type logMailer struct {
+	logger *zap.Logger
+}
+
+func (m logMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Info("Email not sent, no SMTP host configured",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
+
+type smtpMailer struct {
+	cfg config.SMTPConfig
+}
+
+func (m smtpMailer) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	// net/smtp takes no context, so the send is abandoned, not aborted,
+	// when ctx ends first.
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, []byte(b.String()))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("send mail: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
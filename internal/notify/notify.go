@@ -0,0 +1,287 @@
+// Package notify posts sync results and data anomalies to webhooks, such as
+// Slack or Discord channels or a generic JSON endpoint.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// firstBackoff is the wait before the first retry; it doubles for each
+	// further one.
+	firstBackoff = time.Second
+
+	// maxListed is how many stations a message names before summarizing.
+	maxListed = 20
+
+	// discordLimit is the longest message content Discord accepts.
+	discordLimit = 2000
+)
+
+// Message is a notification. It is the body posted to JSON webhooks.
+type Message struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	Text  string    `json:"text"`
+	Data  any       `json:"data,omitempty"`
+}
+
+// Notifier turns sync events into messages and delivers them to the webhooks
+// that want them.
+type Notifier struct {
+	webhooks []config.WebhookConfig
+	retries  int
+	client   *http.Client
+	hub      *events.Hub
+	logger   *zap.Logger
+
+	// cancel abandons deliveries still retrying when Stop gives up.
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a notifier for cfg, which must be valid.
+func New(cfg config.NotifyConfig, hub *events.Hub, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		webhooks: cfg.Webhooks,
+		retries:  cfg.Retries,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		hub:      hub,
+		logger:   logger,
+	}
+}
+
+// Start sends notifications for the events published until ctx is done.
+func (n *Notifier) Start(ctx context.Context) error {
+	// Deliveries outlive ctx, so the messages of a sync finishing during
+	// shutdown still go out.
+	deliverCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	n.cancel = cancel
+
+	sub := n.hub.Subscribe(events.TopicSync, events.TopicAnomaly)
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		defer sub.Close()
+		for {
+			select {
+			case e := <-sub.C:
+				if msg, ok := message(e); ok {
+					n.Send(deliverCtx, msg)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop waits for deliveries in progress, or until ctx expires and abandons
+// them.
+func (n *Notifier) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		n.cancel()
+		return nil
+	case <-ctx.Done():
+		n.cancel()
+		return ctx.Err()
+	}
+}
+
+// Send delivers msg in the background to every webhook that wants it.
+func (n *Notifier) Send(ctx context.Context, msg Message) {
+	for _, w := range n.webhooks {
+		if !w.Wants(msg.Event) {
+			continue
+		}
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			if err := n.deliver(ctx, w, msg); err != nil {
+				n.logger.Error("Failed to deliver webhook",
+					zap.String("event", msg.Event),
+					zap.String("format", w.Format),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+}
+
+// deliver posts msg to w, retrying with exponential backoff after network
+// errors, 429 and 5xx responses.
+func (n *Notifier) deliver(ctx context.Context, w config.WebhookConfig, msg Message) error {
+	body, err := encode(w.Format, msg)
+	if err != nil {
+		return err
+	}
+
+	backoff := firstBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, w, msg.Event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == n.retries {
+			return err
+		}
+		n.logger.Warn("Webhook delivery failed, retrying",
+			zap.String("event", msg.Event),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (n *Notifier) post(ctx context.Context, w config.WebhookConfig, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Format == config.WebhookJSON {
+		req.Header.Set("X-Commuter-Event", event)
+		if w.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			req.Header.Set("X-Commuter-Timestamp", timestamp)
+			req.Header.Set("X-Commuter-Signature", Sign(w.Secret, timestamp, body))
+		}
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// Sign returns the X-Commuter-Signature of a JSON delivery: the hex
+// HMAC-SHA256, keyed with secret, of its X-Commuter-Timestamp, a dot and the
+// body, prefixed with "sha256=". Receivers recompute it to authenticate the
+// delivery and reject old timestamps to stop replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// encode renders msg as the body a webhook of format expects.
+func encode(format string, msg Message) ([]byte, error) {
+	switch format {
+	case config.WebhookSlack:
+		return json.Marshal(map[string]string{"text": msg.Text})
+	case config.WebhookDiscord:
+		text := msg.Text
+		if len(text) > discordLimit {
+			text = text[:discordLimit-3] + "..."
+		}
+		return json.Marshal(map[string]string{"content": text})
+	}
+	return json.Marshal(msg)
+}
+
+// message converts a hub event into a notification, if it is one.
+func message(e events.Event) (Message, bool) {
+	switch e.Type {
+	case events.TypeSyncCompleted:
+		// Only full syncs carry their run; snapshot imports do not notify.
+		run, ok := e.Data.(domain.SyncRun)
+		if !ok {
+			return Message{}, false
+		}
+		return syncMessage(e.Time, run), true
+	case events.TypeScheduleAnomalies:
+		anomalies, ok := e.Data.([]domain.ScheduleAnomaly)
+		if !ok || len(anomalies) == 0 {
+			return Message{}, false
+		}
+		return anomalyMessage(e.Time, anomalies), true
+	}
+	return Message{}, false
+}
+
+func syncMessage(t time.Time, run domain.SyncRun) Message {
+	var took time.Duration
+	if run.FinishedAt != nil {
+		took = run.FinishedAt.Sub(run.StartedAt).Round(time.Second)
+	}
+
+	if run.Status == domain.SyncRunFailed {
+		text := fmt.Sprintf("Sync #%d failed after %s: %s", run.ID, took, run.Error)
+		if run.StationsFailed > 0 {
+			text += fmt.Sprintf("\n%d stations failed: %s", run.StationsFailed, list(run.FailedStations))
+		}
+		return Message{Event: domain.NotifySyncFailed, Time: t, Text: text, Data: run}
+	}
+
+	text := fmt.Sprintf("Sync #%d completed in %s: %d stations synced, %d schedules written.",
+		run.ID, took, run.StationsSucceeded, run.RowsWritten)
+	if run.StationsFailed > 0 {
+		text += fmt.Sprintf("\n%d stations failed: %s", run.StationsFailed, list(run.FailedStations))
+	}
+	return Message{Event: domain.NotifySyncCompleted, Time: t, Text: text, Data: run}
+}
+
+func anomalyMessage(t time.Time, anomalies []domain.ScheduleAnomaly) Message {
+	stations := make([]string, 0, len(anomalies))
+	for _, a := range anomalies {
+		stations = append(stations, fmt.Sprintf("%s (%d before)", a.StationID, a.Before))
+	}
+	text := "These stations have no schedules left after the last sync: " + list(stations)
+	return Message{Event: domain.NotifyAnomaly, Time: t, Text: text, Data: anomalies}
+}
+
+// list joins items, naming at most maxListed of them.
+func list(items []string) string {
+	if len(items) <= maxListed {
+		return strings.Join(items, ", ")
+	}
+	return strings.Join(items[:maxListed], ", ") + fmt.Sprintf(" and %d more", len(items)-maxListed)
+}
@@ -0,0 +1,197 @@
+// Package protoconv converts the domain types to their commuter.v1 protobuf
+// messages, for the gRPC API and the protobuf encoding of the HTTP API.
+package protoconv
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"llm-router/internal/domain"
+	commuterv1 "llm-router/proto/commuter/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrUnsupported is returned by Response for data that has no message.
+var ErrUnsupported = errors.New("no protobuf message for response data")
+
+// Timestamp converts t, leaving zero times unset.
+func Timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// TimestampPtr converts t, leaving nil and zero times unset.
+func TimestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return Timestamp(*t)
+}
+
+func Station(st domain.Station) *commuterv1.Station {
+	resp := &commuterv1.Station{
+		Id:          st.ID,
+		Uid:         st.UID,
+		Name:        st.Name,
+		Type:        string(st.Type),
+		Active:      st.Metadata.Active,
+		DisplayName: st.DisplayName,
+	}
+	for _, a := range st.Aliases {
+		resp.Aliases = append(resp.Aliases, &commuterv1.StationAlias{Name: a.Name, Lang: a.Lang, Display: a.Display})
+	}
+	return resp
+}
+
+func Schedule(sch domain.Schedule) *commuterv1.Schedule {
+	resp := &commuterv1.Schedule{
+		Id:                   sch.ID,
+		StationId:            sch.StationID,
+		StationOriginId:      sch.StationOriginID,
+		StationDestinationId: sch.StationDestinationID,
+		TrainId:              sch.TrainID,
+		Line:                 sch.Line,
+		Route:                sch.Route,
+		DepartsAt:            Timestamp(sch.DepartsAt),
+		ArrivesAt:            Timestamp(sch.ArrivesAt),
+		Color:                sch.Metadata.Origin.Color,
+		ServiceType:          sch.ServiceType,
+		ServiceDay:           sch.ServiceDay,
+		RunId:                sch.RunID,
+		Platform:             sch.Metadata.Platform,
+	}
+	if r := sch.Reliability; r != nil {
+		resp.Reliability = &commuterv1.Reliability{
+			Score:           int32(r.Score),
+			OnTimeRate:      r.OnTimeRate,
+			AvgDelayMinutes: r.AvgDelayMinutes,
+			Observations:    int32(r.Observations),
+			Reports:         int32(r.Reports),
+		}
+	}
+	if a := sch.To; a != nil {
+		resp.To = &commuterv1.Arrival{
+			StationId:       a.StationID,
+			ArrivesAt:       Timestamp(a.ArrivesAt),
+			Estimated:       a.Estimated,
+			DurationMinutes: int32(a.DurationMinutes),
+		}
+	}
+	return resp
+}
+
+func Route(data domain.RouteData) *commuterv1.Route {
+	d := data.Details
+	route := &commuterv1.Route{
+		TrainId:                d.TrainID,
+		Line:                   d.Line,
+		Route:                  d.Route,
+		ServiceType:            d.ServiceType,
+		ServiceDay:             d.ServiceDay,
+		StationOriginId:        d.StationOriginID,
+		StationOriginName:      d.StationOriginName,
+		StationDestinationId:   d.StationDestinationID,
+		StationDestinationName: d.StationDestinationName,
+		ArrivesAt:              Timestamp(d.ArrivesAt),
+		Stops:                  make([]*commuterv1.RouteStop, 0, len(data.Routes)),
+	}
+	for _, stop := range data.Routes {
+		route.Stops = append(route.Stops, &commuterv1.RouteStop{
+			ScheduleId:       stop.ID,
+			StationId:        stop.StationID,
+			StationName:      stop.StationName,
+			DepartsAt:        Timestamp(stop.DepartsAt),
+			ArrivesAt:        TimestampPtr(stop.ArrivesAt),
+			ArrivalEstimated: stop.ArrivalEstimated,
+			Platform:         stop.Platform,
+			Boarding:         stop.Boarding,
+		})
+	}
+	return route
+}
+
+func Line(l domain.Line) *commuterv1.Line {
+	return &commuterv1.Line{
+		Name:         l.Name,
+		Color:        l.Color,
+		StationCount: int32(l.StationCount),
+		Stations:     lineStations(l.Stations),
+		UpdatedAt:    Timestamp(l.UpdatedAt),
+	}
+}
+
+func lineStations(stations []domain.LineStation) []*commuterv1.LineStation {
+	resp := make([]*commuterv1.LineStation, 0, len(stations))
+	for _, st := range stations {
+		resp = append(resp, &commuterv1.LineStation{Position: int32(st.Position), Id: st.ID, Name: st.Name})
+	}
+	return resp
+}
+
+func schedules(list []domain.Schedule) []*commuterv1.Schedule {
+	resp := make([]*commuterv1.Schedule, 0, len(list))
+	for _, sch := range list {
+		resp = append(resp, Schedule(sch))
+	}
+	return resp
+}
+
+// Response converts an HTTP response envelope, returning ErrUnsupported when
+// data is not one of the types the protobuf encoding covers.
+func Response(metadata map[string]interface{}, data interface{}) (*commuterv1.Response, error) {
+	resp := &commuterv1.Response{}
+	switch data := data.(type) {
+	case []domain.Station:
+		list := &commuterv1.StationList{Stations: make([]*commuterv1.Station, 0, len(data))}
+		for _, st := range data {
+			list.Stations = append(list.Stations, Station(st))
+		}
+		resp.Data = &commuterv1.Response_Stations{Stations: list}
+	case []domain.Schedule:
+		resp.Data = &commuterv1.Response_Schedules{Schedules: &commuterv1.ScheduleList{Schedules: schedules(data)}}
+	case domain.RouteData:
+		resp.Data = &commuterv1.Response_Route{Route: Route(data)}
+	case []domain.Line:
+		list := &commuterv1.LineList{Lines: make([]*commuterv1.Line, 0, len(data))}
+		for _, l := range data {
+			list.Lines = append(list.Lines, Line(l))
+		}
+		resp.Data = &commuterv1.Response_Lines{Lines: list}
+	case domain.Line:
+		resp.Data = &commuterv1.Response_Line{Line: Line(data)}
+	case []domain.LineStation:
+		resp.Data = &commuterv1.Response_LineStations{LineStations: &commuterv1.LineStationList{Stations: lineStations(data)}}
+	case domain.DeparturePage:
+		page := &commuterv1.DeparturePage{
+			ServiceDay: data.ServiceDay,
+			Departures: schedules(data.Departures),
+			Limit:      int32(data.Limit),
+			Offset:     int32(data.Offset),
+		}
+		if data.NextOffset != nil {
+			next := int32(*data.NextOffset)
+			page.NextOffset = &next
+		}
+		resp.Data = &commuterv1.Response_Departures{Departures: page}
+	default:
+		return nil, ErrUnsupported
+	}
+
+	// Metadata is free-form, so it goes through its JSON form, as it would
+	// be sent in a JSON response.
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	resp.Metadata = &structpb.Struct{}
+	if err := protojson.Unmarshal(raw, resp.Metadata); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,331 @@
+// Package push sends Web Push notifications (RFC 8030) to browser
+// subscriptions, with payloads encrypted as RFC 8291 aes128gcm and the server
+// identified by VAPID (RFC 8292). Chrome subscriptions are delivered through
+// Firebase Cloud Messaging endpoints, which speak the same protocol.
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+)
+
+const (
+	// recordSize is the aes128gcm record size; a payload is one record.
+	recordSize = 4096
+
+	// maxPayload is the largest payload push services must accept, less
+	// the encryption overhead.
+	maxPayload = 3993
+
+	// tokenTTL is how long a VAPID token is valid; RFC 8292 allows 24h.
+	tokenTTL = 12 * time.Hour
+)
+
+// ErrGone is returned when the push service no longer knows a subscription,
+// which should then be deleted.
+var ErrGone = errors.New("push subscription expired or unsubscribed")
+
+// Urgency tells the push service how soon to wake the device (RFC 8030).
+type Urgency string
+
+const (
+	UrgencyNormal Urgency = "normal"
+	UrgencyHigh   Urgency = "high"
+)
+
+// Message is a notification, delivered to the service worker as its JSON
+// payload.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Tag replaces an earlier notification with the same tag.
+	Tag  string `json:"tag,omitempty"`
+	URL  string `json:"url,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// Sender delivers messages to push subscriptions.
+type Sender struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+	subject   string
+	ttl       time.Duration
+	client    *http.Client
+}
+
+// New returns a sender for cfg, whose VAPIDPrivateKey must be a base64url
+// encoded P-256 private key.
+func New(cfg config.PushConfig) (*Sender, error) {
+	raw, err := decodeBase64(cfg.VAPIDPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode vapid private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse vapid private key: %w", err)
+	}
+	pub, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("vapid public key: %w", err)
+	}
+	return &Sender{
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(pub),
+		subject:   cfg.Subject,
+		ttl:       cfg.TTL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				// Endpoints are checked when subscribing, but their hosts
+				// may resolve elsewhere by the time a message is sent.
+				DialContext: (&net.Dialer{
+					Timeout:   10 * time.Second,
+					KeepAlive: 30 * time.Second,
+					Control:   dialPublic,
+				}).DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+		},
+	}, nil
+}
+
+// PublicKey returns the base64url VAPID public key browsers subscribe with,
+// as the applicationServerKey of PushManager.subscribe.
+func (s *Sender) PublicKey() string {
+	return s.publicKey
+}
+
+// Send delivers msg to sub. It returns ErrGone when the subscription no
+// longer exists.
+func (s *Sender) Send(ctx context.Context, sub domain.PushSubscription, msg Message, urgency Urgency) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		return fmt.Errorf("push payload is %d bytes, more than %d", len(payload), maxPayload)
+	}
+	body, err := encrypt(sub.Keys, payload)
+	if err != nil {
+		return fmt.Errorf("encrypt push payload: %w", err)
+	}
+	token, err := s.token(sub.Endpoint)
+	if err != nil {
+		return fmt.Errorf("sign vapid token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(s.ttl/time.Second)))
+	req.Header.Set("Urgency", string(urgency))
+	if msg.Tag != "" {
+		req.Header.Set("Topic", topic(msg.Tag))
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+s.publicKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("push service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Validate checks that sub has an https endpoint on a public host and
+// well-formed keys. Subscriptions come from users, and the server posts to
+// their endpoints, so ones resolving to loopback, private or link-local
+// addresses are refused rather than reaching internal services.
+func Validate(ctx context.Context, sub domain.PushSubscription) error {
+	u, err := url.Parse(sub.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("endpoint must be an https URL")
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return errors.New("endpoint host does not resolve")
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return errors.New("endpoint host must be a public push service")
+		}
+	}
+	raw, err := decodeBase64(sub.Keys.P256dh)
+	if err != nil {
+		return errors.New("keys.p256dh must be base64url")
+	}
+	if _, err := ecdh.P256().NewPublicKey(raw); err != nil {
+		return errors.New("keys.p256dh must be a P-256 public key")
+	}
+	auth, err := decodeBase64(sub.Keys.Auth)
+	if err != nil || len(auth) != 16 {
+		return errors.New("keys.auth must be 16 base64url bytes")
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is a public unicast address, not a
+// loopback, private, link-local or unspecified one.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// dialPublic refuses connections to addresses that are not public, whatever
+// an endpoint's host resolves to when a message is sent.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(ap.Addr()) {
+		return fmt.Errorf("push endpoint address %s is not public", ap.Addr())
+	}
+	return nil
+}
+
+// token returns a VAPID JWT for the origin of endpoint, signed with ES256.
+func (s *Sender) token(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(tokenTTL).Unix(),
+		"sub": s.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signed))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// JWS encodes an ES256 signature as r and s, each padded to 32 bytes.
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	sig.FillBytes(raw[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// encrypt encrypts payload for a subscription's keys as a single aes128gcm
+// record (RFC 8291 section 3.4).
+func encrypt(keys domain.PushKeys, payload []byte) ([]byte, error) {
+	uaRaw, err := decodeBase64(keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("decode p256dh: %w", err)
+	}
+	authSecret, err := decodeBase64(keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("decode auth: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaRaw)
+	if err != nil {
+		return nil, fmt.Errorf("parse p256dh: %w", err)
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaRaw...), asPublic...)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header is the salt, record size, key ID length and key ID, which
+	// is the server's ephemeral public key. The 0x02 delimiter marks the
+	// last record.
+	out := make([]byte, 0, 16+4+1+len(asPublic)+len(payload)+1+gcm.Overhead())
+	out = append(out, salt...)
+	out = binary.BigEndian.AppendUint32(out, recordSize)
+	out = append(out, byte(len(asPublic)))
+	out = append(out, asPublic...)
+	return gcm.Seal(out, nonce, append(payload, 0x02), nil), nil
+}
+
+// topic turns a tag into a Topic header, which allows at most 32 base64url
+// characters.
+func topic(tag string) string {
+	sum := sha256.Sum256([]byte(tag))
+	return base64.RawURLEncoding.EncodeToString(sum[:24])
+}
+
+// decodeBase64 accepts base64url with or without padding, as browsers and
+// key generators differ.
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,44 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"llm-router/internal/domain"
+)
+
+func TestValidateEndpointHost(t *testing.T) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := domain.PushKeys{
+		P256dh: base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:   base64.RawURLEncoding.EncodeToString(make([]byte, 16)),
+	}
+
+	for _, tc := range []struct {
+		endpoint string
+		ok       bool
+	}{
+		{"https://8.8.8.8/push/1", true},
+		{"https://[2001:4860:4860::8888]/push/1", true},
+		{"http://8.8.8.8/push/1", false},
+		{"https://127.0.0.1/push/1", false},
+		{"https://[::1]:8443/push/1", false},
+		{"https://10.0.0.5/push/1", false},
+		{"https://192.168.1.1/push/1", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://[fe80::1]/push/1", false},
+		{"https://[::ffff:127.0.0.1]/push/1", false},
+		{"https://0.0.0.0/push/1", false},
+	} {
+		err := Validate(context.Background(), domain.PushSubscription{Endpoint: tc.endpoint, Keys: keys})
+		if (err == nil) != tc.ok {
+			t.Errorf("Validate(%s) = %v, want ok %t", tc.endpoint, err, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package retention runs the maintenance job that keeps the database from
+// growing without bound: it deletes data past its retention and then
+// reclaims the space.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/cron"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// lockName is the store lock held while the job runs, so that instances
+// sharing a database take turns.
+const lockName = "retention"
+
+// ErrRunning is returned by Run while another run is in progress.
+var ErrRunning = errors.New("maintenance already running")
+
+// Job runs maintenance whenever its schedule fires, and on demand.
+type Job struct {
+	cfg      *config.Config
+	schedule cron.Schedule
+	store    *store.Store
+	logger   *zap.Logger
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	running bool
+	last    *domain.RetentionRun
+}
+
+// New returns a job for cfg, which must be valid.
+func New(cfg *config.Config, s *store.Store, logger *zap.Logger) (*Job, error) {
+	schedule, err := cfg.Retention.Schedule()
+	if err != nil {
+		return nil, fmt.Errorf("retention schedule: %w", err)
+	}
+	return &Job{cfg: cfg, schedule: schedule, store: s, logger: logger}, nil
+}
+
+// Start schedules the job until ctx is done. It does nothing when the job
+// has no schedule.
+func (j *Job) Start(ctx context.Context) error {
+	if len(j.schedule) == 0 {
+		j.logger.Info("Retention schedule empty, maintenance only runs on demand")
+		return nil
+	}
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		j.loop(ctx)
+	}()
+	return nil
+}
+
+// Stop waits for a run in progress to finish, or for ctx to expire.
+func (j *Job) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *Job) loop(ctx context.Context) {
+	for {
+		target := j.schedule.Next(time.Now().In(calendar.Jakarta))
+		if target.IsZero() {
+			j.logger.Warn("Retention schedule never fires, no maintenance scheduled", zap.Stringer("schedule", j.schedule))
+			return
+		}
+		j.logger.Info("Scheduled next maintenance", zap.Time("target_jakarta", target))
+
+		timer := time.NewTimer(time.Until(target))
+		select {
+		case <-timer.C:
+			if _, err := j.Run(ctx); err != nil && !errors.Is(err, ErrRunning) {
+				j.logger.Error("Scheduled maintenance failed", zap.Error(err))
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Status returns the last run and when the next one is scheduled.
+func (j *Job) Status() domain.RetentionStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := domain.RetentionStatus{Running: j.running}
+	if j.last != nil {
+		last := *j.last
+		status.LastRun = &last
+	}
+	if next := j.schedule.Next(time.Now().In(calendar.Jakarta)); !next.IsZero() {
+		status.NextRun = &next
+	}
+	return status
+}
+
+// Run prunes every kind of data past its retention and then optimizes the
+// database. It returns ErrRunning while another run is in progress here, and
+// skips the run when another instance holds the store lock.
+func (j *Job) Run(ctx context.Context) (domain.RetentionRun, error) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return domain.RetentionRun{}, ErrRunning
+	}
+	j.running = true
+	j.mu.Unlock()
+	defer func() {
+		j.mu.Lock()
+		j.running = false
+		j.mu.Unlock()
+	}()
+
+	unlock, ok, err := j.store.TryLock(ctx, lockName)
+	if err != nil {
+		return domain.RetentionRun{}, err
+	}
+	if !ok {
+		j.logger.Info("Maintenance running on another instance, skipping")
+		return domain.RetentionRun{}, ErrRunning
+	}
+	defer unlock()
+
+	run, err := j.run(ctx)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	j.mu.Lock()
+	j.last = &run
+	j.mu.Unlock()
+
+	j.logger.Info("Maintenance finished",
+		zap.Int64("sync_runs", run.SyncRuns),
+		zap.Int64("raw_fetches", run.RawFetches),
+		zap.Int64("announcements", run.Announcements),
+		zap.Int64("schedule_history", run.ScheduleHistory),
+		zap.Int64("size_before_bytes", run.SizeBeforeBytes),
+		zap.Int64("size_after_bytes", run.SizeAfterBytes),
+		zap.Duration("took", run.FinishedAt.Sub(run.StartedAt)),
+		zap.Error(err),
+	)
+	return run, err
+}
+
+// run does the steps of Run in order, stopping at the first that fails.
+func (j *Job) run(ctx context.Context) (run domain.RetentionRun, err error) {
+	now := time.Now()
+	run.StartedAt = now
+	defer func() { run.FinishedAt = time.Now() }()
+
+	if stats, err := j.store.DBStats(ctx); err == nil {
+		run.SizeBeforeBytes = stats.SizeBytes
+	}
+
+	cfg := j.cfg
+	historyStart := calendar.FormatDate(now.AddDate(0, 0, 1-cfg.ServiceDates.HistoryDays))
+	if run.SyncRuns, err = j.store.PruneSyncRuns(ctx, cfg.SyncRunRetention); err != nil {
+		return run, err
+	}
+	if run.RawFetches, err = j.store.PruneRawFetches(ctx, now.Add(-cfg.Retention.RawFetches)); err != nil {
+		return run, err
+	}
+	if run.Announcements, err = j.store.PruneAnnouncements(ctx, now.Add(-cfg.Retention.Announcements)); err != nil {
+		return run, err
+	}
+	if run.ScheduleHistory, err = j.store.PruneScheduleHistory(ctx, historyStart); err != nil {
+		return run, err
+	}
+	if err := j.store.Optimize(ctx); err != nil {
+		return run, err
+	}
+
+	if stats, err := j.store.DBStats(ctx); err == nil {
+		run.SizeAfterBytes = stats.SizeBytes
+	}
+	return run, nil
+}
@@ -0,0 +1,300 @@
+package scrapper
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+
+	"go.uber.org/zap"
+)
+
+// maxFeedMessage bounds the message kept from a feed entry, in characters,
+// like the limit on announcements entered by hand.
+const maxFeedMessage = 2000
+
+// severityKeywords classify a feed entry by the most severe phrase in its
+// title or text; entries matching none are informational.
+var severityKeywords = []struct {
+	severity string
+	words    []string
+}{
+	{domain.SeveritySevere, []string{"tidak beroperasi", "dihentikan", "tidak dapat melayani", "perjalanan terhenti", "suspended"}},
+	{domain.SeverityWarning, []string{"gangguan", "banjir", "perbaikan", "pemeliharaan", "rekayasa", "keterlambatan", "terlambat", "delay", "disruption"}},
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// feedEntry is an announcement as read from the feed, before it is matched
+// to a line.
+type feedEntry struct {
+	ID       string
+	Title    string
+	Text     string
+	Line     string
+	StartsAt time.Time
+	EndsAt   *time.Time
+}
+
+func (s *Scraper) pollAnnouncements(ctx context.Context) {
+	cfg := s.config.Announcements
+	if cfg.Endpoint == "" {
+		s.logger.Info("Announcement feed not configured, announcement polling disabled")
+		return
+	}
+
+	s.logger.Info("Starting announcement polling",
+		zap.String("endpoint", cfg.Endpoint),
+		zap.Duration("interval", cfg.PollInterval),
+	)
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.syncAnnouncements(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scraper) syncAnnouncements(ctx context.Context) {
+	data, err := s.fetch(s.config.Announcements.Endpoint)
+	if err != nil {
+		s.logger.Warn("Failed to fetch announcements", zap.Error(err))
+		return
+	}
+	now := time.Now()
+	entries, err := parseAnnouncementFeed(data, now)
+	if err != nil {
+		s.logger.Error("Failed to parse announcement feed", zap.Error(err))
+		return
+	}
+
+	lines, err := s.store.GetLines(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read lines for announcements", zap.Error(err))
+	}
+	names := make([]string, 0, len(lines))
+	for _, l := range lines {
+		names = append(names, l.Name)
+	}
+
+	feed := make([]domain.Announcement, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		// An entry already over is left out, so the stored copy is ended.
+		if seen[e.ID] || (e.EndsAt != nil && !e.EndsAt.After(now)) {
+			continue
+		}
+		seen[e.ID] = true
+		feed = append(feed, domain.Announcement{
+			Line:       matchLine(names, e.Line, e.Title+" "+e.Text),
+			Severity:   classifySeverity(e.Title + " " + e.Text),
+			Message:    announcementMessage(e.Title, e.Text),
+			StartsAt:   e.StartsAt,
+			EndsAt:     e.EndsAt,
+			Source:     domain.AnnouncementSourceKAI,
+			ExternalID: e.ID,
+		})
+	}
+
+	changed, err := s.store.SyncAnnouncements(ctx, domain.AnnouncementSourceKAI, feed, now)
+	if err != nil {
+		s.logger.Error("Failed to save announcements", zap.Error(err))
+		return
+	}
+	s.logger.Debug("Synced announcements",
+		zap.Int("count", len(feed)),
+		zap.Int("changed", len(changed)),
+	)
+	if len(changed) > 0 {
+		s.events.Publish(events.Event{Topic: events.TopicAnnouncement, Type: events.TypeAnnouncementsChanged, Data: changed})
+	}
+}
+
+// parseAnnouncementFeed reads the entries of a JSON feed, {"data": [...]},
+// or an RSS feed. Entries without a start time start at now, and entries
+// without an ID are identified by their title and text.
+func parseAnnouncementFeed(data []byte, now time.Time) ([]feedEntry, error) {
+	var entries []feedEntry
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "<") {
+		var rss struct {
+			Items []struct {
+				GUID        string `xml:"guid"`
+				Link        string `xml:"link"`
+				Title       string `xml:"title"`
+				Description string `xml:"description"`
+				PubDate     string `xml:"pubDate"`
+				Category    string `xml:"category"`
+			} `xml:"channel>item"`
+		}
+		if err := xml.Unmarshal(data, &rss); err != nil {
+			return nil, fmt.Errorf("decode rss: %w", err)
+		}
+		for _, it := range rss.Items {
+			id := it.GUID
+			if id == "" {
+				id = it.Link
+			}
+			entries = append(entries, feedEntry{
+				ID:       id,
+				Title:    it.Title,
+				Text:     it.Description,
+				Line:     it.Category,
+				StartsAt: parseFeedTime(it.PubDate),
+			})
+		}
+	} else {
+		var resp struct {
+			Data []struct {
+				ID      json.RawMessage `json:"id"`
+				Title   string          `json:"title"`
+				Content string          `json:"content"`
+				Message string          `json:"message"`
+				Line    string          `json:"line"`
+				Start   string          `json:"start"`
+				End     string          `json:"end"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("decode json: %w", err)
+		}
+		for _, d := range resp.Data {
+			text := d.Content
+			if text == "" {
+				text = d.Message
+			}
+			e := feedEntry{
+				ID:       strings.Trim(string(d.ID), `"`),
+				Title:    d.Title,
+				Text:     text,
+				Line:     d.Line,
+				StartsAt: parseFeedTime(d.Start),
+			}
+			if end := parseFeedTime(d.End); !end.IsZero() {
+				e.EndsAt = &end
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		e.Title, e.Text = plainText(e.Title), plainText(e.Text)
+		if e.Title == "" && e.Text == "" {
+			continue
+		}
+		if e.ID == "" || e.ID == "null" {
+			sum := sha1.Sum([]byte(e.Title + "\n" + e.Text))
+			e.ID = hex.EncodeToString(sum[:])
+		}
+		if e.StartsAt.IsZero() || e.StartsAt.After(now) && e.EndsAt == nil {
+			e.StartsAt = now
+		}
+		if e.EndsAt != nil && !e.EndsAt.After(e.StartsAt) {
+			e.EndsAt = nil
+		}
+		kept = append(kept, e)
+	}
+	return kept, nil
+}
+
+// parseFeedTime accepts the time layouts seen in feeds, reading those
+// without a zone as Jakarta time. It returns the zero time when s matches
+// none.
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, calendar.Jakarta); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// plainText strips markup and collapses whitespace.
+func plainText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(s, " "))), " ")
+}
+
+// classifySeverity returns the severity of the first keyword group that
+// matches text.
+func classifySeverity(text string) string {
+	text = strings.ToLower(text)
+	for _, group := range severityKeywords {
+		for _, word := range group.words {
+			if strings.Contains(text, word) {
+				return group.severity
+			}
+		}
+	}
+	return domain.SeverityInfo
+}
+
+// matchLine returns the known line named by the entry's line field, or else
+// the only known line its text mentions, longest names first so that e.g.
+// "Lin Tangerang" is not taken for a shorter name. It returns "" for an
+// announcement about the whole network or several lines.
+func matchLine(names []string, field, text string) string {
+	for _, name := range names {
+		if field != "" && strings.EqualFold(strings.TrimSpace(field), name) {
+			return name
+		}
+	}
+	sorted := append([]string(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	text = strings.ToLower(field + " " + text)
+	var found string
+	for _, name := range sorted {
+		lower := strings.ToLower(name)
+		if !strings.Contains(text, lower) {
+			continue
+		}
+		if found != "" && !strings.Contains(strings.ToLower(found), lower) {
+			return ""
+		}
+		if found == "" {
+			found = name
+		}
+	}
+	return found
+}
+
+// announcementMessage joins an entry's title and text, within
+// maxFeedMessage characters.
+func announcementMessage(title, text string) string {
+	msg := title
+	switch {
+	case msg == "":
+		msg = text
+	case text != "" && !strings.HasPrefix(text, title):
+		msg = title + ": " + text
+	case text != "":
+		msg = text
+	}
+	if r := []rune(msg); len(r) > maxFeedMessage {
+		msg = string(r[:maxFeedMessage-1]) + "…"
+	}
+	return msg
+}
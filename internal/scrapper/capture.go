@@ -0,0 +1,44 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// replayFetch answers a request from the newest captured response from url,
+// as if the upstream had sent it, without touching the network. A captured
+// failure is returned without its status, so that replaying it neither
+// refreshes the token nor waits out a 429.
+func (s *Scraper) replayFetch(url string) ([]byte, int, error) {
+	f, err := s.store.LatestRawFetch(context.Background(), url)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, 0, fmt.Errorf("replay: no captured response from %s", url)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("replay: %w", err)
+	}
+	if f.Status != http.StatusOK {
+		return nil, 0, fmt.Errorf("status %d: %s", f.Status, string(f.Body))
+	}
+	return f.Body, f.Status, nil
+}
+
+// capture records an upstream response when capture mode is on. Failing to
+// record it does not fail the request.
+func (s *Scraper) capture(url string, status int, body []byte) {
+	if !s.config.Capture.Enabled || s.config.Capture.Replay {
+		return
+	}
+	f := domain.RawFetch{URL: url, Status: status, FetchedAt: time.Now(), Body: body}
+	if err := s.store.SaveRawFetch(context.Background(), f, s.config.Capture.Keep); err != nil {
+		s.logger.Warn("Failed to capture upstream response", zap.String("url", url), zap.Error(err))
+	}
+}
@@ -0,0 +1,169 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// checkIntegrity runs the consistency checks over the stored data, records
+// what they find against the sync run and logs a summary, so data quality
+// regressions show up right after the sync that caused them.
+func (s *Scraper) checkIntegrity(runID int64) {
+	stations, err := s.store.GetStations(context.Background())
+	if err != nil {
+		s.logger.Error("Failed to load stations for integrity checks", zap.Error(err))
+		return
+	}
+	schedules, err := s.store.QuerySchedules(context.Background(), store.ScheduleFilter{})
+	if err != nil {
+		s.logger.Error("Failed to load schedules for integrity checks", zap.Error(err))
+		return
+	}
+
+	issues := findIssues(stations, schedules)
+	if err := s.store.SetSyncIssues(context.Background(), runID, issues); err != nil {
+		s.logger.Error("Failed to record integrity issues", zap.Int64("run", runID), zap.Error(err))
+	}
+	if len(issues) == 0 {
+		s.logger.Info("Integrity checks passed", zap.Int64("run", runID))
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, i := range issues {
+		counts[i.Kind]++
+	}
+	fields := []zap.Field{zap.Int64("run", runID), zap.Int("issues", len(issues))}
+	for _, kind := range domain.IssueKinds {
+		if counts[kind] > 0 {
+			fields = append(fields, zap.Int(kind, counts[kind]))
+		}
+	}
+	s.logger.Warn("Integrity checks found issues", fields...)
+	for _, i := range issues {
+		s.logger.Debug("Integrity issue",
+			zap.String("kind", i.Kind),
+			zap.String("station", i.StationID),
+			zap.String("train", i.TrainID),
+			zap.String("service_day", i.ServiceDay),
+			zap.String("detail", i.Detail),
+		)
+	}
+}
+
+// tripKey identifies one train's stops within a timetable written by one
+// sync run, so stations synced on different days are not compared.
+type tripKey struct {
+	trainID    string
+	serviceDay string
+	runID      int64
+}
+
+// findIssues checks schedules against each other and against stations. A
+// train is reported at most once per kind and timetable.
+func findIssues(stations []domain.Station, schedules []domain.Schedule) []domain.SyncIssue {
+	known := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		known[st.ID] = true
+	}
+
+	var issues []domain.SyncIssue
+	trips := make(map[tripKey][]domain.Schedule)
+	served := make(map[string]bool)
+	for _, sch := range schedules {
+		served[sch.StationID] = true
+		if sch.DepartsAt.IsZero() {
+			continue
+		}
+		key := tripKey{sch.TrainID, sch.ServiceDay, sch.RunID}
+		trips[key] = append(trips[key], sch)
+	}
+
+	// A train's schedules repeat its route at every stop, so endpoints are
+	// checked once per train and timetable.
+	endpoints := make(map[tripKey]bool)
+	for key, stops := range trips {
+		sort.Slice(stops, func(i, j int) bool { return stops[i].DepartsAt.Before(stops[j].DepartsAt) })
+		first := stops[0]
+		endpointKey := tripKey{key.trainID, key.serviceDay, 0}
+		if !endpoints[endpointKey] {
+			endpoints[endpointKey] = true
+			if !known[first.StationOriginID] {
+				issues = append(issues, trainIssue(domain.IssueUnresolvedOrigin, first,
+					fmt.Sprintf("origin of route %q matches no station", first.Route)))
+			}
+			if !known[first.StationDestinationID] {
+				issues = append(issues, trainIssue(domain.IssueUnresolvedDestination, first,
+					fmt.Sprintf("destination of route %q matches no station", first.Route)))
+			}
+		}
+
+		if len(stops) == 1 {
+			issues = append(issues, trainIssue(domain.IssueSingleStop, first, "listed at "+first.StationID+" only"))
+			continue
+		}
+		if detail := monotonicity(stops); detail != "" {
+			issues = append(issues, trainIssue(domain.IssueNonMonotonic, first, detail))
+		}
+	}
+
+	for _, st := range stations {
+		// BRT stops have no timetable; buses run at their corridor's
+		// headway.
+		if st.Metadata.Active && !served[st.ID] && st.Type != domain.StationTypeBRT {
+			issues = append(issues, domain.SyncIssue{
+				Kind:      domain.IssueStationWithoutSchedules,
+				StationID: st.ID,
+				Detail:    "no departures in any timetable",
+			})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.TrainID != b.TrainID {
+			return a.TrainID < b.TrainID
+		}
+		if a.ServiceDay != b.ServiceDay {
+			return a.ServiceDay < b.ServiceDay
+		}
+		return a.StationID < b.StationID
+	})
+	return issues
+}
+
+// monotonicity describes how a train's stops, ordered by departure, break
+// route order, or returns "" when they do not.
+func monotonicity(stops []domain.Schedule) string {
+	first := stops[0]
+	for _, sch := range stops[1:] {
+		if sch.StationID == sch.StationOriginID {
+			return fmt.Sprintf("leaves its origin %s at %s, after %s at %s",
+				sch.StationID, sch.DepartsAt.In(calendar.Jakarta).Format("15:04"), first.StationID, first.DepartsAt.In(calendar.Jakarta).Format("15:04"))
+		}
+	}
+	// Every stop carries the arrival at the destination, which no departure
+	// may follow.
+	last := stops[len(stops)-1]
+	for _, sch := range stops {
+		if !sch.ArrivesAt.IsZero() && last.DepartsAt.After(sch.ArrivesAt) {
+			return fmt.Sprintf("leaves %s at %s, after arriving at its destination at %s",
+				last.StationID, last.DepartsAt.In(calendar.Jakarta).Format("15:04"), sch.ArrivesAt.In(calendar.Jakarta).Format("15:04"))
+		}
+	}
+	return ""
+}
+
+func trainIssue(kind string, sch domain.Schedule, detail string) domain.SyncIssue {
+	return domain.SyncIssue{Kind: kind, TrainID: sch.TrainID, ServiceDay: sch.ServiceDay, Detail: detail}
+}
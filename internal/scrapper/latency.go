@@ -0,0 +1,120 @@
+package scrapper
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindow is the number of most recent requests used to judge
+	// whether upstream is currently in distress.
+	latencyWindow = 20
+	// latencyAbortFactor aborts the sync once the rolling p90 exceeds the
+	// budget by this factor; between 1x and this factor the sync slows down.
+	latencyAbortFactor = 3
+	slowestStations    = 5
+)
+
+type StationLatency struct {
+	StationID string `json:"station_id"`
+	Ms        int64  `json:"ms"`
+}
+
+// LatencyStats summarises upstream latency for one sync run.
+type LatencyStats struct {
+	Count   int              `json:"count"`
+	P50Ms   int64            `json:"p50_ms"`
+	P90Ms   int64            `json:"p90_ms"`
+	P99Ms   int64            `json:"p99_ms"`
+	MaxMs   int64            `json:"max_ms"`
+	Slowest []StationLatency `json:"slowest"`
+}
+
+// latencyTracker records upstream latency per station during a sync.
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples []StationLatency
+}
+
+func (t *latencyTracker) add(stationID string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples = append(t.samples, StationLatency{StationID: stationID, Ms: d.Milliseconds()})
+}
+
+// rollingP90 is the p90 over the most recent latencyWindow samples. It returns
+// zero until a full window has been observed.
+func (t *latencyTracker) rollingP90() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.samples) < latencyWindow {
+		return 0
+	}
+	recent := make([]int64, latencyWindow)
+	for i, s := range t.samples[len(t.samples)-latencyWindow:] {
+		recent[i] = s.Ms
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i] < recent[j] })
+	return time.Duration(percentile(recent, 90)) * time.Millisecond
+}
+
+func (t *latencyTracker) stats() LatencyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := LatencyStats{Count: len(t.samples), Slowest: []StationLatency{}}
+	if len(t.samples) == 0 {
+		return stats
+	}
+
+	sorted := make([]StationLatency, len(t.samples))
+	copy(sorted, t.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Ms < sorted[j].Ms })
+
+	ms := make([]int64, len(sorted))
+	for i, s := range sorted {
+		ms[i] = s.Ms
+	}
+	stats.P50Ms = percentile(ms, 50)
+	stats.P90Ms = percentile(ms, 90)
+	stats.P99Ms = percentile(ms, 99)
+	stats.MaxMs = ms[len(ms)-1]
+
+	for i := len(sorted) - 1; i >= 0 && len(stats.Slowest) < slowestStations; i-- {
+		stats.Slowest = append(stats.Slowest, sorted[i])
+	}
+	return stats
+}
+
+// percentile uses the nearest-rank method on an ascending slice.
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// latencyBudgetAction decides how to pace the next upstream request given the
+// configured budget: no delay, a delay to back off, or abort the sync.
+func (s *Scraper) latencyBudgetAction(t *latencyTracker) (delay time.Duration, abort bool) {
+	budget := s.config.SyncLatencyBudget
+	if budget <= 0 || t == nil {
+		return 0, false
+	}
+
+	p90 := t.rollingP90()
+	switch {
+	case p90 > budget*latencyAbortFactor:
+		return 0, true
+	case p90 > budget:
+		// Back off proportionally to how slow upstream has become.
+		return p90, false
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,139 @@
+package scrapper
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// syncLines derives line membership and station order from the stored
+// schedules; see orderStations. Each line's service type is classified from
+// its name and length and applied to its stored schedules.
+func (s *Scraper) syncLines() error {
+	all, err := s.store.QuerySchedules(context.Background(), store.ScheduleFilter{})
+	if err != nil {
+		s.logger.Error("Failed to load schedules for line sync", zap.Error(err))
+		return err
+	}
+
+	trips := make(map[string][]domain.Schedule)
+	for _, sch := range all {
+		if sch.Line == "" || sch.DepartsAt.IsZero() {
+			continue
+		}
+		trips[sch.TrainID] = append(trips[sch.TrainID], sch)
+	}
+
+	byLine := make(map[string][][]domain.Schedule)
+	for _, stops := range trips {
+		sort.Slice(stops, func(i, j int) bool { return stops[i].DepartsAt.Before(stops[j].DepartsAt) })
+		byLine[stops[0].Line] = append(byLine[stops[0].Line], stops)
+	}
+
+	lines := make([]domain.Line, 0, len(byLine))
+	sizes := make(map[string]int, len(byLine))
+	services := make(map[string]string, len(byLine))
+	for name, lineTrips := range byLine {
+		order := orderStations(lineTrips)
+		sizes[name] = len(order)
+		services[name] = classifyService(name, len(order))
+
+		l := domain.Line{Name: name, Color: lineTrips[0][0].Metadata.Origin.Color}
+		for i, id := range order {
+			l.Stations = append(l.Stations, domain.LineStation{Position: i + 1, ID: id})
+		}
+		lines = append(lines, l)
+	}
+
+	if err := s.store.SetLines(context.Background(), lines); err != nil {
+		s.logger.Error("Failed to save lines", zap.Error(err))
+		return err
+	}
+	s.lineSizes = sizes
+	s.logger.Info("Synced lines", zap.Int("count", len(lines)))
+
+	// Schedules synced before this run's lines were known are reclassified.
+	if err := s.store.SetServiceTypes(context.Background(), services); err != nil {
+		s.logger.Error("Failed to save service types", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// orderStations merges the stop sequences of a line's trips, each in
+// departure order, into one canonical station order. It starts from the
+// longest trip, which on the KRL network usually runs end to end, and then
+// fits in stations only shorter trips call at, such as a newly opened station
+// or the far end of a short working. Trips running the other way are
+// reversed first, and a station a loop visits twice is kept once. Branches
+// end up next to their junction, one after another.
+func orderStations(trips [][]domain.Schedule) []string {
+	sort.SliceStable(trips, func(i, j int) bool { return len(trips[i]) > len(trips[j]) })
+
+	var order []string
+	for _, stops := range trips {
+		seq := make([]string, len(stops))
+		for i, sch := range stops {
+			seq[i] = sch.StationID
+		}
+		if runsBackwards(order, seq) {
+			slices.Reverse(seq)
+		}
+		order = mergeSequence(order, seq)
+	}
+	return order
+}
+
+// runsBackwards reports whether seq visits the stations it shares with order
+// mostly in reverse.
+func runsBackwards(order, seq []string) bool {
+	pos := make(map[string]int, len(order))
+	for i, id := range order {
+		pos[id] = i
+	}
+	forward, backward, last := 0, 0, -1
+	for _, id := range seq {
+		p, ok := pos[id]
+		if !ok {
+			continue
+		}
+		if last >= 0 {
+			if p > last {
+				forward++
+			} else {
+				backward++
+			}
+		}
+		last = p
+	}
+	return backward > forward
+}
+
+// mergeSequence inserts the stations of seq missing from order, each right
+// after the station seq visits before it. Stations seq visits before any
+// known one go right before the first known station it reaches.
+func mergeSequence(order, seq []string) []string {
+	for i, id := range seq {
+		if slices.Contains(order, id) {
+			continue
+		}
+		at := len(order)
+		if i > 0 {
+			at = slices.Index(order, seq[i-1]) + 1
+		} else {
+			for _, next := range seq[1:] {
+				if j := slices.Index(order, next); j >= 0 {
+					at = j
+					break
+				}
+			}
+		}
+		order = slices.Insert(order, at, id)
+	}
+	return order
+}
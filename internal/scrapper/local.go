@@ -0,0 +1,77 @@
+package scrapper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+)
+
+// localTrainSource returns the source of KAI local and regional trains, or
+// nil when it is disabled.
+func localTrainSource(cfg config.LocalTrainsConfig) *tripSource {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	return &tripSource{
+		name:     "local",
+		endpoint: cfg.Endpoint,
+		only:     cfg.Stations,
+		schedules: func(trips []tripRecord, names map[string]string, day time.Time) (map[string][]domain.Schedule, []error) {
+			return localTrainSchedules(cfg, trips, names, day)
+		},
+	}
+}
+
+// localTrainSchedules converts trips of local trains into departures by
+// station, on day. Unlike KRL's, a local train runs beyond the commuter
+// network, so its route names the ends of the whole trip, even where they are
+// not stations of the network, and its arrival there may be hours or a day
+// later. Trips that cannot be read are returned as errors and left out.
+func localTrainSchedules(cfg config.LocalTrainsConfig, trips []tripRecord, names map[string]string, day time.Time) (map[string][]domain.Schedule, []error) {
+	departures := make(map[string][]domain.Schedule)
+	var rejected []error
+	for _, trip := range trips {
+		stops, err := tripStops(trip, day)
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("train %s: %w", trip.TrainID, err))
+			continue
+		}
+
+		line := strings.ToUpper(strings.TrimSpace(trip.Line))
+		if line == "" {
+			line = cfg.Line
+		}
+		origin, dest := stops[0], stops[len(stops)-1]
+		route := stopName(names, origin.stationID) + "-" + stopName(names, dest.stationID)
+		for _, stop := range stops[:len(stops)-1] {
+			departures[stop.stationID] = append(departures[stop.stationID], domain.Schedule{
+				ID:                   fmt.Sprintf("sc_local_%s_%s", stop.stationID, trip.TrainID),
+				StationID:            stop.stationID,
+				StationOriginID:      origin.stationID,
+				StationDestinationID: dest.stationID,
+				TrainID:              trip.TrainID,
+				Line:                 line,
+				Route:                route,
+				DepartsAt:            stop.at,
+				ArrivesAt:            dest.at,
+				ServiceType:          domain.ServiceLocal,
+				Metadata: domain.ScheduleMetadata{
+					Origin: domain.ScheduleOrigin{Color: cfg.Color},
+				},
+			})
+		}
+	}
+	return departures, rejected
+}
+
+// stopName returns the name of station id, or the ID itself for a stop
+// outside the network.
+func stopName(names map[string]string, id string) string {
+	if name := names[id]; name != "" {
+		return name
+	}
+	return id
+}
@@ -0,0 +1,55 @@
+package scrapper
+
+import (
+	"context"
+	"time"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// syncStationLocations geocodes stations that have no known coordinates yet.
+// Locations rarely change, so stations are only looked up once. Geocoding
+// misses are expected and only logged; store failures are returned.
+func (s *Scraper) syncStationLocations() error {
+	if s.geocoder == nil {
+		return nil
+	}
+
+	locs, err := s.store.GetStationLocations(context.Background())
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, loc := range locs {
+		known[loc.StationID] = true
+	}
+
+	stations, err := s.store.GetStations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	located := 0
+	for _, st := range stations {
+		if known[st.ID] {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		place, err := s.geocoder.Geocode(ctx, "Stasiun "+st.Name)
+		cancel()
+		if err != nil {
+			s.logger.Debug("Failed to geocode station", zap.String("station", st.ID), zap.Error(err))
+			continue
+		}
+
+		if err := s.store.SetStationLocation(ctx, domain.StationLocation{StationID: st.ID, Lat: place.Lat, Lng: place.Lng}); err != nil {
+			return err
+		}
+		located++
+	}
+	s.logger.Info("Synced station locations", zap.Int("located", located))
+	return nil
+}
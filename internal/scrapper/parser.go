@@ -0,0 +1,126 @@
+package scrapper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// scheduleParser turns a station's upstream records into schedules stamped on
+// day. Records that cannot be converted are returned as recordErrors rather
+// than stored with zero values. UpdatedAt and RunID are left to the caller.
+type scheduleParser func(s *Scraper, stationID string, records []scheduleRecord, stationNameMap map[string]string, day time.Time) ([]domain.Schedule, []recordError)
+
+// recordError is an upstream record a parser rejected.
+type recordError struct {
+	record scheduleRecord
+	field  string
+	err    error
+}
+
+const defaultParser = "v1"
+
+// scheduleParsers are the parser implementations selectable by name. Only
+// defaultParser writes to the store; the others can be run in shadow to
+// validate them against it before they replace it.
+var scheduleParsers = map[string]scheduleParser{
+	"v1": (*Scraper).parseSchedules,
+	"v2": (*Scraper).parseSchedulesCompactNames,
+}
+
+// ParserNames lists the available schedule parsers.
+func ParserNames() []string {
+	names := make([]string, 0, len(scheduleParsers))
+	for name := range scheduleParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseSchedules is the v1 parser. Route endpoints are resolved by exact
+// station name or alias, ignoring case; known upstream spellings such as
+// "TANAHABANG" are stored as aliases.
+func (s *Scraper) parseSchedules(stationID string, records []scheduleRecord, stationNameMap map[string]string, day time.Time) ([]domain.Schedule, []recordError) {
+	return convertRecords(stationID, records, day, func(name string) string {
+		return stationNameMap[strings.ToUpper(name)]
+	})
+}
+
+// parseSchedulesCompactNames is the v2 parser. It resolves route endpoints by
+// comparing names with spaces removed, so upstream spellings such as
+// "TANAHABANG" resolve without an alias.
+func (s *Scraper) parseSchedulesCompactNames(stationID string, records []scheduleRecord, stationNameMap map[string]string, day time.Time) ([]domain.Schedule, []recordError) {
+	compact := make(map[string]string, len(stationNameMap))
+	for name, id := range stationNameMap {
+		compact[compactName(name)] = id
+	}
+	return convertRecords(stationID, records, day, func(name string) string {
+		return compact[compactName(name)]
+	})
+}
+
+func compactName(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), ""))
+}
+
+// convertRecords holds the conversion shared by the parsers; resolve maps a
+// route endpoint name to a station ID.
+func convertRecords(stationID string, records []scheduleRecord, day time.Time, resolve func(string) string) ([]domain.Schedule, []recordError) {
+	var schedules []domain.Schedule
+	var rejected []recordError
+	for _, d := range records {
+		departsAt, err := parseClock(d.TimeEst, day)
+		if err != nil {
+			rejected = append(rejected, recordError{record: d, field: "departure", err: err})
+			continue
+		}
+		arrivesAt, err := parseClock(d.DestTime, day)
+		if err != nil {
+			rejected = append(rejected, recordError{record: d, field: "arrival", err: err})
+			continue
+		}
+		// A trip that arrives "before" it departs runs past midnight.
+		if arrivesAt.Before(departsAt) {
+			arrivesAt = arrivesAt.AddDate(0, 0, 1)
+		}
+
+		// Parse route name to find Origin/Dest IDs
+		parts := strings.Split(d.RouteName, "-")
+		var originName, destName string
+		if len(parts) >= 2 {
+			originName = strings.TrimSpace(parts[0])
+			destName = strings.TrimSpace(parts[1])
+		} else {
+			originName = d.RouteName
+			destName = d.RouteName
+		}
+
+		platform := strings.TrimSpace(d.Platform)
+		if platform == "" {
+			platform = strings.TrimSpace(d.Track)
+		}
+
+		schedules = append(schedules, domain.Schedule{
+			ID:                   fmt.Sprintf("sc_krl_%s_%s", stationID, d.TrainID),
+			StationID:            stationID,
+			StationOriginID:      resolve(originName),
+			StationDestinationID: resolve(destName),
+			TrainID:              d.TrainID,
+			Line:                 d.KaName,
+			Route:                d.RouteName,
+			DepartsAt:            departsAt,
+			ArrivesAt:            arrivesAt,
+			Metadata: domain.ScheduleMetadata{
+				Origin: domain.ScheduleOrigin{
+					Color: d.Color,
+				},
+				Platform: platform,
+			},
+		})
+	}
+	return schedules, rejected
+}
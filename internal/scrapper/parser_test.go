@@ -0,0 +1,26 @@
+package scrapper
+
+import (
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+)
+
+func TestConvertRecordsWritesJakartaTime(t *testing.T) {
+	// The day a UTC host would build for its own date.
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	records := []scheduleRecord{{TrainID: "1001", KaName: "COMMUTER LINE BOGOR", RouteName: "JAKARTAKOTA-BOGOR", TimeEst: "17:00", DestTime: "00:20"}}
+
+	schedules, rejected := convertRecords("MRI", records, day, func(string) string { return "" })
+	if len(rejected) > 0 || len(schedules) != 1 {
+		t.Fatalf("convertRecords = %d schedules, %d rejected", len(schedules), len(rejected))
+	}
+	sch := schedules[0]
+	if want := time.Date(2026, 10, 19, 17, 0, 0, 0, calendar.Jakarta); !sch.DepartsAt.Equal(want) || sch.DepartsAt.Location() != calendar.Jakarta {
+		t.Errorf("DepartsAt = %v, want %v", sch.DepartsAt, want)
+	}
+	if want := time.Date(2026, 10, 20, 0, 20, 0, 0, calendar.Jakarta); !sch.ArrivesAt.Equal(want) || sch.ArrivesAt.Location() != calendar.Jakarta {
+		t.Errorf("ArrivesAt = %v, want %v", sch.ArrivesAt, want)
+	}
+}
@@ -0,0 +1,107 @@
+package scrapper
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"llm-router/internal/config"
+)
+
+const (
+	// maxThrottleBackoff caps the pause after repeated 429s.
+	maxThrottleBackoff = 10 * time.Minute
+	// maxThrottledRetries is how often a request answered with 429 is
+	// retried, after the pause, before it fails.
+	maxThrottledRetries = 3
+)
+
+// upstreamLimiter paces every request to the upstream, across all sync
+// workers, according to a config.ScrapeConfig.
+type upstreamLimiter struct {
+	delay       time.Duration
+	interval    time.Duration
+	baseBackoff time.Duration
+
+	mu sync.Mutex
+	// next is the earliest start of the next request under the
+	// requests-per-minute ceiling.
+	next time.Time
+	// pausedUntil holds back all requests after a 429; backoff is the
+	// length of the last such pause.
+	pausedUntil time.Time
+	backoff     time.Duration
+}
+
+func newUpstreamLimiter(cfg config.ScrapeConfig) *upstreamLimiter {
+	l := &upstreamLimiter{delay: cfg.RequestDelay, baseBackoff: cfg.ThrottleBackoff}
+	if cfg.RequestsPerMinute > 0 {
+		l.interval = time.Minute / time.Duration(cfg.RequestsPerMinute)
+	}
+	return l
+}
+
+// wait blocks until a request may be sent.
+func (l *upstreamLimiter) wait() {
+	l.mu.Lock()
+	start := time.Now().Add(l.delay)
+	if l.next.After(start) {
+		start = l.next
+	}
+	if l.pausedUntil.After(start) {
+		start = l.pausedUntil
+	}
+	if l.interval > 0 {
+		l.next = start.Add(l.interval)
+	}
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
+
+// throttled pauses all requests after a 429, for retryAfter when the
+// upstream sent one and otherwise for a backoff that doubles with each
+// consecutive 429. It returns the pause.
+func (l *upstreamLimiter) throttled(retryAfter time.Duration) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pause := retryAfter
+	if pause <= 0 {
+		switch {
+		case l.backoff == 0:
+			l.backoff = l.baseBackoff
+		case l.backoff < maxThrottleBackoff:
+			l.backoff = min(2*l.backoff, maxThrottleBackoff)
+		}
+		pause = l.backoff
+	}
+	if until := time.Now().Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	return pause
+}
+
+// succeeded resets the backoff once the upstream accepts requests again.
+func (l *upstreamLimiter) succeeded() {
+	l.mu.Lock()
+	l.backoff = 0
+	l.mu.Unlock()
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, returning zero when it is missing or invalid.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+package scrapper
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"llm-router/internal/domain"
+)
+
+// QualityStats counts upstream schedule records seen and rejected during the
+// current or most recent sync, by rejection reason.
+type QualityStats struct {
+	Records  int            `json:"records"`
+	Rejected int            `json:"rejected"`
+	Reasons  map[string]int `json:"reasons,omitempty"`
+}
+
+type qualityTracker struct {
+	mu    sync.Mutex
+	stats QualityStats
+}
+
+func (q *qualityTracker) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats = QualityStats{}
+}
+
+func (q *qualityTracker) accept() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats.Records++
+}
+
+func (q *qualityTracker) reject(reason string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stats.Records++
+	q.stats.Rejected++
+	if q.stats.Reasons == nil {
+		q.stats.Reasons = make(map[string]int)
+	}
+	q.stats.Reasons[reason]++
+}
+
+func (q *qualityTracker) snapshot() QualityStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	if stats.Reasons != nil {
+		stats.Reasons = make(map[string]int, len(q.stats.Reasons))
+		for k, v := range q.stats.Reasons {
+			stats.Reasons[k] = v
+		}
+	}
+	return stats
+}
+
+// runTracker counts the stations and rows of the current sync for its
+// history record, and collects the anomalies it found for notifications.
+type runTracker struct {
+	mu        sync.Mutex
+	stats     domain.SyncRunStats
+	anomalies []domain.ScheduleAnomaly
+}
+
+func (t *runTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats = domain.SyncRunStats{}
+	t.anomalies = nil
+}
+
+func (t *runTracker) anomaly(a domain.ScheduleAnomaly) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.anomalies = append(t.anomalies, a)
+}
+
+// takeAnomalies returns the anomalies found so far, sorted by station, and
+// forgets them.
+func (t *runTracker) takeAnomalies() []domain.ScheduleAnomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	anomalies := t.anomalies
+	t.anomalies = nil
+	slices.SortFunc(anomalies, func(a, b domain.ScheduleAnomaly) int { return strings.Compare(a.StationID, b.StationID) })
+	return anomalies
+}
+
+func (t *runTracker) station(stationID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.stats.StationsFailed++
+		t.stats.FailedStations = append(t.stats.FailedStations, stationID)
+		return
+	}
+	t.stats.StationsSucceeded++
+}
+
+func (t *runTracker) rows(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats.RowsWritten += n
+}
+
+func (t *runTracker) snapshot() domain.SyncRunStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	stats.FailedStations = slices.Clone(t.stats.FailedStations)
+	slices.Sort(stats.FailedStations)
+	return stats
+}
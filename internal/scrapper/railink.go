@@ -0,0 +1,66 @@
+package scrapper
+
+import (
+	"fmt"
+	"time"
+
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+)
+
+// railinkSource returns the Airport Rail Link source, or nil when it is
+// disabled.
+func railinkSource(cfg config.RailinkConfig) *tripSource {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	return &tripSource{
+		name:     "railink",
+		endpoint: cfg.Endpoint,
+		only:     cfg.Stations,
+		schedules: func(trips []tripRecord, names map[string]string, day time.Time) (map[string][]domain.Schedule, []error) {
+			return railinkSchedules(cfg, trips, names, day)
+		},
+	}
+}
+
+// railinkSchedules converts trips into departures by station, on day. Each
+// departure carries the fares to the stops after it. Trips that cannot be
+// read are returned as errors and left out.
+func railinkSchedules(cfg config.RailinkConfig, trips []tripRecord, names map[string]string, day time.Time) (map[string][]domain.Schedule, []error) {
+	departures := make(map[string][]domain.Schedule)
+	var rejected []error
+	for _, trip := range trips {
+		stops, err := tripStops(trip, day)
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("train %s: %w", trip.TrainID, err))
+			continue
+		}
+
+		origin, dest := stops[0], stops[len(stops)-1]
+		route := stopName(names, origin.stationID) + "-" + stopName(names, dest.stationID)
+		for i, stop := range stops[:len(stops)-1] {
+			fares := make(map[string]int, len(stops)-i-1)
+			for _, later := range stops[i+1:] {
+				fares[later.stationID] = cfg.Fare(stop.stationID, later.stationID)
+			}
+			departures[stop.stationID] = append(departures[stop.stationID], domain.Schedule{
+				ID:                   fmt.Sprintf("sc_railink_%s_%s", stop.stationID, trip.TrainID),
+				StationID:            stop.stationID,
+				StationOriginID:      origin.stationID,
+				StationDestinationID: dest.stationID,
+				TrainID:              trip.TrainID,
+				Line:                 cfg.Line,
+				Route:                route,
+				DepartsAt:            stop.at,
+				ArrivesAt:            dest.at,
+				ServiceType:          domain.ServiceAirport,
+				Metadata: domain.ScheduleMetadata{
+					Origin: domain.ScheduleOrigin{Color: cfg.Color},
+					Fares:  fares,
+				},
+			})
+		}
+	}
+	return departures, rejected
+}
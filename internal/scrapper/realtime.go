@@ -0,0 +1,174 @@
+package scrapper
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+
+	"go.uber.org/zap"
+)
+
+// positionStaleAfter is how long a train position survives without being refreshed
+// before it is dropped, e.g. once the train has finished its trip.
+const positionStaleAfter = 10 * time.Minute
+
+func (s *Scraper) pollRealtime(ctx context.Context) {
+	if s.config.KRLRealtimeEndpoint == "" {
+		s.logger.Info("Realtime endpoint not configured, realtime polling disabled")
+		return
+	}
+
+	s.logger.Info("Starting realtime polling",
+		zap.String("endpoint", s.config.KRLRealtimeEndpoint),
+		zap.Duration("interval", s.config.RealtimePollInterval),
+	)
+
+	ticker := time.NewTicker(s.config.RealtimePollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !s.realtimePaused.Load() {
+			s.syncRealtime(ctx)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SetRealtimePaused stops or resumes realtime polling. Stored positions age
+// out while it is paused.
+func (s *Scraper) SetRealtimePaused(paused bool) {
+	if s.realtimePaused.Swap(paused) != paused {
+		s.logger.Info("Realtime polling pause changed", zap.Bool("paused", paused))
+	}
+}
+
+func (s *Scraper) syncRealtime(ctx context.Context) {
+	data, err := s.fetch(s.config.KRLRealtimeEndpoint)
+	if err != nil {
+		s.logger.Warn("Failed to fetch realtime positions", zap.Error(err))
+		return
+	}
+
+	var resp struct {
+		Data []struct {
+			TrainID     string          `json:"train_id"`
+			StaID       string          `json:"sta_id"`
+			NextStaID   string          `json:"next_sta_id"`
+			Lat         json.Number     `json:"lat"`
+			Lng         json.Number     `json:"lng"`
+			Delay       json.RawMessage `json:"delay"`
+			Status      string          `json:"status"`
+			LastUpdated string          `json:"last_updated"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		s.logger.Error("Failed to unmarshal realtime positions", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	positions := make([]domain.TrainPosition, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		if d.TrainID == "" {
+			continue
+		}
+
+		lat, _ := d.Lat.Float64()
+		lng, _ := d.Lng.Float64()
+
+		observedAt := now
+		if t, err := time.Parse(time.RFC3339, d.LastUpdated); err == nil {
+			observedAt = t
+		}
+
+		positions = append(positions, domain.TrainPosition{
+			TrainID:       d.TrainID,
+			StationID:     d.StaID,
+			NextStationID: d.NextStaID,
+			Latitude:      lat,
+			Longitude:     lng,
+			DelayMinutes:  parseDelayMinutes(d.Delay),
+			Status:        d.Status,
+			ObservedAt:    observedAt,
+			UpdatedAt:     now,
+		})
+	}
+
+	if err := s.store.SetTrainPositions(ctx, positions); err != nil {
+		s.logger.Error("Failed to save train positions", zap.Error(err))
+		return
+	}
+	if err := s.store.PruneTrainPositions(ctx, now.Add(-positionStaleAfter)); err != nil {
+		s.logger.Warn("Failed to prune train positions", zap.Error(err))
+	}
+	if err := s.store.AddDelaySamples(ctx, s.newDelaySamples(positions)); err != nil {
+		s.logger.Warn("Failed to record delay samples", zap.Error(err))
+	}
+
+	for _, p := range positions {
+		s.events.Publish(events.Event{
+			Topic: events.TrainTopic(p.TrainID),
+			Type:  events.TypeTrainPosition,
+			Data:  p,
+		})
+	}
+	s.logger.Debug("Synced realtime positions", zap.Int("count", len(positions)))
+}
+
+// parseDelayMinutes accepts the delay either as a number of minutes or as a
+// string such as "5" or "+5 menit".
+func parseDelayMinutes(raw json.RawMessage) int {
+	if len(raw) == 0 {
+		return 0
+	}
+
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return 0
+	}
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(str), "+"))
+	if len(fields) == 0 {
+		return 0
+	}
+	n, _ = strconv.Atoi(fields[0])
+	return n
+}
+
+// newDelaySamples returns one delay sample per train each time it is seen at a
+// new station, so that reliability history isn't dominated by trains that sit
+// at one stop across many polls.
+func (s *Scraper) newDelaySamples(positions []domain.TrainPosition) []domain.DelaySample {
+	var samples []domain.DelaySample
+	seen := make(map[string]string, len(positions))
+	for _, p := range positions {
+		seen[p.TrainID] = p.StationID
+		if p.StationID == "" || s.lastSampledStation[p.TrainID] == p.StationID {
+			continue
+		}
+		samples = append(samples, domain.DelaySample{
+			TrainID:      p.TrainID,
+			StationID:    p.StationID,
+			Source:       domain.DelaySourceRealtime,
+			DelayMinutes: p.DelayMinutes,
+			ObservedAt:   p.ObservedAt,
+		})
+	}
+	// Only trains still in service are remembered.
+	s.lastSampledStation = seen
+	return samples
+}
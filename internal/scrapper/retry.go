@@ -0,0 +1,186 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	retryBaseDelay   = 5 * time.Minute
+	maxRetryAttempts = 3
+)
+
+// StationFailure describes a station whose schedule could not be synced.
+type StationFailure struct {
+	StationID   string    `json:"station_id"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	LastAttempt time.Time `json:"last_attempt"`
+	// NextRetry is when the station will be attempted again, either by an
+	// automatic retry or by the next scheduled sync.
+	NextRetry time.Time `json:"next_retry"`
+}
+
+// StationFailure reports whether the last sync attempt for stationID failed.
+func (s *Scraper) StationFailure(stationID string) (StationFailure, bool) {
+	s.failMu.RLock()
+	defer s.failMu.RUnlock()
+	f, ok := s.failures[stationID]
+	return f, ok
+}
+
+// FailedStations returns every station currently in a failed state.
+func (s *Scraper) FailedStations() []StationFailure {
+	s.failMu.RLock()
+	defer s.failMu.RUnlock()
+
+	failures := make([]StationFailure, 0, len(s.failures))
+	for _, f := range s.failures {
+		failures = append(failures, f)
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].StationID < failures[j].StationID })
+	return failures
+}
+
+func (s *Scraper) recordStationResult(stationID string, err error) {
+	s.failMu.Lock()
+	defer s.failMu.Unlock()
+
+	if err == nil {
+		delete(s.failures, stationID)
+		return
+	}
+
+	now := time.Now()
+	f := s.failures[stationID]
+	f.StationID = stationID
+	f.Error = err.Error()
+	f.Attempts++
+	f.LastAttempt = now
+	if f.Attempts <= maxRetryAttempts {
+		// 5m, 10m, 20m, ...
+		f.NextRetry = now.Add(retryBaseDelay << (f.Attempts - 1))
+	} else {
+		f.NextRetry = s.nextSync(now)
+	}
+	s.failures[stationID] = f
+}
+
+// scheduleRetry arms a timer for the earliest pending automatic retry, if any.
+func (s *Scraper) scheduleRetry() {
+	s.failMu.Lock()
+	defer s.failMu.Unlock()
+
+	var next time.Time
+	for _, f := range s.failures {
+		if f.Attempts > maxRetryAttempts {
+			continue
+		}
+		if next.IsZero() || f.NextRetry.Before(next) {
+			next = f.NextRetry
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+
+	if s.retryTimer != nil {
+		s.retryTimer.Stop()
+	}
+	delay := time.Until(next)
+	s.retryTimer = time.AfterFunc(delay, s.retryFailedStations)
+	s.logger.Info("Scheduled retry for failed stations", zap.Duration("in", delay))
+}
+
+// retryFailedStations re-syncs stations whose retry is due.
+func (s *Scraper) retryFailedStations() {
+	if s.paused.Load() {
+		s.logger.Warn("Syncs are paused for maintenance, postponing failed station retry")
+		time.AfterFunc(time.Minute, s.retryFailedStations)
+		return
+	}
+	unlock, ok := s.trySyncLock()
+	if !ok {
+		s.logger.Warn("Sync in progress, postponing failed station retry")
+		time.AfterFunc(time.Minute, s.retryFailedStations)
+		return
+	}
+	defer unlock()
+
+	now := time.Now()
+	var due []string
+	for _, f := range s.FailedStations() {
+		if f.Attempts <= maxRetryAttempts && !f.NextRetry.After(now) {
+			due = append(due, f.StationID)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	stations, err := s.store.GetStations(context.Background())
+	if err != nil {
+		s.logger.Error("Failed to load stations, postponing failed station retry", zap.Error(err))
+		time.AfterFunc(time.Minute, s.retryFailedStations)
+		return
+	}
+	stationNameMap := stationNames(stations)
+
+	s.logger.Info("Retrying failed stations", zap.Strings("stations", due))
+	for _, stationID := range due {
+		err := s.syncScheduleForStation(stationID, stationNameMap)
+		s.recordStationResult(stationID, err)
+	}
+
+	// Retried stations belong to the last run, so refresh its snapshot.
+	if s.runID != 0 {
+		if err := s.store.SnapshotSchedules(context.Background(), s.runID); err != nil {
+			s.logger.Error("Failed to update sync run snapshot", zap.Int64("run", s.runID), zap.Error(err))
+		}
+	}
+
+	s.scheduleRetry()
+}
+
+// Errors returned by SyncStation when it cannot run.
+var (
+	ErrSyncPaused     = errors.New("syncs are paused for maintenance")
+	ErrSyncInProgress = errors.New("a sync is already in progress")
+)
+
+// SyncStation re-syncs one station's schedule on demand. Like a retry, the
+// station's schedules join the last run and its snapshot is refreshed.
+func (s *Scraper) SyncStation(stationID string) error {
+	if s.paused.Load() {
+		return ErrSyncPaused
+	}
+	unlock, ok := s.trySyncLock()
+	if !ok {
+		return ErrSyncInProgress
+	}
+	defer unlock()
+
+	stations, err := s.store.GetStations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Syncing station on demand", zap.String("station", stationID))
+	err = s.syncScheduleForStation(stationID, stationNames(stations))
+	s.recordStationResult(stationID, err)
+	if err != nil {
+		s.scheduleRetry()
+		return err
+	}
+
+	if s.runID != 0 {
+		if err := s.store.SnapshotSchedules(context.Background(), s.runID); err != nil {
+			s.logger.Error("Failed to update sync run snapshot", zap.Int64("run", s.runID), zap.Error(err))
+		}
+	}
+	return nil
+}
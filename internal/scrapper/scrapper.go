@@ -1,17 +1,26 @@
 package scrapper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"llm-router/internal/calendar"
 	"llm-router/internal/config"
+	"llm-router/internal/cron"
+	"llm-router/internal/domain"
+	"llm-router/internal/events"
+	"llm-router/internal/geocode"
 	"llm-router/internal/store"
 
 	"go.uber.org/zap"
@@ -20,12 +29,81 @@ import (
 type Scraper struct {
 	config *config.Config
 	store  *store.Store
+	events *events.Hub
 	logger *zap.Logger
 	client *http.Client
 	mu     sync.RWMutex
+
+	// limiter paces requests to the upstream.
+	limiter *upstreamLimiter
+
+	// geocoder locates stations for journey planning; nil disables it.
+	geocoder geocode.Geocoder
+
+	// calendar tags each sync's schedules with the service day it ran on.
+	calendar *calendar.Calendar
+
+	// latency tracks upstream latency for the sync currently holding mu.
+	latency *latencyTracker
+
+	// runID is the sync run that schedule writes are stamped with. It is
+	// only accessed while holding mu.
+	runID int64
+
+	// lineSizes is the station count of each line as of the last line sync,
+	// used to classify services. It is only accessed while holding mu.
+	lineSizes map[string]int
+
+	// sources are the upstreams outside KRL whose trips are merged into
+	// station timetables.
+	sources []*tripSource
+
+	// quality counts rejected upstream records for the current sync.
+	quality qualityTracker
+
+	// run counts the current sync's stations and rows for the run history.
+	run runTracker
+
+	// parser converts upstream records into the schedules that are stored.
+	// shadowParser, when set, is run alongside it for comparison only.
+	parser       scheduleParser
+	shadowParser scheduleParser
+	shadow       shadowTracker
+
+	statusMu sync.RWMutex
+	status   SyncStatus
+
+	// lastSampledStation is owned by the realtime polling goroutine.
+	lastSampledStation map[string]string
+
+	failMu     sync.RWMutex
+	failures   map[string]StationFailure
+	retryTimer *time.Timer
+
+	// settingsMu guards the settings Reconfigure can change while running.
+	// kaiToken is the token in use, which may have been refreshed since
+	// configToken was configured. reschedule wakes scheduleSyncs when the
+	// sync schedule changes.
+	settingsMu   sync.RWMutex
+	kaiToken     string
+	configToken  string
+	syncSchedule cron.Schedule
+	reschedule   chan struct{}
+
+	// refreshMu serializes KAI token refreshes.
+	refreshMu sync.Mutex
+
+	// paused skips syncs while the API is in maintenance mode.
+	paused atomic.Bool
+
+	// realtimePaused skips realtime polls while the feature is turned off.
+	realtimePaused atomic.Bool
+
+	// loops tracks the goroutines started by Start.
+	loops sync.WaitGroup
 }
 
-func NewScraper(cfg *config.Config, s *store.Store, logger *zap.Logger) *Scraper {
+func NewScraper(cfg *config.Config, s *store.Store, hub *events.Hub, geo geocode.Geocoder, cal *calendar.Calendar, logger *zap.Logger) *Scraper {
 	transport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   60 * time.Second,
@@ -43,84 +121,345 @@ func NewScraper(cfg *config.Config, s *store.Store, logger *zap.Logger) *Scraper
 			logger.Info("Using SOCKS5 proxy", zap.String("proxy", cfg.Socks5Proxy))
 		}
 	}
-	
+
 	if cfg.KAIToken != "" {
 		logger.Info("KAI Token configured", zap.Int("length", len(cfg.KAIToken)))
 	} else {
 		logger.Warn("KAI Token is missing or empty")
 	}
 
-	return &Scraper{
-		config: cfg,
-		store:  s,
-		logger: logger,
+	syncSchedule, err := cfg.SyncSchedule()
+	if err != nil {
+		logger.Error("Invalid sync schedule, syncing daily at the sync time", zap.Error(err))
+		syncSchedule = cron.Schedule{cron.Daily(cfg.SyncTime.Hour, cfg.SyncTime.Minute)}
+	}
+
+	parser, ok := scheduleParsers[cfg.ScheduleParser]
+	if !ok {
+		logger.Error("Unknown schedule parser, using default",
+			zap.String("parser", cfg.ScheduleParser),
+			zap.String("default", defaultParser),
+			zap.Strings("available", ParserNames()),
+		)
+		parser = scheduleParsers[defaultParser]
+	}
+
+	var shadowParser scheduleParser
+	shadowName := cfg.Shadow.Parser
+	if shadowName == "" {
+		shadowName = cfg.ScheduleParser
+	}
+	if cfg.Shadow.Enabled() {
+		if shadowParser, ok = scheduleParsers[shadowName]; ok {
+			logger.Info("Shadow sync enabled", zap.String("parser", shadowName), zap.String("endpoint", cfg.Shadow.EndpointBaseURL))
+		} else {
+			logger.Error("Unknown shadow parser, shadow sync disabled",
+				zap.String("parser", shadowName),
+				zap.Strings("available", ParserNames()),
+			)
+		}
+	}
+
+	scr := &Scraper{
+		config:       cfg,
+		store:        s,
+		events:       hub,
+		geocoder:     geo,
+		calendar:     cal,
+		logger:       logger,
+		parser:       parser,
+		shadowParser: shadowParser,
+		failures:     make(map[string]StationFailure),
+		kaiToken:     cfg.KAIToken,
+		configToken:  cfg.KAIToken,
+		syncSchedule: syncSchedule,
+		reschedule:   make(chan struct{}, 1),
+		limiter:      newUpstreamLimiter(cfg.Scrape),
 		client: &http.Client{
 			Transport: transport,
 			Timeout:   120 * time.Second,
 		},
 	}
+	scr.sources = scr.tripSources()
+	scr.loadStoredToken()
+	scr.paused.Store(cfg.Maintenance.Enabled)
+	scr.shadow.report = ShadowReport{Parser: shadowName, Endpoint: cfg.Shadow.EndpointBaseURL, Samples: []ShadowMismatch{}}
+	return scr
 }
 
-func (s *Scraper) Start() {
+// Start runs the initial sync if the database is empty and starts the sync
+// scheduler, realtime polling and announcement polling, which run until ctx
+// is done.
+func (s *Scraper) Start(ctx context.Context) {
+	s.loadLineSizes()
+	if s.config.Capture.Replay {
+		s.logger.Warn("Replay mode is on, syncs read captured responses instead of the upstream")
+	}
+	if err := s.store.InterruptSyncRuns(ctx); err != nil {
+		s.logger.Warn("Failed to close out interrupted sync runs", zap.Error(err))
+	}
+
 	// Check if we have data
-	if s.store.HasStations() {
+	hasStations, err := s.store.HasStations(ctx)
+	if err != nil {
+		s.logger.Error("Failed to check for existing data", zap.Error(err))
+	}
+	switch {
+	case hasStations:
 		s.logger.Info("Data exists, skipping initial sync")
-	} else {
+	case s.config.BootstrapSnapshotURL != "":
+		// Serve the snapshot right away; the sync then refreshes it once the
+		// upstream is reachable, and a failed sync keeps the imported data.
+		go func() {
+			if err := s.bootstrap(); err != nil {
+				s.logger.Error("Failed to import bootstrap snapshot", zap.Error(err))
+			}
+			s.logger.Info("Performing initial sync")
+			s.SyncAll(domain.SyncTriggerStartup)
+		}()
+	default:
 		s.logger.Info("No data found, performing initial sync")
-		go s.SyncAll()
+		go s.SyncAll(domain.SyncTriggerStartup)
 	}
 
-	go s.scheduleDailySync()
+	s.loops.Add(3)
+	go func() {
+		defer s.loops.Done()
+		s.scheduleSyncs(ctx)
+	}()
+	go func() {
+		defer s.loops.Done()
+		s.pollRealtime(ctx)
+	}()
+	go func() {
+		defer s.loops.Done()
+		s.pollAnnouncements(ctx)
+	}()
 }
 
-func (s *Scraper) SyncAll() {
+// Stop waits for the loops started by Start to exit, which they do once its
+// context is done, and for a running sync to finish, so the store can be
+// closed safely. It keeps the sync lock, so no sync starts afterwards.
+func (s *Scraper) Stop(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.mu.Lock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for running sync: %w", ctx.Err())
+	}
+}
+
+// SyncAll runs a full sync and records it in the run history as started by
+// trigger, one of the domain.SyncTrigger values. It does nothing while syncs
+// are paused or another sync is running.
+func (s *Scraper) SyncAll(trigger string) {
+	if s.paused.Load() {
+		s.logger.Warn("Syncs are paused for maintenance, skipping")
+		return
+	}
+
 	// Prevent concurrent syncs
-	if !s.mu.TryLock() {
+	unlock, ok := s.trySyncLock()
+	if !ok {
 		s.logger.Warn("Sync already in progress, skipping")
 		return
 	}
-	defer s.mu.Unlock()
+	defer unlock()
+
+	runID, err := s.store.StartSyncRun(context.Background(), trigger)
+	if err != nil {
+		s.logger.Error("Failed to start sync run", zap.Error(err))
+		s.markSyncFinished(err)
+		return
+	}
+	s.runID = runID
+	s.quality.reset()
+	s.run.reset()
+	s.shadow.reset()
+
+	s.markSyncStarted(runID)
+	s.events.Publish(events.Event{Topic: events.TopicSync, Type: events.TypeSyncStarted})
+
+	// A failed station sync leaves the previous list in place, so the
+	// remaining steps still run against it.
+	err = errors.Join(
+		s.syncStations(),
+		s.syncStationLocations(),
+		s.syncSchedules(),
+		s.syncLines(),
+	)
+	if err != nil {
+		s.logger.Error("Sync finished with errors", zap.Error(err))
+	}
+	s.checkIntegrity(runID)
+
+	if err := s.store.FinishSyncRun(context.Background(), runID, s.run.snapshot(), err); err != nil {
+		s.logger.Error("Failed to record sync run", zap.Int64("run", runID), zap.Error(err))
+	}
+	if _, err := s.store.PruneSyncRuns(context.Background(), s.config.SyncRunRetention); err != nil {
+		s.logger.Warn("Failed to prune old sync runs", zap.Error(err))
+	}
+	s.pruneScheduleHistory()
+
+	s.markSyncFinished(err)
+	var run any
+	if r, err := s.store.GetSyncRun(context.Background(), runID); err != nil {
+		s.logger.Warn("Failed to load finished sync run", zap.Int64("run", runID), zap.Error(err))
+	} else {
+		run = r
+	}
+	s.events.Publish(events.Event{Topic: events.TopicSync, Type: events.TypeSyncCompleted, Data: run})
+	if anomalies := s.run.takeAnomalies(); len(anomalies) > 0 {
+		s.events.Publish(events.Event{Topic: events.TopicAnomaly, Type: events.TypeScheduleAnomalies, Data: anomalies})
+	}
+}
+
+// pruneScheduleHistory drops the timetables of service dates older than the
+// configured history.
+func (s *Scraper) pruneScheduleHistory() {
+	days := s.config.ServiceDates.HistoryDays
+	before := time.Now().In(calendar.Jakarta).AddDate(0, 0, 1-days).Format(calendar.DateLayout)
+	n, err := s.store.PruneScheduleHistory(context.Background(), before)
+	if err != nil {
+		s.logger.Warn("Failed to prune schedule history", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		s.logger.Info("Pruned schedule history", zap.String("before", before), zap.Int64("rows", n))
+	}
+}
 
-	s.syncStations()
-	s.syncSchedules()
+// syncLockName is the store lock held while schedules are written.
+const syncLockName = "sync"
+
+// trySyncLock takes the sync lock of this instance and the store's, so that
+// instances sharing a database do not sync at the same time. It reports
+// false when either is held.
+func (s *Scraper) trySyncLock() (unlock func(), ok bool) {
+	if !s.mu.TryLock() {
+		return nil, false
+	}
+	release, ok, err := s.store.TryLock(context.Background(), syncLockName)
+	if err != nil {
+		s.logger.Error("Failed to take the shared sync lock", zap.Error(err))
+	}
+	if !ok {
+		s.mu.Unlock()
+		return nil, false
+	}
+	return func() {
+		release()
+		s.mu.Unlock()
+	}, true
 }
 
-func (s *Scraper) scheduleDailySync() {
+// scheduleSyncs runs a full sync whenever the sync schedule fires, until ctx
+// is done.
+func (s *Scraper) scheduleSyncs(ctx context.Context) {
 	for {
-		now := time.Now()
+		target := s.nextSync(time.Now())
+		if target.IsZero() {
+			s.logger.Warn("Sync schedule never fires, no sync scheduled", zap.Stringer("schedule", s.currentSchedule()))
+			select {
+			case <-s.reschedule:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		duration := time.Until(target)
+		s.logger.Info("Scheduled next sync",
+			zap.Duration("in", duration),
+			zap.Time("target_jakarta", target),
+			zap.Times("planned", s.plannedSyncs(time.Now())),
+		)
+
+		timer := time.NewTimer(duration)
+		select {
+		case <-timer.C:
+			s.logger.Info("Executing scheduled sync")
+			s.SyncAll(domain.SyncTriggerScheduled)
+		case <-s.reschedule:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
 
-		// Load Jakarta location
-		// Using FixedZone as a fallback if IANA DB is missing on host,
-		// but typically time.LoadLocation("Asia/Jakarta") works safely or returns UTC.
-		// UTC+7 for WIB
-		loc := time.FixedZone("Asia/Jakarta", 7*60*60)
+// SetPaused pauses or resumes syncs, so a planned migration does not race
+// with writes from a scheduled sync. A sync already running is not stopped.
+func (s *Scraper) SetPaused(paused bool) {
+	if s.paused.Swap(paused) != paused {
+		s.logger.Info("Sync pause changed", zap.Bool("paused", paused))
+	}
+}
 
-		// Current time in Jakarta
-		nowJakarta := now.In(loc)
+// Reconfigure applies the settings that can change without a restart: the
+// KAI token and the sync schedule. The log level is applied by the caller.
+func (s *Scraper) Reconfigure(cfg *config.Config) {
+	schedule, err := cfg.SyncSchedule()
+	if err != nil {
+		s.logger.Error("Invalid sync schedule, keeping the current one", zap.Error(err))
+		schedule = s.currentSchedule()
+	}
 
-		// Target: 5 AM today
-		target := time.Date(nowJakarta.Year(), nowJakarta.Month(), nowJakarta.Day(), 5, 0, 0, 0, loc)
+	s.settingsMu.Lock()
+	tokenChanged := cfg.KAIToken != s.configToken
+	scheduleChanged := schedule.String() != s.syncSchedule.String()
+	if tokenChanged {
+		s.kaiToken, s.configToken = cfg.KAIToken, cfg.KAIToken
+	}
+	s.syncSchedule = schedule
+	s.settingsMu.Unlock()
 
-		// If 5 AM has passed, set target for tomorrow
-		if nowJakarta.After(target) {
-			target = target.Add(24 * time.Hour)
+	if tokenChanged {
+		s.logger.Info("KAI Token updated", zap.Int("length", len(cfg.KAIToken)))
+	}
+	if scheduleChanged {
+		s.logger.Info("Sync schedule updated", zap.Stringer("schedule", schedule))
+		select {
+		case s.reschedule <- struct{}{}:
+		default:
 		}
+	}
+}
 
-		duration := target.Sub(nowJakarta)
-		s.logger.Info("Scheduled next sync", zap.Duration("in", duration), zap.Time("target_jakarta", target))
+func (s *Scraper) currentToken() string {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.kaiToken
+}
 
-		time.Sleep(duration)
+func (s *Scraper) currentSchedule() cron.Schedule {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.syncSchedule
+}
 
-		s.logger.Info("Executing scheduled sync")
-		s.SyncAll()
-	}
+// plannedSyncCount is how many upcoming syncs are logged and reported in the
+// sync status.
+const plannedSyncCount = 5
+
+// nextSync returns the next scheduled sync after now, in Jakarta time, or the
+// zero time if the schedule never fires.
+func (s *Scraper) nextSync(now time.Time) time.Time {
+	return s.currentSchedule().Next(now.In(calendar.Jakarta))
 }
 
-func (s *Scraper) runLoop() {
-	// Deprecated in favor of scheduleDailySync
+// plannedSyncs returns the next plannedSyncCount scheduled syncs after now.
+func (s *Scraper) plannedSyncs(now time.Time) []time.Time {
+	return s.currentSchedule().Upcoming(now.In(calendar.Jakarta), plannedSyncCount)
 }
 
-// Headers from user's successful browser request
 // Headers from user's successful browser request
 var commonHeaders = map[string]string{
 	"User-Agent":         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36 Edg/143.0.0.0",
@@ -138,22 +477,43 @@ var commonHeaders = map[string]string{
 	"Sec-Fetch-Site":     "cross-site",
 }
 
-func (s *Scraper) updateSession() error {
-	// No-op for now
-	return nil
+// fetch GETs url from the upstream. When the upstream rejects the KAI token
+// and refreshing is configured, the token is refreshed and the request is
+// retried once. A request answered with 429 is retried once the limiter's
+// pause is over.
+func (s *Scraper) fetch(url string) ([]byte, error) {
+	token := s.currentToken()
+	body, status, err := s.fetchWithToken(url, token)
+	for attempt := 0; status == http.StatusTooManyRequests && attempt < maxThrottledRetries; attempt++ {
+		body, status, err = s.fetchWithToken(url, token)
+	}
+	if (status == http.StatusUnauthorized || status == http.StatusForbidden) && s.config.KAIAuth.Enabled() {
+		s.logger.Warn("KAI token rejected, refreshing", zap.Int("status", status))
+		fresh, refreshErr := s.refreshToken(token)
+		if refreshErr != nil {
+			return nil, fmt.Errorf("%w (token refresh failed: %v)", err, refreshErr)
+		}
+		body, _, err = s.fetchWithToken(url, fresh)
+	}
+	return body, err
 }
 
-func (s *Scraper) fetch(url string) ([]byte, error) {
+// fetchWithToken GETs url with token, returning the response status along
+// with any error. In replay mode the captured response is returned instead.
+func (s *Scraper) fetchWithToken(url, token string) ([]byte, int, error) {
+	if s.config.Capture.Replay {
+		return s.replayFetch(url)
+	}
+
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	for k, v := range commonHeaders {
 		req.Header.Set(k, v)
 	}
 
-	token := s.config.KAIToken
 	if token != "" {
 		if !strings.HasPrefix(token, "Bearer ") {
 			token = "Bearer " + token
@@ -161,21 +521,36 @@ func (s *Scraper) fetch(url string) ([]byte, error) {
 		req.Header.Set("Authorization", token)
 	}
 
+	s.limiter.wait()
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		pause := s.limiter.throttled(retryAfter(resp.Header))
+		s.logger.Warn("Upstream is throttling requests, pausing", zap.String("url", url), zap.Duration("pause", pause))
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+		s.capture(url, resp.StatusCode, body)
+		return nil, resp.StatusCode, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return io.ReadAll(resp.Body)
+	s.limiter.succeeded()
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		s.capture(url, resp.StatusCode, body)
+	}
+	return body, resp.StatusCode, err
 }
 
 func (s *Scraper) fetchWithPreflight(url string) ([]byte, error) {
+	if s.config.Capture.Replay {
+		return s.fetch(url)
+	}
+
 	// 1. Send OPTIONS request
 	reqOptions, err := http.NewRequest("OPTIONS", url, nil)
 	if err != nil {
@@ -188,6 +563,7 @@ func (s *Scraper) fetchWithPreflight(url string) ([]byte, error) {
 	reqOptions.Header.Set("Access-Control-Request-Method", "GET")
 	reqOptions.Header.Set("Access-Control-Request-Headers", "authorization,content-type")
 
+	s.limiter.wait()
 	respOptions, err := s.client.Do(reqOptions)
 	if err != nil {
 		s.logger.Warn("Preflight OPTIONS request failed", zap.Error(err))
@@ -203,13 +579,13 @@ func (s *Scraper) fetchWithPreflight(url string) ([]byte, error) {
 	return s.fetch(url)
 }
 
-func (s *Scraper) syncStations() {
+func (s *Scraper) syncStations() error {
 	s.logger.Info("Syncing stations...")
 	url := fmt.Sprintf("%s/krl-station", s.config.KRLEndpointBaseURL)
 	data, err := s.fetch(url)
 	if err != nil {
 		s.logger.Error("Failed to fetch stations", zap.Error(err))
-		return
+		return fmt.Errorf("fetch stations: %w", err)
 	}
 
 	var resp struct {
@@ -223,10 +599,10 @@ func (s *Scraper) syncStations() {
 
 	if err := json.Unmarshal(data, &resp); err != nil {
 		s.logger.Error("Failed to unmarshal stations", zap.Error(err))
-		return
+		return fmt.Errorf("unmarshal stations: %w", err)
 	}
 
-	var stations []store.Station
+	var stations []domain.Station
 	for _, d := range resp.Data {
 		// Filter WIL stations
 		if len(d.StaID) >= 3 && d.StaID[:3] == "WIL" {
@@ -239,14 +615,14 @@ func (s *Scraper) syncStations() {
 			daop = 1
 		}
 
-		stations = append(stations, store.Station{
+		stations = append(stations, domain.Station{
 			UID:  fmt.Sprintf("st_krl_%s", d.StaID),
 			ID:   d.StaID,
 			Name: d.StaName,
-			Type: store.StationTypeKRL,
-			Metadata: store.Metadata{
+			Type: domain.StationTypeKRL,
+			Metadata: domain.Metadata{
 				Active: true,
-				Origin: store.Origin{
+				Origin: domain.Origin{
 					FgEnable: d.FgEnable,
 					Daop:     daop,
 				},
@@ -254,58 +630,77 @@ func (s *Scraper) syncStations() {
 		})
 	}
 
-	// Add hardcoded stations from TS source
-	// Bandara Soekarno Hatta
-	stations = append(stations, store.Station{
-		UID:  "st_krl_bst",
-		ID:   "BST",
-		Name: "BANDARA SOEKARNO HATTA",
-		Type: "KRL",
-		Metadata: store.Metadata{
-			Active: true,
-			Origin: store.Origin{FgEnable: 1, Daop: 1},
-		},
-	})
-	// Cikampek
-	stations = append(stations, store.Station{
-		UID:  "st_krl_ckp",
-		ID:   "CKP",
-		Name: "CIKAMPEK",
-		Type: "LOCAL",
-		Metadata: store.Metadata{
-			Active: true,
-			Origin: store.Origin{FgEnable: 1, Daop: 1},
-		},
-	})
-	// Purwakarta
-	stations = append(stations, store.Station{
-		UID:  "st_krl_pwk",
-		ID:   "PWK",
-		Name: "PURWAKARTA",
-		Type: "LOCAL",
-		Metadata: store.Metadata{
-			Active: true,
-			Origin: store.Origin{FgEnable: 1, Daop: 2},
-		},
-	})
+	listed := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		listed[st.ID] = true
+	}
+
+	// Supplements add the stations the upstream leaves out, such as the
+	// airport and the local line; those it lists itself are kept as listed.
+	supplements, err := s.supplementStations()
+	if err != nil {
+		s.logger.Error("Failed to load station supplements", zap.Error(err))
+		return err
+	}
+	for _, st := range supplements {
+		if !listed[st.ID] {
+			listed[st.ID] = true
+			stations = append(stations, st)
+		}
+	}
 
-	s.store.SetStations(stations)
+	// Closed stations stay listed after the upstream drops them, so their
+	// history can still be read and their names resolved. BRT stops are
+	// imported, not synced, and are kept as they are.
+	kept, err := s.store.GetStations(context.Background())
+	if err != nil {
+		s.logger.Error("Failed to load stations", zap.Error(err))
+		return err
+	}
+	for _, st := range kept {
+		if st.Type == domain.StationTypeBRT && !listed[st.ID] {
+			st.Aliases, st.Changes = nil, nil
+			stations = append(stations, st)
+			continue
+		}
+		if !listed[st.ID] && st.Changes != nil && st.Changes.ClosesOn != "" {
+			st.Aliases, st.Changes = nil, nil
+			st.Metadata.Active = false
+			stations = append(stations, st)
+		}
+	}
+
+	if err := s.store.SetStations(context.Background(), stations); err != nil {
+		s.logger.Error("Failed to save stations", zap.Error(err))
+		return err
+	}
 	s.logger.Info("Synced stations", zap.Int("count", len(stations)))
+	return nil
 }
 
-func (s *Scraper) syncSchedules() {
+func (s *Scraper) syncSchedules() error {
 	s.logger.Info("Syncing schedules...")
-	stations := s.store.GetStations()
-
-	// Create Name -> ID map for resolution
-	stationNameMap := make(map[string]string)
-	for _, st := range stations {
-		stationNameMap[st.Name] = st.ID
+	stations, err := s.store.GetStations(context.Background())
+	if err != nil {
+		s.logger.Error("Failed to load stations for schedule sync", zap.Error(err))
+		return err
 	}
 
+	stationNameMap := stationNames(stations)
+
+	// Stations closed today and BRT stops have no timetable to fetch.
+	today := time.Now().In(calendar.Jakarta).Format(calendar.DateLayout)
+	stations = slices.DeleteFunc(stations, func(st domain.Station) bool {
+		return st.Type == domain.StationTypeBRT || (st.Changes != nil && st.Changes.ClosedOn(today))
+	})
+
+	tracker := &latencyTracker{}
+	s.latency = tracker
+	var aborted atomic.Bool
+	s.resetTripSources()
+
 	var wg sync.WaitGroup
-	// Limit concurrency - increased to 50 to speed up significantly
-	sem := make(chan struct{}, 50)
+	sem := make(chan struct{}, s.config.Scrape.Concurrency)
 
 	completed := 0
 	var progressMu sync.Mutex
@@ -318,7 +713,28 @@ func (s *Scraper) syncSchedules() {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			s.syncScheduleForStation(stationID, stationNameMap)
+			var err error
+			delay, abort := s.latencyBudgetAction(tracker)
+			switch {
+			case aborted.Load():
+				err = errLatencyBudgetExceeded
+			case abort:
+				if !aborted.Swap(true) {
+					s.logger.Error("Upstream latency far above budget, aborting sync",
+						zap.Duration("p90", tracker.rollingP90()),
+						zap.Duration("budget", s.config.SyncLatencyBudget),
+					)
+				}
+				err = errLatencyBudgetExceeded
+			default:
+				if delay > 0 {
+					s.logger.Warn("Upstream latency above budget, slowing down", zap.Duration("delay", delay))
+					time.Sleep(delay)
+				}
+				err = s.syncScheduleForStation(stationID, stationNameMap)
+			}
+			s.recordStationResult(stationID, err)
+			s.run.station(stationID, err)
 
 			progressMu.Lock()
 			completed++
@@ -329,109 +745,236 @@ func (s *Scraper) syncSchedules() {
 		}(st.ID)
 	}
 	wg.Wait()
-	s.logger.Info("Synced schedules completed")
+
+	stats := tracker.stats()
+	s.setLastRunLatency(stats, aborted.Load())
+	s.logger.Info("Synced schedules completed",
+		zap.Int("failed", len(s.FailedStations())),
+		zap.Bool("aborted", aborted.Load()),
+		zap.Int64("latency_p50_ms", stats.P50Ms),
+		zap.Int64("latency_p90_ms", stats.P90Ms),
+		zap.Int64("latency_p99_ms", stats.P99Ms),
+	)
+
+	s.scheduleRetry()
+	if aborted.Load() {
+		return errLatencyBudgetExceeded
+	}
+	return nil
+}
+
+// scheduleRecord is a single departure as returned by the KRL schedules endpoint.
+type scheduleRecord struct {
+	TrainID   string `json:"train_id"`
+	KaName    string `json:"ka_name"`
+	RouteName string `json:"route_name"`
+	Dest      string `json:"dest"`
+	TimeEst   string `json:"time_est"`
+	Color     string `json:"color"`
+	DestTime  string `json:"dest_time"`
+
+	// Platform is the departure platform, when the upstream includes one.
+	// Some stations list it as the track instead.
+	Platform string `json:"platform,omitempty"`
+	Track    string `json:"track,omitempty"`
 }
 
-func (s *Scraper) syncScheduleForStation(stationID string, stationNameMap map[string]string) {
-	// s.logger.Debug("Fetching schedule", zap.String("station", stationID))
-	url := fmt.Sprintf("%s/schedules?stationid=%s&timefrom=00:00&timeto=23:00", s.config.KRLEndpointBaseURL, stationID)
-	data, err := s.fetchWithPreflight(url)
+// fetchStationSchedules fetches a station's departures from baseURL for every
+// configured time window and merges them, keeping the first record seen per train.
+// A failure in any window fails the whole station so that a partial day
+// never replaces a complete one.
+func (s *Scraper) fetchStationSchedules(baseURL, stationID string) ([]scheduleRecord, error) {
+	var records []scheduleRecord
+	seen := make(map[string]bool)
+
+	for _, window := range s.config.ScheduleTimeWindows {
+		url := fmt.Sprintf("%s/schedules?stationid=%s&timefrom=%s&timeto=%s",
+			baseURL, stationID, window.From, window.To)
+		data, err := s.fetchWithPreflight(url)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Debug("Fetched schedule data", zap.String("station", stationID), zap.String("window", window.String()), zap.String("data", string(data)))
+
+		var resp struct {
+			Data []scheduleRecord `json:"data"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("unmarshal schedule window %s: %w", window, err)
+		}
+
+		for _, d := range resp.Data {
+			if seen[d.TrainID] {
+				continue
+			}
+			seen[d.TrainID] = true
+			records = append(records, d)
+		}
+	}
+	return records, nil
+}
+
+func (s *Scraper) syncScheduleForStation(stationID string, stationNameMap map[string]string) error {
+	var records []scheduleRecord
+	if !s.servedElsewhere(stationID) {
+		start := time.Now()
+		var err error
+		records, err = s.fetchStationSchedules(s.config.KRLEndpointBaseURL, stationID)
+		if s.latency != nil {
+			s.latency.add(stationID, time.Since(start))
+		}
+		if err != nil {
+			// 404 is common for inactive stations, just log debug or warn
+			s.logger.Warn("Failed to fetch schedule", zap.String("station", stationID), zap.Error(err))
+			return err
+		}
+
+		s.logger.Info("Fetched schedule", zap.String("station", stationID))
+	}
+
+	day := syncDay(time.Now())
+	// Upstream serves the timetable of the current day, so that is the
+	// variant this sync replaces and the date it is kept under.
+	serviceDay := s.calendar.ServiceDay(day)
+	serviceDate := calendar.FormatDate(day)
+
+	schedules, rejected := s.parser(s, stationID, records, stationNameMap, day)
+	for _, r := range rejected {
+		s.rejectRecord(stationID, r)
+	}
+	schedules, merged, err := s.mergeTripSources(stationID, schedules, stationNameMap, day)
 	if err != nil {
-		// 404 is common for inactive stations, just log debug or warn
-		s.logger.Warn("Failed to fetch schedule", zap.String("station", stationID), zap.Error(err))
-		return
+		s.logger.Warn("Failed to fetch trip timetable", zap.String("station", stationID), zap.Error(err))
+		return err
+	}
+	krl := len(schedules)
+	schedules = append(schedules, merged...)
+	now := time.Now()
+	for i := range schedules {
+		schedules[i].UpdatedAt = now
+		schedules[i].RunID = s.runID
+		schedules[i].ServiceDate = serviceDate
+		if i < krl {
+			s.quality.accept()
+			schedules[i].ServiceType = classifyService(schedules[i].Line, s.lineSizes[schedules[i].Line])
+		}
+	}
+	tagServiceDay(schedules, serviceDay)
+	if assignments, err := s.store.GetPlatformAssignments(context.Background(), stationID); err != nil {
+		s.logger.Warn("Failed to load platform assignments", zap.String("station", stationID), zap.Error(err))
+	} else {
+		assignPlatforms(schedules, assignments)
+	}
+	if len(schedules) == 0 {
+		s.checkEmptied(stationID, serviceDay)
+	}
+	if err := s.store.SetSchedules(context.Background(), stationID, serviceDay, serviceDate, schedules); err != nil {
+		s.logger.Error("Failed to save schedules", zap.String("station", stationID), zap.Error(err))
+		return err
 	}
+	s.logger.Info("Saved schedules", zap.String("station", stationID), zap.Int("count", len(schedules)))
+	s.run.rows(len(schedules))
 
-	s.logger.Info("Fetched schedule", zap.String("station", stationID))
-	s.logger.Debug("Fetched schedule data", zap.String("data", string(data)))
+	s.events.Publish(events.Event{
+		Topic: events.StationTopic(stationID),
+		Type:  events.TypeScheduleUpdated,
+		Data:  map[string]interface{}{"station_id": stationID, "count": len(schedules)},
+	})
 
-	var resp struct {
-		Data []struct {
-			TrainID   string `json:"train_id"`
-			KaName    string `json:"ka_name"`
-			RouteName string `json:"route_name"`
-			Dest      string `json:"dest"`
-			TimeEst   string `json:"time_est"`
-			Color     string `json:"color"`
-			DestTime  string `json:"dest_time"`
-		} `json:"data"`
+	if s.shadowEnabled() {
+		s.runShadow(stationID, records, stationNameMap, day, schedules[:krl])
 	}
+	return nil
+}
 
-	if err := json.Unmarshal(data, &resp); err != nil {
+// syncDay returns the Jakarta date a sync at now fetches, as midnight in
+// Jakarta. Upstream serves Jakarta's current day whatever zone the host runs
+// in.
+func syncDay(now time.Time) time.Time {
+	y, m, d := now.In(calendar.Jakarta).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, calendar.Jakarta)
+}
+
+// checkEmptied records an anomaly when a sync is about to leave a station
+// without departures on serviceDay that it had before.
+func (s *Scraper) checkEmptied(stationID, serviceDay string) {
+	before, err := s.store.CountSchedules(context.Background(), stationID, serviceDay)
+	if err != nil {
+		s.logger.Warn("Failed to count previous schedules", zap.String("station", stationID), zap.Error(err))
+		return
+	}
+	if before == 0 {
 		return
 	}
+	s.logger.Warn("Station schedules dropped to zero", zap.String("station", stationID), zap.Int("before", before))
+	s.run.anomaly(domain.ScheduleAnomaly{
+		Kind:       domain.AnomalySchedulesEmptied,
+		StationID:  stationID,
+		ServiceDay: serviceDay,
+		Before:     before,
+	})
+}
 
-	var schedules []store.Schedule
-	for _, d := range resp.Data {
-		// Parse route name to find Origin/Dest IDs
-		parts := strings.Split(d.RouteName, "-")
-		var originName, destName string
-		if len(parts) >= 2 {
-			originName = strings.TrimSpace(parts[0])
-			destName = strings.TrimSpace(parts[1])
-		} else {
-			originName = d.RouteName
-			destName = d.RouteName
+// tagServiceDay marks schedules as the timetable of serviceDay. Weekday IDs
+// keep their original form and other variants are suffixed with their service
+// day, so the same train can be stored once per variant.
+func tagServiceDay(schedules []domain.Schedule, serviceDay string) {
+	for i := range schedules {
+		schedules[i].ServiceDay = serviceDay
+		if serviceDay != domain.ServiceDayWeekday {
+			schedules[i].ID += "_" + serviceDay
 		}
-
-		originName = s.normalizeStationName(originName)
-		destName = s.normalizeStationName(destName)
-
-		// Find IDs from map
-		originID := stationNameMap[originName]
-		destID := stationNameMap[destName]
-
-		schedules = append(schedules, store.Schedule{
-			ID:                   fmt.Sprintf("sc_krl_%s_%s", stationID, d.TrainID),
-			StationID:            stationID,
-			StationOriginID:      originID,
-			StationDestinationID: destID,
-			TrainID:              d.TrainID,
-			Line:                 d.KaName,
-			Route:                d.RouteName,
-			DepartsAt:            s.parseTime(d.TimeEst),
-			ArrivesAt:            s.parseTime(d.DestTime),
-			Metadata: store.ScheduleMetadata{
-				Origin: store.ScheduleOrigin{
-					Color: d.Color,
-				},
-			},
-			UpdatedAt: time.Now(),
-		})
 	}
-	s.store.SetSchedules(stationID, schedules)
-	s.logger.Info("Saved schedules", zap.String("station", stationID), zap.Int("count", len(schedules)))
 }
 
-func (s *Scraper) parseTime(timeStr string) time.Time {
-	// Assuming proper HH:mm format, append to today's date
-	now := time.Now()
-	parsed, err := time.Parse("15:04", timeStr)
-	if err != nil {
-		// Try HH:mm:ss
-		parsed, err = time.Parse("15:04:05", timeStr)
-		if err != nil {
-			return time.Time{}
+// assignPlatforms fills in the platforms the upstream left out from a
+// station's assignments, preferring one for the departure's destination over
+// one for its whole line.
+func assignPlatforms(schedules []domain.Schedule, assignments []domain.PlatformAssignment) {
+	if len(assignments) == 0 {
+		return
+	}
+	type key struct{ line, destination string }
+	platforms := make(map[key]string, len(assignments))
+	for _, a := range assignments {
+		platforms[key{a.Line, a.DestinationID}] = a.Platform
+	}
+	for i := range schedules {
+		sch := &schedules[i]
+		if sch.Metadata.Platform != "" {
+			continue
+		}
+		if p, ok := platforms[key{sch.Line, sch.StationDestinationID}]; ok {
+			sch.Metadata.Platform = p
+		} else {
+			sch.Metadata.Platform = platforms[key{sch.Line, ""}]
 		}
 	}
-	return time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, time.Local)
 }
 
-func (s *Scraper) normalizeStationName(name string) string {
-	switch name {
-	case "TANJUNGPRIUK":
-		return "TANJUNG PRIOK"
-	case "JAKARTAKOTA":
-		return "JAKARTA KOTA"
-	case "KAMPUNGBANDAN":
-		return "KAMPUNG BANDAN"
-	case "TANAHABANG":
-		return "TANAH ABANG"
-	case "PARUNGPANJANG":
-		return "PARUNG PANJANG"
-	case "BANDARASOEKARNOHATTA":
-		return "BANDARA SOEKARNO HATTA"
-	default:
-		return name
+// rejectRecord logs and counts an upstream record that cannot be stored.
+func (s *Scraper) rejectRecord(stationID string, r recordError) {
+	s.quality.reject(timeErrorReason(r.field, r.err))
+	s.logger.Warn("Skipping schedule record with unusable time",
+		zap.String("station", stationID),
+		zap.String("train", r.record.TrainID),
+		zap.String("field", r.field),
+		zap.Error(r.err),
+	)
+}
+
+// stationNames maps station names and aliases, upper-cased, to station IDs
+// for resolving route endpoints. A station's own name wins over another
+// station's alias.
+func stationNames(stations []domain.Station) map[string]string {
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		for _, a := range st.Aliases {
+			names[strings.ToUpper(a.Name)] = st.ID
+		}
+	}
+	for _, st := range stations {
+		names[strings.ToUpper(st.Name)] = st.ID
 	}
+	return names
 }
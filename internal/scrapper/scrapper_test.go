@@ -0,0 +1,30 @@
+package scrapper
+
+import (
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+func TestSyncDayOnUTCHost(t *testing.T) {
+	cal, err := calendar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 22:30 UTC on Sunday is 05:30 WIB on Monday, when the default sync runs.
+	now := time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC)
+	day := syncDay(now)
+
+	if got, want := day, time.Date(2026, 10, 19, 0, 0, 0, 0, calendar.Jakarta); !got.Equal(want) || got.Location() != calendar.Jakarta {
+		t.Errorf("syncDay(%v) = %v, want %v", now, got, want)
+	}
+	if got := cal.ServiceDay(day); got != domain.ServiceDayWeekday {
+		t.Errorf("ServiceDay = %q, want %q", got, domain.ServiceDayWeekday)
+	}
+	if got := calendar.FormatDate(day); got != "2026-10-19" {
+		t.Errorf("service date = %q, want 2026-10-19", got)
+	}
+}
@@ -0,0 +1,61 @@
+package scrapper
+
+import (
+	"context"
+	"strings"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// feederMaxStations is the longest a line can be and still count as a feeder:
+// short shuttles that connect a branch to a main line.
+const feederMaxStations = 4
+
+// servicePatterns classify trips by KA name. The first match wins.
+var servicePatterns = []struct {
+	substr  string
+	service string
+}{
+	{"BANDARA", domain.ServiceAirport},
+	{"AIRPORT", domain.ServiceAirport},
+	{"FEEDER", domain.ServiceFeeder},
+	{"LOKAL", domain.ServiceLocal},
+	{"LOCAL", domain.ServiceLocal},
+}
+
+// classifyService returns the service type of a trip on line, which calls at
+// stations stations end to end (zero when the line is not known yet). The KA
+// name decides when it names the service; otherwise short lines are feeders
+// and everything else is a commuter service.
+func classifyService(line string, stations int) string {
+	name := strings.ToUpper(line)
+	for _, p := range servicePatterns {
+		if strings.Contains(name, p.substr) {
+			return p.service
+		}
+	}
+	if stations > 0 && stations <= feederMaxStations {
+		return domain.ServiceFeeder
+	}
+	return domain.ServiceCommuter
+}
+
+// loadLineSizes reads the station count of each stored line, so schedules
+// synced before the next line sync are classified with the known lines.
+func (s *Scraper) loadLineSizes() {
+	lines, err := s.store.GetLines(context.Background())
+	if err != nil {
+		s.logger.Warn("Failed to load lines for service classification", zap.Error(err))
+		return
+	}
+	sizes := make(map[string]int, len(lines))
+	for _, l := range lines {
+		sizes[l.Name] = l.StationCount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lineSizes = sizes
+}
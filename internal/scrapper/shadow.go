@@ -0,0 +1,201 @@
+package scrapper
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// maxShadowSamples caps the mismatches kept for inspection per sync.
+const maxShadowSamples = 50
+
+// ShadowReport compares the schedules a shadow parser or endpoint would have
+// written during the current or most recent sync with those actually written.
+// Shadow output is never stored.
+type ShadowReport struct {
+	Parser   string `json:"parser"`
+	Endpoint string `json:"endpoint,omitempty"`
+
+	Stations int `json:"stations"`
+	Matched  int `json:"matched"`
+	// Failed counts stations whose shadow fetch failed.
+	Failed int `json:"failed"`
+
+	// Missing schedules were written but not produced in shadow, Extra the
+	// reverse, and Changed were produced by both with differing fields.
+	Missing int            `json:"missing"`
+	Extra   int            `json:"extra"`
+	Changed int            `json:"changed"`
+	Fields  map[string]int `json:"fields,omitempty"`
+
+	Samples []ShadowMismatch `json:"samples"`
+}
+
+type ShadowMismatch struct {
+	StationID  string `json:"station_id"`
+	ScheduleID string `json:"schedule_id"`
+	Kind       string `json:"kind"`
+	Field      string `json:"field,omitempty"`
+	Current    string `json:"current,omitempty"`
+	Shadow     string `json:"shadow,omitempty"`
+}
+
+const (
+	mismatchMissing = "missing"
+	mismatchExtra   = "extra"
+	mismatchChanged = "changed"
+)
+
+type shadowTracker struct {
+	mu     sync.Mutex
+	report ShadowReport
+}
+
+// reset clears the counts for a new sync, keeping what is being compared.
+func (t *shadowTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.report = ShadowReport{Parser: t.report.Parser, Endpoint: t.report.Endpoint, Samples: []ShadowMismatch{}}
+}
+
+func (t *shadowTracker) fail() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.report.Stations++
+	t.report.Failed++
+}
+
+func (t *shadowTracker) add(mismatches []ShadowMismatch) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.report.Stations++
+	if len(mismatches) == 0 {
+		t.report.Matched++
+		return
+	}
+	for _, m := range mismatches {
+		switch m.Kind {
+		case mismatchMissing:
+			t.report.Missing++
+		case mismatchExtra:
+			t.report.Extra++
+		case mismatchChanged:
+			t.report.Changed++
+			if t.report.Fields == nil {
+				t.report.Fields = make(map[string]int)
+			}
+			t.report.Fields[m.Field]++
+		}
+		if len(t.report.Samples) < maxShadowSamples {
+			t.report.Samples = append(t.report.Samples, m)
+		}
+	}
+}
+
+func (t *shadowTracker) snapshot() ShadowReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	report := t.report
+	report.Samples = append([]ShadowMismatch{}, t.report.Samples...)
+	if t.report.Fields != nil {
+		report.Fields = make(map[string]int, len(t.report.Fields))
+		for k, v := range t.report.Fields {
+			report.Fields[k] = v
+		}
+	}
+	return report
+}
+
+// shadowEnabled reports whether syncs run a shadow comparison.
+func (s *Scraper) shadowEnabled() bool {
+	return s.shadowParser != nil
+}
+
+// runShadow produces a station's schedules with the shadow parser, from the
+// shadow endpoint if one is configured, and records how they differ from the
+// schedules that were written.
+func (s *Scraper) runShadow(stationID string, records []scheduleRecord, stationNameMap map[string]string, day time.Time, written []domain.Schedule) {
+	if endpoint := s.config.Shadow.EndpointBaseURL; endpoint != "" {
+		var err error
+		if records, err = s.fetchStationSchedules(endpoint, stationID); err != nil {
+			s.logger.Warn("Shadow fetch failed", zap.String("station", stationID), zap.Error(err))
+			s.shadow.fail()
+			return
+		}
+	}
+
+	shadow, _ := s.shadowParser(s, stationID, records, stationNameMap, day)
+	tagServiceDay(shadow, s.calendar.ServiceDay(day))
+	mismatches := compareSchedules(stationID, written, shadow)
+	s.shadow.add(mismatches)
+	if len(mismatches) > 0 {
+		s.logger.Warn("Shadow sync differs",
+			zap.String("station", stationID),
+			zap.Int("mismatches", len(mismatches)),
+		)
+	}
+}
+
+// compareSchedules lists the differences between the schedules written for a
+// station and those produced in shadow, ignoring bookkeeping fields.
+func compareSchedules(stationID string, current, shadow []domain.Schedule) []ShadowMismatch {
+	byID := make(map[string]domain.Schedule, len(shadow))
+	for _, sch := range shadow {
+		byID[sch.ID] = sch
+	}
+
+	var mismatches []ShadowMismatch
+	for _, cur := range current {
+		sh, ok := byID[cur.ID]
+		if !ok {
+			mismatches = append(mismatches, ShadowMismatch{StationID: stationID, ScheduleID: cur.ID, Kind: mismatchMissing})
+			continue
+		}
+		delete(byID, cur.ID)
+
+		for _, f := range scheduleFields(cur, sh) {
+			if f.current != f.shadow {
+				mismatches = append(mismatches, ShadowMismatch{
+					StationID:  stationID,
+					ScheduleID: cur.ID,
+					Kind:       mismatchChanged,
+					Field:      f.name,
+					Current:    f.current,
+					Shadow:     f.shadow,
+				})
+			}
+		}
+	}
+
+	extra := make([]string, 0, len(byID))
+	for id := range byID {
+		extra = append(extra, id)
+	}
+	sort.Strings(extra)
+	for _, id := range extra {
+		mismatches = append(mismatches, ShadowMismatch{StationID: stationID, ScheduleID: id, Kind: mismatchExtra})
+	}
+	return mismatches
+}
+
+type fieldPair struct {
+	name            string
+	current, shadow string
+}
+
+func scheduleFields(cur, sh domain.Schedule) []fieldPair {
+	return []fieldPair{
+		{"train_id", cur.TrainID, sh.TrainID},
+		{"station_origin_id", cur.StationOriginID, sh.StationOriginID},
+		{"station_destination_id", cur.StationDestinationID, sh.StationDestinationID},
+		{"line", cur.Line, sh.Line},
+		{"route", cur.Route, sh.Route},
+		{"departs_at", cur.DepartsAt.Format(time.RFC3339), sh.DepartsAt.Format(time.RFC3339)},
+		{"arrives_at", cur.ArrivesAt.Format(time.RFC3339), sh.ArrivesAt.Format(time.RFC3339)},
+		{"color", cur.Metadata.Origin.Color, sh.Metadata.Origin.Color},
+	}
+}
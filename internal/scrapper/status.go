@@ -0,0 +1,72 @@
+package scrapper
+
+import (
+	"errors"
+	"time"
+)
+
+var errLatencyBudgetExceeded = errors.New("sync aborted: upstream latency budget exceeded")
+
+// SyncStatus describes the current or most recent sync run.
+type SyncStatus struct {
+	Running        bool             `json:"running"`
+	LastStartedAt  *time.Time       `json:"last_started_at"`
+	LastFinishedAt *time.Time       `json:"last_finished_at"`
+	RunID          int64            `json:"run_id,omitempty"`
+	Aborted        bool             `json:"aborted"`
+	LastError      string           `json:"last_error,omitempty"`
+	Latency        LatencyStats     `json:"latency"`
+	Quality        QualityStats     `json:"quality"`
+	Shadow         *ShadowReport    `json:"shadow,omitempty"`
+	NextSyncAt     time.Time        `json:"next_sync_at"`
+	PlannedSyncs   []time.Time      `json:"planned_syncs"`
+	FailedStations []StationFailure `json:"failed_stations"`
+}
+
+// Status returns a snapshot of the sync state.
+func (s *Scraper) Status() SyncStatus {
+	s.statusMu.RLock()
+	status := s.status
+	s.statusMu.RUnlock()
+
+	status.Quality = s.quality.snapshot()
+	if s.shadowEnabled() {
+		shadow := s.shadow.snapshot()
+		status.Shadow = &shadow
+	}
+	now := time.Now()
+	status.NextSyncAt = s.nextSync(now)
+	status.PlannedSyncs = s.plannedSyncs(now)
+	status.FailedStations = s.FailedStations()
+	return status
+}
+
+func (s *Scraper) markSyncStarted(runID int64) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	now := time.Now()
+	s.status.Running = true
+	s.status.RunID = runID
+	s.status.LastStartedAt = &now
+}
+
+// markSyncFinished records the end of a run and the error, if any, that kept
+// it from completing cleanly.
+func (s *Scraper) markSyncFinished(err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	now := time.Now()
+	s.status.Running = false
+	s.status.LastFinishedAt = &now
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+}
+
+func (s *Scraper) setLastRunLatency(stats LatencyStats, aborted bool) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status.Latency = stats
+	s.status.Aborted = aborted
+}
@@ -0,0 +1,93 @@
+package scrapper
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"llm-router/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultSupplements lists the stations added to the upstream list when no
+// supplement file is configured.
+//
+//go:embed stations.supplement.yaml
+var defaultSupplements []byte
+
+// supplementEntry is a station in a supplement file.
+type supplementEntry struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	Daop int    `yaml:"daop"`
+}
+
+// supplementStations reads the stations to add to the upstream list from
+// the configured supplement file, or the built-in list when there is none.
+// The file is read on every sync, so it can change without a restart. It is
+// YAML, of which JSON is a subset.
+func (s *Scraper) supplementStations() ([]domain.Station, error) {
+	path := s.config.StationSupplements
+	if path == "" {
+		return parseSupplements(defaultSupplements)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read station supplements: %w", err)
+	}
+	stations, err := parseSupplements(data)
+	if err != nil {
+		return nil, fmt.Errorf("station supplements %s: %w", path, err)
+	}
+	return stations, nil
+}
+
+// parseSupplements decodes a supplement file into stations.
+func parseSupplements(data []byte) ([]domain.Station, error) {
+	var entries []supplementEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	stations := make([]domain.Station, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		id := strings.ToUpper(strings.TrimSpace(e.ID))
+		name := strings.TrimSpace(e.Name)
+		switch {
+		case id == "" || name == "":
+			return nil, fmt.Errorf("entry %d: id and name are required", i+1)
+		case seen[id]:
+			return nil, fmt.Errorf("entry %d: station %s is listed twice", i+1, id)
+		}
+		seen[id] = true
+
+		typ := domain.StationType(strings.ToUpper(strings.TrimSpace(e.Type)))
+		switch typ {
+		case "":
+			typ = domain.StationTypeKRL
+		case domain.StationTypeKRL, domain.StationTypeLocal:
+		default:
+			return nil, fmt.Errorf("entry %d: type must be %s or %s", i+1, domain.StationTypeKRL, domain.StationTypeLocal)
+		}
+		daop := e.Daop
+		if daop == 0 {
+			daop = 1
+		}
+
+		stations = append(stations, domain.Station{
+			UID:  "st_krl_" + strings.ToLower(id),
+			ID:   id,
+			Name: name,
+			Type: typ,
+			Metadata: domain.Metadata{
+				Active: true,
+				Origin: domain.Origin{FgEnable: 1, Daop: daop},
+			},
+		})
+	}
+	return stations, nil
+}
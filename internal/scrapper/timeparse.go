@@ -0,0 +1,61 @@
+package scrapper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+)
+
+var (
+	errEmptyTime   = errors.New("empty time")
+	errInvalidTime = errors.New("invalid time")
+)
+
+// maxClockHour bounds the "24:xx" notation used upstream for services that
+// run past midnight; anything beyond the following day is treated as garbage.
+const maxClockHour = 47
+
+// parseClock parses an upstream time of day such as "05:12", "5:12:30" or
+// "24:05" onto day's date, in Jakarta time as upstream publishes it, whatever
+// zone day is in. Hours of 24 and above roll over into the next day.
+// Surrounding whitespace is ignored; anything else that is not a valid
+// HH:MM[:SS] time is reported as errEmptyTime or errInvalidTime.
+func parseClock(raw string, day time.Time) (time.Time, error) {
+	v := strings.TrimSpace(raw)
+	if v == "" {
+		return time.Time{}, errEmptyTime
+	}
+
+	parts := strings.Split(v, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("%w %q", errInvalidTime, raw)
+	}
+
+	var fields [3]int
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || len(p) > 2 || (i > 0 && len(p) != 2) {
+			return time.Time{}, fmt.Errorf("%w %q", errInvalidTime, raw)
+		}
+		fields[i] = n
+	}
+
+	hour, minute, second := fields[0], fields[1], fields[2]
+	if hour > maxClockHour || minute > 59 || second > 59 {
+		return time.Time{}, fmt.Errorf("%w %q", errInvalidTime, raw)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, calendar.Jakarta), nil
+}
+
+// timeErrorReason names a parseClock error for quality metrics.
+func timeErrorReason(field string, err error) string {
+	if errors.Is(err, errEmptyTime) {
+		return "empty_" + field
+	}
+	return "invalid_" + field
+}
@@ -0,0 +1,153 @@
+package scrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"llm-router/internal/store"
+
+	"go.uber.org/zap"
+)
+
+// kaiTokenName is the name refreshed KAI tokens are stored under.
+const kaiTokenName = "kai"
+
+// loadStoredToken switches to a token refreshed by an earlier run, which is
+// newer than the configured one. It does nothing unless refreshing is
+// configured.
+func (s *Scraper) loadStoredToken() {
+	if !s.config.KAIAuth.Enabled() {
+		return
+	}
+	token, obtainedAt, err := s.store.GetAuthToken(context.Background(), kaiTokenName)
+	if errors.Is(err, store.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		s.logger.Warn("Failed to load stored KAI token, using the configured one", zap.Error(err))
+		return
+	}
+
+	s.settingsMu.Lock()
+	s.kaiToken = token
+	s.settingsMu.Unlock()
+	s.logger.Info("Using stored KAI token", zap.Time("obtained_at", obtainedAt), zap.Int("length", len(token)))
+}
+
+// refreshToken obtains a new KAI token after stale was rejected and returns
+// the token to retry with. When another request has already replaced stale,
+// that token is returned without asking the upstream again.
+func (s *Scraper) refreshToken(stale string) (string, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if current := s.currentToken(); current != stale {
+		return current, nil
+	}
+
+	return s.replaceToken()
+}
+
+// ErrTokenRefreshUnconfigured is returned by RotateToken when no token URL
+// is configured.
+var ErrTokenRefreshUnconfigured = errors.New("KAI token refresh is not configured")
+
+// RotateToken replaces the KAI token with a new one from the token URL
+// before the current one is rejected, such as after it leaked.
+func (s *Scraper) RotateToken() error {
+	if !s.config.KAIAuth.Enabled() {
+		return ErrTokenRefreshUnconfigured
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	_, err := s.replaceToken()
+	return err
+}
+
+// replaceToken requests a new token and switches to it. The caller holds
+// refreshMu.
+func (s *Scraper) replaceToken() (string, error) {
+	token, err := s.requestToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.settingsMu.Lock()
+	s.kaiToken = token
+	s.settingsMu.Unlock()
+
+	// The new token works for this run even if it cannot be kept.
+	if err := s.store.SetAuthToken(context.Background(), kaiTokenName, token); err != nil {
+		s.logger.Warn("Failed to store refreshed KAI token", zap.Error(err))
+	}
+	s.logger.Info("Refreshed KAI token", zap.Int("length", len(token)))
+	return token, nil
+}
+
+// requestToken asks the configured token URL for a new token.
+func (s *Scraper) requestToken() (string, error) {
+	auth := s.config.KAIAuth
+
+	var body io.Reader
+	if auth.Username != "" || auth.Password != "" {
+		creds, err := json.Marshal(map[string]string{"username": auth.Username, "password": auth.Password})
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(creds)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, auth.TokenURL, body)
+	if err != nil {
+		return "", fmt.Errorf("request token: %w", err)
+	}
+	req.Header.Set("User-Agent", commonHeaders["User-Agent"])
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("request token: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("request token: status %d: %s", resp.StatusCode, string(data))
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("request token: %w", err)
+	}
+	token, ok := lookupField(doc, auth.TokenField).(string)
+	if !ok || token == "" {
+		return "", fmt.Errorf("request token: no token at %q in response", auth.TokenField)
+	}
+	return token, nil
+}
+
+// lookupField follows a dotted path through decoded JSON objects, returning
+// nil when any step is missing.
+func lookupField(doc interface{}, path string) interface{} {
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = obj[key]
+	}
+	return doc
+}
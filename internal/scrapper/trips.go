@@ -0,0 +1,171 @@
+package scrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"llm-router/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// tripRecord is a trip as listed by the upstreams outside KRL, its stops in
+// travel order with times of day as HH:MM. The last stop only has an
+// arrival, and the others may list one. Line is set by upstreams that carry
+// several lines.
+type tripRecord struct {
+	TrainID string `json:"train_id"`
+	Line    string `json:"line,omitempty"`
+	Stops   []struct {
+		StationID string `json:"station_id"`
+		ArrivesAt string `json:"arrives_at"`
+		DepartsAt string `json:"departs_at"`
+	} `json:"stops"`
+}
+
+// tripSource is an upstream outside KRL that lists whole trips rather than
+// station departures, such as the Airport Rail Link. Its timetable is fetched
+// once per sync, by the first station sync that needs it, and its departures
+// are merged into those KRL lists for each station.
+type tripSource struct {
+	name     string
+	endpoint string
+
+	// only are the stations served by the source alone, whose KRL
+	// timetable is not fetched.
+	only []string
+
+	// schedules converts the source's trips into departures by station.
+	schedules func(trips []tripRecord, names map[string]string, day time.Time) (map[string][]domain.Schedule, []error)
+
+	mu         sync.Mutex
+	departures map[string][]domain.Schedule
+}
+
+// tripSources returns the sources enabled in cfg.
+func (s *Scraper) tripSources() []*tripSource {
+	var sources []*tripSource
+	if src := railinkSource(s.config.Railink); src != nil {
+		sources = append(sources, src)
+	}
+	if src := localTrainSource(s.config.LocalTrains); src != nil {
+		sources = append(sources, src)
+	}
+	return sources
+}
+
+// resetTripSources drops the timetables fetched by the last sync, so the
+// next station sync fetches them again.
+func (s *Scraper) resetTripSources() {
+	for _, src := range s.sources {
+		src.mu.Lock()
+		src.departures = nil
+		src.mu.Unlock()
+	}
+}
+
+// servedElsewhere reports whether stationID is served by a trip source
+// alone, so it has no KRL timetable to fetch.
+func (s *Scraper) servedElsewhere(stationID string) bool {
+	for _, src := range s.sources {
+		if slices.Contains(src.only, stationID) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTripSources replaces what schedules lists of the trains of each trip
+// source at stationID with the source's own departures, and returns them
+// after those kept. A failed fetch fails the station, so that its previous
+// timetable is kept and it is retried.
+func (s *Scraper) mergeTripSources(stationID string, schedules []domain.Schedule, names map[string]string, day time.Time) ([]domain.Schedule, []domain.Schedule, error) {
+	var merged []domain.Schedule
+	for _, src := range s.sources {
+		departures, err := s.tripDepartures(src, stationID, names, day)
+		if err != nil {
+			return nil, nil, err
+		}
+		trains := make(map[string]bool, len(departures))
+		for _, sch := range departures {
+			trains[sch.TrainID] = true
+		}
+		schedules = slices.DeleteFunc(schedules, func(sch domain.Schedule) bool { return trains[sch.TrainID] })
+		merged = append(merged, departures...)
+	}
+	return schedules, merged, nil
+}
+
+// tripDepartures returns the departures of src from stationID on day,
+// fetching its timetable the first time a sync needs it. A failed fetch is
+// tried again by the next station that needs it.
+func (s *Scraper) tripDepartures(src *tripSource, stationID string, names map[string]string, day time.Time) ([]domain.Schedule, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if src.departures == nil {
+		data, err := s.fetch(src.endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", src.name, err)
+		}
+		var resp struct {
+			Data []tripRecord `json:"data"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", src.name, err)
+		}
+
+		departures, rejected := src.schedules(resp.Data, names, day)
+		for _, err := range rejected {
+			s.logger.Warn("Rejected trip", zap.String("source", src.name), zap.Error(err))
+		}
+		src.departures = departures
+		s.logger.Info("Fetched trip timetable", zap.String("source", src.name), zap.Int("trips", len(resp.Data)), zap.Int("stations", len(departures)))
+	}
+	return slices.Clone(src.departures[stationID]), nil
+}
+
+// tripStop is a stop of a trip: its departure, or its arrival at the last
+// stop.
+type tripStop struct {
+	stationID string
+	at        time.Time
+}
+
+// tripStops reads the stops of trip on day. Times that go back are past
+// midnight.
+func tripStops(trip tripRecord, day time.Time) ([]tripStop, error) {
+	if strings.TrimSpace(trip.TrainID) == "" {
+		return nil, fmt.Errorf("missing train id")
+	}
+	if len(trip.Stops) < 2 {
+		return nil, fmt.Errorf("%d stops, want at least 2", len(trip.Stops))
+	}
+
+	stops := make([]tripStop, 0, len(trip.Stops))
+	var prev time.Time
+	for i, st := range trip.Stops {
+		raw := st.DepartsAt
+		if i == len(trip.Stops)-1 || raw == "" {
+			raw = st.ArrivesAt
+		}
+		at, err := parseClock(raw, day)
+		if err != nil {
+			return nil, fmt.Errorf("stop %s: %w", st.StationID, err)
+		}
+		for at.Before(prev) {
+			at = at.AddDate(0, 0, 1)
+		}
+		prev = at
+
+		id := strings.ToUpper(strings.TrimSpace(st.StationID))
+		if id == "" {
+			return nil, fmt.Errorf("stop %d: missing station id", i+1)
+		}
+		stops = append(stops, tripStop{stationID: id, at: at})
+	}
+	return stops, nil
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"llm-router/internal/domain"
+)
+
+// Match ranks, best first: the query starts a station's name, one of its
+// aliases or its ID, or a later word of its name.
+const (
+	rankName = iota
+	rankAlias
+	rankWord
+)
+
+// prefixKey is one way into a station by prefix: a normalized name, alias
+// or word onwards, without spaces.
+type prefixKey struct {
+	text    string
+	station int
+	rank    int
+}
+
+// Prefixes is an immutable index of stations by the prefixes of their names,
+// aliases and IDs, for search-as-you-type. Unlike Index it tolerates no
+// misspellings, but a lookup is a binary search.
+type Prefixes struct {
+	stations []domain.Station
+	keys     []prefixKey
+}
+
+// NewPrefixes indexes each station under its name and aliases from each word
+// on, so "abang" finds "Tanah Abang", and under its ID.
+func NewPrefixes(stations []domain.Station) *Prefixes {
+	p := &Prefixes{stations: stations}
+	for i, st := range stations {
+		p.addWords(i, st.Name, rankName)
+		for _, a := range st.Aliases {
+			p.addWords(i, a.Name, rankAlias)
+		}
+		p.add(i, normalize(st.ID), rankAlias)
+	}
+	sort.Slice(p.keys, func(i, j int) bool { return p.keys[i].text < p.keys[j].text })
+	return p
+}
+
+// addWords indexes label from its start at rank, and from each later word at
+// rankWord.
+func (p *Prefixes) addWords(station int, label string, rank int) {
+	words := strings.Fields(normalize(label))
+	for i := range words {
+		if i > 0 {
+			rank = rankWord
+		}
+		p.add(station, strings.Join(words[i:], ""), rank)
+	}
+}
+
+func (p *Prefixes) add(station int, text string, rank int) {
+	if text != "" {
+		p.keys = append(p.keys, prefixKey{text: text, station: station, rank: rank})
+	}
+}
+
+// Complete returns up to limit stations with a name, alias or ID starting
+// with q, spaces aside. Stations whose name q starts come first, then those
+// matched by an alias or ID and then by a later word, exact matches first
+// within each and then by name.
+func (p *Prefixes) Complete(q string, limit int) []domain.Station {
+	text := strings.ReplaceAll(normalize(q), " ", "")
+	if text == "" || limit <= 0 {
+		return []domain.Station{}
+	}
+
+	// Keys starting with text sort together from the first not before it.
+	type match struct {
+		station int
+		rank    int
+		exact   bool
+	}
+	best := make(map[int]match)
+	for i := sort.Search(len(p.keys), func(i int) bool { return p.keys[i].text >= text }); i < len(p.keys); i++ {
+		k := p.keys[i]
+		if !strings.HasPrefix(k.text, text) {
+			break
+		}
+		m := match{station: k.station, rank: k.rank, exact: k.text == text}
+		if prev, ok := best[k.station]; ok && (prev.rank < m.rank || prev.rank == m.rank && prev.exact) {
+			continue
+		}
+		best[k.station] = m
+	}
+
+	matches := make([]match, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.exact != b.exact {
+			return a.exact
+		}
+		return p.stations[a.station].Name < p.stations[b.station].Name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	stations := make([]domain.Station, len(matches))
+	for i, m := range matches {
+		stations[i] = p.stations[m.station]
+	}
+	return stations
+}
@@ -0,0 +1,212 @@
+// Package search resolves free-text station queries with a trigram index,
+// tolerating misspellings ("jatinegra"), abbreviations ("univ indonesia") and
+// aliases ("UI").
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"llm-router/internal/domain"
+)
+
+// MinScore is the lowest score a station needs to be returned.
+const MinScore = 0.3
+
+type Result struct {
+	Station domain.Station `json:"station"`
+	Score   float64        `json:"score"`
+	// Matched is the name or alias the query matched best.
+	Matched string `json:"matched"`
+}
+
+// entry is one searchable form of a station: its name or one of its aliases.
+type entry struct {
+	station int
+	label   string
+	text    string // normalized, single-spaced
+	compact string // normalized without spaces
+	tokens  []string
+	alias   bool
+}
+
+// Index is an immutable trigram index over station names and aliases.
+type Index struct {
+	stations []domain.Station
+	entries  []entry
+	grams    map[string][]int
+}
+
+// NewIndex indexes each station under its name, its aliases, its ID and, for
+// names of more than one word, its initials. Stored aliases are real names
+// ("Beos") and match loosely like the name; the ID and initials only match
+// exactly.
+func NewIndex(stations []domain.Station) *Index {
+	idx := &Index{
+		stations: stations,
+		grams:    make(map[string][]int),
+	}
+	for i, st := range stations {
+		idx.add(i, st.Name, false)
+		for _, a := range st.Aliases {
+			idx.add(i, a.Name, false)
+		}
+		idx.add(i, st.ID, true)
+		if words := strings.Fields(normalize(st.Name)); len(words) > 1 {
+			var initials strings.Builder
+			for _, w := range words {
+				initials.WriteByte(w[0])
+			}
+			idx.add(i, strings.ToUpper(initials.String()), true)
+		}
+	}
+	return idx
+}
+
+func (idx *Index) add(station int, label string, alias bool) {
+	text := normalize(label)
+	if text == "" {
+		return
+	}
+	for _, e := range idx.entries {
+		if e.station == station && e.text == text {
+			return
+		}
+	}
+
+	id := len(idx.entries)
+	idx.entries = append(idx.entries, entry{
+		station: station,
+		label:   label,
+		text:    text,
+		compact: strings.ReplaceAll(text, " ", ""),
+		tokens:  strings.Fields(text),
+		alias:   alias,
+	})
+	for g := range queryGrams(text) {
+		idx.grams[g] = append(idx.grams[g], id)
+	}
+}
+
+// Search returns up to limit stations matching q, best first.
+func (idx *Index) Search(q string, limit int) []Result {
+	text := normalize(q)
+	if text == "" || limit <= 0 {
+		return []Result{}
+	}
+	query := entry{
+		text:    text,
+		compact: strings.ReplaceAll(text, " ", ""),
+		tokens:  strings.Fields(text),
+	}
+
+	candidates := make(map[int]bool)
+	for g := range queryGrams(text) {
+		for _, id := range idx.grams[g] {
+			candidates[id] = true
+		}
+	}
+
+	best := make(map[int]Result)
+	for id := range candidates {
+		e := idx.entries[id]
+		score := scoreEntry(query, e)
+		if score < MinScore {
+			continue
+		}
+		if r, ok := best[e.station]; ok && r.Score >= score {
+			continue
+		}
+		best[e.station] = Result{Station: idx.stations[e.station], Score: score, Matched: e.label}
+	}
+
+	results := make([]Result, 0, len(best))
+	for _, r := range best {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Station.Name < results[j].Station.Name
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// scoreEntry rates how well query matches e, from 0 to 1. Exact matches score
+// 1, word-prefix matches ("univ indo") at least 0.7, and anything else by
+// trigram similarity. Aliases only count when matched exactly, so short codes
+// do not drown out real names.
+func scoreEntry(query, e entry) float64 {
+	if query.compact == e.compact {
+		return 1
+	}
+	if e.alias {
+		return 0
+	}
+
+	score := max(dice(grams(query.text), grams(e.text)), dice(grams(query.compact), grams(e.compact)))
+	if prefixesOf(query.tokens, e.tokens) {
+		score = max(score, 0.7+0.3*float64(len(query.compact))/float64(len(e.compact)))
+	}
+	return score
+}
+
+// prefixesOf reports whether each query word is a prefix of a distinct name
+// word, in order.
+func prefixesOf(query, words []string) bool {
+	i := 0
+	for _, w := range words {
+		if i < len(query) && strings.HasPrefix(w, query[i]) {
+			i++
+		}
+	}
+	return i == len(query)
+}
+
+// queryGrams returns the trigrams used for candidate lookup: those of the
+// spaced text and of its compact form, so "tanahabang" still finds
+// "Tanah Abang".
+func queryGrams(text string) map[string]bool {
+	g := grams(text)
+	for k := range grams(strings.ReplaceAll(text, " ", "")) {
+		g[k] = true
+	}
+	return g
+}
+
+// grams returns the trigrams of s padded with a space on each end.
+func grams(s string) map[string]bool {
+	r := []rune(" " + s + " ")
+	g := make(map[string]bool, len(r))
+	for i := 0; i+3 <= len(r); i++ {
+		g[string(r[i:i+3])] = true
+	}
+	return g
+}
+
+// dice is the Sørensen–Dice coefficient of two trigram sets.
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range a {
+		if b[g] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// normalize lowercases s, turns punctuation into spaces and collapses runs of
+// whitespace.
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
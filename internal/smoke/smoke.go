@@ -0,0 +1,269 @@
+// Package smoke exercises the public API of a running instance and reports
+// whether every endpoint returns well-formed data. It is meant to be run right
+// after a deploy: `commuter smoke --base-url https://example.com`.
+package smoke
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxScheduleProbes bounds how many stations are tried when looking for one
+// that has schedule data.
+const maxScheduleProbes = 10
+
+type Check struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+type Report struct {
+	BaseURL string  `json:"base_url"`
+	Checks  []Check `json:"checks"`
+	Passed  int     `json:"passed"`
+	Failed  int     `json:"failed"`
+}
+
+// Main runs the smoke subcommand with the given arguments and returns the
+// process exit code.
+func Main(args []string) int {
+	fs := flag.NewFlagSet("smoke", flag.ContinueOnError)
+	baseURL := fs.String("base-url", "http://localhost:8873", "Base URL of the instance to check")
+	timeout := fs.Duration("timeout", 15*time.Second, "Per-request timeout")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	report := Run(client, strings.TrimRight(*baseURL, "/"))
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		report.Write(os.Stdout)
+	}
+
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+type envelope struct {
+	Metadata struct {
+		Success bool `json:"success"`
+	} `json:"metadata"`
+	Data json.RawMessage `json:"data"`
+}
+
+type station struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type schedule struct {
+	ID        string    `json:"id"`
+	StationID string    `json:"station_id"`
+	TrainID   string    `json:"train_id"`
+	DepartsAt time.Time `json:"departs_at"`
+}
+
+type runner struct {
+	client  *http.Client
+	baseURL string
+	report  *Report
+}
+
+// Run executes all checks against baseURL. Later checks reuse data discovered
+// by earlier ones (a station with schedules, a train on that station).
+func Run(client *http.Client, baseURL string) Report {
+	report := Report{BaseURL: baseURL}
+	r := &runner{client: client, baseURL: baseURL, report: &report}
+
+	r.check("health", func() error {
+		_, err := r.get("/health")
+		return err
+	})
+
+	var stations []station
+	r.check("station list", func() error {
+		if err := r.getData("/api/v1/station", &stations); err != nil {
+			return err
+		}
+		if len(stations) == 0 {
+			return fmt.Errorf("station list is empty")
+		}
+		for _, st := range stations {
+			if st.ID == "" || st.Name == "" {
+				return fmt.Errorf("station with missing id or name: %+v", st)
+			}
+		}
+		return nil
+	})
+
+	var sample schedule
+	r.check("schedule shape", func() error {
+		if len(stations) == 0 {
+			return fmt.Errorf("skipped: no stations")
+		}
+		for i, st := range stations {
+			if i >= maxScheduleProbes {
+				break
+			}
+			var schedules []schedule
+			if err := r.getData("/api/v1/schedule/"+url.PathEscape(st.ID), &schedules); err != nil {
+				continue
+			}
+			for _, sch := range schedules {
+				if sch.ID == "" || sch.TrainID == "" || sch.StationID != st.ID || sch.DepartsAt.IsZero() {
+					return fmt.Errorf("malformed schedule for %s: %+v", st.ID, sch)
+				}
+			}
+			if len(schedules) > 0 {
+				sample = schedules[0]
+				return nil
+			}
+		}
+		return fmt.Errorf("no schedules found in the first %d stations", maxScheduleProbes)
+	})
+
+	r.check("route joins", func() error {
+		if sample.TrainID == "" {
+			return fmt.Errorf("skipped: no schedule to follow")
+		}
+		var route struct {
+			Routes []struct {
+				StationID   string `json:"station_id"`
+				StationName string `json:"station_name"`
+			} `json:"routes"`
+			Details struct {
+				TrainID string `json:"train_id"`
+			} `json:"details"`
+		}
+		if err := r.getData("/api/v1/route/"+url.PathEscape(sample.TrainID), &route); err != nil {
+			return err
+		}
+		if route.Details.TrainID != sample.TrainID {
+			return fmt.Errorf("route details train_id %q, want %q", route.Details.TrainID, sample.TrainID)
+		}
+		if len(route.Routes) == 0 {
+			return fmt.Errorf("train %s has no stops", sample.TrainID)
+		}
+		for _, stop := range route.Routes {
+			if stop.StationName == "" {
+				return fmt.Errorf("stop %s did not resolve to a station name", stop.StationID)
+			}
+		}
+		return nil
+	})
+
+	r.check("heatmap", func() error {
+		if sample.StationID == "" {
+			return fmt.Errorf("skipped: no station with schedules")
+		}
+		var heatmap struct {
+			Totals []int `json:"totals"`
+		}
+		if err := r.getData("/api/v1/analytics/heatmap/"+url.PathEscape(sample.StationID), &heatmap); err != nil {
+			return err
+		}
+		if len(heatmap.Totals) != 24 {
+			return fmt.Errorf("heatmap has %d hourly totals, want 24", len(heatmap.Totals))
+		}
+		return nil
+	})
+
+	r.check("sync status", func() error {
+		var status struct {
+			NextSyncAt time.Time `json:"next_sync_at"`
+		}
+		if err := r.getData("/api/v1/sync", &status); err != nil {
+			return err
+		}
+		if status.NextSyncAt.IsZero() {
+			return fmt.Errorf("sync status has no next_sync_at")
+		}
+		return nil
+	})
+
+	return report
+}
+
+func (r *runner) check(name string, fn func() error) {
+	start := time.Now()
+	err := fn()
+	c := Check{Name: name, OK: err == nil, Duration: time.Since(start)}
+	if err != nil {
+		c.Detail = err.Error()
+		r.report.Failed++
+	} else {
+		r.report.Passed++
+	}
+	r.report.Checks = append(r.report.Checks, c)
+}
+
+func (r *runner) get(path string) ([]byte, error) {
+	resp, err := r.client.Get(r.baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", path, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// getData fetches path, checks the response envelope and decodes its data.
+func (r *runner) getData(path string, v interface{}) error {
+	body, err := r.get(path)
+	if err != nil {
+		return err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return fmt.Errorf("GET %s: invalid JSON: %w", path, err)
+	}
+	if !env.Metadata.Success {
+		return fmt.Errorf("GET %s: metadata.success is false", path)
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		return fmt.Errorf("GET %s: unexpected data shape: %w", path, err)
+	}
+	return nil
+}
+
+// Write prints a human readable report.
+func (r Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Smoke test against %s\n\n", r.BaseURL)
+	for _, c := range r.Checks {
+		status := "PASS"
+		if !c.OK {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "  [%s] %-16s %6dms", status, c.Name, c.Duration.Milliseconds())
+		if c.Detail != "" {
+			fmt.Fprintf(w, "  %s", c.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", r.Passed, r.Failed)
+}
@@ -0,0 +1,344 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// hashToken is what is stored of a login or session token, so that a copy of
+// the database cannot be used to sign in.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateLoginToken stores a one-time sign-in token for email, valid until
+// expiresAt, and deletes expired login tokens and sessions.
+func (s *Store) CreateLoginToken(ctx context.Context, email, token string, expiresAt time.Time) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now()
+	for _, query := range []string{
+		"DELETE FROM login_tokens WHERE expires_at < ?",
+		"DELETE FROM sessions WHERE expires_at < ?",
+	} {
+		if _, err := s.db.ExecContext(ctx, query, now); err != nil {
+			return fmt.Errorf("create login token: %w", err)
+		}
+	}
+	if _, err := s.db.ExecContext(ctx,
+		"INSERT INTO login_tokens (token_hash, email, expires_at) VALUES (?, ?, ?)",
+		hashToken(token), email, expiresAt,
+	); err != nil {
+		return fmt.Errorf("create login token: %w", err)
+	}
+	return nil
+}
+
+// RedeemLoginToken uses up a login token, creating its user on first sign-in,
+// and opens a session with sessionToken until sessionExpiresAt. It returns
+// ErrNotFound for unknown, used or expired tokens.
+func (s *Store) RedeemLoginToken(ctx context.Context, token, sessionToken string, sessionExpiresAt time.Time) (domain.User, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var email string
+	err = tx.QueryRowContext(ctx,
+		"DELETE FROM login_tokens WHERE token_hash = ? AND expires_at >= ? RETURNING email",
+		hashToken(token), now,
+	).Scan(&email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO users (email, created_at) VALUES (?, ?) ON CONFLICT (email) DO NOTHING", email, now,
+	); err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+	var u domain.User
+	if err := tx.QueryRowContext(ctx,
+		"SELECT id, email, created_at FROM users WHERE email = ?", email,
+	).Scan(&u.ID, &u.Email, &u.CreatedAt); err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO sessions (token_hash, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)",
+		hashToken(sessionToken), u.ID, now, sessionExpiresAt,
+	); err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.User{}, fmt.Errorf("redeem login token: %w", err)
+	}
+	return u, nil
+}
+
+// SessionUser returns the user signed in with a session token, or
+// ErrNotFound when the session does not exist or has expired.
+func (s *Store) SessionUser(ctx context.Context, token string) (domain.User, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var u domain.User
+	err := s.db.QueryRowContext(ctx, `
+		SELECT u.id, u.email, u.created_at FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = ? AND s.expires_at >= ?`, hashToken(token), time.Now(),
+	).Scan(&u.ID, &u.Email, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.User{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get session: %w", err)
+	}
+	return u, nil
+}
+
+// DeleteSession signs a session out.
+func (s *Store) DeleteSession(ctx context.Context, token string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM sessions WHERE token_hash = ?", hashToken(token)); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// GetFavorites returns a user's favorite stations in the order they were
+// saved.
+func (s *Store) GetFavorites(ctx context.Context, userID int64) ([]domain.Favorite, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT station_id, created_at FROM user_favorites WHERE user_id = ? ORDER BY created_at, station_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("get favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []domain.Favorite{}
+	for rows.Next() {
+		var f domain.Favorite
+		if err := rows.Scan(&f.StationID, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("get favorites: %w", err)
+		}
+		favorites = append(favorites, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+// AddFavorite saves a favorite station; saving it again changes nothing.
+func (s *Store) AddFavorite(ctx context.Context, userID int64, stationID string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_favorites (user_id, station_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, station_id) DO NOTHING`, userID, stationID, time.Now(),
+	); err != nil {
+		return fmt.Errorf("add favorite %s: %w", stationID, err)
+	}
+	return nil
+}
+
+// RemoveFavorite returns ErrNotFound when the station is not a favorite.
+func (s *Store) RemoveFavorite(ctx context.Context, userID int64, stationID string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM user_favorites WHERE user_id = ? AND station_id = ?", userID, stationID)
+	if err != nil {
+		return fmt.Errorf("remove favorite %s: %w", stationID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetCommutes returns a user's commutes in the order they were saved.
+func (s *Store) GetCommutes(ctx context.Context, userID int64) ([]domain.Commute, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	commutes, err := s.queryCommutes(ctx, "SELECT "+commuteColumns+" FROM user_commutes WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("get commutes: %w", err)
+	}
+	return commutes, nil
+}
+
+// GetAllCommutes returns every user's commutes, with their UserID and
+// TrainsHash, for the push worker.
+func (s *Store) GetAllCommutes(ctx context.Context) ([]domain.Commute, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	commutes, err := s.queryCommutes(ctx, "SELECT "+commuteColumns+" FROM user_commutes ORDER BY user_id, id")
+	if err != nil {
+		return nil, fmt.Errorf("get all commutes: %w", err)
+	}
+	return commutes, nil
+}
+
+const commuteColumns = `id, user_id, name, from_station, to_station, window_after, window_before,
+	COALESCE(remind_before, 0), COALESCE(trains_hash, ''), created_at`
+
+func (s *Store) queryCommutes(ctx context.Context, query string, args ...any) ([]domain.Commute, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	commutes := []domain.Commute{}
+	for rows.Next() {
+		var c domain.Commute
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.From, &c.To, &c.After, &c.Before,
+			&c.RemindBefore, &c.TrainsHash, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		commutes = append(commutes, c)
+	}
+	return commutes, rows.Err()
+}
+
+// CreateCommute saves a commute and returns it with its ID.
+func (s *Store) CreateCommute(ctx context.Context, userID int64, c domain.Commute) (domain.Commute, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	c.CreatedAt = time.Now()
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO user_commutes (user_id, name, from_station, to_station, window_after, window_before, remind_before, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		userID, c.Name, c.From, c.To, c.After, c.Before, c.RemindBefore, c.CreatedAt,
+	).Scan(&c.ID)
+	if err != nil {
+		return domain.Commute{}, fmt.Errorf("create commute: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteCommute returns ErrNotFound when the user has no commute with the
+// given ID.
+func (s *Store) DeleteCommute(ctx context.Context, userID, id int64) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM user_commutes WHERE user_id = ? AND id = ?", userID, id)
+	if err != nil {
+		return fmt.Errorf("delete commute %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetCommuteTrainsHash records the fingerprint of a commute's trains the push
+// worker last saw.
+func (s *Store) SetCommuteTrainsHash(ctx context.Context, id int64, hash string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "UPDATE user_commutes SET trains_hash = ? WHERE id = ?", hash, id); err != nil {
+		return fmt.Errorf("set commute %d trains hash: %w", id, err)
+	}
+	return nil
+}
+
+// SavePushSubscription registers a browser's push subscription for a user.
+// An endpoint belongs to one user at a time: subscribing it again moves it to
+// the new user and updates its keys.
+func (s *Store) SavePushSubscription(ctx context.Context, userID int64, sub domain.PushSubscription) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO push_subscriptions (endpoint, user_id, p256dh, auth, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (endpoint) DO UPDATE SET
+			user_id = excluded.user_id, p256dh = excluded.p256dh, auth = excluded.auth, created_at = excluded.created_at`,
+		sub.Endpoint, userID, sub.Keys.P256dh, sub.Keys.Auth, time.Now(),
+	); err != nil {
+		return fmt.Errorf("save push subscription: %w", err)
+	}
+	return nil
+}
+
+// GetPushSubscriptions returns a user's push subscriptions.
+func (s *Store) GetPushSubscriptions(ctx context.Context, userID int64) ([]domain.PushSubscription, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT endpoint, p256dh, auth, created_at FROM push_subscriptions WHERE user_id = ? ORDER BY created_at", userID)
+	if err != nil {
+		return nil, fmt.Errorf("get push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []domain.PushSubscription{}
+	for rows.Next() {
+		var sub domain.PushSubscription
+		if err := rows.Scan(&sub.Endpoint, &sub.Keys.P256dh, &sub.Keys.Auth, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("get push subscriptions: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get push subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// DeletePushSubscription removes a user's subscription, returning
+// ErrNotFound when the user has none with endpoint.
+func (s *Store) DeletePushSubscription(ctx context.Context, userID int64, endpoint string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE user_id = ? AND endpoint = ?", userID, endpoint)
+	if err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeletePushEndpoint removes a subscription the push service reported gone.
+func (s *Store) DeletePushEndpoint(ctx context.Context, endpoint string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE endpoint = ?", endpoint); err != nil {
+		return fmt.Errorf("delete push endpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// defaultAliases are seeded into an empty alias table. They cover the
+// upstream route spellings that drop the space from a station's name, and
+// the names riders actually use for a few stations.
+var defaultAliases = map[string][]domain.StationAlias{
+	"JAKK": {
+		{Name: "JAKARTAKOTA"},
+		{Name: "Jakarta Kota", Display: true},
+		{Name: "Beos", Lang: "id"},
+	},
+	"KPB": {
+		{Name: "KAMPUNGBANDAN"},
+		{Name: "Kampung Bandan", Display: true},
+	},
+	"TPK": {
+		{Name: "TANJUNGPRIUK"},
+		{Name: "Tanjung Priok", Display: true},
+	},
+	"THB": {
+		{Name: "TANAHABANG"},
+		{Name: "Tanah Abang", Display: true},
+	},
+	"PRP": {
+		{Name: "PARUNGPANJANG"},
+		{Name: "Parung Panjang", Display: true},
+	},
+	"BST": {
+		{Name: "BANDARASOEKARNOHATTA"},
+		{Name: "Soekarno-Hatta Airport", Lang: "en", Display: true},
+		{Name: "Bandara Soekarno-Hatta", Lang: "id", Display: true},
+		{Name: "Soetta", Lang: "id"},
+	},
+}
+
+// seedAliases writes defaultAliases unless the table already has rows, so
+// that aliases removed through the admin API stay removed.
+func (s *Store) seedAliases(ctx context.Context) error {
+	var count int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM station_aliases").Scan(&count); err != nil {
+		return fmt.Errorf("seed aliases: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	for stationID, aliases := range defaultAliases {
+		for _, a := range aliases {
+			if err := s.SetStationAlias(ctx, stationID, a); err != nil {
+				return fmt.Errorf("seed aliases: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetStationAliases returns a station's aliases, display names first.
+func (s *Store) GetStationAliases(ctx context.Context, stationID string) ([]domain.StationAlias, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	byStation, err := s.queryAliases(ctx, "WHERE station_id = ?", stationID)
+	if err != nil {
+		return nil, fmt.Errorf("get aliases for %s: %w", stationID, err)
+	}
+	return byStation[stationID], nil
+}
+
+// allAliases returns every alias keyed by station ID.
+func (s *Store) allAliases(ctx context.Context) (map[string][]domain.StationAlias, error) {
+	byStation, err := s.queryAliases(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("get aliases: %w", err)
+	}
+	return byStation, nil
+}
+
+func (s *Store) queryAliases(ctx context.Context, clause string, args ...any) (map[string][]domain.StationAlias, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT station_id, alias, lang, display FROM station_aliases `+clause+`
+		ORDER BY station_id, display DESC, lang, alias`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byStation := make(map[string][]domain.StationAlias)
+	for rows.Next() {
+		var stationID string
+		var a domain.StationAlias
+		if err := rows.Scan(&stationID, &a.Name, &a.Lang, &a.Display); err != nil {
+			return nil, err
+		}
+		byStation[stationID] = append(byStation[stationID], a)
+	}
+	return byStation, rows.Err()
+}
+
+// SetStationAlias adds an alias to a station, or updates whether it is a
+// display name when the station already has it for that language.
+func (s *Store) SetStationAlias(ctx context.Context, stationID string, a domain.StationAlias) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO station_aliases (station_id, alias, lang, display, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(station_id, alias, lang) DO UPDATE SET display = excluded.display, updated_at = excluded.updated_at`,
+		stationID, a.Name, a.Lang, a.Display, time.Now())
+	if err != nil {
+		return fmt.Errorf("set alias %q for %s: %w", a.Name, stationID, err)
+	}
+	return nil
+}
+
+// DeleteStationAlias returns ErrNotFound when the station has no such alias
+// for lang.
+func (s *Store) DeleteStationAlias(ctx context.Context, stationID, name, lang string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM station_aliases WHERE station_id = ? AND alias = ? AND lang = ?", stationID, name, lang)
+	if err != nil {
+		return fmt.Errorf("delete alias %q for %s: %w", name, stationID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete alias %q for %s: %w", name, stationID, err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// GetHourlyDepartureCounts aggregates a station's departures in its
+// timetable for serviceDay, or in all of its schedules when serviceDay is
+// empty, by line and hour of day in the timetable's local time.
+func (s *Store) GetHourlyDepartureCounts(ctx context.Context, stationID, serviceDay string) ([]domain.HourlyDepartureCount, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	where := "WHERE station_id = ?"
+	args := []any{stationID}
+	if serviceDay != "" {
+		where += " AND " + serviceDayColumn + " = ?"
+		args = append(args, serviceDay)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT line, `+s.dialect.hour("departs_at")+` AS hour, COUNT(*)
+		FROM schedules `+where+`
+		GROUP BY line, hour
+		ORDER BY line, hour`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get hourly departures for %s: %w", stationID, err)
+	}
+	defer rows.Close()
+
+	var counts []domain.HourlyDepartureCount
+	for rows.Next() {
+		var c domain.HourlyDepartureCount
+		if err := rows.Scan(&c.Line, &c.Hour, &c.Count); err != nil {
+			return nil, fmt.Errorf("get hourly departures for %s: %w", stationID, err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get hourly departures for %s: %w", stationID, err)
+	}
+	return counts, nil
+}
+
+// GetStationServiceDays lists the timetables, by service day, a station has.
+func (s *Store) GetStationServiceDays(ctx context.Context, stationID string) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	days, err := queryStrings(ctx, s.db, "SELECT DISTINCT "+serviceDayColumn+" FROM schedules WHERE station_id = ?", stationID)
+	if err != nil {
+		return nil, fmt.Errorf("get service days for %s: %w", stationID, err)
+	}
+	return days, nil
+}
+
+// GetStationStats aggregates a station's timetable for serviceDay, or all of
+// its schedules when serviceDay is empty. Destination names are left for the
+// caller to fill in.
+func (s *Store) GetStationStats(ctx context.Context, stationID, serviceDay string) (domain.StationStats, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	stats := domain.StationStats{
+		StationID:     stationID,
+		ServiceDay:    serviceDay,
+		TrainsPerHour: make([]int, 24),
+		Lines:         []domain.LineStats{},
+		Destinations:  []domain.DestinationStats{},
+	}
+	where := "WHERE station_id = ?"
+	args := []any{stationID}
+	if serviceDay != "" {
+		where += " AND " + serviceDayColumn + " = ?"
+		args = append(args, serviceDay)
+	}
+	first, last := s.dialect.epoch("MIN(departs_at)"), s.dialect.epoch("MAX(departs_at)")
+	fail := func(err error) (domain.StationStats, error) {
+		return domain.StationStats{}, fmt.Errorf("get stats for %s: %w", stationID, err)
+	}
+
+	var firstAt, lastAt sql.NullInt64
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*), "+first+", "+last+" FROM schedules "+where, args...).
+		Scan(&stats.Departures, &firstAt, &lastAt)
+	if err != nil {
+		return fail(err)
+	}
+	if stats.Departures == 0 {
+		return stats, nil
+	}
+	stats.FirstDeparture = ptr(unixJakarta(firstAt.Int64))
+	stats.LastDeparture = ptr(unixJakarta(lastAt.Int64))
+	stats.AverageHeadway = headway(stats.Departures, firstAt.Int64, lastAt.Int64)
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+s.dialect.hour("departs_at")+" AS hour, COUNT(*) FROM schedules "+where+" GROUP BY hour", args...)
+	if err != nil {
+		return fail(err)
+	}
+	for rows.Next() {
+		var hour, n int
+		if err := rows.Scan(&hour, &n); err != nil {
+			rows.Close()
+			return fail(err)
+		}
+		if hour >= 0 && hour < 24 {
+			stats.TrainsPerHour[hour] = n
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+	busiest := 0
+	for hour, n := range stats.TrainsPerHour {
+		if n > stats.TrainsPerHour[busiest] {
+			busiest = hour
+		}
+	}
+	stats.BusiestHour = &busiest
+
+	rows, err = s.db.QueryContext(ctx, "SELECT line, COUNT(*), "+first+", "+last+" FROM schedules "+where+
+		" GROUP BY line ORDER BY COUNT(*) DESC, line", args...)
+	if err != nil {
+		return fail(err)
+	}
+	for rows.Next() {
+		var l domain.LineStats
+		var firstAt, lastAt int64
+		if err := rows.Scan(&l.Line, &l.Departures, &firstAt, &lastAt); err != nil {
+			rows.Close()
+			return fail(err)
+		}
+		l.AverageHeadway = headway(l.Departures, firstAt, lastAt)
+		stats.Lines = append(stats.Lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT station_destination_id, COUNT(*), "+first+", "+last+" FROM schedules "+where+
+		" GROUP BY station_destination_id ORDER BY station_destination_id", args...)
+	if err != nil {
+		return fail(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d domain.DestinationStats
+		var firstAt, lastAt int64
+		if err := rows.Scan(&d.StationID, &d.Departures, &firstAt, &lastAt); err != nil {
+			return fail(err)
+		}
+		d.FirstDeparture, d.LastDeparture = unixJakarta(firstAt), unixJakarta(lastAt)
+		stats.Destinations = append(stats.Destinations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return fail(err)
+	}
+	return stats, nil
+}
+
+// headway is the average minutes between n departures spread from first to
+// last, in Unix seconds, rounded to a tenth.
+func headway(n int, first, last int64) float64 {
+	if n < 2 {
+		return 0
+	}
+	return math.Round(float64(last-first)/60/float64(n-1)*10) / 10
+}
+
+func unixJakarta(sec int64) time.Time {
+	return time.Unix(sec, 0).In(calendar.Jakarta)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,281 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const announcementColumns = `id, COALESCE(line, ''), station_ids, severity, message, starts_at, ends_at, source, COALESCE(external_id, ''), COALESCE(translations, ''), created_at, updated_at`
+
+func scanAnnouncement(row rowScanner) (domain.Announcement, error) {
+	var a domain.Announcement
+	var stations, translations string
+	var endsAt sql.NullTime
+	if err := row.Scan(&a.ID, &a.Line, &stations, &a.Severity, &a.Message, &a.StartsAt, &endsAt, &a.Source, &a.ExternalID, &translations, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return domain.Announcement{}, err
+	}
+	if endsAt.Valid {
+		a.EndsAt = &endsAt.Time
+	}
+	if err := json.Unmarshal([]byte(stations), &a.StationIDs); err != nil {
+		return domain.Announcement{}, fmt.Errorf("announcement %d stations: %w", a.ID, err)
+	}
+	if translations != "" {
+		if err := json.Unmarshal([]byte(translations), &a.Translations); err != nil {
+			return domain.Announcement{}, fmt.Errorf("announcement %d translations: %w", a.ID, err)
+		}
+	}
+	return a, nil
+}
+
+// CreateAnnouncement stores a new announcement and returns it with its ID.
+func (s *Store) CreateAnnouncement(ctx context.Context, a domain.Announcement) (domain.Announcement, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	stations, err := json.Marshal(a.StationIDs)
+	if err != nil {
+		return domain.Announcement{}, fmt.Errorf("create announcement: %w", err)
+	}
+	translations, err := marshalTranslations(a.Translations)
+	if err != nil {
+		return domain.Announcement{}, fmt.Errorf("create announcement: %w", err)
+	}
+	a.CreatedAt = time.Now()
+	a.UpdatedAt = a.CreatedAt
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO announcements (line, station_ids, severity, message, translations, starts_at, ends_at, source, external_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		a.Line, string(stations), a.Severity, a.Message, translations, a.StartsAt, a.EndsAt, a.Source, a.ExternalID, a.CreatedAt, a.UpdatedAt,
+	).Scan(&a.ID)
+	if err != nil {
+		return domain.Announcement{}, fmt.Errorf("create announcement: %w", err)
+	}
+	return a, nil
+}
+
+// UpdateAnnouncement replaces the content of announcement a.ID. It returns
+// ErrNotFound when there is no such announcement.
+func (s *Store) UpdateAnnouncement(ctx context.Context, a domain.Announcement) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	stations, err := json.Marshal(a.StationIDs)
+	if err != nil {
+		return fmt.Errorf("update announcement %d: %w", a.ID, err)
+	}
+	translations, err := marshalTranslations(a.Translations)
+	if err != nil {
+		return fmt.Errorf("update announcement %d: %w", a.ID, err)
+	}
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE announcements SET line = ?, station_ids = ?, severity = ?, message = ?, translations = ?, starts_at = ?, ends_at = ?, updated_at = ?
+		WHERE id = ?`,
+		a.Line, string(stations), a.Severity, a.Message, translations, a.StartsAt, a.EndsAt, time.Now(), a.ID)
+	if err != nil {
+		return fmt.Errorf("update announcement %d: %w", a.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteAnnouncement returns ErrNotFound when there is no announcement with
+// the given ID.
+func (s *Store) DeleteAnnouncement(ctx context.Context, id int64) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM announcements WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete announcement %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetAnnouncement returns ErrNotFound when there is no announcement with the
+// given ID.
+func (s *Store) GetAnnouncement(ctx context.Context, id int64) (domain.Announcement, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	a, err := scanAnnouncement(s.db.QueryRowContext(ctx, "SELECT "+announcementColumns+" FROM announcements WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Announcement{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Announcement{}, fmt.Errorf("get announcement %d: %w", id, err)
+	}
+	return a, nil
+}
+
+// SyncAnnouncements makes the announcements of source match a poll of its
+// feed, in one transaction. Announcements are matched by ExternalID: known
+// ones are updated, new ones added, and those of source no longer in the
+// feed are ended at now unless they already ended. It returns the
+// announcements the poll added, altered or ended, altered ones both as they
+// were and as they are, so what showed them can be refreshed.
+func (s *Store) SyncAnnouncements(ctx context.Context, source string, feed []domain.Announcement, now time.Time) ([]domain.Announcement, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sync announcements: %w", err)
+	}
+	defer tx.Rollback()
+
+	known := make(map[string]domain.Announcement)
+	rows, err := tx.QueryContext(ctx, "SELECT "+announcementColumns+" FROM announcements WHERE source = ? AND external_id IS NOT NULL", source)
+	if err != nil {
+		return nil, fmt.Errorf("sync announcements: %w", err)
+	}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("sync announcements: %w", err)
+		}
+		known[a.ExternalID] = a
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sync announcements: %w", err)
+	}
+
+	var changed []domain.Announcement
+	listed := make(map[string]bool, len(feed))
+	for _, a := range feed {
+		listed[a.ExternalID] = true
+		stations, err := json.Marshal(a.StationIDs)
+		if err != nil {
+			return nil, fmt.Errorf("sync announcement %s: %w", a.ExternalID, err)
+		}
+		if prev, ok := known[a.ExternalID]; ok {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE announcements SET line = ?, station_ids = ?, severity = ?, message = ?, starts_at = ?, ends_at = ?, updated_at = ?
+				WHERE id = ?`,
+				a.Line, string(stations), a.Severity, a.Message, a.StartsAt, a.EndsAt, now, prev.ID)
+			if err == nil && announcementAltered(prev, a) {
+				changed = append(changed, prev, a)
+			}
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO announcements (line, station_ids, severity, message, starts_at, ends_at, source, external_id, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				a.Line, string(stations), a.Severity, a.Message, a.StartsAt, a.EndsAt, source, a.ExternalID, now, now)
+			changed = append(changed, a)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sync announcement %s: %w", a.ExternalID, err)
+		}
+	}
+
+	for externalID, prev := range known {
+		if listed[externalID] {
+			continue
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE announcements SET ends_at = ?, updated_at = ?
+			WHERE id = ? AND (ends_at IS NULL OR ends_at > ?)`, now, now, prev.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("end announcement %s: %w", externalID, err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			prev.EndsAt = &now
+			changed = append(changed, prev)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("sync announcements: %w", err)
+	}
+	return changed, nil
+}
+
+// announcementAltered reports whether a poll changed what an announcement
+// says or where and when it applies.
+func announcementAltered(prev, next domain.Announcement) bool {
+	endsEqual := prev.EndsAt == nil && next.EndsAt == nil ||
+		prev.EndsAt != nil && next.EndsAt != nil && prev.EndsAt.Equal(*next.EndsAt)
+	return prev.Line != next.Line || !slices.Equal(prev.StationIDs, next.StationIDs) ||
+		prev.Severity != next.Severity || prev.Message != next.Message ||
+		!prev.StartsAt.Equal(next.StartsAt) || !endsEqual
+}
+
+// PruneAnnouncements deletes the announcements that ended before cutoff and
+// returns how many it deleted.
+func (s *Store) PruneAnnouncements(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM announcements WHERE ends_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("prune announcements: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// ListAnnouncements returns every announcement, latest first.
+func (s *Store) ListAnnouncements(ctx context.Context) ([]domain.Announcement, error) {
+	return s.queryAnnouncements(ctx, "ORDER BY starts_at DESC, id DESC")
+}
+
+// GetActiveAnnouncements returns the announcements in effect at t, the
+// latest first.
+func (s *Store) GetActiveAnnouncements(ctx context.Context, t time.Time) ([]domain.Announcement, error) {
+	return s.queryAnnouncements(ctx, `
+		WHERE starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)
+		ORDER BY starts_at DESC, id DESC`, t, t)
+}
+
+// queryAnnouncements returns the announcements matched by clause, which
+// follows the FROM of the query.
+func (s *Store) queryAnnouncements(ctx context.Context, clause string, args ...any) ([]domain.Announcement, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+announcementColumns+" FROM announcements "+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []domain.Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list announcements: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// marshalTranslations encodes an announcement's translations for storage,
+// NULL when it has none.
+func marshalTranslations(translations map[string]string) (any, error) {
+	if len(translations) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(translations)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,10 @@
+package store
+
+import "context"
+
+// Backup writes a consistent copy of the database to path while it stays
+// in use. An existing database at path is overwritten. It returns
+// ErrBackupUnsupported on databases other than SQLite.
+func (s *Store) Backup(ctx context.Context, path string) error {
+	return s.dialect.backup(ctx, s.db, path)
+}
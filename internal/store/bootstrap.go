@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"llm-router/internal/domain"
+)
+
+// ImportSnapshot replaces every station and schedule in one transaction, for
+// seeding an empty database from another instance's dump.
+func (s *Store) ImportSnapshot(ctx context.Context, stations []domain.Station, schedules []domain.Schedule) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceStations(ctx, tx, stations); err != nil {
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schedules"); err != nil {
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+	if err := insertSchedules(ctx, tx, schedules); err != nil {
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import snapshot: %w", err)
+	}
+	return nil
+}
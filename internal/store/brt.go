@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// ImportBRT replaces the BRT network: its stops, stored as stations of type
+// BRT with their locations, its corridors and its transfers to the rail
+// network. Rail stations are left as they are.
+func (s *Store) ImportBRT(ctx context.Context, stops []domain.Station, locs []domain.StationLocation, network domain.BRTNetwork) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("import brt: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, q := range []string{
+		"DELETE FROM station_locations WHERE station_id IN (SELECT id FROM stations WHERE type = ?)",
+		"DELETE FROM stations WHERE type = ?",
+	} {
+		if _, err := tx.ExecContext(ctx, q, domain.StationTypeBRT); err != nil {
+			return fmt.Errorf("import brt: %w", err)
+		}
+	}
+	for _, q := range []string{"DELETE FROM brt_corridors", "DELETE FROM brt_transfers"} {
+		if _, err := tx.ExecContext(ctx, q); err != nil {
+			return fmt.Errorf("import brt: %w", err)
+		}
+	}
+
+	for _, st := range stops {
+		meta, err := json.Marshal(st.Metadata)
+		if err != nil {
+			return fmt.Errorf("import brt stop %s: %w", st.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO stations (uid, id, name, type, metadata) VALUES (?, ?, ?, ?, ?)",
+			st.UID, st.ID, st.Name, domain.StationTypeBRT, meta); err != nil {
+			return fmt.Errorf("import brt stop %s: %w", st.ID, err)
+		}
+	}
+
+	now := time.Now()
+	for _, loc := range locs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO station_locations (station_id, lat, lng, address, municipality, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(station_id) DO UPDATE SET lat = excluded.lat, lng = excluded.lng, updated_at = excluded.updated_at`,
+			loc.StationID, loc.Lat, loc.Lng, loc.Address, loc.Municipality, now); err != nil {
+			return fmt.Errorf("import location for %s: %w", loc.StationID, err)
+		}
+	}
+
+	for _, c := range network.Corridors {
+		stopsJSON, err := json.Marshal(c.Stops)
+		if err != nil {
+			return fmt.Errorf("encode stops of corridor %s: %w", c.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO brt_corridors (id, direction, name, color, headway_minutes, first_departure, last_departure, stops, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.ID, c.Direction, c.Name, c.Color, c.HeadwayMinutes, c.FirstDeparture, c.LastDeparture, string(stopsJSON), now); err != nil {
+			return fmt.Errorf("import corridor %s: %w", c.ID, err)
+		}
+	}
+
+	for _, t := range network.Transfers {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO brt_transfers (station_id, stop_id, walk_minutes) VALUES (?, ?, ?)",
+			t.StationID, t.ToStationID, t.WalkMinutes); err != nil {
+			return fmt.Errorf("import transfer %s-%s: %w", t.StationID, t.ToStationID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import brt: %w", err)
+	}
+	return nil
+}
+
+// GetBRTNetwork returns the imported BRT corridors, by ID and direction, and
+// their transfers. Both are empty when no network has been imported.
+func (s *Store) GetBRTNetwork(ctx context.Context) (domain.BRTNetwork, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var network domain.BRTNetwork
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, direction, name, color, headway_minutes, first_departure, last_departure, stops
+		FROM brt_corridors ORDER BY id, direction`)
+	if err != nil {
+		return domain.BRTNetwork{}, fmt.Errorf("get brt corridors: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c domain.BRTCorridor
+		var stops string
+		if err := rows.Scan(&c.ID, &c.Direction, &c.Name, &c.Color, &c.HeadwayMinutes, &c.FirstDeparture, &c.LastDeparture, &stops); err != nil {
+			return domain.BRTNetwork{}, fmt.Errorf("get brt corridors: %w", err)
+		}
+		if err := json.Unmarshal([]byte(stops), &c.Stops); err != nil {
+			return domain.BRTNetwork{}, fmt.Errorf("decode stops of corridor %s: %w", c.ID, err)
+		}
+		network.Corridors = append(network.Corridors, c)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.BRTNetwork{}, fmt.Errorf("get brt corridors: %w", err)
+	}
+
+	trows, err := s.db.QueryContext(ctx, "SELECT station_id, stop_id, walk_minutes FROM brt_transfers ORDER BY station_id, stop_id")
+	if err != nil {
+		return domain.BRTNetwork{}, fmt.Errorf("get brt transfers: %w", err)
+	}
+	defer trows.Close()
+	for trows.Next() {
+		var t domain.Transfer
+		if err := trows.Scan(&t.StationID, &t.ToStationID, &t.WalkMinutes); err != nil {
+			return domain.BRTNetwork{}, fmt.Errorf("get brt transfers: %w", err)
+		}
+		network.Transfers = append(network.Transfers, t)
+	}
+	if err := trows.Err(); err != nil {
+		return domain.BRTNetwork{}, fmt.Errorf("get brt transfers: %w", err)
+	}
+	return network, nil
+}
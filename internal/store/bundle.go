@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"llm-router/internal/domain"
+)
+
+// BundleFormat is the layout version of offline bundles, raised whenever
+// their tables change incompatibly.
+const BundleFormat = 1
+
+// bundleSchema is the layout of an offline bundle. Stations and schedules
+// keep the store's own columns; bundle_info holds the format. Nothing else
+// varies between bundles of the same data, so their checksums can serve as
+// versions.
+const bundleSchema = `
+	CREATE TABLE bundle_info (
+		key TEXT PRIMARY KEY,
+		value TEXT
+	);
+	CREATE TABLE stations (
+		uid TEXT PRIMARY KEY,
+		id TEXT,
+		name TEXT,
+		type TEXT,
+		metadata JSON
+	);
+	CREATE INDEX idx_stations_id ON stations(id);
+	CREATE TABLE schedules (
+		id TEXT PRIMARY KEY,
+		station_id TEXT,
+		station_origin_id TEXT,
+		station_destination_id TEXT,
+		train_id TEXT,
+		line TEXT,
+		route TEXT,
+		departs_at DATETIME,
+		arrives_at DATETIME,
+		metadata JSON,
+		updated_at DATETIME,
+		run_id INTEGER,
+		service_type TEXT,
+		service_day TEXT,
+		service_date TEXT
+	);
+	CREATE INDEX idx_schedules_station_id ON schedules(station_id);
+	CREATE INDEX idx_schedules_train_id ON schedules(train_id);
+	CREATE TABLE lines (
+		name TEXT,
+		position INTEGER,
+		station_id TEXT,
+		color TEXT,
+		PRIMARY KEY (name, position)
+	);
+	`
+
+// WriteBundle writes every station, schedule and line to a new SQLite
+// database at path, which must not exist yet, for apps to use offline. It
+// works on every driver, since the bundle is always SQLite.
+func (s *Store) WriteBundle(ctx context.Context, path string) error {
+	stations, err := s.GetStations(ctx)
+	if err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+
+	bundle, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	defer bundle.Close()
+	if _, err := bundle.ExecContext(ctx, bundleSchema); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+
+	tx, err := bundle.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO bundle_info (key, value) VALUES ('format', ?)", strconv.Itoa(BundleFormat)); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	if err := replaceStations(ctx, tx, stations); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	if err := s.copyBundleSchedules(ctx, tx); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	if err := s.copyBundleLines(ctx, tx); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("write bundle: %w", err)
+	}
+	return nil
+}
+
+// copyBundleSchedules streams the schedules into the bundle in batches, so
+// the network never has to fit in memory.
+func (s *Store) copyBundleSchedules(ctx context.Context, tx *sql.Tx) error {
+	const batch = 1000
+	schedules := make([]domain.Schedule, 0, batch)
+	err := s.EachSchedule(ctx, func(sch domain.Schedule) error {
+		schedules = append(schedules, sch)
+		if len(schedules) < batch {
+			return nil
+		}
+		err := insertSchedules(ctx, tx, schedules)
+		schedules = schedules[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return insertSchedules(ctx, tx, schedules)
+}
+
+func (s *Store) copyBundleLines(ctx context.Context, tx *sql.Tx) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT name, position, station_id, color FROM lines ORDER BY name, position")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO lines (name, position, station_id, color) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for rows.Next() {
+		var name, stationID, color string
+		var position int
+		if err := rows.Scan(&name, &position, &stationID, &color); err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, name, position, stationID, color); err != nil {
+			return fmt.Errorf("line %s: %w", name, err)
+		}
+	}
+	return rows.Err()
+}
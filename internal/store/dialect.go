@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Database drivers the store runs on.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ErrBackupUnsupported is returned by Backup on databases other than SQLite,
+// which are backed up with their own tools.
+var ErrBackupUnsupported = errors.New("online backups are only supported on SQLite")
+
+// dialect is what differs between the databases the store runs on. Queries
+// are written once, for SQLite with ? placeholders; each dialect connects
+// so that they run unchanged, and covers the rest here.
+type dialect interface {
+	// open connects to the database named by dsn.
+	open(dsn string) (*sql.DB, error)
+	// schema adapts a CREATE TABLE statement or column type written for
+	// SQLite.
+	schema(ddl string) string
+	hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error)
+	// tables lists the store's tables.
+	tables(ctx context.Context, db *sql.DB) ([]string, error)
+	// size is the size of the database in bytes.
+	size(ctx context.Context, db *sql.DB) (int64, error)
+	// hour is an expression for the hour of day, in Jakarta, of a
+	// timestamp column.
+	hour(column string) string
+	// clock is an expression for the time of day, HH:MM in Jakarta, of a
+	// timestamp column.
+	clock(column string) string
+	// epoch is an expression for a timestamp as Unix seconds.
+	epoch(expr string) string
+	backup(ctx context.Context, db *sql.DB, path string) error
+	// optimize lists the statements that reclaim free space and refresh
+	// the query planner's statistics.
+	optimize() []string
+	// tryLock takes a lock named name that is held across every instance
+	// sharing the database, returning ok false when another holds it.
+	tryLock(ctx context.Context, db *sql.DB, name string) (unlock func(), ok bool, err error)
+}
+
+func newDialect(driver string) (dialect, error) {
+	switch driver {
+	case DriverSQLite, "":
+		return sqliteDialect{}, nil
+	case DriverPostgres:
+		return postgresDialect{}, nil
+	}
+	return nil, fmt.Errorf("unknown database driver %q", driver)
+}
+
+// TryLock takes a lock named name that is held across every instance sharing
+// the database, so that only one of them does a job at a time. ok is false
+// when another instance holds it; otherwise unlock must be called once the
+// job is done.
+func (s *Store) TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	unlock, ok, err = s.dialect.tryLock(ctx, s.db, name)
+	if err != nil {
+		return nil, false, fmt.Errorf("lock %s: %w", name, err)
+	}
+	return unlock, ok, nil
+}
+
+// querier is a *sql.DB or *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryStrings returns the single string column of every row of query.
+func queryStrings(ctx context.Context, db querier, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
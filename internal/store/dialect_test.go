@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// testStores are the backends a test runs against: an in-memory SQLite store,
+// and PostgreSQL when TEST_POSTGRES_DSN names a database to use.
+func testStores(t *testing.T) map[string]*Store {
+	t.Helper()
+	stores := map[string]*Store{DriverSQLite: newTestStore(t)}
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		s, err := NewStore(context.Background(), DriverPostgres, dsn, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		stores[DriverPostgres] = s
+	}
+	return stores
+}
+
+func TestDialectsReadJakartaClock(t *testing.T) {
+	for driver, s := range testStores(t) {
+		t.Run(driver, func(t *testing.T) {
+			ctx := context.Background()
+			// Handed over in UTC, as by a caller on a UTC host: 05:30 and
+			// 17:00 WIB fall on the previous and the same UTC date.
+			schedules := filterSchedules()[:3]
+			for i := range schedules {
+				schedules[i].StationID = "TZT"
+				schedules[i].ID += "_tzt"
+				schedules[i].DepartsAt = schedules[i].DepartsAt.UTC()
+				schedules[i].ArrivesAt = schedules[i].ArrivesAt.UTC()
+			}
+			if err := s.SetSchedules(ctx, "TZT", domain.ServiceDayWeekday, "2026-10-19", schedules); err != nil {
+				t.Fatal(err)
+			}
+			// The weekend timetable is stored alongside and left out of the
+			// weekday's counts.
+			weekend := slices.Clone(schedules)
+			for i := range weekend {
+				weekend[i].ID += "_weekend"
+				weekend[i].ServiceDay = domain.ServiceDayWeekend
+			}
+			if err := s.SetSchedules(ctx, "TZT", domain.ServiceDayWeekend, "2026-10-24", weekend); err != nil {
+				t.Fatal(err)
+			}
+
+			counts, err := s.GetHourlyDepartureCounts(ctx, "TZT", domain.ServiceDayWeekday)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []domain.HourlyDepartureCount{
+				{Line: "COMMUTER LINE BOGOR", Hour: 5, Count: 1},
+				{Line: "COMMUTER LINE BOGOR", Hour: 16, Count: 1},
+				{Line: "COMMUTER LINE BOGOR", Hour: 17, Count: 1},
+			}
+			if !slices.Equal(counts, want) {
+				t.Errorf("hourly counts = %v, want %v", counts, want)
+			}
+
+			stats, err := s.GetStationStats(ctx, "TZT", domain.ServiceDayWeekday)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.TrainsPerHour[5] != 1 || stats.TrainsPerHour[17] != 1 {
+				t.Errorf("trains per hour = %v, want one at 5 and at 17", stats.TrainsPerHour)
+			}
+
+			conds, err := ParseFilter("station_id==TZT;departs_at>=17:00")
+			if err != nil {
+				t.Fatal(err)
+			}
+			late, err := s.QuerySchedules(ctx, ScheduleFilter{Conditions: conds, Timetables: map[string]string{"TZT": domain.ServiceDayWeekday}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(late) != 1 || late[0].DepartsAt.In(calendar.Jakarta).Format(time.TimeOnly) != "17:00:00" {
+				t.Errorf("departures from 17:00 = %v, want the 17:00", late)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// GetStationFacilities returns ErrNotFound when nothing is known about the
+// station's facilities.
+func (s *Store) GetStationFacilities(ctx context.Context, stationID string) (domain.StationFacilities, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var metaBytes []byte
+	row := s.db.QueryRowContext(ctx, "SELECT facilities FROM station_facilities WHERE station_id = ?", stationID)
+	if err := row.Scan(&metaBytes); errors.Is(err, sql.ErrNoRows) {
+		return domain.StationFacilities{}, ErrNotFound
+	} else if err != nil {
+		return domain.StationFacilities{}, fmt.Errorf("get facilities for %s: %w", stationID, err)
+	}
+
+	var f domain.StationFacilities
+	if err := json.Unmarshal(metaBytes, &f); err != nil {
+		return domain.StationFacilities{}, fmt.Errorf("get facilities for %s: %w", stationID, err)
+	}
+	return f, nil
+}
+
+func (s *Store) SetStationFacilities(ctx context.Context, stationID string, f domain.StationFacilities) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	metaBytes, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("set facilities for %s: %w", stationID, err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO station_facilities (station_id, facilities, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(station_id) DO UPDATE SET facilities = excluded.facilities, updated_at = excluded.updated_at`,
+		stationID, metaBytes, time.Now())
+	if err != nil {
+		return fmt.Errorf("set facilities for %s: %w", stationID, err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// MaxConditions bounds the conditions of a filter expression.
+const MaxConditions = 10
+
+// Condition is one comparison of a filter expression, such as
+// departs_at>=17:00.
+type Condition struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+// filterField is a schedule field filter expressions may compare. Text
+// fields compare for equality, ignoring case; clock fields compare their
+// time of day in Jakarta as HH:MM.
+type filterField struct {
+	column string
+	clock  bool
+	value  func(domain.Schedule) string
+}
+
+var filterFields = map[string]filterField{
+	"line":                   {column: "line", value: func(s domain.Schedule) string { return s.Line }},
+	"route":                  {column: "route", value: func(s domain.Schedule) string { return s.Route }},
+	"train_id":               {column: "train_id", value: func(s domain.Schedule) string { return s.TrainID }},
+	"station_id":             {column: "station_id", value: func(s domain.Schedule) string { return s.StationID }},
+	"station_origin_id":      {column: "station_origin_id", value: func(s domain.Schedule) string { return s.StationOriginID }},
+	"station_destination_id": {column: "station_destination_id", value: func(s domain.Schedule) string { return s.StationDestinationID }},
+	"service_type":           {column: "service_type", value: func(s domain.Schedule) string { return s.ServiceType }},
+	"departs_at":             {column: "departs_at", clock: true, value: func(s domain.Schedule) string { return clockOf(s.DepartsAt) }},
+	"arrives_at":             {column: "arrives_at", clock: true, value: func(s domain.Schedule) string { return clockOf(s.ArrivesAt) }},
+}
+
+// filterOps are the comparison operators, longest first so that >= is not
+// read as >.
+var filterOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// clockOf returns the time of day of t in Jakarta, or "" when t is unset.
+func clockOf(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(calendar.Jakarta).Format("15:04")
+}
+
+// ParseFilter parses a filter expression: conditions separated by ';', each
+// a field, an operator and a value, as in line==BOGOR;departs_at>=17:00.
+// Text fields take == and != and may list values separated by ',', any of
+// which matches. departs_at and arrives_at take every operator and an HH:MM
+// value. The error says which condition is unusable.
+func ParseFilter(expr string) ([]Condition, error) {
+	var conds []Condition
+	for _, part := range strings.Split(expr, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if len(conds) == MaxConditions {
+			return nil, fmt.Errorf("more than %d conditions", MaxConditions)
+		}
+		c, err := parseCondition(part)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", part, err)
+		}
+		conds = append(conds, c)
+	}
+	return conds, nil
+}
+
+func parseCondition(s string) (Condition, error) {
+	at, op := -1, ""
+	for _, o := range filterOps {
+		if i := strings.Index(s, o); i >= 0 && (at < 0 || i < at) {
+			at, op = i, o
+		}
+	}
+	if at <= 0 {
+		return Condition{}, errors.New("want a field, an operator and a value")
+	}
+	name := strings.ToLower(strings.TrimSpace(s[:at]))
+	field, ok := filterFields[name]
+	if !ok {
+		return Condition{}, fmt.Errorf("unknown field %s", name)
+	}
+	raw := strings.TrimSpace(s[at+len(op):])
+	if raw == "" {
+		return Condition{}, errors.New("missing value")
+	}
+
+	c := Condition{Field: name, Op: op}
+	if field.clock {
+		t, err := time.Parse("15:04", raw)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%s takes a time as HH:MM", name)
+		}
+		c.Values = []string{t.Format("15:04")}
+		return c, nil
+	}
+	if op != "==" && op != "!=" {
+		return Condition{}, fmt.Errorf("%s only takes == and !=", name)
+	}
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			c.Values = append(c.Values, strings.ToLower(v))
+		}
+	}
+	if len(c.Values) == 0 {
+		return Condition{}, errors.New("missing value")
+	}
+	return c, nil
+}
+
+// predicate returns the SQL for c and its arguments. Fields and operators
+// come from fixed sets, and values are always bound.
+func (s *Store) predicate(c Condition) (string, []any) {
+	field := filterFields[c.Field]
+	if field.clock {
+		return s.dialect.clock(field.column) + " " + c.Op + " ?", []any{c.Values[0]}
+	}
+	args := make([]any, len(c.Values))
+	for i, v := range c.Values {
+		args[i] = v
+	}
+	in := "IN"
+	if c.Op == "!=" {
+		in = "NOT IN"
+	}
+	return "LOWER(COALESCE(" + field.column + ", '')) " + in + " (" + placeholders(len(c.Values)) + ")", args
+}
+
+// Match reports whether sch meets c, as the predicate would in the database.
+func (c Condition) Match(sch domain.Schedule) bool {
+	field := filterFields[c.Field]
+	v := field.value(sch)
+	if !field.clock {
+		return slices.Contains(c.Values, strings.ToLower(v)) == (c.Op == "==")
+	}
+	if v == "" {
+		return false
+	}
+	want := c.Values[0]
+	switch c.Op {
+	case "==":
+		return v == want
+	case "!=":
+		return v != want
+	case ">=":
+		return v >= want
+	case "<=":
+		return v <= want
+	case ">":
+		return v > want
+	default:
+		return v < want
+	}
+}
+
+// Clock reports whether c compares a time of day.
+func (c Condition) Clock() bool {
+	return filterFields[c.Field].clock
+}
+
+// MatchAll reports whether sch meets every one of conds.
+func MatchAll(conds []Condition, sch domain.Schedule) bool {
+	for _, c := range conds {
+		if !c.Match(sch) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether sch meets f, as QuerySchedules would select it.
+// Timetables is not checked, since it picks among the stored timetables
+// rather than describing a schedule.
+func (f ScheduleFilter) Match(sch domain.Schedule) bool {
+	if f.Line != "" && !strings.EqualFold(sch.Line, strings.TrimSpace(f.Line)) {
+		return false
+	}
+	if f.DestinationID != "" && sch.StationDestinationID != f.DestinationID {
+		return false
+	}
+	if len(f.ServiceTypes) > 0 && !slices.Contains(f.ServiceTypes, sch.ServiceType) {
+		return false
+	}
+	if slices.Contains(f.ExcludeStations, sch.StationID) {
+		return false
+	}
+	if f.After != "" || f.Before != "" {
+		clock := clockOf(sch.DepartsAt)
+		switch {
+		case clock == "":
+			return false
+		case f.After != "" && f.Before != "" && f.After > f.Before:
+			if clock < f.After && clock > f.Before {
+				return false
+			}
+		case f.After != "" && clock < f.After, f.Before != "" && clock > f.Before:
+			return false
+		}
+	}
+	return MatchAll(f.Conditions, sch)
+}
+
+// Order sorts schedules in the order QuerySchedules returns those matching
+// f: by time of day from After, then by station.
+func (f ScheduleFilter) Order(schedules []domain.Schedule) {
+	sort.SliceStable(schedules, func(i, j int) bool {
+		a, b := clockOf(schedules[i].DepartsAt), clockOf(schedules[j].DepartsAt)
+		if f.After != "" {
+			if lateA, lateB := a < f.After, b < f.After; lateA != lateB {
+				return lateB
+			}
+		}
+		if a != b {
+			return a < b
+		}
+		if schedules[i].StationID != schedules[j].StationID {
+			return schedules[i].StationID < schedules[j].StationID
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+}
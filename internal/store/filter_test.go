@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// newTestStore opens an in-memory SQLite store for t.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(context.Background(), DriverSQLite, fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// filterSchedules are departures from MRI on a day, written in Jakarta time
+// as the scraper writes them.
+func filterSchedules() []domain.Schedule {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, calendar.Jakarta)
+	var schedules []domain.Schedule
+	for i, sch := range []struct {
+		line     string
+		departs  time.Duration
+		duration time.Duration
+	}{
+		{"COMMUTER LINE BOGOR", 5*time.Hour + 30*time.Minute, time.Hour},
+		{"COMMUTER LINE BOGOR", 16*time.Hour + 59*time.Minute, time.Hour},
+		{"COMMUTER LINE BOGOR", 17 * time.Hour, time.Hour},
+		{"COMMUTER LINE CIKARANG", 17*time.Hour + 1*time.Minute, 50 * time.Minute},
+		{"COMMUTER LINE CIKARANG", 23*time.Hour + 30*time.Minute, 50 * time.Minute},
+	} {
+		schedules = append(schedules, domain.Schedule{
+			ID:                   fmt.Sprintf("sc_krl_MRI_%d", 1000+i),
+			StationID:            "MRI",
+			StationOriginID:      "MRI",
+			StationDestinationID: "BOO",
+			TrainID:              fmt.Sprint(1000 + i),
+			Line:                 sch.line,
+			Route:                "MANGGARAI-BOGOR",
+			DepartsAt:            day.Add(sch.departs),
+			ArrivesAt:            day.Add(sch.departs + sch.duration),
+			ServiceType:          domain.ServiceCommuter,
+			ServiceDay:           domain.ServiceDayWeekday,
+		})
+	}
+	return schedules
+}
+
+func TestConditionMatchesPredicate(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	schedules := filterSchedules()
+	if err := s.SetSchedules(ctx, "MRI", domain.ServiceDayWeekday, "2026-10-19", schedules); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expr := range []string{
+		"departs_at>=17:00",
+		"departs_at>17:00",
+		"departs_at<17:00",
+		"departs_at<=16:59",
+		"departs_at==17:00",
+		"departs_at!=17:00",
+		"arrives_at<=01:00",
+		"arrives_at>18:00",
+		"line==commuter line bogor;departs_at>=06:00",
+		"line!=COMMUTER LINE BOGOR",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			conds, err := ParseFilter(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, sch := range schedules {
+				if MatchAll(conds, sch) {
+					want = append(want, sch.ID)
+				}
+			}
+			queried, err := s.QuerySchedules(ctx, ScheduleFilter{Conditions: conds})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sch := range queried {
+				got = append(got, sch.ID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("predicate matched %v, Match matched %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetFeatureFlags returns the feature flags admins have overridden.
+func (s *Store) GetFeatureFlags(ctx context.Context) (map[string]bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT name, enabled FROM feature_flags")
+	if err != nil {
+		return nil, fmt.Errorf("get feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var enabled bool
+		if err := rows.Scan(&name, &enabled); err != nil {
+			return nil, fmt.Errorf("get feature flags: %w", err)
+		}
+		flags[name] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+func (s *Store) SetFeatureFlag(ctx context.Context, name string, enabled bool) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO feature_flags (name, enabled, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET enabled = excluded.enabled, updated_at = excluded.updated_at`,
+		name, enabled, time.Now(),
+	); err != nil {
+		return fmt.Errorf("set feature flag %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteFeatureFlag removes an override, returning the flag to its
+// configured default.
+func (s *Store) DeleteFeatureFlag(ctx context.Context, name string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM feature_flags WHERE name = ?", name); err != nil {
+		return fmt.Errorf("delete feature flag %s: %w", name, err)
+	}
+	return nil
+}
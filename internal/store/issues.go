@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"llm-router/internal/domain"
+)
+
+// SetSyncIssues replaces the integrity issues recorded for a sync run.
+func (s *Store) SetSyncIssues(ctx context.Context, runID int64, issues []domain.SyncIssue) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("set sync issues for run %d: %w", runID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM sync_issues WHERE run_id = ?", runID); err != nil {
+		return fmt.Errorf("set sync issues for run %d: %w", runID, err)
+	}
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO sync_issues (run_id, kind, station_id, train_id, service_day, detail) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("set sync issues for run %d: %w", runID, err)
+	}
+	defer stmt.Close()
+	for _, i := range issues {
+		if _, err := stmt.ExecContext(ctx, runID, i.Kind, i.StationID, i.TrainID, i.ServiceDay, i.Detail); err != nil {
+			return fmt.Errorf("set sync issues for run %d: %w", runID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set sync issues for run %d: %w", runID, err)
+	}
+	return nil
+}
+
+// GetSyncIssues returns the integrity issues recorded for a sync run, of kind
+// if it is not empty, grouped by kind.
+func (s *Store) GetSyncIssues(ctx context.Context, runID int64, kind string) ([]domain.SyncIssue, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT kind, station_id, train_id, service_day, detail FROM sync_issues WHERE run_id = ?"
+	args := []any{runID}
+	if kind != "" {
+		query += " AND kind = ?"
+		args = append(args, kind)
+	}
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY kind, station_id, train_id, service_day", args...)
+	if err != nil {
+		return nil, fmt.Errorf("get sync issues for run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	issues := []domain.SyncIssue{}
+	for rows.Next() {
+		var i domain.SyncIssue
+		if err := rows.Scan(&i.Kind, &i.StationID, &i.TrainID, &i.ServiceDay, &i.Detail); err != nil {
+			return nil, fmt.Errorf("get sync issues for run %d: %w", runID, err)
+		}
+		issues = append(issues, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get sync issues for run %d: %w", runID, err)
+	}
+	return issues, nil
+}
+
+// CountSyncIssues counts the integrity issues recorded for a sync run by kind.
+func (s *Store) CountSyncIssues(ctx context.Context, runID int64) (map[string]int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT kind, COUNT(*) FROM sync_issues WHERE run_id = ? GROUP BY kind", runID)
+	if err != nil {
+		return nil, fmt.Errorf("count sync issues for run %d: %w", runID, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var kind string
+		var n int
+		if err := rows.Scan(&kind, &n); err != nil {
+			return nil, fmt.Errorf("count sync issues for run %d: %w", runID, err)
+		}
+		counts[kind] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("count sync issues for run %d: %w", runID, err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// SetLines replaces every stored line with lines. Only the station IDs and
+// order of each line's stations are persisted; names are joined on read.
+func (s *Store) SetLines(ctx context.Context, lines []domain.Line) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("set lines: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM lines"); err != nil {
+		return fmt.Errorf("set lines: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO lines (name, position, station_id, color, updated_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("set lines: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, l := range lines {
+		for i, st := range l.Stations {
+			if _, err := stmt.ExecContext(ctx, l.Name, i+1, st.ID, l.Color, now); err != nil {
+				return fmt.Errorf("set lines: line %s: %w", l.Name, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set lines: %w", err)
+	}
+	return nil
+}
+
+// GetLines returns every line without its station list.
+func (s *Store) GetLines(ctx context.Context) ([]domain.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, color, updated_at, COUNT(*)
+		FROM lines
+		GROUP BY name, color, updated_at
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("get lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []domain.Line
+	for rows.Next() {
+		var l domain.Line
+		if err := rows.Scan(&l.Name, &l.Color, &l.UpdatedAt, &l.StationCount); err != nil {
+			return nil, fmt.Errorf("get lines: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get lines: %w", err)
+	}
+	return lines, nil
+}
+
+// GetStationLines returns the lines through each station by station ID,
+// ordered by name. DisplayName is left for the caller to localize.
+func (s *Store) GetStationLines(ctx context.Context) (map[string][]domain.LineBadge, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT station_id, name, color FROM lines ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("get station lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make(map[string][]domain.LineBadge)
+	for rows.Next() {
+		var stationID string
+		var b domain.LineBadge
+		if err := rows.Scan(&stationID, &b.Line, &b.Color); err != nil {
+			return nil, fmt.Errorf("get station lines: %w", err)
+		}
+		lines[stationID] = append(lines[stationID], b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get station lines: %w", err)
+	}
+	return lines, nil
+}
+
+// GetLine returns a line and its stations in order. The name is matched
+// case-insensitively; ErrNotFound is returned when no line matches.
+func (s *Store) GetLine(ctx context.Context, name string) (domain.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT l.name, l.color, l.updated_at, l.position, l.station_id, COALESCE(st.name, '')
+		FROM lines l
+		LEFT JOIN stations st ON st.id = l.station_id
+		WHERE LOWER(l.name) = LOWER(?)
+		ORDER BY l.position`, strings.TrimSpace(name))
+	if err != nil {
+		return domain.Line{}, fmt.Errorf("get line %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var l domain.Line
+	for rows.Next() {
+		var st domain.LineStation
+		if err := rows.Scan(&l.Name, &l.Color, &l.UpdatedAt, &st.Position, &st.ID, &st.Name); err != nil {
+			return domain.Line{}, fmt.Errorf("get line %s: %w", name, err)
+		}
+		l.Stations = append(l.Stations, st)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Line{}, fmt.Errorf("get line %s: %w", name, err)
+	}
+	if len(l.Stations) == 0 {
+		return domain.Line{}, ErrNotFound
+	}
+	l.StationCount = len(l.Stations)
+	return l, nil
+}
+
+// SetServiceTypes sets the service type of every schedule on each line in
+// types, keyed by line name.
+func (s *Store) SetServiceTypes(ctx context.Context, types map[string]string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("set service types: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "UPDATE schedules SET service_type = ? WHERE line = ?")
+	if err != nil {
+		return fmt.Errorf("set service types: %w", err)
+	}
+	defer stmt.Close()
+
+	for line, service := range types {
+		if _, err := stmt.ExecContext(ctx, service, line); err != nil {
+			return fmt.Errorf("set service types: line %s: %w", line, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set service types: %w", err)
+	}
+	return nil
+}
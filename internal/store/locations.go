@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// SetStationLocation sets a station's coordinates, keeping any imported
+// address and municipality.
+func (s *Store) SetStationLocation(ctx context.Context, loc domain.StationLocation) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO station_locations (station_id, lat, lng, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(station_id) DO UPDATE SET lat = excluded.lat, lng = excluded.lng, updated_at = excluded.updated_at`,
+		loc.StationID, loc.Lat, loc.Lng, time.Now())
+	if err != nil {
+		return fmt.Errorf("set location for %s: %w", loc.StationID, err)
+	}
+	return nil
+}
+
+// ImportStationLocations merges imported geodata into the station locations
+// by station ID in one transaction. An empty address or municipality keeps
+// the one already stored.
+func (s *Store) ImportStationLocations(ctx context.Context, locs []domain.StationLocation) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("import station locations: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO station_locations (station_id, lat, lng, address, municipality, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(station_id) DO UPDATE SET
+			lat = excluded.lat, lng = excluded.lng,
+			address = COALESCE(NULLIF(excluded.address, ''), station_locations.address),
+			municipality = COALESCE(NULLIF(excluded.municipality, ''), station_locations.municipality),
+			updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("import station locations: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, loc := range locs {
+		if _, err := stmt.ExecContext(ctx, loc.StationID, loc.Lat, loc.Lng, loc.Address, loc.Municipality, now); err != nil {
+			return fmt.Errorf("import location for %s: %w", loc.StationID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import station locations: %w", err)
+	}
+	return nil
+}
+
+const locationColumns = "station_id, lat, lng, COALESCE(address, ''), COALESCE(municipality, '')"
+
+func scanLocation(row rowScanner) (domain.StationLocation, error) {
+	var loc domain.StationLocation
+	err := row.Scan(&loc.StationID, &loc.Lat, &loc.Lng, &loc.Address, &loc.Municipality)
+	return loc, err
+}
+
+// GetStationLocation returns a station's location, or ErrNotFound.
+func (s *Store) GetStationLocation(ctx context.Context, stationID string) (domain.StationLocation, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	loc, err := scanLocation(s.db.QueryRowContext(ctx, "SELECT "+locationColumns+" FROM station_locations WHERE station_id = ?", stationID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.StationLocation{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.StationLocation{}, fmt.Errorf("get location for %s: %w", stationID, err)
+	}
+	return loc, nil
+}
+
+func (s *Store) GetStationLocations(ctx context.Context) ([]domain.StationLocation, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+locationColumns+" FROM station_locations")
+	if err != nil {
+		return nil, fmt.Errorf("get station locations: %w", err)
+	}
+	defer rows.Close()
+
+	var locs []domain.StationLocation
+	for rows.Next() {
+		loc, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get station locations: %w", err)
+		}
+		locs = append(locs, loc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get station locations: %w", err)
+	}
+	return locs, nil
+}
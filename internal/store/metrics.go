@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// DBStats returns the connection pool statistics and the size of the
+// database.
+func (s *Store) DBStats(ctx context.Context) (domain.DBStats, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	size, err := s.dialect.size(ctx, s.db)
+	if err != nil {
+		return domain.DBStats{}, fmt.Errorf("get db stats: %w", err)
+	}
+
+	pool := s.db.Stats()
+	return domain.DBStats{
+		SizeBytes:       size,
+		OpenConnections: pool.OpenConnections,
+		InUse:           pool.InUse,
+		Idle:            pool.Idle,
+		WaitCount:       pool.WaitCount,
+		WaitMillis:      pool.WaitDuration.Milliseconds(),
+	}, nil
+}
+
+func (s *Store) SaveMetricsSnapshot(ctx context.Context, snap domain.MetricsSnapshot) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	caches, err := json.Marshal(snap.Caches)
+	if err != nil {
+		return fmt.Errorf("save metrics snapshot: %w", err)
+	}
+	db, err := json.Marshal(snap.DB)
+	if err != nil {
+		return fmt.Errorf("save metrics snapshot: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		"INSERT INTO metrics_snapshots (taken_at, goroutines, heap_bytes, caches, db) VALUES (?, ?, ?, ?, ?)",
+		snap.TakenAt, snap.Goroutines, snap.HeapBytes, string(caches), string(db),
+	); err != nil {
+		return fmt.Errorf("save metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetMetricsSnapshots returns up to limit snapshots taken after since, newest
+// first.
+func (s *Store) GetMetricsSnapshots(ctx context.Context, since time.Time, limit int) ([]domain.MetricsSnapshot, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, taken_at, goroutines, heap_bytes, caches, db FROM metrics_snapshots
+		WHERE taken_at > ? ORDER BY taken_at DESC LIMIT ?`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get metrics snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snaps := []domain.MetricsSnapshot{}
+	for rows.Next() {
+		var snap domain.MetricsSnapshot
+		var caches, db string
+		if err := rows.Scan(&snap.ID, &snap.TakenAt, &snap.Goroutines, &snap.HeapBytes, &caches, &db); err != nil {
+			return nil, fmt.Errorf("get metrics snapshots: %w", err)
+		}
+		if err := json.Unmarshal([]byte(caches), &snap.Caches); err != nil {
+			return nil, fmt.Errorf("get metrics snapshots: %w", err)
+		}
+		if err := json.Unmarshal([]byte(db), &snap.DB); err != nil {
+			return nil, fmt.Errorf("get metrics snapshots: %w", err)
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get metrics snapshots: %w", err)
+	}
+	return snaps, nil
+}
+
+// PruneMetricsSnapshots deletes snapshots taken before cutoff.
+func (s *Store) PruneMetricsSnapshots(ctx context.Context, cutoff time.Time) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM metrics_snapshots WHERE taken_at < ?", cutoff); err != nil {
+		return fmt.Errorf("prune metrics snapshots: %w", err)
+	}
+	return nil
+}
+
+// TableCounts returns the number of rows in each table.
+func (s *Store) TableCounts(ctx context.Context) (map[string]int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tables, err := s.dialect.tables(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("count table rows: %w", err)
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var n int64
+		// Table names come from the database, not from the request.
+		if err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %q", table)).Scan(&n); err != nil {
+			return nil, fmt.Errorf("count rows of %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
+
+// Ping checks that the database is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+// ScheduleFreshness returns the number of stored schedules and when the
+// newest was written, zero when there are none.
+func (s *Store) ScheduleFreshness(ctx context.Context) (int64, time.Time, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	var updated sql.NullInt64
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*), "+s.dialect.epoch("MAX(updated_at)")+" FROM schedules").
+		Scan(&count, &updated)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("get schedule freshness: %w", err)
+	}
+	if !updated.Valid {
+		return count, time.Time{}, nil
+	}
+	return count, time.Unix(updated.Int64, 0), nil
+}
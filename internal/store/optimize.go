@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// Optimize reclaims the space freed by deletions and refreshes the query
+// planner's statistics. It is not bounded by the store's timeout, since it
+// can take a while on a large database.
+func (s *Store) Optimize(ctx context.Context) error {
+	for _, stmt := range s.dialect.optimize() {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("optimize: %s: %w", stmt, err)
+		}
+	}
+	return nil
+}
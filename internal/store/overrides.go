@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const overrideColumns = `id, kind, train_id, COALESCE(station_id, ''), COALESCE(service_date, ''), COALESCE(shift_minutes, 0),
+	COALESCE(departs_at, ''), COALESCE(arrives_at, ''), COALESCE(line, ''), COALESCE(route, ''), COALESCE(station_origin_id, ''),
+	COALESCE(station_destination_id, ''), COALESCE(service_type, ''), COALESCE(note, ''), created_at, updated_at`
+
+func scanOverride(row rowScanner) (domain.ScheduleOverride, error) {
+	var o domain.ScheduleOverride
+	err := row.Scan(&o.ID, &o.Kind, &o.TrainID, &o.StationID, &o.ServiceDate, &o.ShiftMinutes,
+		&o.DepartsAt, &o.ArrivesAt, &o.Line, &o.Route, &o.StationOriginID,
+		&o.StationDestinationID, &o.ServiceType, &o.Note, &o.CreatedAt, &o.UpdatedAt)
+	return o, err
+}
+
+// CreateScheduleOverride stores a new schedule override and returns it with
+// its ID.
+func (s *Store) CreateScheduleOverride(ctx context.Context, o domain.ScheduleOverride) (domain.ScheduleOverride, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	o.CreatedAt = time.Now()
+	o.UpdatedAt = o.CreatedAt
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO schedule_overrides (kind, train_id, station_id, service_date, shift_minutes, departs_at, arrives_at,
+			line, route, station_origin_id, station_destination_id, service_type, note, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`,
+		o.Kind, o.TrainID, o.StationID, o.ServiceDate, o.ShiftMinutes, o.DepartsAt, o.ArrivesAt,
+		o.Line, o.Route, o.StationOriginID, o.StationDestinationID, o.ServiceType, o.Note, o.CreatedAt, o.UpdatedAt,
+	).Scan(&o.ID)
+	if err != nil {
+		return domain.ScheduleOverride{}, fmt.Errorf("create schedule override: %w", err)
+	}
+	return o, nil
+}
+
+// UpdateScheduleOverride replaces the content of override o.ID. It returns
+// ErrNotFound when there is no such override.
+func (s *Store) UpdateScheduleOverride(ctx context.Context, o domain.ScheduleOverride) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE schedule_overrides SET kind = ?, train_id = ?, station_id = ?, service_date = ?, shift_minutes = ?,
+			departs_at = ?, arrives_at = ?, line = ?, route = ?, station_origin_id = ?, station_destination_id = ?,
+			service_type = ?, note = ?, updated_at = ?
+		WHERE id = ?`,
+		o.Kind, o.TrainID, o.StationID, o.ServiceDate, o.ShiftMinutes, o.DepartsAt, o.ArrivesAt,
+		o.Line, o.Route, o.StationOriginID, o.StationDestinationID, o.ServiceType, o.Note, time.Now(), o.ID)
+	if err != nil {
+		return fmt.Errorf("update schedule override %d: %w", o.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteScheduleOverride returns ErrNotFound when there is no override with
+// the given ID.
+func (s *Store) DeleteScheduleOverride(ctx context.Context, id int64) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM schedule_overrides WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete schedule override %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetScheduleOverride returns ErrNotFound when there is no override with the
+// given ID.
+func (s *Store) GetScheduleOverride(ctx context.Context, id int64) (domain.ScheduleOverride, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	o, err := scanOverride(s.db.QueryRowContext(ctx, "SELECT "+overrideColumns+" FROM schedule_overrides WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ScheduleOverride{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.ScheduleOverride{}, fmt.Errorf("get schedule override %d: %w", id, err)
+	}
+	return o, nil
+}
+
+// ListScheduleOverrides returns every schedule override, oldest first, which
+// is the order they are applied in.
+func (s *Store) ListScheduleOverrides(ctx context.Context) ([]domain.ScheduleOverride, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+overrideColumns+" FROM schedule_overrides ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("list schedule overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []domain.ScheduleOverride{}
+	for rows.Next() {
+		o, err := scanOverride(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list schedule overrides: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list schedule overrides: %w", err)
+	}
+	return overrides, nil
+}
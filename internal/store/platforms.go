@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// GetPlatformAssignments returns a station's platform assignments by line,
+// whole-line assignments first.
+func (s *Store) GetPlatformAssignments(ctx context.Context, stationID string) ([]domain.PlatformAssignment, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT line, destination_id, platform FROM platform_assignments
+		WHERE station_id = ? ORDER BY line, destination_id`, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("get platforms for %s: %w", stationID, err)
+	}
+	defer rows.Close()
+
+	var assignments []domain.PlatformAssignment
+	for rows.Next() {
+		var a domain.PlatformAssignment
+		if err := rows.Scan(&a.Line, &a.DestinationID, &a.Platform); err != nil {
+			return nil, fmt.Errorf("get platforms for %s: %w", stationID, err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get platforms for %s: %w", stationID, err)
+	}
+	return assignments, nil
+}
+
+// SetPlatformAssignment assigns a platform to a station's departures of a
+// line, towards a destination when it is set, replacing any assignment it
+// already has.
+func (s *Store) SetPlatformAssignment(ctx context.Context, stationID string, a domain.PlatformAssignment) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO platform_assignments (station_id, line, destination_id, platform, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(station_id, line, destination_id) DO UPDATE SET platform = excluded.platform, updated_at = excluded.updated_at`,
+		stationID, a.Line, a.DestinationID, a.Platform, time.Now())
+	if err != nil {
+		return fmt.Errorf("set platform for %s %s: %w", stationID, a.Line, err)
+	}
+	return nil
+}
+
+// DeletePlatformAssignment returns ErrNotFound when the station has no
+// assignment for line and destinationID.
+func (s *Store) DeletePlatformAssignment(ctx context.Context, stationID, line, destinationID string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM platform_assignments WHERE station_id = ? AND line = ? AND destination_id = ?", stationID, line, destinationID)
+	if err != nil {
+		return fmt.Errorf("delete platform for %s %s: %w", stationID, line, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete platform for %s %s: %w", stationID, line, err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"llm-router/internal/calendar"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// postgresDialect keeps the data in a PostgreSQL database that several
+// instances can share.
+type postgresDialect struct{}
+
+func (postgresDialect) open(dsn string) (*sql.DB, error) {
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	// Timestamps are read back in Jakarta, the zone schedules are written in
+	// (see writeSchedules), so both backends return the same times.
+	connector := stdlib.GetConnector(*cfg, stdlib.OptionAfterConnect(func(ctx context.Context, conn *pgx.Conn) error {
+		conn.TypeMap().RegisterType(&pgtype.Type{
+			Name:  "timestamptz",
+			OID:   pgtype.TimestamptzOID,
+			Codec: &pgtype.TimestamptzCodec{ScanLocation: calendar.Jakarta},
+		})
+		return nil
+	}))
+
+	db := sql.OpenDB(rebindConnector{connector})
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqliteTypes are the SQLite column types that PostgreSQL lacks or sizes
+// differently, and what they become.
+var sqliteTypes = map[string]string{
+	"INTEGER PRIMARY KEY AUTOINCREMENT": "BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY",
+	"INTEGER":                           "BIGINT",
+	"REAL":                              "DOUBLE PRECISION",
+	"DATETIME":                          "TIMESTAMPTZ",
+	"JSON":                              "TEXT",
+	"BLOB":                              "BYTEA",
+}
+
+var sqliteTypePattern = regexp.MustCompile(`\b(INTEGER PRIMARY KEY AUTOINCREMENT|INTEGER|REAL|DATETIME|JSON|BLOB)\b`)
+
+func (postgresDialect) schema(ddl string) string {
+	return sqliteTypePattern.ReplaceAllStringFunc(ddl, func(t string) string { return sqliteTypes[t] })
+}
+
+func (postgresDialect) hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var n int
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`, table, column).Scan(&n)
+	return n > 0, err
+}
+
+func (postgresDialect) tables(ctx context.Context, db *sql.DB) ([]string, error) {
+	return queryStrings(ctx, db, `
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'`)
+}
+
+func (postgresDialect) size(ctx context.Context, db *sql.DB) (int64, error) {
+	var n int64
+	err := db.QueryRowContext(ctx, "SELECT pg_database_size(current_database())").Scan(&n)
+	return n, err
+}
+
+// optimize leaves reclaiming space to autovacuum.
+func (postgresDialect) optimize() []string {
+	return []string{"ANALYZE"}
+}
+
+func (postgresDialect) hour(column string) string {
+	return "CAST(EXTRACT(HOUR FROM " + column + " AT TIME ZONE 'Asia/Jakarta') AS INTEGER)"
+}
+
+func (postgresDialect) clock(column string) string {
+	return "to_char(" + column + " AT TIME ZONE 'Asia/Jakarta', 'HH24:MI')"
+}
+
+func (postgresDialect) epoch(expr string) string {
+	return "CAST(EXTRACT(EPOCH FROM " + expr + ") AS BIGINT)"
+}
+
+func (postgresDialect) backup(context.Context, *sql.DB, string) error {
+	return ErrBackupUnsupported
+}
+
+// tryLock takes a session-level advisory lock on a connection kept for the
+// holder, so the lock is also released if the instance dies.
+func (postgresDialect) tryLock(ctx context.Context, db *sql.DB, name string) (func(), bool, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext(?))", name).Scan(&ok); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		// Closing the connection would not end the session, since it goes
+		// back to the pool, so the lock is released explicitly.
+		// The lock outlives ctx, so releasing it does not use it.
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext(?))", name)
+		conn.Close()
+	}, true, nil
+}
+
+// rebindConnector hands out connections that accept the store's ? placeholders.
+type rebindConnector struct {
+	driver.Connector
+}
+
+func (c rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rebindConn{conn.(*stdlib.Conn)}, nil
+}
+
+// rebindConn numbers the ? placeholders of every statement before passing it
+// on. Everything else is the pgx connection's.
+type rebindConn struct {
+	*stdlib.Conn
+}
+
+func (c rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.PrepareContext(ctx, rebind(query))
+}
+
+func (c rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.ExecContext(ctx, rebind(query), args)
+}
+
+func (c rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.QueryContext(ctx, rebind(query), args)
+}
+
+// rebind replaces the ? placeholders of query with $1, $2 and so on, leaving
+// quoted strings and identifiers alone.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
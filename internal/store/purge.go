@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// PurgeStale deletes the data of stations no longer in the station list and
+// the delay samples observed before cutoff. Station data
+// is left alone while the station list is empty, so a purge before the first
+// sync does not wipe an imported snapshot.
+func (s *Store) PurgeStale(ctx context.Context, cutoff time.Time) (domain.PurgeResult, error) {
+	result := domain.PurgeResult{Cutoff: cutoff}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.PurgeResult{}, fmt.Errorf("purge stale data: %w", err)
+	}
+	defer tx.Rollback()
+
+	const orphaned = "station_id NOT IN (SELECT id FROM stations) AND EXISTS (SELECT 1 FROM stations)"
+	steps := []struct {
+		n     *int64
+		query string
+		args  []any
+	}{
+		{&result.Schedules, "DELETE FROM schedules WHERE " + orphaned, nil},
+		{&result.Facilities, "DELETE FROM station_facilities WHERE " + orphaned, nil},
+		{&result.Locations, "DELETE FROM station_locations WHERE " + orphaned, nil},
+		{&result.DelaySamples, "DELETE FROM train_delay_samples WHERE observed_at < ?", []any{cutoff}},
+	}
+	for _, step := range steps {
+		if *step.n, err = execCount(ctx, tx, step.query, step.args...); err != nil {
+			return domain.PurgeResult{}, fmt.Errorf("purge stale data: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.PurgeResult{}, fmt.Errorf("purge stale data: %w", err)
+	}
+	return result, nil
+}
+
+func execCount(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
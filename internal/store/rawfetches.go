@@ -0,0 +1,146 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// SaveRawFetch records an upstream response, compressed, and deletes all but
+// the newest keep responses from the same URL.
+func (s *Store) SaveRawFetch(ctx context.Context, f domain.RawFetch, keep int) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(f.Body); err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO raw_fetches (url, status, fetched_at, size, body) VALUES (?, ?, ?, ?, ?)",
+		f.URL, f.Status, f.FetchedAt, len(f.Body), buf.Bytes(),
+	); err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM raw_fetches WHERE url = ? AND id NOT IN (
+			SELECT id FROM raw_fetches WHERE url = ? ORDER BY id DESC LIMIT ?
+		)`, f.URL, f.URL, keep,
+	); err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save raw fetch: %w", err)
+	}
+	return nil
+}
+
+// LatestRawFetch returns the newest recorded response from url, or
+// ErrNotFound.
+func (s *Store) LatestRawFetch(ctx context.Context, url string) (domain.RawFetch, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	f, err := scanRawFetch(s.db.QueryRowContext(ctx,
+		"SELECT id, url, status, fetched_at, size, body FROM raw_fetches WHERE url = ? ORDER BY id DESC LIMIT 1", url))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.RawFetch{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.RawFetch{}, fmt.Errorf("get raw fetch of %s: %w", url, err)
+	}
+	return f, nil
+}
+
+// GetRawFetch returns the recorded response with the given ID, or
+// ErrNotFound.
+func (s *Store) GetRawFetch(ctx context.Context, id int64) (domain.RawFetch, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	f, err := scanRawFetch(s.db.QueryRowContext(ctx,
+		"SELECT id, url, status, fetched_at, size, body FROM raw_fetches WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.RawFetch{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.RawFetch{}, fmt.Errorf("get raw fetch %d: %w", id, err)
+	}
+	return f, nil
+}
+
+// PruneRawFetches deletes the responses recorded before cutoff and returns
+// how many it deleted.
+func (s *Store) PruneRawFetches(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM raw_fetches WHERE fetched_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("prune raw fetches: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanRawFetch(row rowScanner) (domain.RawFetch, error) {
+	var f domain.RawFetch
+	var compressed []byte
+	if err := row.Scan(&f.ID, &f.URL, &f.Status, &f.FetchedAt, &f.Size, &compressed); err != nil {
+		return domain.RawFetch{}, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return domain.RawFetch{}, err
+	}
+	if f.Body, err = io.ReadAll(zr); err != nil {
+		return domain.RawFetch{}, err
+	}
+	return f, nil
+}
+
+// GetRawFetches lists up to limit recorded responses, newest first, without
+// their bodies.
+func (s *Store) GetRawFetches(ctx context.Context, limit int) ([]domain.RawFetch, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, url, status, fetched_at, size FROM raw_fetches ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("get raw fetches: %w", err)
+	}
+	defer rows.Close()
+
+	fetches := []domain.RawFetch{}
+	for rows.Next() {
+		var f domain.RawFetch
+		if err := rows.Scan(&f.ID, &f.URL, &f.Status, &f.FetchedAt, &f.Size); err != nil {
+			return nil, fmt.Errorf("get raw fetches: %w", err)
+		}
+		fetches = append(fetches, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get raw fetches: %w", err)
+	}
+	return fetches, nil
+}
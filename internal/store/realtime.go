@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const trainPositionColumns = `train_id, station_id, next_station_id, latitude, longitude,
+	delay_minutes, status, observed_at, updated_at`
+
+// SetTrainPositions upserts the latest observation for each train.
+func (s *Store) SetTrainPositions(ctx context.Context, positions []domain.TrainPosition) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("set train positions: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO train_positions (`+trainPositionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(train_id) DO UPDATE SET
+			station_id = excluded.station_id,
+			next_station_id = excluded.next_station_id,
+			latitude = excluded.latitude,
+			longitude = excluded.longitude,
+			delay_minutes = excluded.delay_minutes,
+			status = excluded.status,
+			observed_at = excluded.observed_at,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("set train positions: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range positions {
+		_, err := stmt.ExecContext(ctx,
+			p.TrainID, p.StationID, p.NextStationID, p.Latitude, p.Longitude,
+			p.DelayMinutes, p.Status, p.ObservedAt, p.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("set train positions: train %s: %w", p.TrainID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set train positions: %w", err)
+	}
+	return nil
+}
+
+// PruneTrainPositions removes observations that have not been refreshed since before.
+func (s *Store) PruneTrainPositions(ctx context.Context, before time.Time) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM train_positions WHERE updated_at < ?", before); err != nil {
+		return fmt.Errorf("prune train positions: %w", err)
+	}
+	return nil
+}
+
+// GetTrainPosition returns ErrNotFound when the train has no recent position.
+func (s *Store) GetTrainPosition(ctx context.Context, trainID string) (domain.TrainPosition, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+trainPositionColumns+" FROM train_positions WHERE train_id = ?", trainID)
+	p, err := scanTrainPosition(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.TrainPosition{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.TrainPosition{}, fmt.Errorf("get train position %s: %w", trainID, err)
+	}
+	return p, nil
+}
+
+// GetStationTrainPositions returns trains currently at, or heading to, the given station.
+func (s *Store) GetStationTrainPositions(ctx context.Context, stationID string) ([]domain.TrainPosition, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+trainPositionColumns+`
+		FROM train_positions WHERE station_id = ? OR next_station_id = ?
+		ORDER BY observed_at DESC`, stationID, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("get train positions for %s: %w", stationID, err)
+	}
+	defer rows.Close()
+
+	var positions []domain.TrainPosition
+	for rows.Next() {
+		p, err := scanTrainPosition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get train positions for %s: %w", stationID, err)
+		}
+		positions = append(positions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get train positions for %s: %w", stationID, err)
+	}
+	return positions, nil
+}
+
+func scanTrainPosition(row rowScanner) (domain.TrainPosition, error) {
+	var p domain.TrainPosition
+	err := row.Scan(
+		&p.TrainID, &p.StationID, &p.NextStationID, &p.Latitude, &p.Longitude,
+		&p.DelayMinutes, &p.Status, &p.ObservedAt, &p.UpdatedAt,
+	)
+	return p, err
+}
@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+const (
+	// OnTimeThresholdMinutes is the largest delay still counted as on time.
+	OnTimeThresholdMinutes = 5
+	// reportWeight discounts crowdsourced reports against realtime observations.
+	reportWeight = 0.5
+	// scheduleChangePenalty is taken off a train's score for each of its
+	// departures the synced timetables retimed within the window.
+	scheduleChangePenalty = 0.05
+	// alertPenalty is taken off a train's score for each warning or severe
+	// announcement on its line within the window.
+	alertPenalty = 0.03
+	// maxHistoryPenalty bounds what schedule changes and alerts take off.
+	maxHistoryPenalty = 0.5
+)
+
+func (s *Store) AddDelaySamples(ctx context.Context, samples []domain.DelaySample) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("add delay samples: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO train_delay_samples (train_id, station_id, source, delay_minutes, observed_at)
+		VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("add delay samples: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range samples {
+		if _, err := stmt.ExecContext(ctx, d.TrainID, d.StationID, d.Source, d.DelayMinutes, d.ObservedAt); err != nil {
+			return fmt.Errorf("add delay samples: train %s: %w", d.TrainID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("add delay samples: %w", err)
+	}
+	return nil
+}
+
+// AddDelayReport records a rider's report, replacing any earlier report from
+// the same reporter on the same train and service date, so that one client
+// counts once a day towards a train's score.
+func (s *Store) AddDelayReport(ctx context.Context, d domain.DelaySample) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("add delay report: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM train_delay_samples
+		WHERE train_id = ? AND source = ? AND reporter = ? AND service_date = ?`,
+		d.TrainID, domain.DelaySourceReport, d.Reporter, d.ServiceDate)
+	if err != nil {
+		return fmt.Errorf("add delay report: train %s: %w", d.TrainID, err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO train_delay_samples (train_id, station_id, source, delay_minutes, observed_at, reporter, service_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		d.TrainID, d.StationID, domain.DelaySourceReport, d.DelayMinutes, d.ObservedAt, d.Reporter, d.ServiceDate)
+	if err != nil {
+		return fmt.Errorf("add delay report: train %s: %w", d.TrainID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("add delay report: %w", err)
+	}
+	return nil
+}
+
+// GetTrainReliability scores each of the given trains from what happened to
+// it since the given time: the delays observed in realtime and reported by
+// riders, how often the synced timetables retimed its departures, and the
+// disruption announcements on its line. Trains with none of these are
+// omitted.
+func (s *Store) GetTrainReliability(ctx context.Context, trainIDs []string, since time.Time) (map[string]domain.Reliability, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res := make(map[string]domain.Reliability)
+	if len(trainIDs) == 0 {
+		return res, nil
+	}
+
+	ids := make([]any, len(trainIDs))
+	for i, id := range trainIDs {
+		ids[i] = id
+	}
+	in := "(" + placeholders(len(ids)) + ")"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT train_id, source, COUNT(*),
+			SUM(CASE WHEN delay_minutes <= ? THEN 1 ELSE 0 END),
+			SUM(delay_minutes)
+		FROM train_delay_samples
+		WHERE observed_at >= ? AND train_id IN `+in+`
+		GROUP BY train_id, source`, append([]any{OnTimeThresholdMinutes, since}, ids...)...)
+	if err != nil {
+		return nil, fmt.Errorf("get train reliability: %w", err)
+	}
+	defer rows.Close()
+
+	type acc struct {
+		weight, onTime, delay float64
+		observations, reports int
+	}
+	totals := make(map[string]*acc)
+	for rows.Next() {
+		var trainID string
+		var source domain.DelaySource
+		var count, onTime, delaySum int
+		if err := rows.Scan(&trainID, &source, &count, &onTime, &delaySum); err != nil {
+			return nil, fmt.Errorf("get train reliability: %w", err)
+		}
+
+		a, ok := totals[trainID]
+		if !ok {
+			a = &acc{}
+			totals[trainID] = a
+		}
+
+		w := 1.0
+		if source == domain.DelaySourceReport {
+			w = reportWeight
+			a.reports += count
+		} else {
+			a.observations += count
+		}
+		a.weight += w * float64(count)
+		a.onTime += w * float64(onTime)
+		a.delay += w * float64(delaySum)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get train reliability: %w", err)
+	}
+
+	// The timetable of each service date is kept as synced, so a departure
+	// with more than one time of day across them was retimed by a sync.
+	changes, err := s.trainCounts(ctx, `
+		SELECT train_id,
+			COUNT(DISTINCT station_id || '/' || `+serviceDayColumn+` || '/' || `+s.dialect.clock("departs_at")+`)
+			- COUNT(DISTINCT station_id || '/' || `+serviceDayColumn+`)
+		FROM schedule_history
+		WHERE service_date >= ? AND train_id IN `+in+`
+		GROUP BY train_id`, append([]any{since.In(calendar.Jakarta).Format(time.DateOnly)}, ids...)...)
+	if err != nil {
+		return nil, fmt.Errorf("get train reliability: %w", err)
+	}
+	alerts, err := s.trainCounts(ctx, `
+		SELECT t.train_id, COUNT(DISTINCT a.id)
+		FROM announcements a
+		JOIN (SELECT DISTINCT train_id, line FROM schedules WHERE train_id IN `+in+`) t ON t.line = a.line
+		WHERE a.severity IN (?, ?) AND a.starts_at <= ? AND (a.ends_at IS NULL OR a.ends_at >= ?)
+		GROUP BY t.train_id`, append(ids, domain.SeverityWarning, domain.SeveritySevere, time.Now(), since)...)
+	if err != nil {
+		return nil, fmt.Errorf("get train reliability: %w", err)
+	}
+
+	for _, trainID := range trainIDs {
+		a, ok := totals[trainID]
+		if !ok && changes[trainID] == 0 && alerts[trainID] == 0 {
+			continue
+		}
+		rel := domain.Reliability{ScheduleChanges: changes[trainID], Alerts: alerts[trainID]}
+		rate := 1.0
+		if ok && a.weight > 0 {
+			rate = a.onTime / a.weight
+			rel.OnTimeRate = math.Round(rate*1000) / 1000
+			rel.AvgDelayMinutes = math.Round(a.delay/a.weight*10) / 10
+			rel.Observations, rel.Reports = a.observations, a.reports
+		}
+		penalty := min(maxHistoryPenalty, scheduleChangePenalty*float64(rel.ScheduleChanges)+alertPenalty*float64(rel.Alerts))
+		rel.Score = int(math.Round(rate * (1 - penalty) * 100))
+		res[trainID] = rel
+	}
+	return res, nil
+}
+
+// trainCounts runs a query selecting a train ID and a count per row.
+func (s *Store) trainCounts(ctx context.Context, query string, args ...any) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var trainID string
+		var n int
+		if err := rows.Scan(&trainID, &n); err != nil {
+			return nil, err
+		}
+		counts[trainID] = n
+	}
+	return counts, rows.Err()
+}
+
+// GetLineDelays summarises the delay samples observed since the given time
+// by the line of the delayed train. Lines without samples are omitted.
+func (s *Store) GetLineDelays(ctx context.Context, since time.Time) (map[string]domain.LineDelays, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT t.line, COUNT(*), AVG(d.delay_minutes), MAX(d.delay_minutes)
+		FROM train_delay_samples d
+		JOIN (SELECT DISTINCT train_id, line FROM schedules) t ON t.train_id = d.train_id
+		WHERE d.observed_at >= ?
+		GROUP BY t.line`, since)
+	if err != nil {
+		return nil, fmt.Errorf("get line delays: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[string]domain.LineDelays)
+	for rows.Next() {
+		var line string
+		var d domain.LineDelays
+		var avg float64
+		if err := rows.Scan(&line, &d.Samples, &avg, &d.MaxDelayMinutes); err != nil {
+			return nil, fmt.Errorf("get line delays: %w", err)
+		}
+		d.AvgDelayMinutes = math.Round(avg*10) / 10
+		res[line] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get line delays: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+func TestAddDelayReportKeepsOnePerReporterAndDay(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, r := range []struct {
+		reporter, date string
+		delay          int
+	}{
+		{"203.0.113.7", "2026-10-19", 30},
+		{"203.0.113.7", "2026-10-19", 45},
+		{"203.0.113.7", "2026-10-20", 0},
+		{"198.51.100.2", "2026-10-19", 0},
+	} {
+		err := s.AddDelayReport(ctx, domain.DelaySample{
+			TrainID:      "1001",
+			StationID:    "MRI",
+			Source:       domain.DelaySourceReport,
+			DelayMinutes: r.delay,
+			ObservedAt:   now,
+			Reporter:     r.reporter,
+			ServiceDate:  r.date,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scores, err := s.GetTrainReliability(ctx, []string{"1001"}, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel := scores["1001"]
+	if rel.Reports != 3 {
+		t.Errorf("reports = %d, want 3: the repeat on 2026-10-19 replaces the first", rel.Reports)
+	}
+	if rel.AvgDelayMinutes != 15 {
+		t.Errorf("average delay = %v, want 15", rel.AvgDelayMinutes)
+	}
+}
+
+func TestTrainReliabilityWeighsScheduleChangesAndAlerts(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	today := now.In(calendar.Jakarta)
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, calendar.Jakarta)
+
+	// Train 1001 is retimed by the second day's sync; 1002 keeps its time.
+	for i, departs := range []time.Duration{8 * time.Hour, 8*time.Hour + 10*time.Minute} {
+		date := day.AddDate(0, 0, i-2)
+		var schedules []domain.Schedule
+		for j, trainID := range []string{"1001", "1002"} {
+			at := date.Add(9 * time.Hour)
+			if j == 0 {
+				at = date.Add(departs)
+			}
+			schedules = append(schedules, domain.Schedule{
+				ID:                   "MRI-" + trainID,
+				StationID:            "MRI",
+				StationOriginID:      "MRI",
+				StationDestinationID: "BOO",
+				TrainID:              trainID,
+				Line:                 "COMMUTER LINE BOGOR",
+				DepartsAt:            at,
+				ArrivesAt:            at.Add(time.Hour),
+				ServiceDay:           domain.ServiceDayWeekday,
+			})
+		}
+		if err := s.SetSchedules(ctx, "MRI", domain.ServiceDayWeekday, date.Format(time.DateOnly), schedules); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.CreateAnnouncement(ctx, domain.Announcement{
+		Line:     "COMMUTER LINE BOGOR",
+		Severity: domain.SeveritySevere,
+		Message:  "Gangguan sinyal",
+		StartsAt: now.Add(-time.Hour),
+		Source:   "admin",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	scores, err := s.GetTrainReliability(ctx, []string{"1001", "1002"}, now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.Reliability{Score: 92, ScheduleChanges: 1, Alerts: 1}
+	if got := scores["1001"]; got != want {
+		t.Errorf("train 1001 = %+v, want %+v", got, want)
+	}
+	if got := scores["1002"]; got.ScheduleChanges != 0 || got.Alerts != 1 || got.Score != 97 {
+		t.Errorf("train 1002 = %+v, want only the alert counted", got)
+	}
+}
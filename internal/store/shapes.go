@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// ImportLineShapes stores the rail alignment of each line in shapes,
+// replacing any shape already stored for it, in one transaction.
+func (s *Store) ImportLineShapes(ctx context.Context, shapes []domain.LineShape) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("import line shapes: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO line_shapes (line, paths, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(line) DO UPDATE SET paths = excluded.paths, updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("import line shapes: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, shape := range shapes {
+		paths, err := json.Marshal(shape.Paths)
+		if err != nil {
+			return fmt.Errorf("encode shape of %s: %w", shape.Line, err)
+		}
+		if _, err := stmt.ExecContext(ctx, shape.Line, string(paths), now); err != nil {
+			return fmt.Errorf("import shape of %s: %w", shape.Line, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import line shapes: %w", err)
+	}
+	return nil
+}
+
+// GetLineShape returns the rail alignment of a line, matched by name
+// regardless of case, or ErrNotFound.
+func (s *Store) GetLineShape(ctx context.Context, line string) (domain.LineShape, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var shape domain.LineShape
+	var paths string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT line, paths, updated_at FROM line_shapes WHERE LOWER(line) = LOWER(?)`,
+		strings.TrimSpace(line)).Scan(&shape.Line, &paths, &shape.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LineShape{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.LineShape{}, fmt.Errorf("get shape of %s: %w", line, err)
+	}
+	if err := json.Unmarshal([]byte(paths), &shape.Paths); err != nil {
+		return domain.LineShape{}, fmt.Errorf("decode shape of %s: %w", line, err)
+	}
+	return shape, nil
+}
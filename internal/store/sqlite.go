@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDialect keeps the database in one file, for a single instance.
+type sqliteDialect struct{}
+
+func (sqliteDialect) open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optimize SQLite settings. auto_vacuum only takes effect on a new
+	// database, which lets optimize return free pages to the file system.
+	for _, pragma := range []string{
+		"PRAGMA auto_vacuum = INCREMENTAL",
+		"PRAGMA busy_timeout = 5000",
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA synchronous = NORMAL",
+	} {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+func (sqliteDialect) schema(ddl string) string {
+	return ddl
+}
+
+// hasColumn is needed since SQLite has no ADD COLUMN IF NOT EXISTS.
+func (sqliteDialect) hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func (sqliteDialect) tables(ctx context.Context, db *sql.DB) ([]string, error) {
+	return queryStrings(ctx, db, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+}
+
+func (sqliteDialect) size(ctx context.Context, db *sql.DB) (int64, error) {
+	var pages, pageSize int64
+	if err := db.QueryRowContext(ctx, "SELECT page_count, page_size FROM pragma_page_count(), pragma_page_size()").Scan(&pages, &pageSize); err != nil {
+		return 0, err
+	}
+	return pages * pageSize, nil
+}
+
+// optimize frees pages only in databases created with incremental
+// auto_vacuum; older ones keep their free pages for reuse.
+func (sqliteDialect) optimize() []string {
+	return []string{"PRAGMA incremental_vacuum", "ANALYZE"}
+}
+
+// hour reads the hour straight from the stored text, "YYYY-MM-DD HH:MM:SS...",
+// which keeps the offset the time was written with. writeSchedules writes
+// schedules in Jakarta time, so this is the Jakarta hour as on PostgreSQL.
+func (sqliteDialect) hour(column string) string {
+	return "CAST(substr(" + column + ", 12, 2) AS INTEGER)"
+}
+
+// clock reads the time of day from the stored text, like hour.
+func (sqliteDialect) clock(column string) string {
+	return "substr(" + column + ", 12, 5)"
+}
+
+// epoch relies on strftime honoring the offset stored with the time.
+func (sqliteDialect) epoch(expr string) string {
+	return "CAST(strftime('%s', " + expr + ") AS INTEGER)"
+}
+
+// backup uses SQLite's online backup API. The copy is taken in a single read
+// transaction, so in WAL mode neither readers nor writers wait for it.
+func (sqliteDialect) backup(ctx context.Context, db *sql.DB, path string) error {
+	dst, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("backup to %s: %w", path, err)
+	}
+	defer dst.Close()
+
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("backup to %s: %w", path, err)
+	}
+	defer dstConn.Close()
+	srcConn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("backup to %s: %w", path, err)
+	}
+	defer srcConn.Close()
+
+	err = dstConn.Raw(func(dc any) error {
+		return srcConn.Raw(func(sc any) error {
+			to, ok := dc.(*sqlite3.SQLiteConn)
+			from, ok2 := sc.(*sqlite3.SQLiteConn)
+			if !ok || !ok2 {
+				return fmt.Errorf("unexpected driver connection %T", sc)
+			}
+			bk, err := to.Backup("main", from, "main")
+			if err != nil {
+				return err
+			}
+			if _, err := bk.Step(-1); err != nil {
+				bk.Finish()
+				return err
+			}
+			return bk.Finish()
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("backup to %s: %w", path, err)
+	}
+	return nil
+}
+
+// tryLock always succeeds, since only one instance uses a SQLite file.
+func (sqliteDialect) tryLock(context.Context, *sql.DB, string) (func(), bool, error) {
+	return func() {}, true, nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// GetStationChanges returns the closures and renames of every station that
+// has one, by station ID.
+func (s *Store) GetStationChanges(ctx context.Context) (map[string]domain.StationChange, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT station_id, closes_on, name, former_name, renamed_on FROM station_changes")
+	if err != nil {
+		return nil, fmt.Errorf("get station changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make(map[string]domain.StationChange)
+	for rows.Next() {
+		var id string
+		var c domain.StationChange
+		if err := rows.Scan(&id, &c.ClosesOn, &c.Name, &c.FormerName, &c.RenamedOn); err != nil {
+			return nil, fmt.Errorf("get station changes: %w", err)
+		}
+		changes[id] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get station changes: %w", err)
+	}
+	return changes, nil
+}
+
+// GetStationChange returns ErrNotFound when the station has no closure or
+// rename.
+func (s *Store) GetStationChange(ctx context.Context, stationID string) (domain.StationChange, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var c domain.StationChange
+	err := s.db.QueryRowContext(ctx, "SELECT closes_on, name, former_name, renamed_on FROM station_changes WHERE station_id = ?", stationID).
+		Scan(&c.ClosesOn, &c.Name, &c.FormerName, &c.RenamedOn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.StationChange{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.StationChange{}, fmt.Errorf("get station change %s: %w", stationID, err)
+	}
+	return c, nil
+}
+
+// SetStationChange replaces the closure and rename of a station. A zero c
+// removes both.
+func (s *Store) SetStationChange(ctx context.Context, stationID string, c domain.StationChange) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var err error
+	if c == (domain.StationChange{}) {
+		_, err = s.db.ExecContext(ctx, "DELETE FROM station_changes WHERE station_id = ?", stationID)
+	} else {
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO station_changes (station_id, closes_on, name, former_name, renamed_on, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(station_id) DO UPDATE SET closes_on = excluded.closes_on, name = excluded.name,
+				former_name = excluded.former_name, renamed_on = excluded.renamed_on, updated_at = excluded.updated_at`,
+			stationID, c.ClosesOn, c.Name, c.FormerName, c.RenamedOn, time.Now())
+	}
+	if err != nil {
+		return fmt.Errorf("set station change %s: %w", stationID, err)
+	}
+	return nil
+}
@@ -1,43 +1,123 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
 )
 
+// ErrNotFound is returned by lookups of a single record that does not exist.
+var ErrNotFound = errors.New("not found")
+
+// DefaultTimeout is the operation timeout of a store opened without one.
+const DefaultTimeout = 10 * time.Second
+
+// Options tunes a store. Zero fields keep the defaults: DefaultTimeout and
+// database/sql's own pool settings.
+type Options struct {
+	// Timeout bounds each operation.
+	Timeout time.Duration
+	// MaxOpenConns and MaxIdleConns size the connection pool, and
+	// ConnMaxLifetime recycles its connections.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
 type Store struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect
+	stmts   statements
+
+	// timeout bounds each operation, so that a held lock or a hung
+	// connection cannot block its caller forever.
+	timeout time.Duration
+}
+
+// statements are the queries run for most API requests, prepared once when
+// the store is opened instead of on every call.
+type statements struct {
+	station          *sql.Stmt
+	stationSchedules *sql.Stmt
+	trainSchedules   *sql.Stmt
 }
 
-func NewStore(dbPath string) (*Store, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+// NewStore opens the database of driver, DriverSQLite or DriverPostgres, at
+// dsn: a file path for SQLite or a connection string for PostgreSQL.
+func NewStore(ctx context.Context, driver, dsn string, opts Options) (*Store, error) {
+	d, err := newDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := d.open(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-
-	// Optimize SQLite settings
-	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
-		return nil, err
+	if opts.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
 	}
-	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
-		return nil, err
+	if opts.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
 	}
-	if _, err := db.Exec("PRAGMA synchronous = NORMAL"); err != nil {
-		return nil, err
+	if opts.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
 	}
 
-	s := &Store{db: db}
-	if err := s.InitDB(); err != nil {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	s := &Store{db: db, dialect: d, timeout: timeout}
+	if err := s.InitDB(ctx); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to init database: %w", err)
 	}
+	if err := s.prepare(ctx); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to prepare statements: %w", err)
+	}
 	return s, nil
 }
 
-func (s *Store) InitDB() error {
+// prepare prepares s.stmts. The tables must exist.
+func (s *Store) prepare(ctx context.Context) error {
+	for _, st := range []struct {
+		dst   **sql.Stmt
+		query string
+	}{
+		{&s.stmts.station, "SELECT uid, id, name, type, metadata FROM stations WHERE id = ?"},
+		{&s.stmts.stationSchedules, "SELECT " + scheduleColumns + " FROM schedules WHERE station_id = ? ORDER BY departs_at ASC"},
+		{&s.stmts.trainSchedules, "SELECT " + scheduleColumns + " FROM schedules WHERE train_id = ? ORDER BY departs_at ASC"},
+	} {
+		stmt, err := s.db.PrepareContext(ctx, st.query)
+		if err != nil {
+			return err
+		}
+		*st.dst = stmt
+	}
+	return nil
+}
+
+// withTimeout bounds an operation by the store's timeout, or ctx's deadline
+// if that is sooner. Operations that copy or stream the whole network are
+// only bounded by their caller's ctx.
+func (s *Store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// InitDB creates the tables and adds the columns of later releases. It is
+// not limited by the store's timeout, since migrations may take longer.
+func (s *Store) InitDB(ctx context.Context) error {
 	const createStationTable = `
 	CREATE TABLE IF NOT EXISTS stations (
 		uid TEXT PRIMARY KEY,
@@ -61,202 +141,1136 @@ func (s *Store) InitDB() error {
 		departs_at DATETIME,
 		arrives_at DATETIME,
 		metadata JSON,
-		updated_at DATETIME
+		updated_at DATETIME,
+		run_id INTEGER,
+		service_type TEXT,
+		service_day TEXT
 	);
 	CREATE INDEX IF NOT EXISTS idx_schedules_station_id ON schedules(station_id);
+	CREATE INDEX IF NOT EXISTS idx_schedules_line ON schedules(line);
+	CREATE INDEX IF NOT EXISTS idx_schedules_updated_at ON schedules(updated_at);
+	`
+
+	// sync_runs versions the schedule data: every row written by a sync is
+	// stamped with its run, and the full timetable is snapshotted per run so
+	// that runs can be diffed. sync_issues holds what the integrity checks
+	// after each run found.
+	const createSyncRunTables = `
+	CREATE TABLE IF NOT EXISTS sync_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		trigger_source TEXT,
+		started_at DATETIME,
+		finished_at DATETIME,
+		status TEXT,
+		error TEXT,
+		stations_succeeded INTEGER,
+		stations_failed INTEGER,
+		rows_written INTEGER,
+		failed_stations TEXT
+	);
+	CREATE TABLE IF NOT EXISTS schedule_snapshots (
+		run_id INTEGER,
+		station_id TEXT,
+		train_id TEXT,
+		line TEXT,
+		route TEXT,
+		departs_at DATETIME,
+		service_day TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_schedule_snapshots_run_id ON schedule_snapshots(run_id);
+	CREATE TABLE IF NOT EXISTS sync_issues (
+		run_id INTEGER,
+		kind TEXT,
+		station_id TEXT,
+		train_id TEXT,
+		service_day TEXT,
+		detail TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_sync_issues_run_id ON sync_issues(run_id);
+	`
+
+	const createTrainPositionTable = `
+	CREATE TABLE IF NOT EXISTS train_positions (
+		train_id TEXT PRIMARY KEY,
+		station_id TEXT,
+		next_station_id TEXT,
+		latitude REAL,
+		longitude REAL,
+		delay_minutes INTEGER,
+		status TEXT,
+		observed_at DATETIME,
+		updated_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_train_positions_station_id ON train_positions(station_id);
+	CREATE INDEX IF NOT EXISTS idx_train_positions_next_station_id ON train_positions(next_station_id);
+	`
+
+	const createStationFacilitiesTable = `
+	CREATE TABLE IF NOT EXISTS station_facilities (
+		station_id TEXT PRIMARY KEY,
+		facilities JSON,
+		updated_at DATETIME
+	);
+	`
+
+	// station_aliases are kept apart from stations, like their facilities,
+	// since the station list is replaced on every sync. lang is empty for
+	// aliases that are not specific to a language.
+	const createStationAliasTable = `
+	CREATE TABLE IF NOT EXISTS station_aliases (
+		station_id TEXT,
+		alias TEXT,
+		lang TEXT NOT NULL DEFAULT '',
+		display BOOLEAN,
+		updated_at DATETIME,
+		PRIMARY KEY (station_id, alias, lang)
+	);
+	`
+
+	// platform_assignments are curated, like aliases, and fill in departure
+	// platforms the upstream leaves out. destination_id is empty for an
+	// assignment covering the whole line.
+	const createPlatformAssignmentTable = `
+	CREATE TABLE IF NOT EXISTS platform_assignments (
+		station_id TEXT,
+		line TEXT,
+		destination_id TEXT NOT NULL DEFAULT '',
+		platform TEXT,
+		updated_at DATETIME,
+		PRIMARY KEY (station_id, line, destination_id)
+	);
+	`
+
+	// station_changes are curated, like aliases, since the stations table is
+	// replaced on every sync. Dates are service dates as YYYY-MM-DD, empty
+	// when the station has no closure or rename.
+	const createStationChangeTable = `
+	CREATE TABLE IF NOT EXISTS station_changes (
+		station_id TEXT PRIMARY KEY,
+		closes_on TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL DEFAULT '',
+		former_name TEXT NOT NULL DEFAULT '',
+		renamed_on TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME
+	);
+	`
+
+	const createSubmissionTable = `
+	CREATE TABLE IF NOT EXISTS station_submissions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		station_id TEXT,
+		kind TEXT,
+		payload JSON,
+		submitter TEXT,
+		status TEXT,
+		review_note TEXT,
+		submitted_at DATETIME,
+		reviewed_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_station_submissions_status ON station_submissions(status);
+	CREATE INDEX IF NOT EXISTS idx_station_submissions_station_id ON station_submissions(station_id);
+	`
+
+	const createDelaySampleTable = `
+	CREATE TABLE IF NOT EXISTS train_delay_samples (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		train_id TEXT,
+		station_id TEXT,
+		source TEXT,
+		delay_minutes INTEGER,
+		observed_at DATETIME,
+		reporter TEXT,
+		service_date TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_train_delay_samples_train ON train_delay_samples(train_id, observed_at);
+	`
+
+	const createLineTable = `
+	CREATE TABLE IF NOT EXISTS lines (
+		name TEXT,
+		position INTEGER,
+		station_id TEXT,
+		color TEXT,
+		updated_at DATETIME,
+		PRIMARY KEY (name, position)
+	);
+	CREATE INDEX IF NOT EXISTS idx_lines_station_id ON lines(station_id);
+	`
+
+	const createStationLocationTable = `
+	CREATE TABLE IF NOT EXISTS station_locations (
+		station_id TEXT PRIMARY KEY,
+		lat REAL,
+		lng REAL,
+		updated_at DATETIME
+	);
+	`
+
+	// schedule_history keeps the timetable each station ran on each service
+	// date, so past dates and the previous day's late trains stay readable
+	// after a sync replaces schedules.
+	const createScheduleHistoryTable = `
+	CREATE TABLE IF NOT EXISTS schedule_history (
+		service_date TEXT,
+		id TEXT,
+		station_id TEXT,
+		station_origin_id TEXT,
+		station_destination_id TEXT,
+		train_id TEXT,
+		line TEXT,
+		route TEXT,
+		departs_at DATETIME,
+		arrives_at DATETIME,
+		metadata JSON,
+		updated_at DATETIME,
+		run_id INTEGER,
+		service_type TEXT,
+		service_day TEXT,
+		PRIMARY KEY (service_date, id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_schedule_history_station ON schedule_history(service_date, station_id);
+	CREATE INDEX IF NOT EXISTS idx_schedule_history_train ON schedule_history(service_date, train_id);
+	`
+
+	const createLineShapeTable = `
+	CREATE TABLE IF NOT EXISTS line_shapes (
+		line TEXT PRIMARY KEY,
+		paths TEXT,
+		updated_at DATETIME
+	);
+	`
+
+	const createAnnouncementTable = `
+	CREATE TABLE IF NOT EXISTS announcements (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		line TEXT,
+		station_ids TEXT,
+		severity TEXT,
+		message TEXT,
+		starts_at DATETIME,
+		ends_at DATETIME,
+		source TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_announcements_ends_at ON announcements(ends_at);
+	`
+
+	// schedule_overrides are operator corrections to the scraped timetable.
+	// They live apart from schedules so that syncs never overwrite them, and
+	// are merged over the timetable when it is read.
+	const createScheduleOverrideTable = `
+	CREATE TABLE IF NOT EXISTS schedule_overrides (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT,
+		train_id TEXT,
+		station_id TEXT,
+		service_date TEXT,
+		shift_minutes INTEGER,
+		departs_at TEXT,
+		arrives_at TEXT,
+		line TEXT,
+		route TEXT,
+		station_origin_id TEXT,
+		station_destination_id TEXT,
+		service_type TEXT,
+		note TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	);
+	`
+
+	// The BRT network is imported as a whole, with its stops as stations of
+	// type BRT. Corridor stops are JSON in travel order, and transfers pair
+	// a rail station with a BRT stop within walking distance.
+	const createBRTTables = `
+	CREATE TABLE IF NOT EXISTS brt_corridors (
+		id TEXT,
+		direction INTEGER,
+		name TEXT,
+		color TEXT,
+		headway_minutes INTEGER,
+		first_departure TEXT,
+		last_departure TEXT,
+		stops TEXT,
+		updated_at DATETIME,
+		PRIMARY KEY (id, direction)
+	);
+	CREATE TABLE IF NOT EXISTS brt_transfers (
+		station_id TEXT,
+		stop_id TEXT,
+		walk_minutes INTEGER,
+		PRIMARY KEY (station_id, stop_id)
+	);
+	`
+
+	// station_transfers are curated walks between nearby stations, either
+	// way, stored once with station_a before station_b.
+	const createStationTransferTable = `
+	CREATE TABLE IF NOT EXISTS station_transfers (
+		station_a TEXT,
+		station_b TEXT,
+		walk_minutes INTEGER,
+		note TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME,
+		PRIMARY KEY (station_a, station_b)
+	);
+	`
+
+	const createUsageTable = `
+	CREATE TABLE IF NOT EXISTS usage_counts (
+		kind TEXT,
+		key TEXT,
+		hits INTEGER,
+		updated_at DATETIME,
+		PRIMARY KEY (kind, key)
+	);
+	`
+
+	const createAuthTokenTable = `
+	CREATE TABLE IF NOT EXISTS auth_tokens (
+		name TEXT PRIMARY KEY,
+		token TEXT,
+		obtained_at DATETIME
+	);
 	`
 
-	if _, err := s.db.Exec(createStationTable); err != nil {
+	const createFeatureFlagTable = `
+	CREATE TABLE IF NOT EXISTS feature_flags (
+		name TEXT PRIMARY KEY,
+		enabled BOOLEAN,
+		updated_at DATETIME
+	);
+	`
+
+	// raw_fetches holds upstream responses recorded in capture mode, body
+	// gzipped.
+	const createRawFetchTable = `
+	CREATE TABLE IF NOT EXISTS raw_fetches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT,
+		status INTEGER,
+		fetched_at DATETIME,
+		size INTEGER,
+		body BLOB
+	);
+	CREATE INDEX IF NOT EXISTS idx_raw_fetches_url ON raw_fetches(url);
+	`
+
+	// Accounts: users sign in with a one-time login token and then use a
+	// session token. Only hashes of tokens are stored.
+	const createAccountTables = `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email TEXT UNIQUE,
+		created_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS login_tokens (
+		token_hash TEXT PRIMARY KEY,
+		email TEXT,
+		expires_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS sessions (
+		token_hash TEXT PRIMARY KEY,
+		user_id INTEGER,
+		created_at DATETIME,
+		expires_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS user_favorites (
+		user_id INTEGER,
+		station_id TEXT,
+		created_at DATETIME,
+		PRIMARY KEY (user_id, station_id)
+	);
+	CREATE TABLE IF NOT EXISTS user_commutes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER,
+		name TEXT,
+		from_station TEXT,
+		to_station TEXT,
+		window_after TEXT,
+		window_before TEXT,
+		created_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_user_commutes_user_id ON user_commutes(user_id);
+	CREATE TABLE IF NOT EXISTS push_subscriptions (
+		endpoint TEXT PRIMARY KEY,
+		user_id INTEGER,
+		p256dh TEXT,
+		auth TEXT,
+		created_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_push_subscriptions_user_id ON push_subscriptions(user_id);
+	`
+
+	const createMetricsTable = `
+	CREATE TABLE IF NOT EXISTS metrics_snapshots (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		taken_at DATETIME,
+		goroutines INTEGER,
+		heap_bytes INTEGER,
+		caches TEXT,
+		db TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_metrics_snapshots_taken_at ON metrics_snapshots(taken_at);
+	`
+
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createScheduleTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createTrainPositionTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationFacilitiesTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationAliasTable)); err != nil {
+		return err
+	}
+	if err := s.seedAliases(ctx); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createPlatformAssignmentTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationChangeTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createSubmissionTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createDelaySampleTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createLineTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationLocationTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createScheduleHistoryTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createLineShapeTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createAnnouncementTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createScheduleOverrideTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createBRTTables)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createStationTransferTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createUsageTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createSyncRunTables)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createMetricsTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createAuthTokenTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createFeatureFlagTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createRawFetchTable)); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.schema(createAccountTables)); err != nil {
+		return err
+	}
+
+	// Columns added after the first release.
+	if err := s.addColumn(ctx, "schedules", "run_id", "INTEGER"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "schedules", "service_type", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "schedules", "service_day", "TEXT"); err != nil {
 		return err
 	}
-	if _, err := s.db.Exec(createScheduleTable); err != nil {
+	if err := s.addColumn(ctx, "schedule_snapshots", "service_day", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "sync_runs", "trigger_source", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "sync_runs", "stations_succeeded", "INTEGER"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "sync_runs", "stations_failed", "INTEGER"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "sync_runs", "rows_written", "INTEGER"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "sync_runs", "failed_stations", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "station_locations", "address", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "station_locations", "municipality", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "user_commutes", "remind_before", "INTEGER"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "user_commutes", "trains_hash", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "announcements", "external_id", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "announcements", "translations", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "schedules", "service_date", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "train_delay_samples", "reporter", "TEXT"); err != nil {
+		return err
+	}
+	if err := s.addColumn(ctx, "train_delay_samples", "service_date", "TEXT"); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *Store) HasStations() bool {
+// addColumn adds a column to an existing table unless it is already there.
+func (s *Store) addColumn(ctx context.Context, table, column, decl string) error {
+	ok, err := s.dialect.hasColumn(ctx, s.db, table, column)
+	if err != nil || ok {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, s.dialect.schema(decl)))
+	return err
+}
+
+func (s *Store) HasStations(ctx context.Context) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	var count int
-	err := s.db.QueryRow("SELECT COUNT(*) FROM stations").Scan(&count)
-	if err != nil {
-		return false
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stations").Scan(&count); err != nil {
+		return false, fmt.Errorf("count stations: %w", err)
 	}
-	return count > 0
+	return count > 0, nil
 }
 
-func (s *Store) SetStations(stations []Station) {
-	tx, err := s.db.Begin()
+func (s *Store) SetStations(ctx context.Context, stations []domain.Station) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return
+		return fmt.Errorf("set stations: %w", err)
 	}
 	defer tx.Rollback()
 
+	if err := replaceStations(ctx, tx, stations); err != nil {
+		return fmt.Errorf("set stations: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set stations: %w", err)
+	}
+	return nil
+}
+
+func replaceStations(ctx context.Context, tx *sql.Tx, stations []domain.Station) error {
 	// Replace all stations
-	if _, err := tx.Exec("DELETE FROM stations"); err != nil {
-		return
+	if _, err := tx.ExecContext(ctx, "DELETE FROM stations"); err != nil {
+		return err
 	}
 
-	stmt, err := tx.Prepare("INSERT INTO stations (uid, id, name, type, metadata) VALUES (?, ?, ?, ?, ?)")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO stations (uid, id, name, type, metadata) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
-		return
+		return err
 	}
 	defer stmt.Close()
 
 	for _, st := range stations {
-		metaBytes, _ := json.Marshal(st.Metadata)
-		_, err := stmt.Exec(st.UID, st.ID, st.Name, st.Type, metaBytes)
+		metaBytes, err := json.Marshal(st.Metadata)
 		if err != nil {
-			continue
+			return fmt.Errorf("station %s: %w", st.ID, err)
+		}
+		if _, err := stmt.ExecContext(ctx, st.UID, st.ID, st.Name, st.Type, metaBytes); err != nil {
+			return fmt.Errorf("station %s: %w", st.ID, err)
 		}
 	}
-
-	tx.Commit()
+	return nil
 }
 
-func (s *Store) GetStations() []Station {
-	rows, err := s.db.Query("SELECT uid, id, name, type, metadata FROM stations")
+func (s *Store) GetStations(ctx context.Context) ([]domain.Station, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT uid, id, name, type, metadata FROM stations")
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("get stations: %w", err)
 	}
 	defer rows.Close()
 
-	var stations []Station
+	var stations []domain.Station
 	for rows.Next() {
-		var st Station
-		var metaBytes []byte
-		if err := rows.Scan(&st.UID, &st.ID, &st.Name, &st.Type, &metaBytes); err != nil {
-			continue
+		st, err := scanStation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get stations: %w", err)
 		}
-		json.Unmarshal(metaBytes, &st.Metadata)
 		stations = append(stations, st)
 	}
-	return stations
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get stations: %w", err)
+	}
+
+	aliases, err := s.allAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get stations: %w", err)
+	}
+	changes, err := s.GetStationChanges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get stations: %w", err)
+	}
+	for i := range stations {
+		stations[i].Aliases = aliases[stations[i].ID]
+		if c, ok := changes[stations[i].ID]; ok {
+			stations[i].Changes = &c
+		}
+	}
+	return stations, nil
 }
 
-func (s *Store) GetStation(id string) (Station, bool) {
-	row := s.db.QueryRow("SELECT uid, id, name, type, metadata FROM stations WHERE id = ?", id)
-	var st Station
+// GetStation returns ErrNotFound when no station has the given ID.
+func (s *Store) GetStation(ctx context.Context, id string) (domain.Station, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	st, err := scanStation(s.stmts.station.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Station{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Station{}, fmt.Errorf("get station %s: %w", id, err)
+	}
+	if st.Aliases, err = s.GetStationAliases(ctx, id); err != nil {
+		return domain.Station{}, err
+	}
+	c, err := s.GetStationChange(ctx, id)
+	switch {
+	case err == nil:
+		st.Changes = &c
+	case !errors.Is(err, ErrNotFound):
+		return domain.Station{}, err
+	}
+	return st, nil
+}
+
+func scanStation(row rowScanner) (domain.Station, error) {
+	var st domain.Station
 	var metaBytes []byte
 	if err := row.Scan(&st.UID, &st.ID, &st.Name, &st.Type, &metaBytes); err != nil {
-		return Station{}, false
+		return domain.Station{}, err
+	}
+	if err := json.Unmarshal(metaBytes, &st.Metadata); err != nil {
+		return domain.Station{}, fmt.Errorf("station %s metadata: %w", st.ID, err)
 	}
-	json.Unmarshal(metaBytes, &st.Metadata)
-	return st, true
+	return st, nil
 }
 
-func (s *Store) SetSchedules(stationID string, schedules []Schedule) {
-	tx, err := s.db.Begin()
+// SetSchedules replaces a station's timetable for one service day, leaving
+// its timetables for other kinds of day in place. Rows are upserted and only
+// the departures missing from schedules are deleted, so the table never
+// churns through an empty timetable and readers see either the old one or
+// the new one. The new timetable is also kept in the history as the one the
+// station runs on serviceDate, a YYYY-MM-DD date.
+func (s *Store) SetSchedules(ctx context.Context, stationID, serviceDay, serviceDate string, schedules []domain.Schedule) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
 	}
 	defer tx.Rollback()
 
-	// Clear schedules for this station
-	if _, err := tx.Exec("DELETE FROM schedules WHERE station_id = ?", stationID); err != nil {
-		return
+	// Rows from before service days were recorded count as the weekday
+	// timetable.
+	existing, err := queryStrings(ctx, tx, "SELECT id FROM schedules WHERE station_id = ? AND "+serviceDayColumn+" = ?", stationID, serviceDay)
+	if err != nil {
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
+	}
+	if err := writeSchedules(ctx, tx, upsertSchedule, schedules); err != nil {
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
+	}
+	kept := make(map[string]bool, len(schedules))
+	for _, sch := range schedules {
+		kept[sch.ID] = true
+	}
+	var stale []string
+	for _, id := range existing {
+		if !kept[id] {
+			stale = append(stale, id)
+		}
+	}
+	if err := deleteSchedules(ctx, tx, stale); err != nil {
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
+	}
+	if err := recordScheduleHistory(ctx, tx, stationID, serviceDay, serviceDate); err != nil {
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("set schedules for %s: %w", stationID, err)
 	}
+	return nil
+}
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO schedules (
-			id, station_id, station_origin_id, station_destination_id, 
-			train_id, line, route, departs_at, arrives_at, metadata, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
+// recordScheduleHistory replaces the history of a station on serviceDate
+// with its current timetable for serviceDay.
+func recordScheduleHistory(ctx context.Context, tx *sql.Tx, stationID, serviceDay, serviceDate string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schedule_history WHERE service_date = ? AND station_id = ?", serviceDate, stationID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO schedule_history (
+			service_date, id, station_id, station_origin_id, station_destination_id,
+			train_id, line, route, departs_at, arrives_at, metadata, updated_at, run_id, service_type, service_day
+		)
+		SELECT CAST(? AS TEXT), id, station_id, station_origin_id, station_destination_id,
+			train_id, line, route, departs_at, arrives_at, metadata, updated_at, run_id, service_type, `+serviceDayColumn+`
+		FROM schedules WHERE station_id = ? AND `+serviceDayColumn+` = ?`,
+		serviceDate, stationID, serviceDay)
+	return err
+}
+
+// GetScheduleHistory returns the departures a station ran on date, a
+// YYYY-MM-DD service date, or none when the history does not cover it.
+func (s *Store) GetScheduleHistory(ctx context.Context, stationID, date string) ([]domain.Schedule, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	schedules, err := collectSchedules(s.db.QueryContext(ctx,
+		"SELECT "+scheduleColumns+" FROM schedule_history WHERE service_date = ? AND station_id = ? ORDER BY departs_at ASC", date, stationID))
 	if err != nil {
-		return
+		return nil, fmt.Errorf("get schedule history for %s on %s: %w", stationID, date, err)
+	}
+	return schedules, nil
+}
+
+// GetRouteHistory returns a train's schedules on date, a YYYY-MM-DD service
+// date, or none when the history does not cover it.
+func (s *Store) GetRouteHistory(ctx context.Context, trainID, date string) ([]domain.Schedule, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	schedules, err := collectSchedules(s.db.QueryContext(ctx,
+		"SELECT "+scheduleColumns+" FROM schedule_history WHERE service_date = ? AND train_id = ? ORDER BY departs_at ASC", date, trainID))
+	if err != nil {
+		return nil, fmt.Errorf("get route history for %s on %s: %w", trainID, date, err)
+	}
+	return schedules, nil
+}
+
+// PruneScheduleHistory deletes the history of service dates before before, a
+// YYYY-MM-DD date, and returns how many rows it deleted.
+func (s *Store) PruneScheduleHistory(ctx context.Context, before string) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM schedule_history WHERE service_date < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("prune schedule history: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// deleteSchedules deletes the schedules with the given IDs.
+func deleteSchedules(ctx context.Context, tx *sql.Tx, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, "DELETE FROM schedules WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, id := range ids {
+		if _, err := stmt.ExecContext(ctx, id); err != nil {
+			return fmt.Errorf("schedule %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// CountSchedules returns how many departures a station has in its timetable
+// for serviceDay.
+func (s *Store) CountSchedules(ctx context.Context, stationID, serviceDay string) (int, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var n int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schedules WHERE station_id = ? AND "+serviceDayColumn+" = ?", stationID, serviceDay).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count schedules for %s: %w", stationID, err)
+	}
+	return n, nil
+}
+
+const insertSchedule = `
+	INSERT INTO schedules (
+		id, station_id, station_origin_id, station_destination_id, 
+		train_id, line, route, departs_at, arrives_at, metadata, updated_at, run_id, service_type, service_day, service_date
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// upsertSchedule is insertSchedule, overwriting a schedule that has the
+// same ID.
+const upsertSchedule = insertSchedule + `
+	ON CONFLICT (id) DO UPDATE SET
+		station_id = excluded.station_id, station_origin_id = excluded.station_origin_id,
+		station_destination_id = excluded.station_destination_id, train_id = excluded.train_id,
+		line = excluded.line, route = excluded.route, departs_at = excluded.departs_at,
+		arrives_at = excluded.arrives_at, metadata = excluded.metadata, updated_at = excluded.updated_at,
+		run_id = excluded.run_id, service_type = excluded.service_type, service_day = excluded.service_day,
+		service_date = excluded.service_date
+`
+
+func insertSchedules(ctx context.Context, tx *sql.Tx, schedules []domain.Schedule) error {
+	return writeSchedules(ctx, tx, insertSchedule, schedules)
+}
+
+// writeSchedules runs query, insertSchedule or upsertSchedule, for each of
+// schedules. Times are written in Jakarta, which the dialects' hour and clock
+// read the time of day in.
+func writeSchedules(ctx context.Context, tx *sql.Tx, query string, schedules []domain.Schedule) error {
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
 	}
 	defer stmt.Close()
 
 	for _, sch := range schedules {
-		metaBytes, _ := json.Marshal(sch.Metadata)
-		_, err := stmt.Exec(
+		metaBytes, err := json.Marshal(sch.Metadata)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", sch.ID, err)
+		}
+		_, err = stmt.ExecContext(ctx,
 			sch.ID, sch.StationID, sch.StationOriginID, sch.StationDestinationID,
-			sch.TrainID, sch.Line, sch.Route, sch.DepartsAt, sch.ArrivesAt, metaBytes, sch.UpdatedAt,
+			sch.TrainID, sch.Line, sch.Route, inJakarta(sch.DepartsAt), inJakarta(sch.ArrivesAt), metaBytes, sch.UpdatedAt, sch.RunID, sch.ServiceType, sch.ServiceDay, sch.ServiceDate,
 		)
 		if err != nil {
-			continue
+			return fmt.Errorf("schedule %s: %w", sch.ID, err)
 		}
 	}
+	return nil
+}
+
+// inJakarta returns t in Jakarta, leaving an unset time as it is.
+func inJakarta(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(calendar.Jakarta)
+}
+
+// serviceDayColumn reads a schedule's service day, treating rows written
+// before it was recorded as weekday schedules.
+const serviceDayColumn = "COALESCE(NULLIF(service_day, ''), '" + domain.ServiceDayWeekday + "')"
 
-	tx.Commit()
+const scheduleColumns = `id, station_id, station_origin_id, station_destination_id, 
+			   train_id, line, route, departs_at, arrives_at, metadata, updated_at, COALESCE(run_id, 0),
+			   COALESCE(service_type, ''), ` + serviceDayColumn + `, COALESCE(service_date, '')`
+
+// scanSchedule scans a schedule row. Rows share a handful of metadata values,
+// so each is decoded once into seen, keyed by its JSON.
+func scanSchedule(row rowScanner, seen map[string]domain.ScheduleMetadata) (domain.Schedule, error) {
+	var sch domain.Schedule
+	var metaBytes []byte
+	if err := row.Scan(
+		&sch.ID, &sch.StationID, &sch.StationOriginID, &sch.StationDestinationID,
+		&sch.TrainID, &sch.Line, &sch.Route, &sch.DepartsAt, &sch.ArrivesAt, &metaBytes, &sch.UpdatedAt, &sch.RunID,
+		&sch.ServiceType, &sch.ServiceDay, &sch.ServiceDate,
+	); err != nil {
+		return domain.Schedule{}, err
+	}
+	if meta, ok := seen[string(metaBytes)]; ok {
+		sch.Metadata = meta
+		return sch, nil
+	}
+	if err := json.Unmarshal(metaBytes, &sch.Metadata); err != nil {
+		return domain.Schedule{}, fmt.Errorf("schedule %s metadata: %w", sch.ID, err)
+	}
+	seen[string(metaBytes)] = sch.Metadata
+	return sch, nil
 }
 
-func (s *Store) GetSchedules(stationID string) []Schedule {
-	rows, err := s.db.Query(`
-		SELECT id, station_id, station_origin_id, station_destination_id, 
-			   train_id, line, route, departs_at, arrives_at, metadata, updated_at 
-		FROM schedules WHERE station_id = ?
-		ORDER BY departs_at ASC`, stationID)
+func (s *Store) GetSchedules(ctx context.Context, stationID string) ([]domain.Schedule, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	schedules, err := collectSchedules(s.stmts.stationSchedules.QueryContext(ctx, stationID))
 	if err != nil {
+		return nil, fmt.Errorf("get schedules for %s: %w", stationID, err)
+	}
+	return schedules, nil
+}
+
+// ScheduleFilter selects schedules across every station. Zero fields match
+// everything.
+type ScheduleFilter struct {
+	// Line matches line names case-insensitively.
+	Line          string
+	DestinationID string
+	ServiceTypes  []string
+	// After and Before bound the departure time of day, as HH:MM, both
+	// inclusive. An After later than Before wraps past midnight.
+	After  string
+	Before string
+	// Timetables maps stations to the service day whose timetable is
+	// selected there; other stations match every service day.
+	Timetables map[string]string
+	// Conditions are those of a filter expression, from ParseFilter.
+	Conditions []Condition
+	// ExcludeStations leaves out the departures from these stations, such
+	// as those closed on the date asked for.
+	ExcludeStations []string
+	// Limit caps the number of schedules returned, after skipping Offset.
+	// Zero returns them all.
+	Limit  int
+	Offset int
+}
+
+// QuerySchedules returns the schedules matching f, ordered by time of day
+// from After, then station.
+func (s *Store) QuerySchedules(ctx context.Context, f ScheduleFilter) ([]domain.Schedule, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	schedules := []domain.Schedule{}
+	clause, args := s.scheduleQuery(f)
+	err := s.eachSchedule(ctx, func(sch domain.Schedule) error {
+		schedules = append(schedules, sch)
 		return nil
+	}, clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query schedules: %w", err)
 	}
-	defer rows.Close()
+	return schedules, nil
+}
 
-	var schedules []Schedule
-	for rows.Next() {
-		var sch Schedule
-		var metaBytes []byte
-		if err := rows.Scan(
-			&sch.ID, &sch.StationID, &sch.StationOriginID, &sch.StationDestinationID,
-			&sch.TrainID, &sch.Line, &sch.Route, &sch.DepartsAt, &sch.ArrivesAt, &metaBytes, &sch.UpdatedAt,
-		); err != nil {
-			continue
+// EachQueriedSchedule streams the schedules matching f to fn in the order of
+// QuerySchedules, like EachSchedule.
+func (s *Store) EachQueriedSchedule(ctx context.Context, f ScheduleFilter, fn func(domain.Schedule) error) error {
+	clause, args := s.scheduleQuery(f)
+	if err := s.eachSchedule(ctx, fn, clause, args...); err != nil {
+		return fmt.Errorf("query schedules: %w", err)
+	}
+	return nil
+}
+
+// scheduleQuery returns the clause selecting f from the schedules table.
+func (s *Store) scheduleQuery(f ScheduleFilter) (string, []any) {
+	clock := s.dialect.clock("departs_at")
+	var conds []string
+	var args []any
+	if f.Line != "" {
+		conds = append(conds, "LOWER(line) = LOWER(?)")
+		args = append(args, strings.TrimSpace(f.Line))
+	}
+	if f.DestinationID != "" {
+		conds = append(conds, "station_destination_id = ?")
+		args = append(args, f.DestinationID)
+	}
+	if len(f.ServiceTypes) > 0 {
+		conds = append(conds, "service_type IN ("+placeholders(len(f.ServiceTypes))+")")
+		for _, t := range f.ServiceTypes {
+			args = append(args, t)
 		}
-		json.Unmarshal(metaBytes, &sch.Metadata)
-		schedules = append(schedules, sch)
 	}
-	return schedules
+	switch {
+	case f.After != "" && f.Before != "" && f.After > f.Before:
+		conds = append(conds, "("+clock+" >= ? OR "+clock+" <= ?)")
+		args = append(args, f.After, f.Before)
+	default:
+		if f.After != "" {
+			conds = append(conds, clock+" >= ?")
+			args = append(args, f.After)
+		}
+		if f.Before != "" {
+			conds = append(conds, clock+" <= ?")
+			args = append(args, f.Before)
+		}
+	}
+	if len(f.ExcludeStations) > 0 {
+		conds = append(conds, "station_id NOT IN ("+placeholders(len(f.ExcludeStations))+")")
+		for _, id := range f.ExcludeStations {
+			args = append(args, id)
+		}
+	}
+	for _, c := range f.Conditions {
+		pred, predArgs := s.predicate(c)
+		conds = append(conds, pred)
+		args = append(args, predArgs...)
+	}
+	if len(f.Timetables) > 0 {
+		byDay := make(map[string][]string)
+		var listed []any
+		for stationID, day := range f.Timetables {
+			byDay[day] = append(byDay[day], stationID)
+			listed = append(listed, stationID)
+		}
+		var days []string
+		for _, day := range slices.Sorted(maps.Keys(byDay)) {
+			days = append(days, "("+serviceDayColumn+" = ? AND station_id IN ("+placeholders(len(byDay[day]))+"))")
+			args = append(args, day)
+			for _, id := range byDay[day] {
+				args = append(args, id)
+			}
+		}
+		days = append(days, "station_id NOT IN ("+placeholders(len(listed))+")")
+		args = append(args, listed...)
+		conds = append(conds, "("+strings.Join(days, " OR ")+")")
+	}
+
+	var clause string
+	if len(conds) > 0 {
+		clause = "WHERE " + strings.Join(conds, " AND ")
+	}
+	// Departures before After come last, after midnight.
+	order := clock
+	if f.After != "" {
+		order = "CASE WHEN " + clock + " >= ? THEN 0 ELSE 1 END, " + clock
+		args = append(args, f.After)
+	}
+	clause += " ORDER BY " + order + ", station_id, id"
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit == 0 {
+			limit = math.MaxInt32
+		}
+		clause += " LIMIT ? OFFSET ?"
+		args = append(args, limit, f.Offset)
+	}
+	return clause, args
 }
 
-func (s *Store) GetAllSchedules() map[string][]Schedule {
-	rows, err := s.db.Query(`
-		SELECT id, station_id, station_origin_id, station_destination_id, 
-			   train_id, line, route, departs_at, arrives_at, metadata, updated_at 
-		FROM schedules`)
+// GetServiceDays lists the timetables, by service day, of every station that
+// has schedules.
+func (s *Store) GetServiceDays(ctx context.Context) (map[string][]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT DISTINCT station_id, "+serviceDayColumn+" FROM schedules")
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("get service days: %w", err)
 	}
 	defer rows.Close()
 
-	res := make(map[string][]Schedule)
+	days := make(map[string][]string)
 	for rows.Next() {
-		var sch Schedule
-		var metaBytes []byte
-		if err := rows.Scan(
-			&sch.ID, &sch.StationID, &sch.StationOriginID, &sch.StationDestinationID,
-			&sch.TrainID, &sch.Line, &sch.Route, &sch.DepartsAt, &sch.ArrivesAt, &metaBytes, &sch.UpdatedAt,
-		); err != nil {
-			continue
+		var stationID, day string
+		if err := rows.Scan(&stationID, &day); err != nil {
+			return nil, fmt.Errorf("get service days: %w", err)
 		}
-		json.Unmarshal(metaBytes, &sch.Metadata)
-		res[sch.StationID] = append(res[sch.StationID], sch)
+		days[stationID] = append(days[stationID], day)
 	}
-	return res
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get service days: %w", err)
+	}
+	return days, nil
+}
+
+// placeholders returns n comma-separated ? placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
 }
 
-func (s *Store) GetRoute(trainID string) []Schedule {
-	rows, err := s.db.Query(`
-		SELECT id, station_id, station_origin_id, station_destination_id, 
-			   train_id, line, route, departs_at, arrives_at, metadata, updated_at 
-		FROM schedules WHERE train_id = ?
-		ORDER BY departs_at ASC`, trainID)
+func (s *Store) GetRoute(ctx context.Context, trainID string) ([]domain.Schedule, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	schedules, err := collectSchedules(s.stmts.trainSchedules.QueryContext(ctx, trainID))
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("get route for %s: %w", trainID, err)
 	}
+	return schedules, nil
+}
+
+// EachSchedule streams every schedule row to fn in station order without
+// loading the whole table into memory. Iteration stops at the first error
+// returned by fn.
+func (s *Store) EachSchedule(ctx context.Context, fn func(domain.Schedule) error) error {
+	return s.eachSchedule(ctx, fn, "ORDER BY station_id, departs_at")
+}
+
+// EachScheduleSince streams the schedule rows updated after since to fn,
+// oldest update first, like EachSchedule.
+func (s *Store) EachScheduleSince(ctx context.Context, since time.Time, fn func(domain.Schedule) error) error {
+	return s.eachSchedule(ctx, fn, "WHERE updated_at > ? ORDER BY updated_at, id", since)
+}
+
+// eachSchedule runs fn for each schedule row matched by clause, which follows
+// the FROM of the query.
+func (s *Store) eachSchedule(ctx context.Context, fn func(domain.Schedule) error, clause string, args ...any) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+scheduleColumns+" FROM schedules "+clause, args...)
+	if err != nil {
+		return err
+	}
+	return scanSchedules(rows, fn)
+}
+
+// collectSchedules returns the schedules of rows, the result of a query for
+// scheduleColumns.
+func collectSchedules(rows *sql.Rows, err error) ([]domain.Schedule, error) {
+	if err != nil {
+		return nil, err
+	}
+	var schedules []domain.Schedule
+	err = scanSchedules(rows, func(sch domain.Schedule) error {
+		schedules = append(schedules, sch)
+		return nil
+	})
+	return schedules, err
+}
+
+// scanSchedules runs fn for each schedule of rows and closes them.
+func scanSchedules(rows *sql.Rows, fn func(domain.Schedule) error) error {
 	defer rows.Close()
 
-	var schedules []Schedule
+	seen := make(map[string]domain.ScheduleMetadata)
 	for rows.Next() {
-		var sch Schedule
-		var metaBytes []byte
-		if err := rows.Scan(
-			&sch.ID, &sch.StationID, &sch.StationOriginID, &sch.StationDestinationID,
-			&sch.TrainID, &sch.Line, &sch.Route, &sch.DepartsAt, &sch.ArrivesAt, &metaBytes, &sch.UpdatedAt,
-		); err != nil {
-			continue
+		sch, err := scanSchedule(rows, seen)
+		if err != nil {
+			return err
+		}
+		if err := fn(sch); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Close closes the prepared statements and the database.
+func (s *Store) Close() error {
+	for _, stmt := range []*sql.Stmt{s.stmts.station, s.stmts.stationSchedules, s.stmts.trainSchedules} {
+		if stmt != nil {
+			stmt.Close()
 		}
-		json.Unmarshal(metaBytes, &sch.Metadata)
-		schedules = append(schedules, sch)
 	}
-	return schedules
+	return s.db.Close()
 }
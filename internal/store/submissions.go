@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const submissionColumns = `id, station_id, kind, payload, submitter, status, review_note, submitted_at, reviewed_at`
+
+func (s *Store) CreateSubmission(ctx context.Context, sub domain.Submission) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	payload, err := json.Marshal(sub.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("create submission: %w", err)
+	}
+
+	var id int64
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO station_submissions (station_id, kind, payload, submitter, status, review_note, submitted_at)
+		VALUES (?, ?, ?, ?, ?, '', ?) RETURNING id`,
+		sub.StationID, sub.Kind, payload, sub.Submitter, domain.SubmissionPending, sub.SubmittedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("create submission: %w", err)
+	}
+	return id, nil
+}
+
+// GetSubmission returns ErrNotFound when no submission has the given ID.
+func (s *Store) GetSubmission(ctx context.Context, id int64) (domain.Submission, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	row := s.db.QueryRowContext(ctx, "SELECT "+submissionColumns+" FROM station_submissions WHERE id = ?", id)
+	sub, err := scanSubmission(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Submission{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Submission{}, fmt.Errorf("get submission %d: %w", id, err)
+	}
+	return sub, nil
+}
+
+// ListSubmissions returns submissions with the given status, oldest first.
+func (s *Store) ListSubmissions(ctx context.Context, status domain.SubmissionStatus) ([]domain.Submission, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+submissionColumns+` FROM station_submissions
+		WHERE status = ? ORDER BY submitted_at ASC`, status)
+	if err != nil {
+		return nil, fmt.Errorf("list submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []domain.Submission
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list submissions: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list submissions: %w", err)
+	}
+	return subs, nil
+}
+
+// ReviewSubmission records a moderation decision. Approving an amenity
+// submission merges its facilities into the station's facility record in the
+// same transaction. It returns ErrNotFound when no submission has the given ID.
+func (s *Store) ReviewSubmission(ctx context.Context, id int64, status domain.SubmissionStatus, note string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sub, err := scanSubmission(tx.QueryRowContext(ctx, "SELECT "+submissionColumns+" FROM station_submissions WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE station_submissions SET status = ?, review_note = ?, reviewed_at = ? WHERE id = ?",
+		status, note, now, id,
+	); err != nil {
+		return err
+	}
+
+	if status == domain.SubmissionApproved && sub.Kind == domain.SubmissionKindAmenity && sub.Payload.Facilities != nil {
+		var current domain.StationFacilities
+		var metaBytes []byte
+		err := tx.QueryRowContext(ctx, "SELECT facilities FROM station_facilities WHERE station_id = ?", sub.StationID).Scan(&metaBytes)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(metaBytes, &current); err != nil {
+				return fmt.Errorf("station %s facilities: %w", sub.StationID, err)
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		merged, err := json.Marshal(mergeFacilities(current, *sub.Payload.Facilities))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO station_facilities (station_id, facilities, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(station_id) DO UPDATE SET facilities = excluded.facilities, updated_at = excluded.updated_at`,
+			sub.StationID, merged, now,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetApprovedPhotos returns a station's approved community photos, newest first.
+func (s *Store) GetApprovedPhotos(ctx context.Context, stationID string) ([]domain.StationPhoto, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+submissionColumns+` FROM station_submissions
+		WHERE station_id = ? AND kind = ? AND status = ?
+		ORDER BY submitted_at DESC`, stationID, domain.SubmissionKindPhoto, domain.SubmissionApproved)
+	if err != nil {
+		return nil, fmt.Errorf("get photos for %s: %w", stationID, err)
+	}
+	defer rows.Close()
+
+	var photos []domain.StationPhoto
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get photos for %s: %w", stationID, err)
+		}
+		photos = append(photos, domain.StationPhoto{
+			URL:         sub.Payload.PhotoURL,
+			Caption:     sub.Payload.Caption,
+			SubmittedAt: sub.SubmittedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get photos for %s: %w", stationID, err)
+	}
+	return photos, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubmission(row rowScanner) (domain.Submission, error) {
+	var sub domain.Submission
+	var payload []byte
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&sub.ID, &sub.StationID, &sub.Kind, &payload, &sub.Submitter,
+		&sub.Status, &sub.ReviewNote, &sub.SubmittedAt, &reviewedAt,
+	); err != nil {
+		return domain.Submission{}, err
+	}
+	if err := json.Unmarshal(payload, &sub.Payload); err != nil {
+		return domain.Submission{}, fmt.Errorf("submission %d payload: %w", sub.ID, err)
+	}
+	if reviewedAt.Valid {
+		sub.ReviewedAt = &reviewedAt.Time
+	}
+	return sub, nil
+}
+
+// mergeFacilities overlays the known fields of update onto base.
+func mergeFacilities(base, update domain.StationFacilities) domain.StationFacilities {
+	merge := func(dst **bool, src *bool) {
+		if src != nil {
+			*dst = src
+		}
+	}
+	merge(&base.Toilets, update.Toilets)
+	merge(&base.PrayerRoom, update.PrayerRoom)
+	merge(&base.Lifts, update.Lifts)
+	merge(&base.Escalators, update.Escalators)
+	merge(&base.ParkAndRide, update.ParkAndRide)
+	merge(&base.TicketMachines, update.TicketMachines)
+	if update.Notes != "" {
+		base.Notes = update.Notes
+	}
+	return base
+}
@@ -0,0 +1,376 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+const syncRunColumns = `id, COALESCE(trigger_source, ''), started_at, finished_at, status, COALESCE(error, ''),
+	COALESCE(stations_succeeded, 0), COALESCE(stations_failed, 0), COALESCE(rows_written, 0), COALESCE(failed_stations, '')`
+
+// StartSyncRun records the start of a run started by trigger, one of the
+// domain.SyncTrigger values.
+func (s *Store) StartSyncRun(ctx context.Context, trigger string) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var id int64
+	err := s.db.QueryRowContext(ctx, "INSERT INTO sync_runs (trigger_source, started_at, status) VALUES (?, ?, ?) RETURNING id",
+		trigger, time.Now(), domain.SyncRunRunning).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("start sync run: %w", err)
+	}
+	return id, nil
+}
+
+// FinishSyncRun marks a run as finished with what it did and snapshots the
+// schedules as they stand at the end of it.
+func (s *Store) FinishSyncRun(ctx context.Context, id int64, stats domain.SyncRunStats, runErr error) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	status, errText := domain.SyncRunSucceeded, ""
+	if runErr != nil {
+		status, errText = domain.SyncRunFailed, runErr.Error()
+	}
+	failed, err := json.Marshal(stats.FailedStations)
+	if err != nil {
+		return fmt.Errorf("finish sync run %d: %w", id, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("finish sync run %d: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE sync_runs SET finished_at = ?, status = ?, error = ?,
+			stations_succeeded = ?, stations_failed = ?, rows_written = ?, failed_stations = ?
+		WHERE id = ?`,
+		time.Now(), status, errText,
+		stats.StationsSucceeded, stats.StationsFailed, stats.RowsWritten, failed, id,
+	); err != nil {
+		return fmt.Errorf("finish sync run %d: %w", id, err)
+	}
+	if err := snapshotSchedules(ctx, tx, id); err != nil {
+		return fmt.Errorf("finish sync run %d: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("finish sync run %d: %w", id, err)
+	}
+	return nil
+}
+
+// InterruptSyncRuns marks runs still recorded as running as interrupted. It
+// is called at startup, when no run can be in progress, so that a run cut
+// short by a crash does not show as running forever.
+func (s *Store) InterruptSyncRuns(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, "UPDATE sync_runs SET status = ? WHERE status = ?", domain.SyncRunInterrupted, domain.SyncRunRunning)
+	if err != nil {
+		return fmt.Errorf("interrupt sync runs: %w", err)
+	}
+	return nil
+}
+
+// SnapshotSchedules replaces the snapshot of run id with the current
+// schedules. It is used when stations are re-synced after their run finished.
+func (s *Store) SnapshotSchedules(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("snapshot run %d: %w", id, err)
+	}
+	defer tx.Rollback()
+
+	if err := snapshotSchedules(ctx, tx, id); err != nil {
+		return fmt.Errorf("snapshot run %d: %w", id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("snapshot run %d: %w", id, err)
+	}
+	return nil
+}
+
+func snapshotSchedules(ctx context.Context, tx *sql.Tx, id int64) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schedule_snapshots WHERE run_id = ?", id); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO schedule_snapshots (run_id, station_id, train_id, line, route, departs_at, service_day)
+		SELECT CAST(? AS BIGINT), station_id, train_id, line, route, departs_at, `+serviceDayColumn+` FROM schedules`, id)
+	return err
+}
+
+// PruneSyncRuns deletes all but the most recent keep runs, their snapshots
+// and issues, and returns how many runs it deleted.
+func (s *Store) PruneSyncRuns(ctx context.Context, keep int) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+	defer tx.Rollback()
+
+	var cutoff sql.NullInt64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM sync_runs ORDER BY id DESC LIMIT 1 OFFSET ?", keep).Scan(&cutoff)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schedule_snapshots WHERE run_id <= ?", cutoff.Int64); err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM sync_issues WHERE run_id <= ?", cutoff.Int64); err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM sync_runs WHERE id <= ?", cutoff.Int64)
+	if err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+	n, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("prune sync runs: %w", err)
+	}
+	return n, nil
+}
+
+// GetSyncRun returns ErrNotFound when the run does not exist or was pruned.
+func (s *Store) GetSyncRun(ctx context.Context, id int64) (domain.SyncRun, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.getSyncRun(ctx, "SELECT "+syncRunColumns+" FROM sync_runs WHERE id = ?", id)
+}
+
+// GetLatestSyncRun returns the most recent finished run.
+func (s *Store) GetLatestSyncRun(ctx context.Context) (domain.SyncRun, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.getSyncRun(ctx, "SELECT "+syncRunColumns+" FROM sync_runs WHERE finished_at IS NOT NULL ORDER BY id DESC LIMIT 1")
+}
+
+// GetSyncRunAt returns the last run that had finished by t.
+func (s *Store) GetSyncRunAt(ctx context.Context, t time.Time) (domain.SyncRun, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.getSyncRun(ctx, "SELECT "+syncRunColumns+" FROM sync_runs WHERE finished_at <= ? ORDER BY id DESC LIMIT 1", t)
+}
+
+func (s *Store) getSyncRun(ctx context.Context, query string, args ...any) (domain.SyncRun, error) {
+	run, err := scanSyncRun(s.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.SyncRun{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.SyncRun{}, fmt.Errorf("get sync run: %w", err)
+	}
+	return run, nil
+}
+
+// ListSyncRuns returns up to limit runs, most recent first.
+func (s *Store) ListSyncRuns(ctx context.Context, limit int) ([]domain.SyncRun, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT "+syncRunColumns+" FROM sync_runs ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("list sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []domain.SyncRun{}
+	for rows.Next() {
+		run, err := scanSyncRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list sync runs: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list sync runs: %w", err)
+	}
+	return runs, nil
+}
+
+func scanSyncRun(row rowScanner) (domain.SyncRun, error) {
+	var run domain.SyncRun
+	var finishedAt sql.NullTime
+	var failed string
+	err := row.Scan(&run.ID, &run.Trigger, &run.StartedAt, &finishedAt, &run.Status, &run.Error,
+		&run.StationsSucceeded, &run.StationsFailed, &run.RowsWritten, &failed)
+	if err != nil {
+		return domain.SyncRun{}, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	if failed != "" {
+		if err := json.Unmarshal([]byte(failed), &run.FailedStations); err != nil {
+			return domain.SyncRun{}, fmt.Errorf("sync run %d failed stations: %w", run.ID, err)
+		}
+	}
+	return run, nil
+}
+
+// snapshotTrain is one train's stops in a snapshot, keyed by station with
+// HH:MM:SS departure times. Dates are ignored since every sync restamps them.
+type snapshotTrain struct {
+	line, route string
+	stops       map[string]string
+}
+
+// snapshotKey identifies a train within one service day's timetable, since
+// the same train number may run at other times on weekends and holidays.
+type snapshotKey struct {
+	serviceDay, trainID string
+}
+
+func (s *Store) loadSnapshot(ctx context.Context, runID int64) (map[snapshotKey]*snapshotTrain, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT station_id, train_id, line, route, departs_at, "+serviceDayColumn+" FROM schedule_snapshots WHERE run_id = ?", runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trains := make(map[snapshotKey]*snapshotTrain)
+	for rows.Next() {
+		var stationID, line, route string
+		var key snapshotKey
+		var departsAt time.Time
+		if err := rows.Scan(&stationID, &key.trainID, &line, &route, &departsAt, &key.serviceDay); err != nil {
+			return nil, err
+		}
+		t, ok := trains[key]
+		if !ok {
+			t = &snapshotTrain{line: line, route: route, stops: make(map[string]string)}
+			trains[key] = t
+		}
+		t.stops[stationID] = departsAt.Format("15:04:05")
+	}
+	return trains, rows.Err()
+}
+
+// ChangedStations returns the stations whose departures differ between the
+// snapshots of two runs, sorted. It returns ErrNotFound when since has no
+// snapshot to compare with, as when it has been pruned.
+func (s *Store) ChangedStations(ctx context.Context, since, current domain.SyncRun) ([]string, error) {
+	before, err := s.loadSnapshot(ctx, since.ID)
+	if err != nil {
+		return nil, fmt.Errorf("changed stations: %w", err)
+	}
+	if len(before) == 0 {
+		return nil, ErrNotFound
+	}
+	after, err := s.loadSnapshot(ctx, current.ID)
+	if err != nil {
+		return nil, fmt.Errorf("changed stations: %w", err)
+	}
+
+	changed := make(map[string]bool)
+	compare := func(a, b map[snapshotKey]*snapshotTrain) {
+		for key, t := range a {
+			other, ok := b[key]
+			for stationID, at := range t.stops {
+				if !ok || other.stops[stationID] != at {
+					changed[stationID] = true
+				}
+			}
+		}
+	}
+	compare(after, before)
+	compare(before, after)
+	return slices.Sorted(maps.Keys(changed)), nil
+}
+
+// DiffSchedules reports the trains added, removed or re-timed between the
+// snapshots of two runs. Each service day's timetable is compared with its
+// own, so the first sync on a new kind of day reports its trains as added.
+func (s *Store) DiffSchedules(ctx context.Context, since, current domain.SyncRun) (domain.ScheduleDiff, error) {
+	diff := domain.ScheduleDiff{
+		Since:   since,
+		Current: current,
+		Added:   []domain.TrainChange{},
+		Removed: []domain.TrainChange{},
+		Retimed: []domain.TrainChange{},
+	}
+
+	before, err := s.loadSnapshot(ctx, since.ID)
+	if err != nil {
+		return domain.ScheduleDiff{}, fmt.Errorf("diff schedules: %w", err)
+	}
+	after, err := s.loadSnapshot(ctx, current.ID)
+	if err != nil {
+		return domain.ScheduleDiff{}, fmt.Errorf("diff schedules: %w", err)
+	}
+
+	for key, a := range after {
+		b, ok := before[key]
+		if !ok {
+			diff.Added = append(diff.Added, domain.TrainChange{TrainID: key.trainID, ServiceDay: key.serviceDay, Line: a.line, Route: a.route})
+			continue
+		}
+
+		var stops []domain.StopChange
+		for stationID, t := range a.stops {
+			if b.stops[stationID] != t {
+				stops = append(stops, domain.StopChange{StationID: stationID, Before: b.stops[stationID], After: t, ShiftMinutes: shiftMinutes(b.stops[stationID], t)})
+			}
+		}
+		for stationID, t := range b.stops {
+			if _, ok := a.stops[stationID]; !ok {
+				stops = append(stops, domain.StopChange{StationID: stationID, Before: t})
+			}
+		}
+		if len(stops) > 0 {
+			sort.Slice(stops, func(i, j int) bool { return stops[i].StationID < stops[j].StationID })
+			diff.Retimed = append(diff.Retimed, domain.TrainChange{TrainID: key.trainID, ServiceDay: key.serviceDay, Line: a.line, Route: a.route, Stops: stops})
+		}
+	}
+	for key, b := range before {
+		if _, ok := after[key]; !ok {
+			diff.Removed = append(diff.Removed, domain.TrainChange{TrainID: key.trainID, ServiceDay: key.serviceDay, Line: b.line, Route: b.route})
+		}
+	}
+
+	for _, changes := range [][]domain.TrainChange{diff.Added, diff.Removed, diff.Retimed} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].TrainID != changes[j].TrainID {
+				return changes[i].TrainID < changes[j].TrainID
+			}
+			return changes[i].ServiceDay < changes[j].ServiceDay
+		})
+	}
+	return diff, nil
+}
+
+// shiftMinutes returns how many whole minutes after before, both HH:MM:SS,
+// after is, or zero when either is missing.
+func shiftMinutes(before, after string) int {
+	b, err1 := time.Parse("15:04:05", before)
+	a, err2 := time.Parse("15:04:05", after)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(a.Sub(b) / time.Minute)
+}
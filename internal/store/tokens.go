@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetAuthToken returns the stored upstream token with the given name and when
+// it was obtained, or ErrNotFound.
+func (s *Store) GetAuthToken(ctx context.Context, name string) (string, time.Time, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var token string
+	var obtainedAt time.Time
+	err := s.db.QueryRowContext(ctx, "SELECT token, obtained_at FROM auth_tokens WHERE name = ?", name).Scan(&token, &obtainedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("get %s token: %w", name, err)
+	}
+	return token, obtainedAt, nil
+}
+
+// SetAuthToken stores an upstream token, replacing any with the same name.
+func (s *Store) SetAuthToken(ctx context.Context, name, token string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO auth_tokens (name, token, obtained_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET token = excluded.token, obtained_at = excluded.obtained_at`,
+		name, token, time.Now(),
+	); err != nil {
+		return fmt.Errorf("set %s token: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"llm-router/internal/domain"
+)
+
+// transferPair orders the ends of a transfer as it is stored.
+func transferPair(a, b string) (string, string) {
+	if b < a {
+		return b, a
+	}
+	return a, b
+}
+
+// GetTransfers returns every curated transfer.
+func (s *Store) GetTransfers(ctx context.Context) ([]domain.Transfer, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT station_a, station_b, walk_minutes, note FROM station_transfers ORDER BY station_a, station_b")
+	if err != nil {
+		return nil, fmt.Errorf("get transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []domain.Transfer
+	for rows.Next() {
+		t := domain.Transfer{Curated: true}
+		if err := rows.Scan(&t.StationID, &t.ToStationID, &t.WalkMinutes, &t.Note); err != nil {
+			return nil, fmt.Errorf("get transfers: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// GetStationTransfers returns the walks from a station to the stations near
+// it, curated or found by the BRT import, the shortest first. A curated
+// transfer replaces an imported one between the same stations.
+func (s *Store) GetStationTransfers(ctx context.Context, stationID string) ([]domain.Transfer, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	byStation := make(map[string]domain.Transfer)
+	queries := []struct {
+		query   string
+		curated bool
+	}{
+		{`SELECT stop_id, walk_minutes, '' FROM brt_transfers WHERE station_id = ?
+		UNION ALL SELECT station_id, walk_minutes, '' FROM brt_transfers WHERE stop_id = ?`, false},
+		{`SELECT station_b, walk_minutes, note FROM station_transfers WHERE station_a = ?
+		UNION ALL SELECT station_a, walk_minutes, note FROM station_transfers WHERE station_b = ?`, true},
+	}
+	for _, q := range queries {
+		rows, err := s.db.QueryContext(ctx, q.query, stationID, stationID)
+		if err != nil {
+			return nil, fmt.Errorf("get transfers for %s: %w", stationID, err)
+		}
+		for rows.Next() {
+			t := domain.Transfer{StationID: stationID, Curated: q.curated}
+			if err := rows.Scan(&t.ToStationID, &t.WalkMinutes, &t.Note); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("get transfers for %s: %w", stationID, err)
+			}
+			byStation[t.ToStationID] = t
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("get transfers for %s: %w", stationID, err)
+		}
+	}
+
+	transfers := make([]domain.Transfer, 0, len(byStation))
+	for _, t := range byStation {
+		transfers = append(transfers, t)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].WalkMinutes != transfers[j].WalkMinutes {
+			return transfers[i].WalkMinutes < transfers[j].WalkMinutes
+		}
+		return transfers[i].ToStationID < transfers[j].ToStationID
+	})
+	return transfers, nil
+}
+
+// SetTransfer curates the walk between t's stations, either way, replacing
+// the one they already have.
+func (s *Store) SetTransfer(ctx context.Context, t domain.Transfer) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	a, b := transferPair(t.StationID, t.ToStationID)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO station_transfers (station_a, station_b, walk_minutes, note, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(station_a, station_b) DO UPDATE SET walk_minutes = excluded.walk_minutes, note = excluded.note, updated_at = excluded.updated_at`,
+		a, b, t.WalkMinutes, t.Note, time.Now())
+	if err != nil {
+		return fmt.Errorf("set transfer %s-%s: %w", a, b, err)
+	}
+	return nil
+}
+
+// DeleteTransfer returns ErrNotFound when no transfer between the stations
+// is curated.
+func (s *Store) DeleteTransfer(ctx context.Context, stationID, toStationID string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	a, b := transferPair(stationID, toStationID)
+	res, err := s.db.ExecContext(ctx, "DELETE FROM station_transfers WHERE station_a = ? AND station_b = ?", a, b)
+	if err != nil {
+		return fmt.Errorf("delete transfer %s-%s: %w", a, b, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete transfer %s-%s: %w", a, b, err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
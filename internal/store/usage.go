@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Usage kinds recorded by the API for cache warming.
+const (
+	UsageKindStation = "station"
+	UsageKindLine    = "line"
+)
+
+// AddUsageCounts adds request counts for keys of the given kind.
+func (s *Store) AddUsageCounts(ctx context.Context, kind string, counts map[string]int) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("add %s usage: %w", kind, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO usage_counts (kind, key, hits, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(kind, key) DO UPDATE SET hits = usage_counts.hits + excluded.hits, updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("add %s usage: %w", kind, err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for key, n := range counts {
+		if _, err := stmt.ExecContext(ctx, kind, key, n, now); err != nil {
+			return fmt.Errorf("add %s usage: %w", kind, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("add %s usage: %w", kind, err)
+	}
+	return nil
+}
+
+// GetTopUsage returns up to n keys of the given kind, most requested first.
+func (s *Store) GetTopUsage(ctx context.Context, kind string, n int) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT key FROM usage_counts WHERE kind = ? ORDER BY hits DESC LIMIT ?", kind, n)
+	if err != nil {
+		return nil, fmt.Errorf("get top %s usage: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("get top %s usage: %w", kind, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get top %s usage: %w", kind, err)
+	}
+	return keys, nil
+}
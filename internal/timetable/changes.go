@@ -0,0 +1,99 @@
+package timetable
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+)
+
+// StationChanges returns the closures and renames of stations by ID,
+// loading them on first use after each change.
+func (t *Reader) StationChanges(ctx context.Context) (map[string]domain.StationChange, error) {
+	if changes := t.stationChanges.Load(); changes != nil {
+		return *changes, nil
+	}
+	changes, err := t.store.GetStationChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.stationChanges.Store(&changes)
+	return changes, nil
+}
+
+// StationChangesChanged drops the loaded closures and renames once one has
+// been edited.
+func (t *Reader) StationChangesChanged() {
+	t.stationChanges.Store(nil)
+}
+
+// ClosedStations returns the stations closed on date.
+func (t *Reader) ClosedStations(ctx context.Context, date time.Time) ([]string, error) {
+	changes, err := t.StationChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	day := calendar.FormatDate(date)
+	var closed []string
+	for id, c := range changes {
+		if c.ClosedOn(day) {
+			closed = append(closed, id)
+		}
+	}
+	slices.Sort(closed)
+	return closed, nil
+}
+
+// StationClosed reports whether stationID is closed on date.
+func (t *Reader) StationClosed(ctx context.Context, stationID string, date time.Time) (bool, error) {
+	changes, err := t.StationChanges(ctx)
+	if err != nil {
+		return false, err
+	}
+	return changes[stationID].ClosedOn(calendar.FormatDate(date)), nil
+}
+
+// changedRoute returns data as on date: without its stops at stations closed
+// then, and with the names renamed stations have then. data itself, which
+// may be cached, is returned when no change applies.
+func (t *Reader) changedRoute(ctx context.Context, data domain.RouteData, date time.Time) (domain.RouteData, error) {
+	changes, err := t.StationChanges(ctx)
+	if err != nil || len(changes) == 0 {
+		return data, err
+	}
+	day := calendar.FormatDate(date)
+	applies := func(id string) bool {
+		c, ok := changes[id]
+		return ok && (c.ClosedOn(day) || c.NameOn(day) != "")
+	}
+	if !slices.ContainsFunc(data.Routes, func(stop domain.RouteStop) bool { return applies(stop.StationID) }) &&
+		!applies(data.Details.StationOriginID) && !applies(data.Details.StationDestinationID) {
+		return data, nil
+	}
+
+	stops := make([]domain.RouteStop, 0, len(data.Routes))
+	for _, stop := range data.Routes {
+		c := changes[stop.StationID]
+		if c.ClosedOn(day) {
+			continue
+		}
+		if name := c.NameOn(day); name != "" {
+			stop.StationName = name
+		}
+		stops = append(stops, stop)
+	}
+	// A train whose origin is closed starts at the first stop left.
+	if len(stops) > 0 && stops[0].ID != data.Routes[0].ID {
+		stops[0].ArrivesAt, stops[0].ArrivalEstimated = nil, false
+	}
+	data.Routes = stops
+	if name := changes[data.Details.StationOriginID].NameOn(day); name != "" {
+		data.Details.StationOriginName = name
+	}
+	if name := changes[data.Details.StationDestinationID].NameOn(day); name != "" {
+		data.Details.StationDestinationName = name
+	}
+	return data, nil
+}
@@ -0,0 +1,149 @@
+package timetable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/store"
+)
+
+// DatedSchedules returns the schedules across the network matching f as they
+// run on date: from the timetable each station runs on date's kind of day,
+// without the departures from stations closed then, and with the schedule
+// overrides in effect on date applied. f's Timetables are picked for date and
+// the closed stations are added to its ExcludeStations.
+func (t *Reader) DatedSchedules(ctx context.Context, f store.ScheduleFilter, date time.Time) ([]domain.Schedule, error) {
+	f, active, err := t.datedFilter(ctx, f, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		return t.store.QuerySchedules(ctx, f)
+	}
+	return t.overriddenSchedules(ctx, f, active, date)
+}
+
+// EachDatedSchedule calls fn with each of DatedSchedules in turn, streaming
+// them from the store when no override is in effect on date.
+func (t *Reader) EachDatedSchedule(ctx context.Context, f store.ScheduleFilter, date time.Time, fn func(domain.Schedule) error) error {
+	f, active, err := t.datedFilter(ctx, f, date)
+	if err != nil {
+		return err
+	}
+	if len(active) == 0 {
+		return t.store.EachQueriedSchedule(ctx, f, fn)
+	}
+	schedules, err := t.overriddenSchedules(ctx, f, active, date)
+	if err != nil {
+		return err
+	}
+	for _, sch := range schedules {
+		if err := fn(sch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NetworkKey names the network as it runs on date: its kind of day, and the
+// overrides and closures in effect then, if any. Views built from
+// DatedSchedules can be cached under it, since dates with the same key run
+// the same timetable.
+func (t *Reader) NetworkKey(ctx context.Context, date time.Time) (string, error) {
+	overrides, err := t.Overrides(ctx)
+	if err != nil {
+		return "", err
+	}
+	closed, err := t.ClosedStations(ctx, date)
+	if err != nil {
+		return "", err
+	}
+	key := t.calendar.ServiceDay(date)
+	if active := activeOverrides(overrides, date); len(active) > 0 {
+		ids := make([]string, len(active))
+		for i, o := range active {
+			ids[i] = fmt.Sprint(o.ID)
+		}
+		key += "/overrides:" + strings.Join(ids, ",")
+	}
+	if len(closed) > 0 {
+		key += "/closed:" + strings.Join(closed, ",")
+	}
+	return key, nil
+}
+
+// datedFilter returns f for date, and the overrides in effect then.
+func (t *Reader) datedFilter(ctx context.Context, f store.ScheduleFilter, date time.Time) (store.ScheduleFilter, []domain.ScheduleOverride, error) {
+	timetables, err := t.timetables(ctx, t.calendar.ServiceDay(date))
+	if err != nil {
+		return f, nil, err
+	}
+	closed, err := t.ClosedStations(ctx, date)
+	if err != nil {
+		return f, nil, err
+	}
+	overrides, err := t.Overrides(ctx)
+	if err != nil {
+		return f, nil, err
+	}
+	f.Timetables = timetables
+	f.ExcludeStations = append(append([]string(nil), f.ExcludeStations...), closed...)
+	return f, activeOverrides(overrides, date), nil
+}
+
+// overriddenSchedules is DatedSchedules with overrides in effect. Retimes
+// can move a departure across f's times of day, so the store is asked
+// without them; the overridden schedules are then held to all of f, ordered
+// and paged as the store would.
+func (t *Reader) overriddenSchedules(ctx context.Context, f store.ScheduleFilter, active []domain.ScheduleOverride, date time.Time) ([]domain.Schedule, error) {
+	q := f
+	q.After, q.Before, q.Limit, q.Offset = "", "", 0, 0
+	q.Conditions = nil
+	for _, c := range f.Conditions {
+		if !c.Clock() {
+			q.Conditions = append(q.Conditions, c)
+		}
+	}
+	schedules, err := t.store.QuerySchedules(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules = t.applyOverrides(schedules, active, date, func(domain.ScheduleOverride) bool { return true })
+	matched := []domain.Schedule{}
+	for _, sch := range schedules {
+		if f.Match(sch) {
+			matched = append(matched, sch)
+		}
+	}
+	f.Order(matched)
+
+	if f.Offset >= len(matched) {
+		return []domain.Schedule{}, nil
+	}
+	matched = matched[f.Offset:]
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched, nil
+}
+
+// timetables picks, for every station with more than one timetable, the one
+// it runs on serviceDay, as calendar.Timetable does for a single station.
+func (t *Reader) timetables(ctx context.Context, serviceDay string) (map[string]string, error) {
+	days, err := t.store.GetServiceDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timetables := make(map[string]string)
+	for stationID, stationDays := range days {
+		if day := calendar.Variant(stationDays, serviceDay); day != "" {
+			timetables[stationID] = day
+		}
+	}
+	return timetables, nil
+}
@@ -0,0 +1,214 @@
+package timetable
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"llm-router/internal/calendar"
+	"llm-router/internal/domain"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+)
+
+// Overrides returns every schedule override, loading them on first use
+// after each change.
+func (t *Reader) Overrides(ctx context.Context) ([]domain.ScheduleOverride, error) {
+	if overrides := t.overrides.Load(); overrides != nil {
+		return *overrides, nil
+	}
+	overrides, err := t.store.ListScheduleOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.overrides.Store(&overrides)
+	return overrides, nil
+}
+
+// OverridesChanged drops the loaded overrides once they have been edited.
+func (t *Reader) OverridesChanged() {
+	t.overrides.Store(nil)
+}
+
+// overriddenBoard is a station's departures on date with the overrides in
+// effect then applied. board itself is returned when none changes it.
+func (t *Reader) overriddenBoard(ctx context.Context, stationID string, board []domain.Schedule, date time.Time) ([]domain.Schedule, error) {
+	overrides, err := t.Overrides(ctx)
+	if err != nil || len(overrides) == 0 {
+		return board, err
+	}
+	return t.applyOverrides(board, overrides, date, func(o domain.ScheduleOverride) bool {
+		return o.StationID == stationID
+	}), nil
+}
+
+// overriddenRoute is DatedRoute for a train with overrides in effect on
+// date. The result is not cached, since overrides may hold for a single
+// date.
+func (t *Reader) overriddenRoute(ctx context.Context, trainID string, date time.Time, overrides []domain.ScheduleOverride) (domain.RouteData, error) {
+	var schedules []domain.Schedule
+	var err error
+	if t.PastDate(date) {
+		if schedules, err = t.store.GetRouteHistory(ctx, trainID, calendar.FormatDate(date)); err != nil {
+			return domain.RouteData{}, err
+		}
+	}
+	if len(schedules) == 0 {
+		if schedules, err = t.store.GetRoute(ctx, trainID); err != nil {
+			return domain.RouteData{}, err
+		}
+		schedules = calendar.Timetable(schedules, t.calendar.ServiceDay(date))
+	}
+	schedules = t.applyOverrides(schedules, overrides, date, func(domain.ScheduleOverride) bool { return true })
+	if len(schedules) == 0 {
+		return domain.RouteData{}, store.ErrNotFound
+	}
+
+	names, err := t.StationNames(ctx)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	return journey.NewRoute(trainID, schedules, names), nil
+}
+
+// trainOverrides returns the overrides of trainID in effect on date.
+func trainOverrides(overrides []domain.ScheduleOverride, trainID string, date time.Time) []domain.ScheduleOverride {
+	day := calendar.FormatDate(date)
+	var matched []domain.ScheduleOverride
+	for _, o := range overrides {
+		if o.TrainID == trainID && o.AppliesOn(day) {
+			matched = append(matched, o)
+		}
+	}
+	return matched
+}
+
+// activeOverrides returns those of overrides in effect on date.
+func activeOverrides(overrides []domain.ScheduleOverride, date time.Time) []domain.ScheduleOverride {
+	day := calendar.FormatDate(date)
+	var active []domain.ScheduleOverride
+	for _, o := range overrides {
+		if o.AppliesOn(day) {
+			active = append(active, o)
+		}
+	}
+	return active
+}
+
+// applyOverrides returns schedules with the cancels and retimes in effect on
+// date applied, and the adds that list accepts among them, in departure
+// order. Added departures fall on the day of the timetable they join.
+// schedules is not modified, and is itself returned when nothing changes.
+func (t *Reader) applyOverrides(schedules []domain.Schedule, overrides []domain.ScheduleOverride, date time.Time, list func(domain.ScheduleOverride) bool) []domain.Schedule {
+	day := calendar.FormatDate(date)
+	active := activeOverrides(overrides, date)
+	if len(active) == 0 {
+		return schedules
+	}
+
+	base := date
+	if i := slices.IndexFunc(schedules, func(sch domain.Schedule) bool { return !sch.DepartsAt.IsZero() }); i >= 0 {
+		base = schedules[i].DepartsAt
+	}
+	colors := make(map[string]string)
+	for _, sch := range schedules {
+		colors[sch.Line] = sch.Metadata.Origin.Color
+	}
+
+	changed := false
+	result := make([]domain.Schedule, 0, len(schedules))
+	for _, sch := range schedules {
+		cancelled := false
+		for _, o := range active {
+			if o.Kind == domain.OverrideAdd || o.TrainID != sch.TrainID || (o.StationID != "" && o.StationID != sch.StationID) {
+				continue
+			}
+			changed = true
+			if o.Kind == domain.OverrideCancel {
+				cancelled = true
+				break
+			}
+			sch = retimed(sch, o, base)
+		}
+		if !cancelled {
+			result = append(result, sch)
+		}
+	}
+	for _, o := range active {
+		if o.Kind == domain.OverrideAdd && list(o) {
+			changed = true
+			result = append(result, addedSchedule(o, base, day, t.calendar.ServiceDay(date), colors[o.Line]))
+		}
+	}
+	if !changed {
+		return schedules
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].DepartsAt.Before(result[j].DepartsAt) })
+	return result
+}
+
+// retimed returns sch moved as retime override o says.
+func retimed(sch domain.Schedule, o domain.ScheduleOverride, base time.Time) domain.Schedule {
+	if o.DepartsAt != "" {
+		if !sch.DepartsAt.IsZero() {
+			base = sch.DepartsAt
+		}
+		sch.DepartsAt = atClock(base, o.DepartsAt)
+	} else {
+		shift := time.Duration(o.ShiftMinutes) * time.Minute
+		if !sch.DepartsAt.IsZero() {
+			sch.DepartsAt = sch.DepartsAt.Add(shift)
+		}
+		if !sch.ArrivesAt.IsZero() {
+			sch.ArrivesAt = sch.ArrivesAt.Add(shift)
+		}
+	}
+	if o.ArrivesAt != "" {
+		sch.ArrivesAt = atClock(sch.DepartsAt, o.ArrivesAt)
+		if sch.ArrivesAt.Before(sch.DepartsAt) {
+			sch.ArrivesAt = sch.ArrivesAt.AddDate(0, 0, 1)
+		}
+	}
+	sch.Metadata.Override = domain.OverrideRetime
+	sch.Metadata.OverrideNote = o.Note
+	return sch
+}
+
+// addedSchedule is the departure add override o lists, on the day of base.
+func addedSchedule(o domain.ScheduleOverride, base time.Time, serviceDate, serviceDay, color string) domain.Schedule {
+	sch := domain.Schedule{
+		ID:                   fmt.Sprintf("sc_override_%d", o.ID),
+		StationID:            o.StationID,
+		StationOriginID:      o.StationOriginID,
+		StationDestinationID: o.StationDestinationID,
+		TrainID:              o.TrainID,
+		Line:                 o.Line,
+		Route:                o.Route,
+		DepartsAt:            atClock(base, o.DepartsAt),
+		UpdatedAt:            o.UpdatedAt,
+		ServiceType:          o.ServiceType,
+		ServiceDay:           serviceDay,
+		ServiceDate:          serviceDate,
+		Metadata: domain.ScheduleMetadata{
+			Origin:       domain.ScheduleOrigin{Color: color},
+			Override:     domain.OverrideAdd,
+			OverrideNote: o.Note,
+		},
+	}
+	if o.ArrivesAt != "" {
+		sch.ArrivesAt = atClock(base, o.ArrivesAt)
+		if sch.ArrivesAt.Before(sch.DepartsAt) {
+			sch.ArrivesAt = sch.ArrivesAt.AddDate(0, 0, 1)
+		}
+	}
+	return sch
+}
+
+// atClock returns clock, HH:MM, on t's date in Jakarta.
+func atClock(t time.Time, clock string) time.Time {
+	c, _ := time.Parse("15:04", clock)
+	y, m, d := t.In(calendar.Jakarta).Date()
+	return time.Date(y, m, d, c.Hour(), c.Minute(), 0, 0, calendar.Jakarta)
+}
@@ -0,0 +1,217 @@
+// Package timetable reads station boards and train routes as they run on a
+// service date, for both the JSON and gRPC APIs: from the history for past
+// dates, with the schedule overrides in effect and the stations' closures
+// and renames applied. The assembled boards and routes are cached until the
+// next sync.
+package timetable
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"llm-router/internal/cache"
+	"llm-router/internal/calendar"
+	"llm-router/internal/config"
+	"llm-router/internal/domain"
+	"llm-router/internal/journey"
+	"llm-router/internal/store"
+)
+
+// Reader reads the timetables from the store. It is safe for concurrent use.
+type Reader struct {
+	config   *config.Config
+	store    *store.Store
+	calendar *calendar.Calendar
+
+	routes *cache.Cache[domain.RouteData]
+	boards *cache.Cache[[]domain.Schedule]
+
+	// overrides are the schedule overrides, loaded on first use after each
+	// change; see Overrides.
+	overrides atomic.Pointer[[]domain.ScheduleOverride]
+	// stationChanges are the stations' closures and renames by ID, loaded
+	// on first use after each change; see StationChanges.
+	stationChanges atomic.Pointer[map[string]domain.StationChange]
+}
+
+func New(cfg *config.Config, s *store.Store, cal *calendar.Calendar) *Reader {
+	return &Reader{
+		config:   cfg,
+		store:    s,
+		calendar: cal,
+		routes:   cache.New[domain.RouteData](),
+		boards:   cache.New[[]domain.Schedule](),
+	}
+}
+
+// Reset drops the cached boards and routes, once a sync has replaced them.
+func (t *Reader) Reset() {
+	t.routes.Reset()
+	t.boards.Reset()
+}
+
+// CacheStats reports the route and board caches for the metrics.
+func (t *Reader) CacheStats() map[string]domain.CacheStats {
+	return map[string]domain.CacheStats{
+		"routes": t.routes.Stats(),
+		"boards": t.boards.Stats(),
+	}
+}
+
+// OperatingDate returns the service date running now.
+func (t *Reader) OperatingDate() time.Time {
+	return calendar.OperatingDate(time.Now(), t.config.ServiceDates.DayStartHour)
+}
+
+// PastDate reports whether date is a service date before the one running
+// now, whose timetable is read from the history rather than the current
+// timetables.
+func (t *Reader) PastDate(date time.Time) bool {
+	return calendar.FormatDate(date) < calendar.FormatDate(t.OperatingDate())
+}
+
+// Board returns a station's schedules. The slice is shared with the cache
+// and must not be modified.
+func (t *Reader) Board(ctx context.Context, stationID string) ([]domain.Schedule, error) {
+	if schedules, ok := t.boards.Get(stationID); ok {
+		return schedules, nil
+	}
+
+	schedules, err := t.store.GetSchedules(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) > 0 {
+		t.boards.Set(stationID, schedules)
+	}
+	return schedules, nil
+}
+
+// Route returns the assembled route for a train in the timetable of
+// serviceDay, building and caching it on first use. It returns
+// store.ErrNotFound when the train has no schedules.
+func (t *Reader) Route(ctx context.Context, trainID, serviceDay string) (domain.RouteData, error) {
+	key := serviceDay + "/" + trainID
+	if data, ok := t.routes.Get(key); ok {
+		return data, nil
+	}
+
+	schedules, err := t.store.GetRoute(ctx, trainID)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	schedules = calendar.Timetable(schedules, serviceDay)
+	if len(schedules) == 0 {
+		return domain.RouteData{}, store.ErrNotFound
+	}
+
+	// Looking up every station name is cheap with 100-odd stations, and the
+	// assembled result is cached per train until the next sync.
+	stationMap, err := t.StationNames(ctx)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+
+	data := journey.NewRoute(trainID, schedules, stationMap)
+	t.routes.Set(key, data)
+	return data, nil
+}
+
+// DatedBoard returns a station's departures on date: the timetable it ran
+// then when date is past and the history holds it, or else the current
+// timetable for date's kind of day, with the schedule overrides in effect on
+// date applied. A station closed on date has none.
+func (t *Reader) DatedBoard(ctx context.Context, stationID string, date time.Time) ([]domain.Schedule, error) {
+	return t.datedBoard(ctx, stationID, date, t.Board)
+}
+
+// UncachedDatedBoard is DatedBoard reading the current timetable from the
+// store, for views that follow a station through a sync, before the cached
+// boards are dropped at its end.
+func (t *Reader) UncachedDatedBoard(ctx context.Context, stationID string, date time.Time) ([]domain.Schedule, error) {
+	return t.datedBoard(ctx, stationID, date, t.store.GetSchedules)
+}
+
+// datedBoard is DatedBoard reading the current timetable with board.
+func (t *Reader) datedBoard(ctx context.Context, stationID string, date time.Time, board func(context.Context, string) ([]domain.Schedule, error)) ([]domain.Schedule, error) {
+	if closed, err := t.StationClosed(ctx, stationID, date); err != nil || closed {
+		return []domain.Schedule{}, err
+	}
+	if t.PastDate(date) {
+		history, err := t.store.GetScheduleHistory(ctx, stationID, calendar.FormatDate(date))
+		if err != nil {
+			return nil, err
+		}
+		if len(history) > 0 {
+			return t.overriddenBoard(ctx, stationID, history, date)
+		}
+	}
+	current, err := board(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	return t.overriddenBoard(ctx, stationID, calendar.Timetable(current, t.calendar.ServiceDay(date)), date)
+}
+
+// DatedRoute is Route for date, reading the train's trip from the history
+// when date is past and the history holds it, as the stations' closures and
+// renames leave it on date (see changedRoute). The result may be cached, so
+// its stops must not be modified.
+func (t *Reader) DatedRoute(ctx context.Context, trainID string, date time.Time) (domain.RouteData, error) {
+	data, err := t.scheduledRoute(ctx, trainID, date)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	return t.changedRoute(ctx, data, date)
+}
+
+// scheduledRoute is DatedRoute before station changes. Past trips are cached
+// like current ones, until the next sync. Trains with schedule overrides in
+// effect on date are built by overriddenRoute instead.
+func (t *Reader) scheduledRoute(ctx context.Context, trainID string, date time.Time) (domain.RouteData, error) {
+	overrides, err := t.Overrides(ctx)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	if overrides = trainOverrides(overrides, trainID, date); len(overrides) > 0 {
+		return t.overriddenRoute(ctx, trainID, date, overrides)
+	}
+
+	if !t.PastDate(date) {
+		return t.Route(ctx, trainID, t.calendar.ServiceDay(date))
+	}
+
+	day := calendar.FormatDate(date)
+	key := day + "/" + trainID
+	if data, ok := t.routes.Get(key); ok {
+		return data, nil
+	}
+	schedules, err := t.store.GetRouteHistory(ctx, trainID, day)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	if len(schedules) == 0 {
+		return t.Route(ctx, trainID, t.calendar.ServiceDay(date))
+	}
+	names, err := t.StationNames(ctx)
+	if err != nil {
+		return domain.RouteData{}, err
+	}
+	data := journey.NewRoute(trainID, schedules, names)
+	t.routes.Set(key, data)
+	return data, nil
+}
+
+// StationNames maps station IDs to their names.
+func (t *Reader) StationNames(ctx context.Context) (map[string]string, error) {
+	stations, err := t.store.GetStations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(stations))
+	for _, st := range stations {
+		names[st.ID] = st.Name
+	}
+	return names, nil
+}
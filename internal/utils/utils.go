@@ -1,11 +1,13 @@
 package utils
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"unicode"
@@ -91,7 +93,9 @@ func LogRequestResponse(logger *zap.Logger, req *http.Request, resp *http.Respon
 
 type ResponseRecorder struct {
 	http.ResponseWriter
-	StatusCode     int
+	StatusCode int
+	// Size is the number of response body bytes written, captured or not.
+	Size           int
 	Body           bytes.Buffer
 	streaming      bool
 	maxCaptureSize int
@@ -108,6 +112,14 @@ func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
 	}
 }
 
+// NewStatusRecorder returns a recorder that tracks the status and size of a
+// response without capturing its body.
+func NewStatusRecorder(w http.ResponseWriter) *ResponseRecorder {
+	rec := NewResponseRecorder(w)
+	rec.maxCaptureSize = 0
+	return rec
+}
+
 func (r *ResponseRecorder) WriteHeader(statusCode int) {
 	r.StatusCode = statusCode
 	r.ResponseWriter.WriteHeader(statusCode)
@@ -119,6 +131,7 @@ func (r *ResponseRecorder) WriteHeader(statusCode int) {
 
 func (r *ResponseRecorder) Write(b []byte) (int, error) {
 	n, err := r.ResponseWriter.Write(b)
+	r.Size += n
 
 	if err == nil && n > 0 && r.capturedSize < r.maxCaptureSize {
 		remainingCapacity := r.maxCaptureSize - r.capturedSize
@@ -150,6 +163,21 @@ func (r *ResponseRecorder) Header() http.Header {
 	return r.ResponseWriter.Header()
 }
 
+// Hijack hands the connection over for WebSocket upgrades, which are
+// recorded as 101 Switching Protocols.
+func (r *ResponseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.StatusCode = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func extractDeltaContent(line string) string {
 	if !strings.HasPrefix(line, "{") {
 		return ""
@@ -0,0 +1,16 @@
+//go:build !embedweb
+
+// Package web embeds the built frontend into the binary when it is built
+// with the embedweb tag, after the frontend build has written web/dist:
+//
+//	go build -tags embedweb .
+//
+// Without the tag the server reads the frontend from disk.
+package web
+
+import "io/fs"
+
+// Dist returns nil: the frontend was not embedded.
+func Dist() fs.FS {
+	return nil
+}
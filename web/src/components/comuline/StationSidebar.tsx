@@ -1,8 +1,7 @@
 import { Button } from '@/components/ui/button';
-import { Dialog, DialogContent, DialogDescription, DialogFooter, DialogHeader, DialogTitle, DialogTrigger, DialogClose } from '@/components/ui/dialog';
 import { Input } from '@/components/ui/input';
 import { ScrollArea } from '@/components/ui/scroll-area';
-import { Train, Search, AlertCircle, Loader2, RefreshCw, ChevronRight, Star } from 'lucide-react';
+import { Train, Search, AlertCircle, Loader2, ChevronRight, Star } from 'lucide-react';
 import type { Station } from '@/hooks/useComuline';
 import { useFavorites } from '@/store';
 import { format } from 'date-fns';
@@ -15,9 +14,6 @@ interface StationSidebarProps {
   onSelectStation: (station: Station) => void;
   searchQuery: string;
   setSearchQuery: (query: string) => void;
-  sync: () => void;
-  isSyncing: boolean;
-  isMobile?: boolean;
   lastUpdated?: Date | null;
 }
 
@@ -29,9 +25,6 @@ export function StationSidebar({
   onSelectStation,
   searchQuery,
   setSearchQuery,
-  sync,
-  isSyncing,
-  isMobile,
   lastUpdated
 }: StationSidebarProps) {
   const { favorites, toggleFavorite } = useFavorites();
@@ -99,41 +92,6 @@ export function StationSidebar({
               <h1 className="text-xl font-bold tracking-tight">Comuline</h1>
             )}
           </div>
-          <div className={`flex items-center gap-1 ${isMobile ? 'mr-8' : ''}`}>
-            <Dialog>
-              <DialogTrigger asChild>
-                <Button
-                  variant="ghost"
-                  size="icon"
-                  disabled={isSyncing}
-                  title="Sync Data"
-                  className="h-8 w-8 text-terminal-muted hover:text-terminal-text"
-                >
-                  <RefreshCw className={`size-4 ${isSyncing ? 'animate-spin' : ''}`} />
-                </Button>
-              </DialogTrigger>
-              <DialogContent className="sm:max-w-[425px]">
-                <DialogHeader>
-                  <DialogTitle className="text-terminal-green">Sync Data</DialogTitle>
-                  <DialogDescription className="text-terminal-muted">
-                    This will fetch the latest schedule data from the API. This process might take a few moments. Are you sure you want to proceed?
-                  </DialogDescription>
-                </DialogHeader>
-                <DialogFooter className="gap-2 sm:gap-0">
-                  <DialogClose asChild>
-                    <Button variant="outline" className="border-terminal-border hover:bg-terminal-surface text-terminal-text">
-                      Cancel
-                    </Button>
-                  </DialogClose>
-                  <DialogClose asChild>
-                    <Button onClick={() => sync()} className="bg-terminal-green text-black hover:bg-terminal-green/80">
-                      Sync Now
-                    </Button>
-                  </DialogClose>
-                </DialogFooter>
-              </DialogContent>
-            </Dialog>
-          </div>
         </div>
         <div className="relative">
           <Search className="absolute left-2.5 top-2.5 size-4 text-terminal-muted" />
@@ -1,4 +1,5 @@
-import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
+import { useQuery, useMutation } from '@tanstack/react-query';
+import type { Session } from '@/store';
 
 const API_BASE_URL = '/api';
 
@@ -100,20 +101,17 @@ export function useRoute(trainId: string | null) {
   });
 }
 
-export function useSync() {
-  const queryClient = useQueryClient();
-
+export function useVerifySignIn() {
   return useMutation({
-    mutationFn: async () => {
-      const response = await fetch(`${API_BASE_URL}/v1/sync`, {
+    mutationFn: async (token: string): Promise<Session> => {
+      const response = await fetch(`${API_BASE_URL}/v1/auth/verify`, {
         method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ token }),
       });
-      if (!response.ok) throw new Error('Failed to trigger sync');
-      return response.json();
-    },
-    onSuccess: () => {
-      queryClient.invalidateQueries({ queryKey: ['stations'] });
-      queryClient.invalidateQueries({ queryKey: ['schedule'] });
+      if (!response.ok) throw new Error('Failed to sign in');
+      const json = await response.json();
+      return json.data;
     },
   });
 }
@@ -1,6 +1,6 @@
 import { useState, useEffect, useRef, useMemo } from 'react';
 import { createFileRoute } from '@tanstack/react-router';
-import { useStations, useSchedule, useRoute, useSync, useNextDepartures, type Station } from '@/hooks/useComuline';
+import { useStations, useSchedule, useRoute, useNextDepartures, type Station } from '@/hooks/useComuline';
 import { ScrollArea } from '@/components/ui/scroll-area';
 import { Button } from '@/components/ui/button';
 import { Sheet, SheetContent, SheetTrigger } from '@/components/ui/sheet';
@@ -23,7 +23,6 @@ function ComulinePage() {
   const { data: stations, isLoading: isLoadingStations, error: stationsError } = useStations();
   const { data: schedules, isLoading: isLoadingSchedules } = useSchedule(selectedStation?.id || null);
   const { data: routeData, isLoading: isLoadingRoute } = useRoute(selectedTrainId);
-  const { mutate: sync, isPending: isSyncing } = useSync();
   const { data: nextDepartures, isLoading: isLoadingNextDepartures } = useNextDepartures(favorites);
 
   const scheduleRefs = useRef<(HTMLButtonElement | null)[]>([]);
@@ -106,8 +105,6 @@ function ComulinePage() {
     onSelectStation: handleSelectStation,
     searchQuery,
     setSearchQuery,
-    sync,
-    isSyncing,
     lastUpdated
   };
 
@@ -133,7 +130,7 @@ function ComulinePage() {
             </Button>
           </SheetTrigger>
           <SheetContent side="left" className="p-0 border-r border-terminal-border bg-terminal-bg w-80">
-            <StationSidebar {...sidebarProps} isMobile={true} />
+            <StationSidebar {...sidebarProps} />
           </SheetContent>
         </Sheet>
       </div>
@@ -0,0 +1,61 @@
+import { useEffect, useState } from 'react';
+import { Link, createFileRoute } from '@tanstack/react-router';
+import { useVerifySignIn } from '@/hooks/useComuline';
+import { Button } from '@/components/ui/button';
+import { Loader2, LogIn } from 'lucide-react';
+import { useSessionStore } from '@/store';
+
+export const Route = createFileRoute('/sign-in')({
+  component: SignInPage,
+});
+
+// The emailed sign-in link carries its token in the fragment. It is only
+// posted once the user confirms, so mail scanners opening the link do not
+// spend it.
+function SignInPage() {
+  const [token] = useState(() => new URLSearchParams(window.location.hash.slice(1)).get('token'));
+  const setSession = useSessionStore((s) => s.setSession);
+  const verify = useVerifySignIn();
+
+  // Keep the token out of the history once it is read.
+  useEffect(() => {
+    window.history.replaceState(null, '', window.location.pathname);
+  }, []);
+
+  const signIn = () => {
+    if (!token) return;
+    verify.mutate(token, { onSuccess: setSession });
+  };
+
+  return (
+    <div className="flex min-h-screen items-center justify-center bg-background p-6">
+      <div className="w-full max-w-sm space-y-4 rounded-xl border bg-card p-6 text-card-foreground shadow-sm">
+        <h1 className="text-lg font-semibold">Sign in to Commuter</h1>
+        {verify.isSuccess ? (
+          <>
+            <p className="text-sm text-muted-foreground">Signed in as {verify.data.user.email}.</p>
+            <Button asChild className="w-full">
+              <Link to="/">Continue</Link>
+            </Button>
+          </>
+        ) : !token ? (
+          <p className="text-sm text-muted-foreground">
+            This sign-in link is incomplete. Open the link from your email again.
+          </p>
+        ) : (
+          <>
+            <p className="text-sm text-muted-foreground">
+              {verify.isError
+                ? 'This sign-in link has expired or was already used. Ask for a new one.'
+                : 'Confirm to finish signing in on this device.'}
+            </p>
+            <Button className="w-full" onClick={signIn} disabled={verify.isPending || verify.isError}>
+              {verify.isPending ? <Loader2 className="animate-spin" /> : <LogIn />}
+              Sign in
+            </Button>
+          </>
+        )}
+      </div>
+    </div>
+  );
+}
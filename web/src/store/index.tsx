@@ -57,3 +57,30 @@ export const useFavorites = () => useUIStore(useShallow((s) => ({
   favorites: s.favoriteStations,
   toggleFavorite: s.toggleFavoriteStation
 })));
+
+export interface Session {
+  token: string;
+  expires_at: string;
+  user: {
+    id: number;
+    email: string;
+  };
+}
+
+interface SessionState {
+  session: Session | null;
+  setSession: (session: Session | null) => void;
+}
+
+export const useSessionStore = create<SessionState>()(
+  persist(
+    (set) => ({
+      session: null,
+      setSession: (session) => set({ session }),
+    }),
+    {
+      name: 'session-store',
+      storage: createJSONStorage(() => localStorage),
+    }
+  )
+);